@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"math"
@@ -15,18 +17,22 @@ import (
 
 const (
 	// Audio settings
-	bufferSize = 4096
 	sampleRate = 44100
 	channels   = 1
 
 	// Debug settings
-	enableLevelDebug = true                   // Set to true to update debug info in UI
+	enableLevelDebug = true                   // Set to true to update the level meter and debug info in UI
 	debugInterval    = time.Millisecond * 200 // How often to update debug info
+	statsInterval    = time.Second            // How often to update the status bar
 
-	// Note stabilization
-	stabilizationDelay = 300 * time.Millisecond // Delay after volume increase before registering note
+	clipLevel = 0.999 // Sample magnitude counted as clipping, just under full scale
 
 	amplificationLevel = 7.0
+
+	// Detection settings
+	silenceThreshold = -30 // Frames quieter than this (dB) count as silence; high, to clear notes promptly
+	confidenceFloor  = 0.5 // Detections below this confidence are dropped
+	fftPaddingFactor = 4   // Zero-pad frames to 4x length for finer resolution on low notes
 )
 
 // getAudioLevel calculates RMS and dB level
@@ -54,53 +60,436 @@ func getAudioLevel(buffer *audio.AudioBuffer) (rms, db float32) {
 	return rms, db
 }
 
+// getPeakLevel calculates the largest sample magnitude in dB and whether any
+// sample clipped
+func getPeakLevel(buffer *audio.AudioBuffer) (db float32, clipped bool) {
+	peak := float32(0)
+	for _, sample := range buffer.Samples {
+		peak = max(peak, sample, -sample)
+	}
+
+	if peak > 0.0000001 {
+		db = 20 * float32(math.Log10(float64(peak)))
+	} else {
+		db = -100
+	}
+	return db, peak >= clipLevel
+}
+
+// noiseLevel returns the detector's adaptive noise estimate in dB
+func noiseLevel(detector *pitch.FFTDetector) float32 {
+	estimate := detector.NoiseEstimate()
+	if estimate <= 0 {
+		return -100
+	}
+	return float32(20 * math.Log10(estimate))
+}
+
+// loopSettings are the processing loop's own settings adjustable from the UI
+type loopSettings struct {
+	silenceDB       float32 // Frames quieter than this count as silence
+	confidenceFloor float64 // Detections below this confidence are dropped
+	paused          bool    // Whether detection is paused from the UI
+}
+
+// loopStats measures the processing loop for the status bar
+type loopStats struct {
+	since      time.Time         // Start of the current measurement
+	detections int               // Detections since then
+	latency    time.Duration     // Sum of the time from capture to detection
+	previous   audio.StreamStats // Capturer's counts at the start of the measurement
+}
+
+// detected counts a detection made from audio captured at the given time
+func (s *loopStats) detected(capturedAt time.Time) {
+	s.detections++
+	s.latency += time.Since(capturedAt)
+}
+
+// send sends the status bar's figures once a measurement has lasted the
+// interval, and starts the next one
+func (s *loopStats) send(p *tea.Program, capturer *audio.PortAudioCapturer, info audio.StreamInfo) {
+	elapsed := time.Since(s.since)
+	if elapsed < statsInterval {
+		return
+	}
+
+	msg := ui.UpdateStatsMsg{
+		Device:           info.Device,
+		SampleRate:       info.SampleRate,
+		Latency:          info.Latency,
+		DetectionsPerSec: float64(s.detections) / elapsed.Seconds(),
+	}
+	if s.detections > 0 {
+		msg.Latency += s.latency / time.Duration(s.detections)
+	}
+	stats := capturer.Stats()
+	if blocks := stats.Blocks - s.previous.Blocks; blocks > 0 {
+		msg.DroppedPercent = 100 * float64(stats.Dropped-s.previous.Dropped) / float64(blocks)
+	}
+	p.Send(msg)
+
+	*s = loopStats{since: time.Now(), previous: stats}
+}
+
+// pipelineState follows the processing loop for the debug panel
+type pipelineState struct {
+	gate          string        // "open", "closed" or "hold"
+	lastError     string        // Why the latest frame without a detection had none
+	detectionTime time.Duration // How long the latest detection took
+	frames        int64         // Blocks processed
+}
+
+// send sends the debug panel the loop's state and thresholds
+func (s *pipelineState) send(p *tea.Program, capturer *audio.PortAudioCapturer, detector *pitch.FFTDetector,
+	detectorName string, settings *loopSettings) {
+	p.Send(ui.UpdateDebugMsg{
+		Gate:            s.gate,
+		Detector:        detectorName,
+		LastError:       s.lastError,
+		DetectionTime:   s.detectionTime,
+		Frames:          s.frames,
+		Dropped:         capturer.Stats().Dropped,
+		SilenceDB:       float64(settings.silenceDB),
+		ConfidenceFloor: settings.confidenceFloor,
+		VolumeThreshold: detector.VolumeThreshold(),
+		PeakThreshold:   detector.PeakThreshold(),
+	})
+}
+
+// errorReporter sends problems to the UI's banner, as anything printed would
+// corrupt its screen. Each source's problem is sent once while it persists.
+type errorReporter struct {
+	p    *tea.Program
+	last map[string]string // Latest problem sent by source
+}
+
+// report sends a source's problem unless it's the one last sent
+func (r *errorReporter) report(source string, err error, severity ui.Severity) {
+	if err == nil || r.last[source] == err.Error() {
+		return
+	}
+	r.last[source] = err.Error()
+	r.p.Send(ui.ErrorMsg{Err: err, Severity: severity})
+}
+
+// resolved lets a source's problem be sent again should it recur
+func (r *errorReporter) resolved(source string) {
+	delete(r.last, source)
+}
+
+// applyCommands applies all pending UI commands without blocking
+func applyCommands(commands <-chan ui.Command, converter *pitch.NoteConverter, tracker *pitch.NoteTracker,
+	capturer *audio.PortAudioCapturer, attack *pitch.AttackGate, settings *loopSettings, problems *errorReporter) {
+	for {
+		select {
+		case command := <-commands:
+			switch command := command.(type) {
+			case ui.SetReferenceA4Command:
+				converter.SetReferenceA4(command.Hz)
+			case ui.SetTranspositionCommand:
+				converter.SetTransposition(command.Transposition)
+			case ui.SetCapoOffsetCommand:
+				converter.SetCapoOffset(command.Semitones)
+			case ui.SetSpellingCommand:
+				converter.SetSpelling(command.Spelling)
+				converter.SetKeySignature(command.KeySignature)
+			case ui.SetNamingCommand:
+				converter.SetNaming(command.Naming)
+			case ui.SetTemperamentCommand:
+				converter.SetTemperament(command.Temperament)
+				converter.SetTonic(command.Tonic)
+			case ui.SetOctaveFoldCommand:
+				tracker.SetOctaveFold(command.Fold)
+			case ui.SetGainCommand:
+				capturer.SetAmplification(float32(command.Factor))
+			case ui.SetSilenceThresholdCommand:
+				settings.silenceDB = float32(command.DB)
+			case ui.SetConfidenceFloorCommand:
+				settings.confidenceFloor = command.Confidence
+			case ui.SetAttackSettleCommand:
+				attack.SetSettleThreshold(command.Change)
+			case ui.SetPausedCommand:
+				settings.paused = command.Paused
+				var err error
+				if command.Paused {
+					err = capturer.Pause()
+				} else {
+					err = capturer.Resume()
+				}
+				if err != nil {
+					problems.report("pause", fmt.Errorf("pausing audio capture: %w", err), ui.SeverityError)
+				} else {
+					problems.resolved("pause")
+				}
+			}
+		default:
+			return
+		}
+	}
+}
+
+// forwardNoteEvents sends the tracker's pending note lifecycle events to the UI
+func forwardNoteEvents(p *tea.Program, tracker *pitch.NoteTracker) {
+	for _, event := range tracker.Events() {
+		switch event := event.(type) {
+		case pitch.NoteOn:
+			p.Send(ui.NoteOnMsg(event))
+		case pitch.NoteOff:
+			p.Send(ui.NoteOffMsg(event))
+		case pitch.Rest:
+			p.Send(ui.RestMsg(event))
+		case pitch.Glissando:
+			p.Send(ui.GlissandoMsg(event))
+		}
+	}
+}
+
 func main() {
+	// Command-line flags
+	chordMode := flag.Bool("chord", false, "Detect up to four simultaneous notes instead of a single note")
+	presetName := flag.String("preset", "chromatic", "Instrument preset: guitar, bass, ukulele, violin, voice, piano or chromatic")
+	scalePath := flag.String("scale", "", "Measure cents against the scale in this Scala (.scl) file")
+	scaleReference := flag.Float64("scale-ref", 0, "Frequency of the scale's 1/1 in Hz (default: C, equal-tempered from A4)")
+	melodyPath := flag.String("melody", "", "Sing along to the melody in this file, started with r")
+	stringNames := flag.String("strings", "", "Start the string tuner in a tuning: standard, drop-d, dadgad, open-g, eb-standard, one from -tunings, or notes such as \"D2 G2 D3 G3 B3 D4\"")
+	tuningsPath := flag.String("tunings", "", "Add the tunings in this file (lines such as \"Open C: C2 G2 C3 G3 C4 E4\") to those cycled with i")
+	historyLength := flag.Int("history", 1000, "Keep this many notes in the timeline history")
+	themeName := flag.String("theme", "default", "Color theme: default, light or high-contrast (default: the theme last used)")
+	noColor := flag.Bool("no-color", os.Getenv("NO_COLOR") != "", "Draw in plain ASCII without colors, toggled with A (default: on when NO_COLOR is set, otherwise as last used)")
+	exportPath := flag.String("export", "", "Write the timeline here when e is pressed: a .csv or .json file, or a directory for both (default: timestamped files in the current directory)")
+	noteHold := flag.Duration("hold", 750*time.Millisecond, "Keep the last note up, dimmed, this long after the sound stops")
+	inTuneTolerance := flag.Float64("tolerance", pitch.DefaultInTuneTolerance, "Show notes as in tune once within this many cents")
+	inTuneDwell := flag.Duration("dwell", pitch.DefaultInTuneDwell, "Show notes as in tune once within the tolerance for this long")
+	neighborThreshold := flag.Float64("neighbor", 35, "Point out the neighboring note once a note is more than this many cents off")
+	bell := flag.Bool("bell", false, "Ring the terminal bell each time a note locks in tune")
+	attackDelay := flag.Duration("attack-delay", 0, "Wait this long after each onset before registering a pitch, instead of waiting for the attack to settle")
+	flag.Parse()
+
+	preset, ok := pitch.PresetByName(*presetName)
+	if !ok {
+		log.Fatalf("Unknown instrument preset: %s", *presetName)
+	}
+	theme, ok := ui.ThemeByName(*themeName)
+	if !ok {
+		log.Fatalf("Unknown theme: %s", *themeName)
+	}
+
+	// Start from the preferences of the last run, overridden by the flags
+	// given. They stay unsaved when there's nowhere to keep them.
+	prefs := ui.DefaultPreferences()
+	var prefsErr error
+	prefsPath, err := ui.PreferencesPath()
+	if err != nil {
+		prefsErr = fmt.Errorf("finding where to keep preferences: %w", err)
+	} else {
+		prefs, prefsErr = ui.LoadPreferences(prefsPath)
+	}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "theme":
+			prefs.Theme = theme.Name
+		case "no-color":
+			prefs.Plain = *noColor
+		}
+	})
+	if os.Getenv("NO_COLOR") != "" {
+		prefs.Plain = *noColor
+	}
+
 	fmt.Println("TuneNote - Starting application...")
 
-	// Create audio capturer with PortAudio
-	capturer, err := audio.NewPortAudioCapturer(bufferSize, sampleRate, channels)
+	// Create the note converter shared with the UI's reference pitch setting
+	converter := pitch.NewNoteConverter()
+	converter.SetReferenceA4(prefs.ReferenceA4)
+	converter.SetSpelling(prefs.Spelling)
+	converter.SetNaming(prefs.Naming)
+	converter.SetCapoOffset(prefs.CapoOffset)
+
+	// Create FFT-based pitch detector configured for the instrument, refining
+	// the frequency from the phase advance between captured blocks
+	detector, err := pitch.NewFFTDetectorWithPreset(preset, pitch.WithPhaseRefinement(true))
+	if err != nil {
+		log.Fatalf("Failed to create pitch detector: %v", err)
+	}
+	detector.SetPaddingFactor(fftPaddingFactor)
+	detector.SetConverter(converter)
+	if err := detector.ValidateConfig(sampleRate); err != nil {
+		log.Fatalf("Invalid pitch detector configuration: %v", err)
+	}
+
+	// Create audio capturer with PortAudio, sized for the preset's window
+	capturer, err := audio.NewPortAudioCapturer(preset.WindowSize, sampleRate, channels)
 	if err != nil {
 		log.Fatalf("Failed to create audio capturer: %v", err)
 	}
 
-	// Create FFT-based pitch detector
-	detector := pitch.NewFFTDetector(bufferSize)
+	// Create note tracker to stabilize the displayed note
+	tracker := pitch.NewNoteTracker()
+	tracker.SetOctaveFold(prefs.OctaveFold)
 
-	// Create UI model
-	model := ui.NewModel()
+	// Create onset detector to register new notes as they are played
+	onsets := pitch.NewOnsetDetector()
 
-	// Start audio capture
-	err = capturer.Start()
+	// Create attack gate to skip each note's attack transient
+	attack := pitch.NewAttackGate()
+	if err := attack.SetSettleThreshold(preset.AttackSettle); err != nil {
+		log.Fatalf("Invalid attack settings: %v", err)
+	}
+	attack.SetFixedDelay(*attackDelay)
+
+	// Create pitch track to suppress one-frame leaps to a harmonic
+	pitchTrack := pitch.NewPitchTrack()
+
+	// Create vibrato analyzer for held notes
+	vibrato := pitch.NewVibratoAnalyzer()
+
+	// Create steadiness meter for long tones
+	steadiness := pitch.NewSteadinessMeter()
+
+	// Create precision analyzer to refine the cents of sustained notes over
+	// several consecutive captured blocks
+	precision, err := pitch.NewPrecisionAnalyzer(preset.WindowSize)
 	if err != nil {
-		log.Fatalf("Failed to start audio capture: %v", err)
+		log.Fatalf("Failed to create precision analyzer: %v", err)
+	}
+	precision.SetConverter(converter)
+
+	// Create UI model with a channel for commands back to the processing loop
+	commands := make(chan ui.Command, 16)
+	model := ui.NewModel(commands).WithTimelineLength(*historyLength).WithExportPath(*exportPath).WithPreferences(prefs).WithPreferencesPath(prefsPath).
+		WithNoteHold(*noteHold).WithInTuneLock(*inTuneTolerance, *inTuneDwell).WithBell(*bell).WithNeighborThreshold(*neighborThreshold).
+		WithSettings(ui.Settings{
+			Gain:            amplificationLevel,
+			SilenceDB:       silenceThreshold,
+			ConfidenceFloor: confidenceFloor,
+			AttackSettle:    preset.AttackSettle,
+		})
+
+	// Measure against a custom scale when one is given
+	if *scalePath != "" {
+		scale, err := pitch.LoadScala(*scalePath)
+		if err != nil {
+			log.Fatalf("Failed to load scale: %v", err)
+		}
+		scale.Reference = *scaleReference
+		converter.SetTemperament(*scale)
+		model = model.WithTemperament(*scale)
+	}
+
+	// Score singing along to a target melody when one is given
+	if *melodyPath != "" {
+		melody, err := pitch.LoadMelody(*melodyPath)
+		if err != nil {
+			log.Fatalf("Failed to load melody: %v", err)
+		}
+		model = model.WithMelody(melody)
+	}
+
+	// Add custom tunings to the built-in ones
+	var tunings []pitch.StringSet
+	if *tuningsPath != "" {
+		tunings, err = pitch.LoadStringSets(*tuningsPath)
+		if err != nil {
+			log.Fatalf("Failed to load tunings: %v", err)
+		}
+		model = model.WithStringSets(tunings)
+	}
+
+	// Tune the strings of a guitar when a tuning is given
+	if *stringNames != "" {
+		set, ok := pitch.FindStringSet(tunings, *stringNames)
+		if !ok {
+			set, ok = pitch.StringSetByName(*stringNames)
+		}
+		if !ok {
+			set, err = pitch.ParseStringSet("Custom", *stringNames)
+			if err != nil {
+				log.Fatalf("Invalid strings: %v", err)
+			}
+		}
+		model = model.WithStringSet(set)
+	}
+
+	// Start audio capture, leaving a failure to show in the UI
+	startErr := capturer.Start()
+	if startErr == nil {
+		defer capturer.Stop()
 	}
-	defer capturer.Stop()
 
 	// Start UI
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	problems := errorReporter{p: p, last: map[string]string{}}
 
 	// Variables
 	lastDebugTime := time.Now()
+	peakDB, clipped := float32(-100), false // Peak and clipping since the last level update
 	lastNoteTime := time.Now()
-	isVolumeRising := false
-	volumeRiseTime := time.Time{}
-	lastDB := float32(-100)
 
 	// Increase audio input sensitivity
 	capturer.SetAmplification(amplificationLevel)
 
+	// Settings the UI may change while running
+	settings := loopSettings{
+		silenceDB:       silenceThreshold,
+		confidenceFloor: confidenceFloor,
+	}
+
+	// Describe the stream for the status bar
+	info := capturer.Info()
+	stats := loopStats{since: time.Now(), previous: capturer.Stats()}
+
+	// Follow the loop for the debug panel
+	pipeline := pipelineState{gate: "closed"}
+	detectorName := "FFT, " + preset.Name + " preset"
+	if *chordMode {
+		detectorName += ", chords"
+	}
+
 	// Print startup message
 	fmt.Println("Listening for musical notes...")
 
 	// Start a goroutine for audio processing
 	go func() {
+		if startErr != nil {
+			problems.report("capture", fmt.Errorf("failed to start audio capture: %w", startErr), ui.SeverityError)
+		}
+		if prefsErr != nil {
+			problems.report("preferences", fmt.Errorf("%w; using the defaults", prefsErr), ui.SeverityWarning)
+		}
+
 		for {
+			// Apply settings changed from the UI
+			applyCommands(commands, converter, tracker, capturer, attack, &settings, &problems)
+
+			// Update the status bar
+			stats.send(p, capturer, info)
+
+			// Leave the audio alone while paused, ending the note so that on
+			// resuming a new one has to settle before it shows
+			if settings.paused {
+				tracker.UpdateAt(nil, time.Now())
+				forwardNoteEvents(p, tracker)
+				onsets.Reset()
+				attack.Reset()
+				pitchTrack.Reset()
+				vibrato.Reset()
+				steadiness.Reset()
+				precision.Reset()
+				time.Sleep(time.Millisecond * 50)
+				continue
+			}
+
 			// Get audio buffer
 			buffer, err := capturer.GetBuffer()
 			if err != nil {
+				if capturer.IsCapturing() {
+					problems.report("capture", fmt.Errorf("reading audio: %w", err), ui.SeverityError)
+				}
 				time.Sleep(time.Millisecond * 10)
 				continue
 			}
+			problems.resolved("capture")
 
 			// Skip if buffer is empty or too small
 			if len(buffer.Samples) < 512 {
@@ -108,62 +497,213 @@ func main() {
 				continue
 			}
 
+			pipeline.frames++
+
+			// Keep the recent stream for precision analysis
+			precision.Add(buffer)
+
+			// Timestamp events with the capture time when the capturer provides it
+			capturedAt := buffer.Timestamp
+			if capturedAt.IsZero() {
+				capturedAt = time.Now()
+			}
+
 			// Get audio levels for monitoring
 			rms, db := getAudioLevel(buffer)
+			framePeak, frameClipped := getPeakLevel(buffer)
+			peakDB = max(peakDB, framePeak)
+			clipped = clipped || frameClipped
 
 			// Send audio levels to UI instead of printing to terminal
 			if enableLevelDebug && time.Since(lastDebugTime) > debugInterval {
 				p.Send(ui.UpdateAudioLevelMsg{
-					RMS: rms,
-					DB:  db,
+					RMS:     rms,
+					DB:      db,
+					NoiseDB: noiseLevel(detector),
+					PeakDB:  peakDB,
+					Clipped: clipped,
 				})
+				pipeline.send(p, capturer, detector, detectorName, &settings)
+				p.Send(ui.UpdateWaveformMsg{Samples: buffer.Samples})
+				peakDB, clipped = -100, false
 				lastDebugTime = time.Now()
 			}
 
-			// Detect when volume is rising (note beginning)
-			if db > lastDB+3 && db > -40 {
-				// Volume is rising significantly and above threshold
-				if !isVolumeRising {
-					isVolumeRising = true
-					volumeRiseTime = time.Now()
-					// Don't attempt pitch detection until stabilization period is over
-					lastDB = db
-					time.Sleep(time.Millisecond * 10)
-					continue
-				}
-			}
-			lastDB = db
-
 			// MUCH more aggressive silence detection - higher dB threshold
 			// and clear notes immediately on silence
-			if db < -30 { // Was -50, now -30 for more aggressive silence detection
+			if db < settings.silenceDB {
+				pipeline.gate, pipeline.lastError = "closed", "volume below threshold"
+				tracker.UpdateAt(nil, capturedAt)
+				forwardNoteEvents(p, tracker)
+				onsets.Reset()
+				attack.Reset()
+				pitchTrack.Reset()
+				vibrato.Reset()
+				steadiness.Reset()
+				precision.Reset()
+				p.Send(ui.ClearNoteMsg{})
+				time.Sleep(time.Millisecond * 50)
+				continue
+			}
+
+			// Compute the spectrum once for both onset and pitch detection
+			spectrum, err := detector.Spectrum(buffer)
+			if err != nil {
+				// Frames too quiet for the detector are silence, not a failure
+				pipeline.lastError = err.Error()
+				if errors.Is(err, pitch.ErrVolumeThreshold) {
+					pipeline.gate = "closed"
+				} else {
+					problems.report("spectrum", fmt.Errorf("computing the spectrum: %w", err), ui.SeverityWarning)
+				}
+				tracker.UpdateAt(nil, capturedAt)
+				forwardNoteEvents(p, tracker)
+				onsets.Reset()
+				attack.Reset()
+				pitchTrack.Reset()
+				vibrato.Reset()
+				steadiness.Reset()
+				precision.Reset()
 				p.Send(ui.ClearNoteMsg{})
-				isVolumeRising = false // Reset volume rising flag
 				time.Sleep(time.Millisecond * 50)
 				continue
 			}
+			problems.resolved("spectrum")
+			p.Send(ui.UpdateSpectrumMsg{
+				Scale:  pitch.DefaultSpectrogramScale,
+				Levels: pitch.DefaultSpectrogramScale.Levels(spectrum, buffer.SampleRate),
+			})
+
+			// A new note starts at an onset. Skip its attack, whose spectrum
+			// is still smeared, and let the tracker register the new note
+			// once it has passed without waiting out its hysteresis.
+			if onset, ok := onsets.Process(spectrum, capturedAt); ok {
+				pipeline.gate = "hold"
+				p.Send(ui.OnsetMsg(onset))
+				attack.Onset(float64(rms), capturedAt)
+				tracker.UpdateAt(nil, capturedAt)
+				forwardNoteEvents(p, tracker)
+				pitchTrack.Reset()
+				vibrato.Reset()
+				steadiness.Reset()
+				precision.Reset()
+				time.Sleep(time.Millisecond * 10)
+				continue
+			}
 
-			// If we're in the initial rising volume period, wait for stabilization
-			if isVolumeRising && time.Since(volumeRiseTime) < stabilizationDelay {
+			if !attack.Process(float64(rms), capturedAt) {
+				pipeline.gate = "hold"
 				time.Sleep(time.Millisecond * 10)
 				continue
 			}
+			pipeline.gate = "open"
+
+			// In chord mode, report every simultaneous note instead
+			if *chordMode {
+				detectionStart := time.Now()
+				notes, err := detector.DetectChordSpectrum(spectrum, buffer.SampleRate)
+				pipeline.detectionTime = time.Since(detectionStart)
+				if err != nil {
+					pipeline.lastError = err.Error()
+					p.Send(ui.ClearNoteMsg{})
+					time.Sleep(time.Millisecond * 50)
+					continue
+				}
+				stats.detected(capturedAt)
+
+				if time.Since(lastNoteTime) > 80*time.Millisecond {
+					chord := ui.UpdateChordMsg{Notes: make([]pitch.Note, len(notes))}
+					for i, note := range notes {
+						chord.Notes[i] = *note
+					}
+					p.Send(chord)
+					lastNoteTime = time.Now()
+				}
 
-			// Past stabilization period, note should be stable
-			isVolumeRising = false
+				time.Sleep(time.Millisecond * 50)
+				continue
+			}
 
-			// Try to detect pitch
-			note, err := detector.DetectPitch(buffer)
+			// Try to detect pitch, along with the harmonic levels for the debug panel
+			detectionStart := time.Now()
+			detection, err := detector.AnalyzeSpectrum(spectrum, buffer.SampleRate)
+			pipeline.detectionTime = time.Since(detectionStart)
 			if err != nil {
+				pipeline.lastError = err.Error()
 				// Any error in pitch detection should clear the display
+				tracker.UpdateAt(nil, capturedAt)
+				forwardNoteEvents(p, tracker)
+				pitchTrack.Reset()
+				vibrato.Reset()
+				steadiness.Reset()
+				precision.Reset()
 				p.Send(ui.ClearNoteMsg{})
 				time.Sleep(time.Millisecond * 50)
 				continue
 			}
 
-			// Only send note updates at reasonable intervals to prevent flicker
-			if time.Since(lastNoteTime) > 80*time.Millisecond {
-				p.Send(ui.UpdateNoteMsg(*note))
+			stats.detected(capturedAt)
+			note := detection.Note
+
+			// Drop untrustworthy results (attacks, fret noise) instead of forwarding them
+			if note.Confidence < settings.confidenceFloor {
+				pipeline.lastError = fmt.Sprintf("confidence %.2f below floor", note.Confidence)
+				time.Sleep(time.Millisecond * 50)
+				continue
+			}
+
+			// Hold back sudden leaps (usually a harmonic picked for a single
+			// frame) until they persist. A leap that does persist is a genuine
+			// new note, so let the tracker switch to it without hysteresis,
+			// unless it only changes the octave and octaves are ignored.
+			corrected, jumped := pitchTrack.Update(note.Frequency)
+			if corrected != note.Frequency {
+				time.Sleep(time.Millisecond * 50)
+				continue
+			}
+			if jumped && !tracker.Matches(note) {
+				tracker.UpdateAt(nil, capturedAt)
+				forwardNoteEvents(p, tracker)
+			}
+
+			// Feed the detection through the tracker so borderline pitches
+			// don't flip the displayed note
+			stable, changed := tracker.UpdateAt(note, capturedAt)
+			forwardNoteEvents(p, tracker)
+			if stable == nil {
+				time.Sleep(time.Millisecond * 50)
+				continue
+			}
+
+			// Follow the pitch of the held note for vibrato and steadiness,
+			// starting over whenever the note changes
+			if changed {
+				vibrato.Reset()
+				steadiness.Reset()
+				precision.Reset()
+			}
+			var vibratoMsg ui.UpdateVibratoMsg
+			var steadinessMsg ui.UpdateSteadinessMsg
+			if note.ConcertName == stable.ConcertName && note.ConcertOctave == stable.ConcertOctave {
+				vibratoMsg.Vibrato, vibratoMsg.Detected = vibrato.Add(note.Frequency, time.Now())
+				steadinessMsg.Steadiness, steadinessMsg.Measured = steadiness.Add(note.Frequency, time.Now())
+			}
+
+			// Once the note has been held a while, measure its cents over a
+			// longer window
+			if refined, ok := precision.Refine(stable, capturedAt); ok {
+				stable = refined
+			}
+
+			// Send note changes right away, and refresh the current note at
+			// reasonable intervals to prevent flicker
+			if changed || time.Since(lastNoteTime) > 80*time.Millisecond {
+				p.Send(ui.UpdateNoteMsg(*stable))
+				p.Send(vibratoMsg)
+				p.Send(steadinessMsg)
+				if enableLevelDebug {
+					p.Send(ui.UpdateHarmonicsMsg{Harmonics: detection.Harmonics, HNR: detection.HNR})
+				}
 				lastNoteTime = time.Now()
 			}
 
@@ -3,12 +3,15 @@ package audio
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // AudioBuffer represents a buffer of audio samples
 type AudioBuffer struct {
 	Samples    []float32
 	SampleRate int
+	Timestamp  time.Time // When the last sample was captured (zero if unknown)
+	Position   int64     // Index of the first sample in the capture stream (0 if unknown)
 }
 
 // Capturer defines the interface for audio capture
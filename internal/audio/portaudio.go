@@ -3,6 +3,7 @@ package audio
 import (
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/gordonklaus/portaudio"
 )
@@ -18,6 +19,24 @@ type PortAudioCapturer struct {
 	inputBuffer   []float32
 	bufferMutex   sync.Mutex
 	amplification float32 // Audio signal amplification factor
+	paused        bool    // Whether the stream is stopped by Pause
+	position      int64   // Number of frames captured so far
+	unread        bool    // Whether the current block hasn't been returned by GetBuffer
+	blocks        int64   // Number of blocks captured so far
+	dropped       int64   // Number of blocks replaced before GetBuffer returned them
+}
+
+// StreamInfo describes the input stream being captured
+type StreamInfo struct {
+	Device     string        // Name of the input device
+	SampleRate int           // Sample rate in Hz
+	Latency    time.Duration // Input latency reported by the device
+}
+
+// StreamStats counts the blocks captured since Start
+type StreamStats struct {
+	Blocks  int64 // Blocks the device delivered
+	Dropped int64 // Blocks replaced by the next one before GetBuffer returned them
 }
 
 // NewPortAudioCapturer creates a new audio capturer using PortAudio
@@ -74,6 +93,30 @@ func (c *PortAudioCapturer) Start() error {
 	return nil
 }
 
+// Info describes the stream being captured, empty before Start
+func (c *PortAudioCapturer) Info() StreamInfo {
+	if !c.isCapturing {
+		return StreamInfo{}
+	}
+
+	info := StreamInfo{SampleRate: c.sampleRate}
+	if device, err := portaudio.DefaultInputDevice(); err == nil {
+		info.Device = device.Name
+	}
+	if stream := c.stream.Info(); stream != nil {
+		info.SampleRate = int(stream.SampleRate)
+		info.Latency = stream.InputLatency
+	}
+	return info
+}
+
+// Stats counts the blocks captured and dropped so far
+func (c *PortAudioCapturer) Stats() StreamStats {
+	c.bufferMutex.Lock()
+	defer c.bufferMutex.Unlock()
+	return StreamStats{Blocks: c.blocks, Dropped: c.dropped}
+}
+
 // Stop ends audio capture
 func (c *PortAudioCapturer) Stop() error {
 	if !c.isCapturing {
@@ -101,11 +144,64 @@ func (c *PortAudioCapturer) Stop() error {
 	return nil
 }
 
+// Pause stops the stream without closing it, leaving the microphone idle
+// until Resume. The buffered audio is dropped, so none from before the pause
+// is returned after it.
+func (c *PortAudioCapturer) Pause() error {
+	if !c.isCapturing {
+		return errors.New("audio capture not started")
+	}
+	if c.paused {
+		return nil
+	}
+
+	if err := c.stream.Stop(); err != nil {
+		return err
+	}
+	c.paused = true
+
+	c.bufferMutex.Lock()
+	c.buffer.Samples = c.buffer.Samples[:0]
+	c.unread = false
+	c.bufferMutex.Unlock()
+	return nil
+}
+
+// Resume restarts the stream stopped by Pause
+func (c *PortAudioCapturer) Resume() error {
+	if !c.isCapturing {
+		return errors.New("audio capture not started")
+	}
+	if !c.paused {
+		return nil
+	}
+
+	if err := c.stream.Start(); err != nil {
+		return err
+	}
+	c.paused = false
+	return nil
+}
+
 // processAudio is the callback function for audio processing
 func (c *PortAudioCapturer) processAudio(in, _ []float32) {
 	c.bufferMutex.Lock()
 	defer c.bufferMutex.Unlock()
 
+	// Remember when this block arrived, for timestamping detections
+	c.buffer.Timestamp = time.Now()
+
+	// Number the block's samples so consumers can tell how far apart two buffers are
+	c.buffer.Position = c.position
+	c.position += int64(len(in) / c.channels)
+
+	// Count the blocks replaced before anyone read them
+	c.blocks++
+	if c.unread {
+		c.dropped++
+	}
+	c.unread = true
+
 	// If we have multi-channel input, we'll average the channels
 	if c.channels > 1 {
 		// Create a mono buffer for averaging channels
@@ -145,8 +241,11 @@ func (c *PortAudioCapturer) GetBuffer() (*AudioBuffer, error) {
 	bufferCopy := &AudioBuffer{
 		Samples:    make([]float32, len(c.buffer.Samples)),
 		SampleRate: c.buffer.SampleRate,
+		Timestamp:  c.buffer.Timestamp,
+		Position:   c.buffer.Position,
 	}
 	copy(bufferCopy.Samples, c.buffer.Samples)
+	c.unread = false
 
 	return bufferCopy, nil
 }
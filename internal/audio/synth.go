@@ -0,0 +1,31 @@
+package audio
+
+import "math"
+
+// SynthesizeTone returns a buffer holding a steady tone: a fundamental plus
+// overtones with the given relative amplitudes (harmonics[0] is the
+// fundamental). The peak level is normalized to 0.5, and the output is fully
+// deterministic, so it suits reproducible benchmarks and calibration.
+func SynthesizeTone(frequency float64, harmonics []float64, samples, sampleRate int) *AudioBuffer {
+	buffer := &AudioBuffer{
+		Samples:    make([]float32, samples),
+		SampleRate: sampleRate,
+	}
+
+	values := make([]float64, samples)
+	peak := 0.0
+	for i := range values {
+		t := float64(i) / float64(sampleRate)
+		for h, amplitude := range harmonics {
+			values[i] += amplitude * math.Sin(2*math.Pi*frequency*float64(h+1)*t)
+		}
+		peak = math.Max(peak, math.Abs(values[i]))
+	}
+
+	if peak > 0 {
+		for i, value := range values {
+			buffer.Samples[i] = float32(0.5 * value / peak)
+		}
+	}
+	return buffer
+}
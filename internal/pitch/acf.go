@@ -0,0 +1,135 @@
+package pitch
+
+import (
+	"math"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+// ACFDetector implements pitch detection using time-domain autocorrelation.
+// It is cheaper than the FFT path and works well on clean, monophonic signals.
+type ACFDetector struct {
+	minFrequency        float64 // Lowest frequency to detect (Hz)
+	maxFrequency        float64 // Highest frequency to detect (Hz)
+	volumeThreshold     float64 // Minimum RMS volume level for note detection
+	confidenceThreshold float64 // Minimum normalized autocorrelation peak (0.0-1.0)
+	peakTolerance       float64 // Fraction of the highest peak a shorter period must reach
+
+	converter *NoteConverter // Converts detected frequencies into notes
+}
+
+// NewACFDetector creates a new autocorrelation-based pitch detector
+func NewACFDetector() *ACFDetector {
+	return &ACFDetector{
+		converter:           NewNoteConverter(),
+		minFrequency:        80.0,   // Same range as the FFT detector
+		maxFrequency:        1200.0, // Same range as the FFT detector
+		volumeThreshold:     0.005,  // Same silence handling as the FFT detector
+		confidenceThreshold: 0.5,    // Peaks below half the signal energy are unreliable
+		peakTolerance:       0.9,    // Guards against picking a multiple of the period
+	}
+}
+
+// SetConverter sets the converter used to turn frequencies into notes
+func (d *ACFDetector) SetConverter(converter *NoteConverter) {
+	d.converter = converter
+}
+
+// DetectPitch analyzes an audio buffer and returns the detected note
+func (d *ACFDetector) DetectPitch(buffer *audio.AudioBuffer) (*Note, error) {
+	if buffer == nil || len(buffer.Samples) == 0 {
+		return nil, ErrEmptyBuffer
+	}
+
+	samples := buffer.Samples
+	n := len(samples)
+
+	// Zero-lag autocorrelation is the total energy of the frame
+	energy := autocorrelate(samples, 0)
+	if math.Sqrt(energy/float64(n)) < d.volumeThreshold {
+		return nil, ErrVolumeThreshold
+	}
+
+	// Convert the frequency range into a lag range (in samples)
+	minLag := int(float64(buffer.SampleRate) / d.maxFrequency)
+	if minLag < 1 {
+		minLag = 1
+	}
+	maxLag := int(math.Ceil(float64(buffer.SampleRate) / d.minFrequency))
+	if maxLag > n-2 {
+		maxLag = n - 2
+	}
+	if minLag >= maxLag {
+		return nil, ErrLowConfidence
+	}
+
+	// Compute the normalized autocorrelation from lag 1, plus one extra lag
+	// past maxLag so the peak can always be interpolated
+	acf := make([]float64, maxLag+2)
+	for lag := 1; lag <= maxLag+1; lag++ {
+		acf[lag] = autocorrelate(samples, lag) / energy
+	}
+
+	// Skip the zero-lag lobe: everything up to the first local minimum
+	lag := 1
+	for lag < maxLag && acf[lag+1] < acf[lag] {
+		lag++
+	}
+	if lag < minLag {
+		lag = minLag
+	}
+
+	// Collect every peak after the zero-lag lobe
+	var peakLags []int
+	highest := 0.0
+	for ; lag <= maxLag; lag++ {
+		if acf[lag] > acf[lag-1] && acf[lag] >= acf[lag+1] {
+			peakLags = append(peakLags, lag)
+			if acf[lag] > highest {
+				highest = acf[lag]
+			}
+		}
+	}
+
+	// Pick the highest peak, but prefer the shortest period among peaks that
+	// are nearly as high, since multiples of the period also correlate well
+	bestLag := -1
+	bestValue := 0.0
+	for _, peakLag := range peakLags {
+		if acf[peakLag] >= highest*d.peakTolerance {
+			bestLag = peakLag
+			bestValue = acf[peakLag]
+			break
+		}
+	}
+
+	// Reject frames without a clear periodicity
+	if bestLag < 0 || bestValue < d.confidenceThreshold {
+		return nil, ErrLowConfidence
+	}
+
+	// Refine the lag with parabolic interpolation
+	prev, current, next := acf[bestLag-1], acf[bestLag], acf[bestLag+1]
+	period := float64(bestLag) + parabolicOffset(prev, current, next)
+
+	frequency := float64(buffer.SampleRate) / period
+	if frequency < d.minFrequency || frequency > d.maxFrequency {
+		return nil, ErrOutOfRange
+	}
+
+	note := d.converter.FromFrequency(frequency)
+	if note == nil {
+		return nil, ErrNoPitchDetected
+	}
+	note.Confidence = math.Min(bestValue, 1.0)
+	return note, nil
+}
+
+// autocorrelate returns the autocorrelation of the samples at the given lag
+func autocorrelate(samples []float32, lag int) float64 {
+	sum := 0.0
+	for i := 0; i+lag < len(samples); i++ {
+		sum += float64(samples[i]) * float64(samples[i+lag])
+	}
+	return sum
+}
@@ -0,0 +1,85 @@
+package pitch
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+var _ Detector = (*ACFDetector)(nil)
+
+// sawtooth returns the overtone levels of a sawtooth wave up to the given
+// harmonic: each falls off as 1/n
+func sawtooth(harmonics int) []float64 {
+	levels := make([]float64, harmonics)
+	for i := range levels {
+		levels[i] = 1 / float64(i+1)
+	}
+	return levels
+}
+
+// rangeFrequencies spans the default 80-1200 Hz range
+var rangeFrequencies = []float64{82.41, 110, 146.83, 196, 261.63, 329.63, 440, 659.25, 880, 1046.5, 1174.66}
+
+func TestACFDetectorAccuracy(t *testing.T) {
+	waves := []struct {
+		name      string
+		harmonics []float64
+	}{
+		{"sine", []float64{1}},
+		{"sawtooth", sawtooth(8)},
+	}
+	detector := NewACFDetector()
+	for _, wave := range waves {
+		for _, frequency := range rangeFrequencies {
+			note, err := detector.DetectPitch(audio.SynthesizeTone(frequency, wave.harmonics, 4096, 44100))
+			if err != nil {
+				t.Errorf("%s at %v Hz: %v", wave.name, frequency, err)
+				continue
+			}
+			if cents := 1200 * math.Log2(note.Frequency/frequency); math.Abs(cents) > 5 {
+				t.Errorf("%s at %v Hz read %.2f Hz, %+.1f cents off", wave.name, frequency, note.Frequency, cents)
+			}
+			if note.Confidence < 0.5 || note.Confidence > 1 {
+				t.Errorf("%s at %v Hz has confidence %.2f", wave.name, frequency, note.Confidence)
+			}
+		}
+	}
+}
+
+func TestACFDetectorRejectsSilence(t *testing.T) {
+	silence := &audio.AudioBuffer{Samples: make([]float32, 4096), SampleRate: 44100}
+	if note, err := NewACFDetector().DetectPitch(silence); err == nil {
+		t.Errorf("detected %s%d in silence", note.Name, note.Octave)
+	}
+}
+
+// BenchmarkDetectors compares the ACF and FFT detectors on the same buffers
+func BenchmarkDetectors(b *testing.B) {
+	fft, err := NewFFTDetector(4096)
+	if err != nil {
+		b.Fatal(err)
+	}
+	detectors := []struct {
+		name     string
+		detector Detector
+	}{
+		{"ACF", NewACFDetector()},
+		{"FFT", fft},
+	}
+	for _, frequency := range []float64{82.41, 440} {
+		buffer := audio.SynthesizeTone(frequency, guitarTone, 4096, 44100)
+		for _, d := range detectors {
+			b.Run(fmt.Sprintf("%s/%v", d.name, frequency), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := d.detector.DetectPitch(buffer); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
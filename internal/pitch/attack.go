@@ -0,0 +1,91 @@
+package pitch
+
+import (
+	"math"
+	"time"
+)
+
+// AttackGate holds back pitch registration after an onset until the note's
+// attack transient has passed, whose spectrum is smeared and often pitched
+// wrong. Rather than waiting a fixed time, which is too long for a piano
+// and too short for a slowly bowed cello, it follows the level envelope:
+// the attack has passed once the envelope has peaked and settled, changing
+// by less than a threshold from one frame to the next. A plucked note
+// settles within a frame or two of its peak, a bowed one only once its slow
+// rise levels off. A fixed delay can be set instead.
+type AttackGate struct {
+	settle     float64       // Relative level change per frame below which the attack has passed
+	maxWait    time.Duration // Longest hold after an onset, for notes that never settle (tremolo)
+	fixedDelay time.Duration // When nonzero, hold this long after an onset instead of following the envelope
+
+	onset    time.Time // Most recent onset, zero once the attack has passed
+	previous float64   // Level of the previous frame
+}
+
+// NewAttackGate creates a new attack gate
+func NewAttackGate() *AttackGate {
+	return &AttackGate{
+		settle:  0.15,        // Level steady within 15% per frame
+		maxWait: time.Second, // Longer than any attack
+	}
+}
+
+// SetSettleThreshold sets the relative change of the level between frames
+// (0.0-1.0) below which an attack counts as passed, e.g. a preset's
+// AttackSettle. Higher values register notes sooner.
+func (g *AttackGate) SetSettleThreshold(change float64) error {
+	if change <= 0 || change > 1 {
+		return ErrInvalidThreshold
+	}
+
+	g.settle = change
+	return nil
+}
+
+// SetFixedDelay makes the gate hold for a fixed time after each onset
+// instead of following the envelope; zero returns to following it
+func (g *AttackGate) SetFixedDelay(delay time.Duration) {
+	g.fixedDelay = max(0, delay)
+}
+
+// Onset starts holding back registration for the attack of a new note,
+// given the level (RMS) of the frame containing the onset
+func (g *AttackGate) Onset(level float64, at time.Time) {
+	g.onset = at
+	g.previous = level
+}
+
+// Process takes the level (RMS) of the next frame and reports whether its
+// pitch may be registered: always, unless an attack is still under way.
+// Frames are expected in order.
+func (g *AttackGate) Process(level float64, at time.Time) bool {
+	previous := g.previous
+	g.previous = level
+	if g.onset.IsZero() {
+		return true
+	}
+
+	held := at.Sub(g.onset)
+	var passed bool
+	switch {
+	case g.fixedDelay > 0:
+		passed = held >= g.fixedDelay
+	case held >= g.maxWait:
+		passed = true
+	default:
+		// Still rising towards the peak, or falling away from it quickly
+		change := math.Abs(level-previous) / math.Max(level, previous)
+		passed = !(change >= g.settle)
+	}
+
+	if passed {
+		g.onset = time.Time{}
+	}
+	return passed
+}
+
+// Reset forgets any attack under way, e.g. when the sound stops
+func (g *AttackGate) Reset() {
+	g.onset = time.Time{}
+	g.previous = 0
+}
@@ -0,0 +1,91 @@
+package pitch
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// registration sends an onset at the first frame of an envelope, sampled
+// every frame interval from then on, and returns how long after the onset
+// the gate first let a frame through
+func registration(t *testing.T, gate *AttackGate, envelope func(seconds float64) float64) time.Duration {
+	t.Helper()
+	start := time.Unix(0, 0)
+	gate.Onset(envelope(0), start)
+	for frame := 1; frame <= 40; frame++ {
+		elapsed := time.Duration(frame) * frameInterval
+		if gate.Process(envelope(elapsed.Seconds()), start.Add(elapsed)) {
+			return elapsed
+		}
+	}
+	t.Fatal("gate held for two seconds")
+	return 0
+}
+
+// Level envelopes of a struck, a plucked, a bowed and a tremolo note
+var (
+	pianoEnvelope   = func(s float64) float64 { return 0.6 * math.Exp(-s/0.4) }
+	pluckEnvelope   = func(s float64) float64 { return 0.5 * math.Exp(-s/1.0) }
+	bowedEnvelope   = func(s float64) float64 { return 0.05 + 0.45*math.Min(s/0.6, 1) }
+	tremoloEnvelope = func(s float64) float64 { return 0.35 + 0.15*math.Cos(2*math.Pi*10*s) }
+)
+
+func TestAttackGateFollowsEnvelope(t *testing.T) {
+	tests := []struct {
+		name     string
+		preset   Preset
+		envelope func(float64) float64
+		min, max time.Duration
+	}{
+		{"piano", PresetPiano, pianoEnvelope, 50 * time.Millisecond, 50 * time.Millisecond},
+		{"guitar pluck", PresetGuitar, pluckEnvelope, 50 * time.Millisecond, 50 * time.Millisecond},
+		{"bowed violin", PresetViolin, bowedEnvelope, 400 * time.Millisecond, 650 * time.Millisecond},
+		{"sung", PresetVoice, bowedEnvelope, 400 * time.Millisecond, 650 * time.Millisecond},
+		{"tremolo", PresetChromatic, tremoloEnvelope, time.Second, time.Second},
+	}
+	for _, tt := range tests {
+		gate := NewAttackGate()
+		if err := gate.SetSettleThreshold(tt.preset.AttackSettle); err != nil {
+			t.Fatal(err)
+		}
+		if got := registration(t, gate, tt.envelope); got < tt.min || got > tt.max {
+			t.Errorf("%s: registered %v after the onset, want %v to %v", tt.name, got, tt.min, tt.max)
+		}
+
+		// Frames after the attack pass, and without an onset nothing is held
+		if !gate.Process(tt.envelope(2), time.Unix(2, 0)) {
+			t.Errorf("%s: held a frame after the attack had passed", tt.name)
+		}
+	}
+
+	if !NewAttackGate().Process(0.5, time.Unix(0, 0)) {
+		t.Error("held a frame with no onset")
+	}
+}
+
+func TestAttackGateFixedDelay(t *testing.T) {
+	// A fixed delay ignores the envelope entirely
+	for _, envelope := range []func(float64) float64{pianoEnvelope, pluckEnvelope, bowedEnvelope, tremoloEnvelope} {
+		gate := NewAttackGate()
+		gate.SetFixedDelay(200 * time.Millisecond)
+		if got := registration(t, gate, envelope); got != 200*time.Millisecond {
+			t.Errorf("registered %v after the onset, want 200ms", got)
+		}
+	}
+}
+
+func TestAttackGateReset(t *testing.T) {
+	gate := NewAttackGate()
+	gate.Onset(0.05, time.Unix(0, 0))
+	gate.Reset()
+	if !gate.Process(0.5, time.Unix(0, int64(frameInterval))) {
+		t.Error("still holding after Reset")
+	}
+
+	for _, change := range []float64{0, -0.1, 1.5} {
+		if err := gate.SetSettleThreshold(change); err != ErrInvalidThreshold {
+			t.Errorf("SetSettleThreshold(%v) = %v, want %v", change, err, ErrInvalidThreshold)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package pitch
+
+import (
+	"math/cmplx"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+// WithSpectrumAveraging enables Welch-style averaging: the frame is split into
+// sub-frames of the given length overlapping by half, each is windowed and
+// transformed, and their magnitude spectra are averaged before peak picking.
+// On noisy input the result is far steadier than analyzing a single
+// sub-frame, but a whole frame of the same length resolves a steady tone more
+// precisely, so this suits short sub-frames (quick response to pitch changes)
+// more than long windows. A length of 0 disables averaging; frames no longer
+// than a sub-frame are always analyzed whole. The averaged spectrum carries
+// no phase, so phase refinement is skipped while averaging.
+func WithSpectrumAveraging(subFrame int) Option {
+	return func(d *FFTDetector) error {
+		if subFrame < 0 {
+			return ErrInvalidSubFrame
+		}
+
+		d.subFrame = subFrame
+		return nil
+	}
+}
+
+// averages reports whether frames of the given length are split into sub-frames
+func (d *FFTDetector) averages(frameLength int) bool {
+	return d.subFrame > 0 && frameLength > d.subFrame
+}
+
+// averageSpectrum windows and transforms each half-overlapping sub-frame of
+// the buffer and returns the mean of their magnitude spectra. The scratch
+// buffers must already be sized for sub-frames.
+func (d *FFTDetector) averageSpectrum(buffer *audio.AudioBuffer) []complex128 {
+	hop := max(d.subFrame/2, 1)
+	frames := (len(buffer.Samples)-d.subFrame)/hop + 1
+
+	if len(d.averaged) != len(d.transform.spectrum) {
+		d.averaged = make([]complex128, len(d.transform.spectrum))
+	}
+	for i := range d.averaged {
+		d.averaged[i] = 0
+	}
+
+	for frame := 0; frame < frames; frame++ {
+		d.applyWindow(buffer.Samples[frame*hop : frame*hop+d.subFrame])
+		for i, value := range d.transform.transform(d.padded) {
+			d.averaged[i] += complex(cmplx.Abs(value), 0)
+		}
+	}
+
+	scale := complex(1/float64(frames), 0)
+	for i := range d.averaged {
+		d.averaged[i] *= scale
+	}
+
+	// Phase refinement can't work on magnitudes, and the transform buffer
+	// no longer holds a whole frame
+	d.hasFrame = false
+	d.hop = 0
+	return d.averaged
+}
@@ -0,0 +1,47 @@
+package pitch
+
+import (
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+func TestAveragingSteadiesNoisyFrequency(t *testing.T) {
+	// 50 frames of G3 at 10 dB SNR, read from one 2048-sample sub-frame and
+	// from the average of the seven half-overlapping ones in 8192 samples
+	const subFrame, frame, frames = 2048, 8192, 50
+	single, err := NewFFTDetector(subFrame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	averaged, err := NewFFTDetector(frame, WithSpectrumAveraging(subFrame))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var singleReadings, averagedReadings []float64
+	for seed := int64(1); seed <= frames; seed++ {
+		buffer := addNoise(audio.SynthesizeTone(196, guitarTone, frame, 44100), 10, seed)
+		last := &audio.AudioBuffer{Samples: buffer.Samples[frame-subFrame:], SampleRate: buffer.SampleRate}
+		for _, run := range []struct {
+			detector *FFTDetector
+			buffer   *audio.AudioBuffer
+			readings *[]float64
+		}{
+			{single, last, &singleReadings},
+			{averaged, buffer, &averagedReadings},
+		} {
+			note, err := run.detector.DetectPitch(run.buffer)
+			if err != nil {
+				t.Fatalf("frame %d: %v", seed, err)
+			}
+			*run.readings = append(*run.readings, note.Frequency)
+		}
+	}
+
+	singleVariance, averagedVariance := variance(singleReadings), variance(averagedReadings)
+	if averagedVariance > singleVariance/2 {
+		t.Errorf("averaging left a variance of %.4f Hz², want well under half the single sub-frame's %.4f Hz²",
+			averagedVariance, singleVariance)
+	}
+}
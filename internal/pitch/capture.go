@@ -0,0 +1,86 @@
+package pitch
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Range and floor of the captured spectrum
+const (
+	captureMinFrequency = 50.0   // Lowest frequency shown (Hz)
+	captureMaxFrequency = 5000.0 // Highest frequency shown (Hz)
+	captureFloorDB      = -80.0  // Level of bins with no energy
+)
+
+// WithSpectrumCapture makes the detector keep a downsampled copy of each
+// frame's magnitude spectrum for visualization, readable with LastSpectrum.
+// The spectrum is reduced to the given number of log-spaced bins between
+// 50 Hz and 5 kHz.
+func WithSpectrumCapture(bins int) Option {
+	return func(d *FFTDetector) error {
+		if bins <= 0 {
+			return ErrInvalidSpectrumBins
+		}
+
+		d.captured = make([]float32, bins)
+		for i := range d.captured {
+			d.captured[i] = captureFloorDB
+		}
+		return nil
+	}
+}
+
+// LastSpectrum returns a copy of the spectrum captured from the last frame,
+// in dB relative to its strongest bin (0 dB down to -80 dB), lowest frequency
+// first. It returns nil when spectrum capture is disabled.
+func (d *FFTDetector) LastSpectrum() []float32 {
+	if d.captured == nil {
+		return nil
+	}
+
+	spectrum := make([]float32, len(d.captured))
+	copy(spectrum, d.captured)
+	return spectrum
+}
+
+// captureSpectrum reduces a one-sided spectrum to the capture bins, keeping
+// the strongest FFT bin within each log-spaced band
+func (d *FFTDetector) captureSpectrum(spectrum []complex128, sampleRate int) {
+	binSizeHz := spectrumBinSize(spectrum, sampleRate)
+	ratio := captureMaxFrequency / captureMinFrequency
+	bins := len(d.captured)
+
+	strongest := 0.0
+	for i := range d.captured {
+		low := captureMinFrequency * math.Pow(ratio, float64(i)/float64(bins))
+		high := captureMinFrequency * math.Pow(ratio, float64(i+1)/float64(bins))
+
+		// Bands narrower than an FFT bin still take the nearest bin
+		first := int(math.Round(low / binSizeHz))
+		last := int(math.Round(high/binSizeHz)) - 1
+		if last < first {
+			last = first
+		}
+		if last >= len(spectrum) {
+			last = len(spectrum) - 1
+		}
+
+		magnitude := 0.0
+		for bin := first; bin <= last; bin++ {
+			magnitude = math.Max(magnitude, cmplx.Abs(spectrum[bin]))
+		}
+		strongest = math.Max(strongest, magnitude)
+
+		// Store the raw magnitude for now; converted to dB below
+		d.captured[i] = float32(magnitude)
+	}
+
+	// Convert to dB relative to the strongest band
+	for i, magnitude := range d.captured {
+		level := captureFloorDB
+		if strongest > 0 && magnitude > 0 {
+			level = math.Max(20*math.Log10(float64(magnitude)/strongest), captureFloorDB)
+		}
+		d.captured[i] = float32(level)
+	}
+}
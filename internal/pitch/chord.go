@@ -0,0 +1,163 @@
+package pitch
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+// Polyphonic detection settings
+const (
+	chordHarmonics     = 6   // Harmonics summed into a candidate's salience
+	chordSalienceRatio = 0.2 // Candidates weaker than this fraction of the strongest are ignored
+)
+
+// PolyDetector defines the interface for polyphonic pitch detection
+type PolyDetector interface {
+	// DetectChord analyzes an audio buffer and returns the simultaneous notes,
+	// strongest first
+	DetectChord(buffer *audio.AudioBuffer) ([]*Note, error)
+}
+
+// SetMaxChordNotes sets the maximum number of simultaneous notes DetectChord reports
+func (d *FFTDetector) SetMaxChordNotes(notes int) {
+	// Ensure at least one note can be reported
+	if notes < 1 {
+		notes = 1
+	}
+
+	d.maxChordNotes = notes
+}
+
+// DetectChord analyzes an audio buffer and returns up to maxChordNotes
+// simultaneous notes sorted by salience
+func (d *FFTDetector) DetectChord(buffer *audio.AudioBuffer) ([]*Note, error) {
+	spectrum, err := d.Spectrum(buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.DetectChordSpectrum(spectrum, buffer.SampleRate)
+}
+
+// DetectChordSpectrum is like DetectChord, but works on a spectrum previously
+// returned by Spectrum. It uses iterative spectral peak picking: take the
+// strongest fundamental candidate, remove its harmonic series from the
+// spectrum, and repeat.
+func (d *FFTDetector) DetectChordSpectrum(spectrum []complex128, sampleRate int) ([]*Note, error) {
+	// Work on a copy of the magnitude spectrum so harmonics can be removed
+	magnitudes := make([]float64, len(spectrum))
+	for i := range magnitudes {
+		magnitudes[i] = cmplx.Abs(spectrum[i])
+	}
+
+	binSizeHz := spectrumBinSize(spectrum, sampleRate)
+	minBin := int(d.minFrequency / binSizeHz)
+	if minBin < 1 {
+		minBin = 1
+	}
+	maxBin := int(d.maxFrequency / binSizeHz)
+	if maxBin >= len(magnitudes)-1 {
+		maxBin = len(magnitudes) - 2
+	}
+
+	// Harmonics are searched within the Hann main lobe (two unpadded bins)
+	lobeBins := 2 * d.paddingFactor
+
+	// Candidates must stand out from the noise
+	threshold := d.updateNoiseEstimate(spectrum, minBin, maxBin)
+
+	var notes []*Note
+	strongest := 0.0
+	for len(notes) < d.maxChordNotes {
+		// Find the candidate peak with the highest harmonic salience
+		bestBin := -1
+		bestSalience := 0.0
+		for i := minBin + 1; i < maxBin; i++ {
+			if magnitudes[i] < threshold ||
+				magnitudes[i] <= magnitudes[i-1] || magnitudes[i] <= magnitudes[i+1] {
+				continue
+			}
+
+			salience := harmonicSalience(magnitudes, float64(i), lobeBins)
+			if salience > bestSalience {
+				bestBin = i
+				bestSalience = salience
+			}
+		}
+
+		if bestBin < 0 {
+			break
+		}
+		if len(notes) == 0 {
+			strongest = bestSalience
+		} else if bestSalience < strongest*chordSalienceRatio {
+			break
+		}
+
+		// Refine the candidate with quadratic interpolation
+		prev, current, next := magnitudes[bestBin-1], magnitudes[bestBin], magnitudes[bestBin+1]
+		position := float64(bestBin) + parabolicOffset(prev, current, next)
+
+		note := d.converter.FromFrequency(position * binSizeHz)
+		if note != nil {
+			note.Confidence = bestSalience / strongest
+			notes = append(notes, note)
+		}
+
+		// Remove the candidate's harmonic series so its overtones don't
+		// show up as separate notes
+		suppressHarmonics(magnitudes, position, lobeBins)
+	}
+
+	if len(notes) == 0 {
+		return nil, ErrNoPitchDetected
+	}
+
+	// Sort notes by salience (descending)
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].Confidence > notes[j].Confidence
+	})
+
+	return notes, nil
+}
+
+// harmonicSalience sums the magnitudes found near the first chordHarmonics
+// multiples of a fundamental bin, weighting higher harmonics less
+func harmonicSalience(magnitudes []float64, fundamentalBin float64, lobeBins int) float64 {
+	salience := 0.0
+	for h := 1; h <= chordHarmonics; h++ {
+		center := int(math.Round(fundamentalBin * float64(h)))
+		if center >= len(magnitudes) {
+			break
+		}
+
+		// Take the strongest bin near the expected harmonic position
+		peak := 0.0
+		for i := center - lobeBins/2; i <= center+lobeBins/2; i++ {
+			if i >= 0 && i < len(magnitudes) && magnitudes[i] > peak {
+				peak = magnitudes[i]
+			}
+		}
+		salience += peak / float64(h)
+	}
+	return salience
+}
+
+// suppressHarmonics zeroes the bins around every harmonic of a fundamental bin
+func suppressHarmonics(magnitudes []float64, fundamentalBin float64, lobeBins int) {
+	for h := 1; h <= chordHarmonics; h++ {
+		center := int(math.Round(fundamentalBin * float64(h)))
+		if center >= len(magnitudes) {
+			break
+		}
+
+		for i := center - lobeBins; i <= center+lobeBins; i++ {
+			if i >= 0 && i < len(magnitudes) {
+				magnitudes[i] = 0
+			}
+		}
+	}
+}
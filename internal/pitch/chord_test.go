@@ -0,0 +1,66 @@
+package pitch
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+var _ PolyDetector = (*FFTDetector)(nil)
+
+// mix sums tones of the given frequencies, each with the same overtones,
+// into one buffer
+func mix(frequencies []float64, overtones []float64, samples, sampleRate int) *audio.AudioBuffer {
+	buffer := &audio.AudioBuffer{Samples: make([]float32, samples), SampleRate: sampleRate}
+	for _, frequency := range frequencies {
+		tone := audio.SynthesizeTone(frequency, overtones, samples, sampleRate)
+		for i, sample := range tone.Samples {
+			buffer.Samples[i] += sample / float32(len(frequencies))
+		}
+	}
+	return buffer
+}
+
+func TestDetectChordRecoversPitchClasses(t *testing.T) {
+	tests := []struct {
+		name        string
+		frequencies []float64
+		want        []string
+	}{
+		{"C4+E4+G4", []float64{261.63, 329.63, 392}, []string{"C", "E", "G"}},
+		{"A3+C#4+E4", []float64{220, 277.18, 329.63}, []string{"A", "C#", "E"}},
+		{"E2+B2", []float64{82.41, 123.47}, []string{"E", "B"}},
+		{"D3+F#3", []float64{146.83, 185}, []string{"D", "F#"}},
+	}
+	for _, overtones := range [][]float64{{1}, guitarTone} {
+		for _, sampleRate := range []int{44100, 48000} {
+			for _, tt := range tests {
+				detector, err := NewFFTDetector(8192)
+				if err != nil {
+					t.Fatal(err)
+				}
+				detector.SetPaddingFactor(benchPadding)
+				notes, err := detector.DetectChord(mix(tt.frequencies, overtones, 8192, sampleRate))
+				if err != nil {
+					t.Errorf("%s at %d Hz: %v", tt.name, sampleRate, err)
+					continue
+				}
+
+				var got []string
+				for _, note := range notes {
+					if !slices.Contains(got, note.Name) {
+						got = append(got, note.Name)
+					}
+				}
+				for _, name := range tt.want {
+					if !slices.Contains(got, name) {
+						t.Errorf("%s at %d Hz with %d overtones: found %s, missing %s",
+							tt.name, sampleRate, len(overtones), strings.Join(got, " "), name)
+					}
+				}
+			}
+		}
+	}
+}
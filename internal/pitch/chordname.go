@@ -0,0 +1,180 @@
+package pitch
+
+import (
+	"slices"
+	"strings"
+)
+
+// ChordQuality is a chord type, described by its intervals above the root
+type ChordQuality struct {
+	Name      string // e.g. "minor seventh"
+	Suffix    string // Appended to the root in chord symbols, e.g. "m7"
+	Intervals []int  // Semitones above the root, root (0) first, in stacking order
+}
+
+// Built-in chord qualities, in order of preference when a set of notes
+// matches several of them equally well
+var ChordQualities = []ChordQuality{
+	{Name: "major", Suffix: "", Intervals: []int{0, 4, 7}},
+	{Name: "minor", Suffix: "m", Intervals: []int{0, 3, 7}},
+	{Name: "diminished", Suffix: "dim", Intervals: []int{0, 3, 6}},
+	{Name: "augmented", Suffix: "aug", Intervals: []int{0, 4, 8}},
+	{Name: "suspended fourth", Suffix: "sus4", Intervals: []int{0, 5, 7}},
+	{Name: "suspended second", Suffix: "sus2", Intervals: []int{0, 2, 7}},
+	{Name: "dominant seventh", Suffix: "7", Intervals: []int{0, 4, 7, 10}},
+	{Name: "major seventh", Suffix: "maj7", Intervals: []int{0, 4, 7, 11}},
+	{Name: "minor seventh", Suffix: "m7", Intervals: []int{0, 3, 7, 10}},
+	{Name: "minor major seventh", Suffix: "mMaj7", Intervals: []int{0, 3, 7, 11}},
+	{Name: "half-diminished seventh", Suffix: "m7b5", Intervals: []int{0, 3, 6, 10}},
+	{Name: "diminished seventh", Suffix: "dim7", Intervals: []int{0, 3, 6, 9}},
+	{Name: "augmented seventh", Suffix: "aug7", Intervals: []int{0, 4, 8, 10}},
+	{Name: "dominant seventh suspended fourth", Suffix: "7sus4", Intervals: []int{0, 5, 7, 10}},
+}
+
+// Chord is an identified chord
+type Chord struct {
+	Root      int          // Pitch class of the root (0 = C)
+	RootName  string       // e.g. "Eb"
+	Bass      int          // Pitch class of the lowest note
+	BassName  string       // e.g. "G"
+	Quality   ChordQuality // Chord type
+	Inversion int          // 0 in root position, 1 with the third in the bass, 2 with the fifth, 3 with the seventh
+	Symbol    string       // e.g. "Ebmaj7" or "Am/C" for inversions
+}
+
+// ChordIdentifier names chords from the pitch classes of simultaneous notes
+type ChordIdentifier struct {
+	qualities []ChordQuality
+}
+
+// NewChordIdentifier creates a chord identifier for the built-in qualities
+func NewChordIdentifier() *ChordIdentifier {
+	return &ChordIdentifier{
+		qualities: ChordQualities,
+	}
+}
+
+// Identify returns the chord formed by the notes. The notes' written pitch
+// classes must match a chord quality exactly; octave doublings are ignored.
+// It reports false for fewer than three distinct pitch classes, which don't
+// define a chord unambiguously, and for sets that match no quality.
+func (c *ChordIdentifier) Identify(notes []*Note) (Chord, bool) {
+	// Collect the distinct pitch classes and find the lowest note
+	var pitchClasses []int
+	var lowest *Note
+	for _, note := range notes {
+		if note == nil {
+			continue
+		}
+		if !slices.Contains(pitchClasses, note.PitchClass) {
+			pitchClasses = append(pitchClasses, note.PitchClass)
+		}
+		if lowest == nil || note.Frequency < lowest.Frequency {
+			lowest = note
+		}
+	}
+	if len(pitchClasses) < 3 {
+		return Chord{}, false
+	}
+
+	best := Chord{}
+	bestScore := -1
+	for root := range 12 {
+		if !slices.Contains(pitchClasses, root) {
+			continue
+		}
+
+		for rank, quality := range c.qualities {
+			if !matchesQuality(pitchClasses, root, quality) {
+				continue
+			}
+
+			// Prefer root position, then the root spelled with fewer
+			// accidentals, then the quality listed first
+			rootName := chordRootName(root, quality)
+			score := (len(c.qualities) - rank)
+			score += 100 * (2 - strings.Count(rootName, "b") - strings.Count(rootName, "#"))
+			if root == lowest.PitchClass {
+				score += 1000
+			}
+			if score <= bestScore {
+				continue
+			}
+
+			bestScore = score
+			best = Chord{
+				Root:      root,
+				RootName:  rootName,
+				Bass:      lowest.PitchClass,
+				Quality:   quality,
+				Inversion: slices.Index(quality.Intervals, (lowest.PitchClass-root+12)%12),
+			}
+		}
+	}
+	if bestScore < 0 {
+		return Chord{}, false
+	}
+
+	best.BassName = spellChordTone(best.RootName, (best.Bass-best.Root+12)%12)
+
+	best.Symbol = best.RootName + best.Quality.Suffix
+	if best.Inversion > 0 {
+		best.Symbol += "/" + best.BassName
+	}
+	return best, true
+}
+
+// matchesQuality reports whether the pitch classes are exactly the chord
+// quality built on the root
+func matchesQuality(pitchClasses []int, root int, quality ChordQuality) bool {
+	if len(pitchClasses) != len(quality.Intervals) {
+		return false
+	}
+
+	for _, interval := range quality.Intervals {
+		if !slices.Contains(pitchClasses, (root+interval)%12) {
+			return false
+		}
+	}
+	return true
+}
+
+// Letter steps above the root for each chord interval: seconds, thirds,
+// fourths, fifths and sevenths
+var chordToneSteps = map[int]int{0: 0, 2: 1, 3: 2, 4: 2, 5: 3, 6: 4, 7: 4, 8: 4, 9: 6, 10: 6, 11: 6}
+
+// Natural letters and their pitch classes
+var (
+	naturalLetters      = []string{"C", "D", "E", "F", "G", "A", "B"}
+	naturalPitchClasses = []int{0, 2, 4, 5, 7, 9, 11}
+)
+
+// spellChordTone spells the chord tone the given interval above the root, so
+// that e.g. the fifth of Edim is Bb rather than A#
+func spellChordTone(rootName string, interval int) string {
+	rootLetter := slices.Index(naturalLetters, rootName[:1])
+	rootPitchClass := naturalPitchClasses[rootLetter]
+	rootPitchClass += strings.Count(rootName, "#") - strings.Count(rootName, "b")
+
+	letter := (rootLetter + chordToneSteps[interval]) % 7
+	target := rootPitchClass + interval
+
+	// Express the difference from the natural letter as accidentals
+	offset := ((target-naturalPitchClasses[letter])%12+18)%12 - 6
+	switch {
+	case offset > 0:
+		return naturalLetters[letter] + strings.Repeat("#", offset)
+	case offset < 0:
+		return naturalLetters[letter] + strings.Repeat("b", -offset)
+	}
+	return naturalLetters[letter]
+}
+
+// chordRootName spells a chord root the way its key would: minor-type chords
+// like a minor key, everything else like a major key
+func chordRootName(root int, quality ChordQuality) string {
+	if slices.Contains(quality.Intervals, 3) {
+		return minorKeyNames[root]
+	}
+	return majorKeyNames[root]
+}
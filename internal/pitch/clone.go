@@ -0,0 +1,30 @@
+package pitch
+
+// Clone returns a detector with the same configuration and its own scratch
+// buffers, so each goroutine (e.g. one per input channel) can detect
+// independently. Per-stream state such as the noise estimate and the previous
+// frame starts afresh. The window coefficients and note converter are only
+// read during detection and are shared with the original.
+func (d *FFTDetector) Clone() *FFTDetector {
+	clone := *d
+
+	clone.noiseEstimate = 0
+	clone.noiseScratch = nil
+	clone.peaks = nil
+	clone.averaged = nil
+	clone.hasFrame = false
+	clone.hop = 0
+
+	// Force fresh transform buffers; the window is kept since it fits
+	clone.padded = nil
+	clone.previous = nil
+	clone.prepareBuffers(clone.windowSize)
+
+	if d.captured != nil {
+		clone.captured = make([]float32, len(d.captured))
+		for i := range clone.captured {
+			clone.captured[i] = captureFloorDB
+		}
+	}
+	return &clone
+}
@@ -0,0 +1,49 @@
+package pitch
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+// TestCloneConcurrentDetection detects two tones at once on clones of one
+// detector. Run with -race: clones sharing scratch space would both race and
+// mix up their results.
+func TestCloneConcurrentDetection(t *testing.T) {
+	detector, err := NewFFTDetector(4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tones := []struct {
+		frequency float64
+		want      string
+	}{
+		{110, "A2"},
+		{329.63, "E4"},
+	}
+
+	var wg sync.WaitGroup
+	for _, tone := range tones {
+		clone := detector.Clone()
+		buffer := audio.SynthesizeTone(tone.frequency, guitarTone, 4096, 44100)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				note, err := clone.DetectPitch(buffer)
+				if err != nil {
+					t.Errorf("detecting %s: %v", tone.want, err)
+					return
+				}
+				if got := fmt.Sprintf("%s%d", note.Name, note.Octave); got != tone.want {
+					t.Errorf("detected %s while %s was playing", got, tone.want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,182 @@
+package pitch
+
+import "math"
+
+// Reference pitch limits (Hz)
+const (
+	DefaultReferenceA4 = 440.0
+	MinReferenceA4     = 400.0
+	MaxReferenceA4     = 480.0
+)
+
+// Capo offset limits (semitones)
+const (
+	MinCapoOffset = -12
+	MaxCapoOffset = 12
+)
+
+// Range of frequencies FromFrequency accepts (Hz), well beyond hearing at
+// either end; outside it note names and octaves stop being meaningful
+const (
+	MinNoteFrequency = 1.0
+	MaxNoteFrequency = 100000.0
+)
+
+// Transposition describes a transposing instrument: the number of semitones
+// its written pitch lies above the sounding (concert) pitch
+type Transposition struct {
+	Name      string
+	Semitones int
+}
+
+// Transposition presets
+var (
+	TranspositionConcert = Transposition{Name: "Concert", Semitones: 0}
+	TranspositionBb      = Transposition{Name: "B♭", Semitones: 2} // Trumpet, clarinet: written C sounds B♭
+	TranspositionEb      = Transposition{Name: "E♭", Semitones: 9} // Alto sax: written C sounds E♭ a sixth below
+	TranspositionF       = Transposition{Name: "F", Semitones: 7}  // Horn: written C sounds F a fifth below
+)
+
+// Transpositions lists the presets in the order the UI cycles through them
+var Transpositions = []Transposition{
+	TranspositionConcert,
+	TranspositionBb,
+	TranspositionEb,
+	TranspositionF,
+}
+
+// NoteConverter converts frequencies to musical notes relative to a
+// configurable A4 reference pitch
+type NoteConverter struct {
+	A4            float64       // Reference frequency for A4 in Hz
+	Transposition Transposition // Written pitch offset for transposing instruments
+	CapoOffset    int           // Further semitones added to the written pitch, e.g. -3 for a capo on the 3rd fret
+
+	Spelling     Spelling     // How accidentals are named
+	KeySignature int          // Sharps (positive) or flats (negative) used by SpellingAuto and movable do
+	Naming       NamingScheme // Letters or solfège syllables
+
+	Temperament Temperament // Tuning system cents deviations are measured against
+	Tonic       int         // Pitch class (0 = C) the temperament is built on
+}
+
+// NewNoteConverter creates a new note converter tuned to A4 = 440Hz
+func NewNoteConverter() *NoteConverter {
+	return &NoteConverter{
+		A4:            DefaultReferenceA4,
+		Transposition: TranspositionConcert,
+		Spelling:      SpellingSharps,
+		Naming:        NamingLetters,
+		Temperament:   TemperamentEqual,
+	}
+}
+
+// SetTransposition sets the transposition applied to displayed note names
+func (c *NoteConverter) SetTransposition(transposition Transposition) {
+	c.Transposition = transposition
+}
+
+// SetCapoOffset sets the semitones added to the displayed (written) note on
+// top of the transposition, so with a capo on the 3rd fret (-3) a sounding
+// E♭ is named by its C shape. The sounding note stays in the Concert fields.
+func (c *NoteConverter) SetCapoOffset(semitones int) {
+	c.CapoOffset = max(MinCapoOffset, min(semitones, MaxCapoOffset))
+}
+
+// SetReferenceA4 sets the A4 reference pitch in Hz
+func (c *NoteConverter) SetReferenceA4(hz float64) {
+	// Keep the reference within a sane calibration range
+	if hz < MinReferenceA4 {
+		hz = MinReferenceA4
+	}
+	if hz > MaxReferenceA4 {
+		hz = MaxReferenceA4
+	}
+
+	c.A4 = hz
+}
+
+// FromFrequency converts a frequency to a musical note. It returns nil for
+// frequencies that are not finite or lie outside MinNoteFrequency to
+// MaxNoteFrequency, such as the result of a failed interpolation.
+func (c *NoteConverter) FromFrequency(frequency float64) *Note {
+	// The negated comparison also rejects NaN
+	if !(frequency >= MinNoteFrequency && frequency <= MaxNoteFrequency) {
+		return nil
+	}
+
+	// Calculate semitones from A4
+	semitones := 12 * math.Log2(frequency/c.A4)
+
+	// Find the nearest note of the temperament and the cents deviation from it
+	roundedSemitones, cents := c.nearestDegree(semitones)
+	degree := 0
+	if len(c.Temperament.Degrees) > 0 {
+		roundedSemitones, cents, degree = c.nearestScaleDegree(frequency)
+	}
+
+	// Sounding note, then the written note for transposing instruments
+	concertPitchClass, concertOctave := noteFromSemitones(roundedSemitones)
+	pitchClass, octave := noteFromSemitones(roundedSemitones + float64(c.Transposition.Semitones+c.CapoOffset))
+
+	return &Note{
+		Name:              c.noteName(pitchClass),
+		Octave:            octave,
+		PitchClass:        pitchClass,
+		ConcertName:       c.noteName(concertPitchClass),
+		ConcertOctave:     concertOctave,
+		ConcertPitchClass: concertPitchClass,
+		MIDINote:          midiNoteA4 + int(roundedSemitones),
+		Frequency:         frequency,
+		Cents:             cents,
+		RawCents:          cents,
+		Degree:            degree,
+	}
+}
+
+// Sounding returns a copy of the note named by its sounding (concert) pitch
+// rather than the written one
+func (n Note) Sounding() Note {
+	n.Name = n.ConcertName
+	n.Octave = n.ConcertOctave
+	n.PitchClass = n.ConcertPitchClass
+	return n
+}
+
+// Neighbor returns the note a number of semitones away, e.g. 1 for the next
+// note up, with its octave carried across B and C and its cents measured
+// from it. It's named with sharps; Rename names it in another scheme.
+func (n Note) Neighbor(semitones int) Note {
+	n.PitchClass, n.Octave = shiftPitchClass(n.PitchClass, n.Octave, semitones)
+	n.ConcertPitchClass, n.ConcertOctave = shiftPitchClass(n.ConcertPitchClass, n.ConcertOctave, semitones)
+	n.Name = PitchClassName(n.PitchClass)
+	n.ConcertName = PitchClassName(n.ConcertPitchClass)
+	n.MIDINote += semitones
+	n.Cents -= float64(100 * semitones)
+	n.RawCents -= float64(100 * semitones)
+	return n
+}
+
+// shiftPitchClass returns the pitch class (0 = C) and octave a number of
+// semitones from the given ones
+func shiftPitchClass(pitchClass, octave, semitones int) (int, int) {
+	index := octave*12 + pitchClass + semitones
+	shifted := (index%12 + 12) % 12
+	return shifted, (index - shifted) / 12
+}
+
+// noteFromSemitones returns the pitch class (0 = C) and octave of the note a
+// whole number of semitones away from A4
+func noteFromSemitones(semitones float64) (int, int) {
+	// Calculate note index (0 = C, 1 = C#, etc.)
+	// A4 is 9 semitones above C4, so we add 9 to the semitone count
+	noteIndex := int(math.Mod(semitones+9, 12))
+	if noteIndex < 0 {
+		noteIndex += 12
+	}
+
+	// Calculate octave (A4 is in octave 4)
+	octave := 4 + int(math.Floor((semitones+9)/12))
+
+	return noteIndex, octave
+}
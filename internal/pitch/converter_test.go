@@ -0,0 +1,277 @@
+package pitch
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+// checkNote fails the test unless a converted note holds together: one of
+// the twelve names, cents within half a semitone, an octave in the range
+// the valid frequencies span and a MIDI number matching its name and octave
+func checkNote(t *testing.T, frequency float64, note *Note) {
+	t.Helper()
+	if note == nil {
+		t.Fatalf("FromFrequency(%v) = nil for a valid frequency", frequency)
+	}
+	if !slices.Contains(noteNames, note.Name) {
+		t.Errorf("FromFrequency(%v) named the note %q", frequency, note.Name)
+	}
+	if math.IsNaN(note.Cents) || note.Cents < -50 || note.Cents > 50 {
+		t.Errorf("FromFrequency(%v) is %v cents off", frequency, note.Cents)
+	}
+	if note.Octave < -5 || note.Octave > 12 {
+		t.Errorf("FromFrequency(%v) is in octave %d", frequency, note.Octave)
+	}
+	if want := (note.Octave+1)*12 + note.PitchClass; note.MIDINote != want || noteNames[note.PitchClass] != note.Name {
+		t.Errorf("FromFrequency(%v) = %s%d with MIDI note %d, want %d", frequency, note.Name, note.Octave, note.MIDINote, want)
+	}
+}
+
+func TestFromFrequencyInvariants(t *testing.T) {
+	converter := NewNoteConverter()
+	for _, frequency := range []float64{
+		MinNoteFrequency, 8.18, 27.5, 80, 82.41, 261.63, 440, 466.16, 1200, 4186.01, 20000, MaxNoteFrequency,
+	} {
+		checkNote(t, frequency, converter.FromFrequency(frequency))
+	}
+}
+
+func TestFromFrequencyKnownNotes(t *testing.T) {
+	converter := NewNoteConverter()
+	tests := []struct {
+		frequency float64
+		name      string
+		octave    int
+		midi      int
+	}{
+		{440, "A", 4, 69},
+		{261.63, "C", 4, 60},
+		{82.41, "E", 2, 40},
+		{1318.51, "E", 6, 88},
+		{452.89, "A", 4, 69}, // 49.9 cents sharp still rounds down
+	}
+	for _, tt := range tests {
+		note := converter.FromFrequency(tt.frequency)
+		if note == nil || note.Name != tt.name || note.Octave != tt.octave || note.MIDINote != tt.midi {
+			t.Errorf("FromFrequency(%v) = %+v, want %s%d (MIDI %d)", tt.frequency, note, tt.name, tt.octave, tt.midi)
+		}
+	}
+}
+
+func TestFromFrequencyRejectsInvalid(t *testing.T) {
+	converter := NewNoteConverter()
+	for _, frequency := range []float64{
+		0, -440, math.NaN(), math.Inf(1), math.Inf(-1), math.SmallestNonzeroFloat64, MinNoteFrequency / 2, MaxNoteFrequency * 2,
+	} {
+		if note := converter.FromFrequency(frequency); note != nil {
+			t.Errorf("FromFrequency(%v) = %s%d, want nil", frequency, note.Name, note.Octave)
+		}
+	}
+}
+
+func FuzzFrequencyToNote(f *testing.F) {
+	for _, frequency := range []float64{440, 0, -1, 1, 82.41, 1200, 99999.9, math.NaN(), math.Inf(1)} {
+		f.Add(frequency)
+	}
+
+	converter := NewNoteConverter()
+	f.Fuzz(func(t *testing.T, frequency float64) {
+		note := converter.FromFrequency(frequency)
+		if !(frequency >= MinNoteFrequency && frequency <= MaxNoteFrequency) {
+			if note != nil {
+				t.Fatalf("FromFrequency(%v) = %s%d, want nil outside the valid range", frequency, note.Name, note.Octave)
+			}
+			return
+		}
+		checkNote(t, frequency, note)
+	})
+}
+
+func TestCapoOffsetKeepsSoundingNote(t *testing.T) {
+	converter := NewNoteConverter()
+	converter.SetSpelling(SpellingFlats)
+	converter.SetCapoOffset(-3)
+
+	note := converter.FromFrequency(311.13) // E♭4
+	if note.Name != "C" || note.Octave != 4 {
+		t.Errorf("shown as %s%d, want C4", note.Name, note.Octave)
+	}
+	if note.ConcertName != "Eb" || note.ConcertOctave != 4 || note.MIDINote != 63 {
+		t.Errorf("sounding %s%d (MIDI %d), want Eb4 (MIDI 63)", note.ConcertName, note.ConcertOctave, note.MIDINote)
+	}
+}
+
+func TestTranspositionWrittenNote(t *testing.T) {
+	// Concert B♭4 is a written C5 on a B♭ trumpet, a tone below its written
+	// note. On an F horn, sounding a fifth below written, it is a written F5:
+	// the request's "G5" would sound C5, not B♭4.
+	tests := []struct {
+		transposition Transposition
+		name          string
+		octave        int
+	}{
+		{TranspositionConcert, "A#", 4},
+		{TranspositionBb, "C", 5},
+		{TranspositionF, "F", 5},
+	}
+	for _, tt := range tests {
+		converter := NewNoteConverter()
+		converter.SetTransposition(tt.transposition)
+		note := converter.FromFrequency(466.16)
+		if note.Name != tt.name || note.Octave != tt.octave {
+			t.Errorf("%s: written %s%d, want %s%d", tt.transposition.Name, note.Name, note.Octave, tt.name, tt.octave)
+		}
+		if note.ConcertName != "A#" || note.ConcertOctave != 4 || note.MIDINote != 70 {
+			t.Errorf("%s: sounding %s%d (MIDI %d), want A#4 (MIDI 70)", tt.transposition.Name, note.ConcertName, note.ConcertOctave, note.MIDINote)
+		}
+	}
+}
+
+func TestReferenceA4Cents(t *testing.T) {
+	// 1200·log2(442/440) ≈ 7.85: the same string is in tune for an
+	// orchestra at 442 and reads sharp against the 440 standard
+	tests := []struct {
+		reference float64
+		cents     float64
+	}{
+		{442, 0},
+		{440, 7.85},
+	}
+	for _, tt := range tests {
+		converter := NewNoteConverter()
+		converter.SetReferenceA4(tt.reference)
+		note := converter.FromFrequency(442)
+		if got := fmt.Sprintf("%s%d", note.Name, note.Octave); got != "A4" {
+			t.Errorf("A4 = %v Hz: 442 Hz read as %s, want A4", tt.reference, got)
+		}
+		if math.Abs(note.Cents-tt.cents) > 0.01 {
+			t.Errorf("A4 = %v Hz: 442 Hz is %+.2f cents, want %+.2f", tt.reference, note.Cents, tt.cents)
+		}
+	}
+}
+
+func TestSpellingAccidentals(t *testing.T) {
+	// Octave 4 from C#4 up to B4; naturals are named alike in every mode
+	tests := []struct {
+		frequency   float64
+		sharp, flat string
+	}{
+		{277.18, "C#", "Db"},
+		{293.66, "D", "D"},
+		{311.13, "D#", "Eb"},
+		{369.99, "F#", "Gb"},
+		{415.30, "G#", "Ab"},
+		{466.16, "A#", "Bb"},
+		{493.88, "B", "B"},
+	}
+	modes := []struct {
+		spelling     Spelling
+		keySignature int
+		flats        bool
+	}{
+		{SpellingSharps, 0, false},
+		{SpellingSharps, -3, false}, // The key only matters to Auto
+		{SpellingFlats, 0, true},
+		{SpellingFlats, 2, true},
+		{SpellingAuto, 0, false}, // C major
+		{SpellingAuto, 2, false}, // D major
+		{SpellingAuto, -1, true}, // F major
+		{SpellingAuto, -3, true}, // E♭ major
+	}
+	for _, mode := range modes {
+		converter := NewNoteConverter()
+		converter.SetSpelling(mode.spelling)
+		converter.SetKeySignature(mode.keySignature)
+		for _, tt := range tests {
+			want := tt.sharp
+			if mode.flats {
+				want = tt.flat
+			}
+			note := converter.FromFrequency(tt.frequency)
+			if note.Name != want || note.Octave != 4 {
+				t.Errorf("%v in %s: %v Hz named %s%d, want %s4",
+					mode.spelling, KeySignatureName(mode.keySignature), tt.frequency, note.Name, note.Octave, want)
+			}
+		}
+	}
+}
+
+func TestTemperamentMajorThird(t *testing.T) {
+	// Thirds above each tonic, which temperament equal-tempers from A4
+	tests := []struct {
+		tonic       int     // Pitch class
+		ratio       float64 // Of the third to the tonic
+		name        string
+		temperament Temperament
+		cents       float64
+	}{
+		{0, 5.0 / 4, "E", TemperamentJustMajor, 0},
+		{0, 5.0 / 4, "E", TemperamentEqual, -13.69},
+		{0, 5.0 / 4, "E", TemperamentQuarterCommaMeantone, 0}, // Meantone keeps its thirds pure
+		{0, 5.0 / 4, "E", TemperamentPythagorean, -21.51},     // A syntonic comma under the ditone
+		{7, 5.0 / 4, "B", TemperamentJustMajor, 0},
+		{7, 5.0 / 4, "B", TemperamentEqual, -13.69},
+		{2, 81.0 / 64, "F#", TemperamentPythagorean, 0},
+		{2, 81.0 / 64, "F#", TemperamentEqual, 7.82},
+	}
+	for _, tt := range tests {
+		converter := NewNoteConverter()
+		converter.SetTemperament(tt.temperament)
+		converter.SetTonic(tt.tonic)
+		tonic := 440 * math.Pow(2, float64(tt.tonic-9)/12)
+		note := converter.FromFrequency(tonic * tt.ratio)
+		if note.Name != tt.name || note.Octave != 4 {
+			t.Errorf("%s on %s: third named %s%d, want %s4", tt.temperament.Name, noteNames[tt.tonic], note.Name, note.Octave, tt.name)
+		}
+		if math.Abs(note.Cents-tt.cents) > 0.01 {
+			t.Errorf("%s on %s: %v third reads %+.2f cents, want %+.2f",
+				tt.temperament.Name, noteNames[tt.tonic], tt.ratio, note.Cents, tt.cents)
+		}
+	}
+}
+
+func TestNoteNeighbor(t *testing.T) {
+	// Leaning 45 cents across B3 and C4 in each direction, and across the
+	// bottom of octave 0
+	tests := []struct {
+		frequency float64
+		semitones int
+		name      string
+		octave    int
+		midi      int
+		cents     float64
+	}{
+		{246.94 * math.Pow(2, 45.0/1200), 1, "C", 4, 60, -55},
+		{261.63 * math.Pow(2, -45.0/1200), -1, "B", 3, 59, 55},
+		{261.63, -1, "B", 3, 59, 100},
+		{246.94, 1, "C", 4, 60, -100},
+		{246.94, 2, "C#", 4, 61, -200},
+		{261.63, -13, "B", 2, 47, 1300},
+		{16.3516, -1, "B", -1, 11, 100},
+	}
+	converter := NewNoteConverter()
+	for _, tt := range tests {
+		note := converter.FromFrequency(tt.frequency)
+		neighbor := note.Neighbor(tt.semitones)
+		if neighbor.Name != tt.name || neighbor.Octave != tt.octave || neighbor.MIDINote != tt.midi {
+			t.Errorf("%s%d.Neighbor(%d) = %s%d (MIDI %d), want %s%d (MIDI %d)", note.Name, note.Octave, tt.semitones,
+				neighbor.Name, neighbor.Octave, neighbor.MIDINote, tt.name, tt.octave, tt.midi)
+		}
+		if math.Abs(neighbor.Cents-tt.cents) > 0.1 || neighbor.Cents != note.Cents-float64(100*tt.semitones) {
+			t.Errorf("%s%d.Neighbor(%d) is %v cents off, want %v", note.Name, note.Octave, tt.semitones, neighbor.Cents, tt.cents)
+		}
+		if neighbor.Frequency != note.Frequency {
+			t.Errorf("%s%d.Neighbor(%d) moved the frequency to %v", note.Name, note.Octave, tt.semitones, neighbor.Frequency)
+		}
+	}
+
+	// A B♭ instrument's written note moves with the sounding one
+	converter.SetTransposition(TranspositionBb)
+	neighbor := converter.FromFrequency(246.94).Neighbor(1)
+	if neighbor.Name != "D" || neighbor.Octave != 4 || neighbor.ConcertName != "C" || neighbor.ConcertOctave != 4 {
+		t.Errorf("B♭ instrument's B3 up a semitone is written %s%d sounding %s%d, want D4 sounding C4",
+			neighbor.Name, neighbor.Octave, neighbor.ConcertName, neighbor.ConcertOctave)
+	}
+}
@@ -9,16 +9,47 @@ import (
 
 // Errors
 var (
-	ErrEmptyBuffer     = errors.New("empty audio buffer")
-	ErrVolumeThreshold = errors.New("volume below threshold")
+	ErrEmptyBuffer      = errors.New("empty audio buffer")
+	ErrVolumeThreshold  = errors.New("volume below threshold")
+	ErrLowConfidence    = errors.New("pitch confidence below threshold")
+	ErrNoPitchDetected  = errors.New("no pitch detected")
+	ErrOutOfRange       = errors.New("detected frequency out of range")
+	ErrInvalidNoteName  = errors.New("invalid note name")
+	ErrInvalidScala     = errors.New("invalid Scala scale file")
+	ErrInvalidMelody    = errors.New("invalid melody file")
+	ErrInvalidStringSet = errors.New("invalid string set")
+)
+
+// Configuration errors
+var (
+	ErrInvalidWindowSize     = errors.New("window size must be positive")
+	ErrInvalidFrequencyRange = errors.New("invalid frequency range")
+	ErrInvalidThreshold      = errors.New("threshold must be between 0 and 1")
+	ErrInvalidSpectrumBins   = errors.New("spectrum bins must be positive")
+	ErrInvalidNoiseMargin    = errors.New("noise margin must not be negative")
+	ErrInvalidReferencePitch = errors.New("reference pitch must be positive")
+	ErrInvalidSubFrame       = errors.New("sub-frame length must not be negative")
+	ErrInvalidSampleRate     = errors.New("sample rate must be positive")
+	ErrWindowTooShort        = errors.New("window too short for the lowest frequency")
+	ErrAboveNyquist          = errors.New("highest frequency above the Nyquist frequency")
+	ErrInvalidTarget         = errors.New("target frequency out of range")
 )
 
 // Note represents a musical note
 type Note struct {
-	Name      string  // e.g., "A", "A#", "B"
-	Octave    int     // e.g., 4 for middle C (C4)
-	Frequency float64 // Frequency in Hz
-	Cents     float64 // Cents deviation from perfect pitch (-50 to +50)
+	Name              string  // e.g., "A", "A#", "B" (written pitch for transposing instruments)
+	Octave            int     // e.g., 4 for middle C (C4)
+	PitchClass        int     // Written pitch class, independent of naming (0 = C, 1 = C#/Db, ..., 11 = B)
+	ConcertName       string  // Sounding (concert pitch) note name
+	ConcertOctave     int     // Sounding (concert pitch) octave
+	ConcertPitchClass int     // Sounding (concert pitch) pitch class
+	MIDINote          int     // Sounding MIDI note number (A4 = 69); may fall outside 0-127 for extreme frequencies
+	Frequency         float64 // Frequency in Hz
+	Cents             float64 // Cents deviation from perfect pitch (-50 up to but excluding +50, a little beyond while NoteTracker holds a note across the boundary), smoothed by NoteTracker
+	RawCents          float64 // Cents deviation of this frame alone, before any smoothing
+	Confidence        float64 // How trustworthy the detection is (0.0-1.0)
+	Precise           bool    // Whether Frequency and Cents were refined over a long window by PrecisionAnalyzer
+	Degree            int     // Degree (0 = 1/1) of a temperament loaded from a Scala file that Cents are measured from
 }
 
 // Detector defines the interface for pitch detection
@@ -27,12 +58,40 @@ type Detector interface {
 	DetectPitch(buffer *audio.AudioBuffer) (*Note, error)
 }
 
-// DefaultDetector is a basic implementation of pitch detection
-type DefaultDetector struct{}
+// DefaultDetector is a lightweight time-domain pitch detector. It measures
+// the period from positive-going threshold crossings, with hysteresis so that
+// noise around zero doesn't add crossings. This only works for signals
+// dominated by a single component (sines, whistling, flutes); a strong
+// overtone adds crossings within each period, and such frames are rejected
+// as irregular rather than reported at the wrong pitch. Use FFTDetector for
+// harmonic-rich instruments.
+type DefaultDetector struct {
+	minFrequency    float64 // Lowest frequency to detect (Hz)
+	maxFrequency    float64 // Highest frequency to detect (Hz)
+	volumeThreshold float64 // Minimum RMS volume level for note detection
+	hysteresis      float64 // Crossing threshold as fraction of the peak amplitude
+	maxIrregularity float64 // Largest relative spread of the crossing intervals
+	maxMismatch     float64 // Largest difference between the waveform and itself one period later
+
+	converter *NoteConverter
+}
 
 // NewDefaultDetector creates a new pitch detector
 func NewDefaultDetector() *DefaultDetector {
-	return &DefaultDetector{}
+	return &DefaultDetector{
+		minFrequency:    80.0,   // Same range as the FFT detector defaults
+		maxFrequency:    1200.0, //
+		volumeThreshold: 0.005,  // Same as the FFT detector default
+		hysteresis:      0.3,    // Noise must swing 30% of the peak to cause a crossing
+		maxIrregularity: 0.05,   // A clean tone's periods vary well under 5%
+		maxMismatch:     0.1,    // Overtones make a half period differ far more than this
+		converter:       NewNoteConverter(),
+	}
+}
+
+// SetConverter sets the converter used to turn frequencies into notes
+func (d *DefaultDetector) SetConverter(converter *NoteConverter) {
+	d.converter = converter
 }
 
 // Musical note frequencies (A4 = 440Hz)
@@ -54,47 +113,107 @@ var noteFrequencies = map[string]float64{
 // All note names in chromatic order
 var noteNames = []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
 
+// All note names in chromatic order, spelled with flats
+var flatNoteNames = []string{"C", "Db", "D", "Eb", "E", "F", "Gb", "G", "Ab", "A", "Bb", "B"}
+
 // DetectPitch analyzes an audio buffer and returns the detected note
 func (d *DefaultDetector) DetectPitch(buffer *audio.AudioBuffer) (*Note, error) {
 	if buffer == nil || len(buffer.Samples) == 0 {
-		return nil, errors.New("empty audio buffer")
+		return nil, ErrEmptyBuffer
 	}
 
-	// TODO: Implement actual pitch detection using FFT
-	// This is a placeholder that returns A4 (440Hz)
-	frequency := 440.0
+	// Measure the DC offset, level and peak of the buffer
+	mean := 0.0
+	for _, sample := range buffer.Samples {
+		mean += float64(sample)
+	}
+	mean /= float64(len(buffer.Samples))
+
+	sumSquares := 0.0
+	peakValue := 0.0
+	for _, sample := range buffer.Samples {
+		value := float64(sample) - mean
+		sumSquares += value * value
+		peakValue = math.Max(peakValue, math.Abs(value))
+	}
+	rmsVolume := math.Sqrt(sumSquares / float64(len(buffer.Samples)))
+	if rmsVolume < d.volumeThreshold || peakValue < d.volumeThreshold*2 {
+		return nil, ErrVolumeThreshold
+	}
 
-	return frequencyToNote(frequency), nil
-}
+	// Record where the signal rises through the upper threshold, only
+	// counting a crossing once it has dropped below the lower one again
+	high := d.hysteresis * peakValue
+	low := -high
+	var crossings []float64
+	armed := false
+	previous := float64(buffer.Samples[0]) - mean
+	for i := 1; i < len(buffer.Samples); i++ {
+		value := float64(buffer.Samples[i]) - mean
+		if value < low {
+			armed = true
+		} else if armed && previous < high && value >= high {
+			// Interpolate the crossing between the two samples
+			crossings = append(crossings, float64(i-1)+(high-previous)/(value-previous))
+			armed = false
+		}
+		previous = value
+	}
 
-// frequencyToNote converts a frequency to a musical note
-func frequencyToNote(frequency float64) *Note {
-	// A4 = 440Hz, calculate semitones from A4
-	semitones := 12 * math.Log2(frequency/440.0)
+	// At least two full periods are needed to judge regularity
+	if len(crossings) < 3 {
+		return nil, ErrNoPitchDetected
+	}
 
-	// Round to nearest semitone
-	roundedSemitones := math.Round(semitones)
+	// Reject irregular crossing patterns (noise, strong overtones)
+	period := (crossings[len(crossings)-1] - crossings[0]) / float64(len(crossings)-1)
+	variance := 0.0
+	for i := 1; i < len(crossings); i++ {
+		deviation := crossings[i] - crossings[i-1] - period
+		variance += deviation * deviation
+	}
+	irregularity := math.Sqrt(variance/float64(len(crossings)-1)) / period
+	if irregularity > d.maxIrregularity {
+		return nil, ErrNoPitchDetected
+	}
 
-	// Calculate cents deviation (difference between actual and rounded semitones)
-	cents := 100 * (semitones - roundedSemitones)
+	// A strong overtone can cross just as regularly at a multiple of the
+	// pitch, so confirm the waveform actually repeats after one period
+	if periodMismatch(buffer.Samples, period) > d.maxMismatch {
+		return nil, ErrNoPitchDetected
+	}
 
-	// Calculate note index (0 = C, 1 = C#, etc.)
-	// A4 is 9 semitones above C4, so we add 9 to the semitone count
-	noteIndex := int(math.Mod(roundedSemitones+9, 12))
-	if noteIndex < 0 {
-		noteIndex += 12
+	frequency := float64(buffer.SampleRate) / period
+	if frequency < d.minFrequency || frequency > d.maxFrequency {
+		return nil, ErrOutOfRange
 	}
 
-	// Calculate octave (A4 is in octave 4)
-	octave := 4 + int(math.Floor((roundedSemitones+9)/12))
+	note := d.converter.FromFrequency(frequency)
+	if note == nil {
+		return nil, ErrNoPitchDetected
+	}
+	note.Confidence = 1 - irregularity/d.maxIrregularity/2 // 1.0 for a perfectly regular tone, 0.5 at the limit
+	return note, nil
+}
 
-	// Get note name
-	noteName := noteNames[noteIndex]
+// periodMismatch returns the energy of the difference between the signal and
+// itself delayed by the period, relative to their combined energy: near 0 for
+// a waveform that repeats with that period, around 1 for unrelated ones
+func periodMismatch(samples []float32, period float64) float64 {
+	whole := int(period)
+	fraction := period - float64(whole)
+
+	difference := 0.0
+	energy := 0.0
+	for i := 0; i+whole+1 < len(samples); i++ {
+		current := float64(samples[i])
+		delayed := float64(samples[i+whole])*(1-fraction) + float64(samples[i+whole+1])*fraction
+		difference += (current - delayed) * (current - delayed)
+		energy += current*current + delayed*delayed
+	}
 
-	return &Note{
-		Name:      noteName,
-		Octave:    octave,
-		Frequency: frequency,
-		Cents:     cents,
+	if energy == 0 {
+		return 1
 	}
+	return difference / energy
 }
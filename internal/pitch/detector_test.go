@@ -0,0 +1,75 @@
+package pitch
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+var _ Detector = (*DefaultDetector)(nil)
+
+func TestDefaultDetectorSines(t *testing.T) {
+	for _, sampleRate := range []int{44100, 48000} {
+		for _, frequency := range []float64{81, 82.41, 110, 196, 261.63, 440, 659.26, 987.77, 1190} {
+			note, err := NewDefaultDetector().DetectPitch(audio.SynthesizeTone(frequency, []float64{1}, 4096, sampleRate))
+			if err != nil {
+				t.Errorf("%v Hz at %d Hz: %v", frequency, sampleRate, err)
+				continue
+			}
+			if cents := 1200 * math.Log2(note.Frequency/frequency); math.Abs(cents) > 10 {
+				t.Errorf("%v Hz at %d Hz: detected %.2f Hz, %+.1f cents off", frequency, sampleRate, note.Frequency, cents)
+			}
+		}
+	}
+}
+
+func TestDefaultDetectorRejectsUnclearPitch(t *testing.T) {
+	// Crossings of overtones stronger than the fundamental, or of noise,
+	// don't measure the pitch; such frames must be errors, not wrong notes
+	for _, frequency := range []float64{82.41, 220, 440, 880} {
+		for _, overtones := range [][]float64{{1, 2}, {0.3, 1, 0.6}} {
+			buffer := audio.SynthesizeTone(frequency, overtones, 4096, 44100)
+			if note, err := NewDefaultDetector().DetectPitch(buffer); !errors.Is(err, ErrNoPitchDetected) {
+				t.Errorf("%v Hz with overtones %v: got %v, %v, want ErrNoPitchDetected", frequency, overtones, note, err)
+			}
+		}
+
+		buffer := addNoise(audio.SynthesizeTone(frequency, []float64{1}, 4096, 44100), 0, 1)
+		if note, err := NewDefaultDetector().DetectPitch(buffer); !errors.Is(err, ErrNoPitchDetected) {
+			t.Errorf("%v Hz at 0 dB SNR: got %v, %v, want ErrNoPitchDetected", frequency, note, err)
+		}
+	}
+
+	for seed := int64(1); seed <= 5; seed++ {
+		if note, err := NewDefaultDetector().DetectPitch(whiteNoise(0.1, 4096, 44100, seed)); !errors.Is(err, ErrNoPitchDetected) {
+			t.Errorf("white noise %d: got %v, %v, want ErrNoPitchDetected", seed, note, err)
+		}
+	}
+}
+
+func TestDefaultDetectorNeverWrong(t *testing.T) {
+	// Whatever the detector makes of a harmonic-rich or noisy tone, any note
+	// it does report is the right one
+	for _, frequency := range []float64{82.41, 146.83, 329.63, 523.25, 1046.5} {
+		for _, overtones := range [][]float64{guitarTone, sawtooth(10), {1, 0, 0.8}, {1, 1, 1, 1}} {
+			for _, snrDB := range []float64{math.Inf(1), 30, 20, 10} {
+				buffer := audio.SynthesizeTone(frequency, overtones, 4096, 44100)
+				if !math.IsInf(snrDB, 1) {
+					addNoise(buffer, snrDB, int64(frequency))
+				}
+				note, err := NewDefaultDetector().DetectPitch(buffer)
+				if err != nil {
+					if !errors.Is(err, ErrNoPitchDetected) {
+						t.Errorf("%v Hz with %d overtones at %v dB: %v", frequency, len(overtones), snrDB, err)
+					}
+					continue
+				}
+				if cents := 1200 * math.Log2(note.Frequency/frequency); math.Abs(cents) > 10 {
+					t.Errorf("%v Hz with %d overtones at %v dB SNR: detected %.2f Hz", frequency, len(overtones), snrDB, note.Frequency)
+				}
+			}
+		}
+	}
+}
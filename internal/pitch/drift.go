@@ -0,0 +1,139 @@
+package pitch
+
+import (
+	"math"
+	"time"
+)
+
+// DriftTrend is the trend of the cents deviations over the recent minutes
+type DriftTrend struct {
+	Cents float64       // Change over Span at the trend's rate, positive when going sharp
+	Slope float64       // Trend in cents per minute
+	Span  time.Duration // Time the trend covers, up to the monitor's window
+	Means []float64     // Mean deviation per minute over Span, oldest first; NaN for minutes without samples
+}
+
+// driftBucket accumulates the deviations of one minute
+type driftBucket struct {
+	sum   float64
+	count int
+}
+
+// DriftMonitor follows how the tuning drifts over a session, e.g. a wind
+// instrument going sharp as it warms up. It averages the cents deviations of
+// confident detections per minute and fits a line through the last ten
+// minutes. Samples are held back for a few seconds before they are counted,
+// so that those taken during a glissando, which is reported once it lands,
+// can still be excluded.
+type DriftMonitor struct {
+	bucketSize    time.Duration // Time averaged into one point of the trend
+	window        time.Duration // Time the trend covers
+	holdTime      time.Duration // How long samples wait for exclusion before they count
+	minConfidence float64       // Detections less confident than this are ignored
+
+	start   time.Time       // Time of the first sample, where the first minute begins
+	buckets []driftBucket   // One per minute since start
+	pending []vibratoSample // Samples not yet counted, oldest first
+}
+
+// NewDriftMonitor creates a new drift monitor
+func NewDriftMonitor() *DriftMonitor {
+	return &DriftMonitor{
+		bucketSize:    time.Minute,
+		window:        10 * time.Minute, // Long enough to show warming up
+		holdTime:      10 * time.Second, // Longer than any glissando
+		minConfidence: 0.8,              // Well clear of marginal detections
+	}
+}
+
+// Add records the deviation of a detection, its RawCents, at the given time.
+// Samples must be added in order.
+func (d *DriftMonitor) Add(note Note, at time.Time) {
+	if note.Confidence < d.minConfidence {
+		return
+	}
+	if d.start.IsZero() {
+		d.start = at
+	}
+	d.pending = append(d.pending, vibratoSample{at: at, cents: note.RawCents})
+
+	// Count the samples that can no longer be excluded
+	count := 0
+	for count < len(d.pending) && at.Sub(d.pending[count].at) > d.holdTime {
+		sample := d.pending[count]
+		index := int(sample.at.Sub(d.start) / d.bucketSize)
+		for len(d.buckets) <= index {
+			d.buckets = append(d.buckets, driftBucket{})
+		}
+		d.buckets[index].sum += sample.cents
+		d.buckets[index].count++
+		count++
+	}
+	d.pending = d.pending[count:]
+}
+
+// Exclude drops the samples taken between two times, such as during a
+// glissando, provided they haven't been counted yet
+func (d *DriftMonitor) Exclude(from, to time.Time) {
+	kept := d.pending[:0]
+	for _, sample := range d.pending {
+		if sample.at.Before(from) || sample.at.After(to) {
+			kept = append(kept, sample)
+		}
+	}
+	d.pending = kept
+}
+
+// Trend returns the drift over the last ten minutes of counted samples, or
+// false until two minutes have samples
+func (d *DriftMonitor) Trend() (DriftTrend, bool) {
+	first := max(0, len(d.buckets)-int(d.window/d.bucketSize))
+	recent := d.buckets[first:]
+
+	// Fit a line through the minute means, weighted by their sample counts
+	var weight, sumX, sumY float64
+	minutes := 0
+	for i, bucket := range recent {
+		if bucket.count == 0 {
+			continue
+		}
+		n := float64(bucket.count)
+		weight += n
+		sumX += n * float64(i)
+		sumY += bucket.sum
+		minutes++
+	}
+	if minutes < 2 {
+		return DriftTrend{}, false
+	}
+
+	meanX, meanY := sumX/weight, sumY/weight
+	var covariance, variance float64
+	means := make([]float64, len(recent))
+	for i, bucket := range recent {
+		if bucket.count == 0 {
+			means[i] = math.NaN()
+			continue
+		}
+		mean := bucket.sum / float64(bucket.count)
+		means[i] = mean
+		n := float64(bucket.count)
+		covariance += n * (float64(i) - meanX) * (mean - meanY)
+		variance += n * (float64(i) - meanX) * (float64(i) - meanX)
+	}
+
+	slope := covariance / variance
+	return DriftTrend{
+		Cents: slope * float64(len(recent)),
+		Slope: slope,
+		Span:  time.Duration(len(recent)) * d.bucketSize,
+		Means: means,
+	}, true
+}
+
+// Reset forgets every sample, e.g. when the note history is cleared
+func (d *DriftMonitor) Reset() {
+	d.start = time.Time{}
+	d.buckets = d.buckets[:0]
+	d.pending = d.pending[:0]
+}
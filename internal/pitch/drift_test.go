@@ -0,0 +1,90 @@
+package pitch
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// driftSession plays one confident detection per second for the given
+// minutes, rising at slope cents per minute. Every half minute it bends up
+// by a semitone for three seconds, then excludes the bend if asked to, and
+// interleaves an unconfident detection far off pitch.
+func driftSession(minutes int, slope float64, excludeBends bool) *DriftMonitor {
+	monitor := NewDriftMonitor()
+	start := time.Unix(0, 0)
+	for second := 0; second < minutes*60; second++ {
+		at := start.Add(time.Duration(second) * time.Second)
+		cents := slope * float64(second) / 60
+		if second%30 >= 27 {
+			cents += 100
+		}
+		monitor.Add(Note{RawCents: cents, Confidence: 0.95}, at)
+		monitor.Add(Note{RawCents: -45, Confidence: 0.5}, at.Add(time.Millisecond))
+		if second%30 == 29 && excludeBends {
+			monitor.Exclude(at.Add(-2*time.Second), at)
+		}
+	}
+	return monitor
+}
+
+func TestDriftSlopeOfRampedSession(t *testing.T) {
+	for _, slope := range []float64{-1.5, 0, 0.6, 2} {
+		trend, ok := driftSession(12, slope, true).Trend()
+		if !ok {
+			t.Errorf("%+v¢/min: no trend after 12 minutes", slope)
+			continue
+		}
+		if math.Abs(trend.Slope-slope) > 0.01 {
+			t.Errorf("%+v¢/min: slope %+.3f¢/min", slope, trend.Slope)
+		}
+		if trend.Span != 10*time.Minute || len(trend.Means) != 10 || math.Abs(trend.Cents-10*trend.Slope) > 1e-9 {
+			t.Errorf("%+v¢/min: covers %v in %d means, %+.2f¢", slope, trend.Span, len(trend.Means), trend.Cents)
+		}
+
+		// Each minute's mean is the ramp at the mean time of its unbent
+		// seconds, 28s in, with neither the bends nor the unconfident frames
+		// pulling it off. The trend covers minutes 2-11, the last of them
+		// not yet fully counted.
+		for i, mean := range trend.Means[:len(trend.Means)-1] {
+			minute := float64(2 + i)
+			if want := slope * (minute + 28.0/60); math.Abs(mean-want) > 1e-9 {
+				t.Errorf("%+v¢/min: minute %v mean %+.3f¢, want %+.3f¢", slope, minute, mean, want)
+			}
+		}
+	}
+}
+
+func TestDriftIncludesUnexcludedBends(t *testing.T) {
+	// The same session without excluding its bends reads a semitone sharp a
+	// tenth of the time
+	excluded, _ := driftSession(6, 1, true).Trend()
+	included, _ := driftSession(6, 1, false).Trend()
+	for i := range excluded.Means[:len(excluded.Means)-1] {
+		if diff := included.Means[i] - excluded.Means[i]; math.Abs(diff-10) > 0.5 {
+			t.Errorf("minute %d: bends raised the mean by %+.2f¢, want about 10¢", i, diff)
+		}
+	}
+}
+
+func TestDriftNeedsTwoMinutes(t *testing.T) {
+	if _, ok := driftSession(1, 1, true).Trend(); ok {
+		t.Error("trend after one minute")
+	}
+
+	monitor := driftSession(5, 1, true)
+	monitor.Reset()
+	if _, ok := monitor.Trend(); ok {
+		t.Error("trend after Reset")
+	}
+
+	// A silent minute between two played ones shows as a gap
+	monitor.Add(Note{RawCents: 2, Confidence: 1}, time.Unix(0, 0))
+	for second := 120; second <= 190; second++ {
+		monitor.Add(Note{RawCents: 4, Confidence: 1}, time.Unix(int64(second), 0))
+	}
+	trend, ok := monitor.Trend()
+	if !ok || len(trend.Means) != 3 || trend.Means[0] != 2 || !math.IsNaN(trend.Means[1]) || trend.Means[2] != 4 || math.Abs(trend.Slope-1) > 1e-9 {
+		t.Errorf("trend with a silent minute: %+v, %v", trend, ok)
+	}
+}
@@ -0,0 +1,66 @@
+package pitch
+
+import "time"
+
+// NoteEvent is a note lifecycle event emitted by NoteTracker: NoteOn, NoteOff,
+// Rest or Glissando
+type NoteEvent interface {
+	// EventTime returns when the event happened
+	EventTime() time.Time
+}
+
+// NoteOn is emitted when a stable note begins
+type NoteOn struct {
+	Note Note      // The note that started
+	At   time.Time // When it started
+
+	Previous *Note    // The stable note before this one, nil for the first note
+	Interval Interval // Leap from Previous to Note, zero without a previous note
+
+	Glide bool // Whether the note was reached by a glissando rather than attacked
+}
+
+// NoteOff is emitted when a stable note ends, either in silence or because a
+// different note took over
+type NoteOff struct {
+	Note     Note          // The note that ended, as last reported
+	At       time.Time     // When it ended
+	Duration time.Duration // How long it lasted
+}
+
+// Rest is emitted when a note starts after a silence long enough to count as
+// a rest, just before its NoteOn
+type Rest struct {
+	At       time.Time     // When the silence began
+	Duration time.Duration // How long it lasted
+}
+
+// Glissando is emitted when a slide or bend spanning more than a semitone
+// ends, just before the NoteOn of the note it landed on. The notes passed on
+// the way produce no events.
+type Glissando struct {
+	From     Note          // Stable note the slide started from
+	To       Note          // Note it landed on
+	At       time.Time     // When the slide began
+	Duration time.Duration // How long it took
+}
+
+// EventTime returns when the note started
+func (e NoteOn) EventTime() time.Time {
+	return e.At
+}
+
+// EventTime returns when the note ended
+func (e NoteOff) EventTime() time.Time {
+	return e.At
+}
+
+// EventTime returns when the rest began
+func (e Rest) EventTime() time.Time {
+	return e.At
+}
+
+// EventTime returns when the slide began
+func (e Glissando) EventTime() time.Time {
+	return e.At
+}
@@ -1,42 +1,286 @@
 package pitch
 
 import (
+	"cmp"
 	"math"
 	"math/cmplx"
-	"sort"
+	"slices"
 
 	"github.com/0xlemi/tunenote/internal/audio"
-	"github.com/mjibson/go-dsp/fft"
 )
 
-// FFTDetector implements pitch detection using FFT
+// Number of harmonics counted as signal when computing spectral confidence
+const confidenceHarmonics = 5
+
+// Half-width, in unpadded bins, of the region counted as a harmonic for
+// spectral confidence: the Hann window's main lobe
+const confidenceLobeBins = 2
+
+// Distance, in unpadded bins, over which a tone's strongest Hann sidelobes
+// can show up as peaks of their own
+const sidelobeReachBins = 4
+
+// FFTDetector implements pitch detection using FFT. It reuses internal
+// scratch buffers between calls, so a detector is not safe for concurrent
+// use; give each goroutine its own detector, e.g. with Clone.
 type FFTDetector struct {
 	windowSize      int
 	minFrequency    float64 // Lowest frequency to detect (Hz)
 	maxFrequency    float64 // Highest frequency to detect (Hz)
-	noiseFloor      float64 // Noise threshold (0.0-1.0)
 	peakThreshold   float64 // Minimum peak height as fraction of highest peak
 	volumeThreshold float64 // Minimum RMS volume level for note detection
+	minConfidence   float64 // Spectra with less harmonic energy than this are treated as noise
+	harmonics       int     // Number of harmonics multiplied in the harmonic product spectrum
+	paddingFactor   int     // Frame is zero-padded to this multiple of its length before the FFT
+
+	subharmonicCheck bool    // Whether to look for a stronger candidate at f/2 and f/3
+	subharmonicRatio float64 // Minimum subharmonic magnitude as fraction of the winner's
+
+	noiseMargin    float64   // Peaks must exceed the noise estimate by this much (dB)
+	noiseSmoothing float64   // Weight of each new frame in the noise estimate while it rises
+	noiseEstimate  float64   // Smoothed median spectral magnitude, 0 before the first frame
+	noiseScratch   []float64 // Scratch buffer for the median
+
+	maxChordNotes int // Maximum number of simultaneous notes reported by DetectChord
+
+	inharmonicity bool // Whether to correct the fundamental for stiff-string partials
+
+	subFrame int          // Length of the averaged sub-frames, 0 to analyze frames whole
+	averaged []complex128 // Mean magnitude spectrum of the sub-frames
+
+	phaseRefinement bool         // Whether to refine the frequency from the phase advance between frames
+	previous        []complex128 // Spectrum of the previous frame, for phase refinement
+	framePosition   int64        // Stream position of the last transformed frame
+	hasFrame        bool         // Whether framePosition and the transform output hold a frame
+	hop             int64        // Samples from the previous frame to the current one, 0 if unknown
+
+	converter *NoteConverter // Converts detected frequencies into notes
+
+	// Scratch buffers reused across calls to avoid per-frame allocations
+	window    []float64 // Hann window coefficients for the current frame length
+	padded    []float64 // Windowed, zero-padded frame fed to the FFT
+	transform *realFFT  // Real-input FFT sized for the padded frame
+	peaks     []Peak    // Spectral peaks of the current frame
+	products  []float64 // Harmonic product spectrum of the current frame
+
+	captured []float32 // Downsampled spectrum of the last frame, nil unless capture is enabled
 }
 
-// NewFFTDetector creates a new FFT-based pitch detector
-func NewFFTDetector(windowSize int) *FFTDetector {
-	return &FFTDetector{
+// NewFFTDetector creates a new FFT-based pitch detector. Options are applied
+// on top of the defaults; the first invalid option aborts construction.
+func NewFFTDetector(windowSize int, opts ...Option) (*FFTDetector, error) {
+	if windowSize <= 0 {
+		return nil, ErrInvalidWindowSize
+	}
+
+	detector := &FFTDetector{
+		converter:       NewNoteConverter(),
 		windowSize:      windowSize,
+		paddingFactor:   1,      // No zero-padding by default
 		minFrequency:    80.0,   // E2 on guitar is ~82 Hz
 		maxFrequency:    1200.0, // E6 on guitar is ~1319 Hz
-		noiseFloor:      0.01,   // Reduced from 0.05 to 0.01 (more sensitive to quieter sounds)
 		peakThreshold:   0.2,    // Reduced from 0.3 to 0.2 (consider smaller peaks as valid)
 		volumeThreshold: 0.005,  // Increased from 0.002 to 0.005 for better silence handling
+		minConfidence:   0.2,    // White noise scores well below this
+		harmonics:       5,      // Downsample by factors 2-5 for the harmonic product spectrum
+
+		subharmonicCheck: true,
+		subharmonicRatio: 0.3, // A fundamental at 30% of the winner's height is still real
+
+		noiseMargin:    20.0, // Noise spectra rarely peak more than ~12 dB above their median
+		noiseSmoothing: 0.1,  // Follows a change in the noise within a second or two
+
+		maxChordNotes: 4,
+	}
+
+	for _, opt := range opts {
+		if err := opt(detector); err != nil {
+			return nil, err
+		}
+	}
+
+	detector.prepareBuffers(windowSize)
+	return detector, nil
+}
+
+// SetConverter sets the converter used to turn frequencies into notes
+func (d *FFTDetector) SetConverter(converter *NoteConverter) {
+	d.converter = converter
+}
+
+// SetHarmonics sets the number of harmonics used by the harmonic product
+// spectrum. A value of 1 disables the HPS stage.
+func (d *FFTDetector) SetHarmonics(harmonics int) {
+	// Ensure at least the fundamental is used
+	if harmonics < 1 {
+		harmonics = 1
+	}
+
+	d.harmonics = harmonics
+}
+
+// SetPaddingFactor sets how many times longer than the frame the FFT input is
+// made by zero-padding. Padding interpolates the spectrum so peaks of low notes
+// land much closer to their true frequency.
+func (d *FFTDetector) SetPaddingFactor(factor int) {
+	// Ensure the frame is never truncated
+	if factor < 1 {
+		factor = 1
+	}
+
+	d.paddingFactor = factor
+	d.prepareBuffers(d.windowSize)
+}
+
+// applyWindow copies a frame into the FFT input with the Hann window
+// applied, zero-padding the rest
+func (d *FFTDetector) applyWindow(samples []float32) {
+	for i, sample := range samples {
+		d.padded[i] = float64(sample) * d.window[i]
+	}
+	for i := len(samples); i < len(d.padded); i++ {
+		d.padded[i] = 0
+	}
+}
+
+// prepareBuffers sizes the scratch buffers for frames of the given length,
+// keeping the existing ones when they already fit
+func (d *FFTDetector) prepareBuffers(frameLength int) {
+	if len(d.window) != frameLength {
+		d.window = make([]float64, frameLength)
+		for i := range d.window {
+			// Hann window coefficient
+			d.window[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(frameLength-1)))
+		}
+	}
+
+	paddedLength := frameLength * d.paddingFactor
+	if len(d.padded) != paddedLength {
+		d.padded = make([]float64, paddedLength)
+		d.transform = newRealFFT(paddedLength)
+		d.products = make([]float64, paddedLength/2+1)
+		d.hasFrame = false
+	}
+
+	if d.phaseRefinement && len(d.previous) != paddedLength/2+1 {
+		d.previous = make([]complex128, paddedLength/2+1)
 	}
 }
 
+// SetSubharmonicCheck enables or disables the subharmonic validation step
+func (d *FFTDetector) SetSubharmonicCheck(enabled bool) {
+	d.subharmonicCheck = enabled
+}
+
+// SetSubharmonicRatio sets the minimum magnitude, as a fraction of the chosen
+// peak's, that a peak at f/2 or f/3 needs to be preferred over it
+func (d *FFTDetector) SetSubharmonicRatio(ratio float64) {
+	// Ensure the ratio is within a meaningful range
+	if ratio < 0 {
+		ratio = 0
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+
+	d.subharmonicRatio = ratio
+}
+
 // DetectPitch analyzes an audio buffer and returns the detected note
 func (d *FFTDetector) DetectPitch(buffer *audio.AudioBuffer) (*Note, error) {
+	spectrum, err := d.Spectrum(buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.detect(spectrum, buffer.SampleRate)
+}
+
+// Analyze detects the note in an audio buffer and also measures the levels of
+// its first analysisHarmonics harmonics
+func (d *FFTDetector) Analyze(buffer *audio.AudioBuffer) (*Detection, error) {
+	spectrum, err := d.Spectrum(buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.AnalyzeSpectrum(spectrum, buffer.SampleRate)
+}
+
+// Spectrum returns the one-sided magnitude spectrum of an audio buffer so it
+// can be shared between pitch and onset detection. The slice is reused by the
+// next call. It returns ErrVolumeThreshold for frames that are too quiet.
+func (d *FFTDetector) Spectrum(buffer *audio.AudioBuffer) ([]complex128, error) {
 	if buffer == nil || len(buffer.Samples) == 0 {
 		return nil, ErrEmptyBuffer
 	}
 
+	return d.computeSpectrum(buffer)
+}
+
+// AnalyzeSpectrum is like Analyze, but works on a spectrum previously
+// returned by Spectrum
+func (d *FFTDetector) AnalyzeSpectrum(spectrum []complex128, sampleRate int) (*Detection, error) {
+	note, err := d.detect(spectrum, sampleRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Detection{
+		Note:       note,
+		Harmonics:  d.measureHarmonics(spectrum, note.Frequency, sampleRate),
+		Confidence: note.Confidence,
+		HNR:        d.harmonicToNoise(spectrum, note.Frequency, sampleRate),
+	}, nil
+}
+
+// detect runs the detection pipeline on a spectrum and returns the note
+func (d *FFTDetector) detect(spectrum []complex128, sampleRate int) (*Note, error) {
+	// Find the fundamental frequency using peak detection
+	peakFreq, err := d.findFundamentalFrequency(spectrum, sampleRate)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the detected frequency is too low or too high, it's likely noise.
+	// A tone right at the edge of the range can interpolate to just outside
+	// it, so allow a bin of slack; the measured frequency is kept rather than
+	// clamped, as moving it to the edge would misreport the cents.
+	tolerance := spectrumBinSize(spectrum, sampleRate)
+	if peakFreq < d.minFrequency-tolerance || peakFreq > d.maxFrequency+tolerance {
+		return nil, ErrOutOfRange
+	}
+
+	// Broadband noise has no dominant harmonic series
+	confidence := d.spectralConfidence(spectrum, peakFreq, sampleRate)
+	if confidence < d.minConfidence {
+		return nil, ErrNoPitchDetected
+	}
+
+	// Successive frames pin the frequency down from the peak's phase advance
+	if d.phaseRefinement {
+		peakFreq = d.refinePhase(spectrum, peakFreq, sampleRate)
+	}
+
+	// Stiff strings push every partial sharp, including the first
+	if d.inharmonicity {
+		peakFreq, _ = d.correctInharmonicity(spectrum, peakFreq, sampleRate)
+	}
+
+	// Convert frequency to note
+	note := d.converter.FromFrequency(peakFreq)
+	if note == nil {
+		return nil, ErrNoPitchDetected
+	}
+	note.Confidence = confidence
+	return note, nil
+}
+
+// computeSpectrum checks the frame's volume, then windows, zero-pads, and
+// transforms it. The returned one-sided spectrum (bins 0 through N/2) is
+// reused by the next call. It returns ErrVolumeThreshold for frames that are
+// too quiet.
+func (d *FFTDetector) computeSpectrum(buffer *audio.AudioBuffer) ([]complex128, error) {
 	// Calculate RMS volume of the buffer
 	sumSquares := 0.0
 	peakValue := 0.0
@@ -69,39 +313,97 @@ func (d *FFTDetector) DetectPitch(buffer *audio.AudioBuffer) (*Note, error) {
 		return nil, ErrVolumeThreshold
 	}
 
-	// Apply windowing function (Hann window)
-	windowedSamples := applyHannWindow(buffer.Samples)
+	// Average the spectra of overlapping sub-frames when enabled
+	if d.averages(len(buffer.Samples)) {
+		d.prepareBuffers(d.subFrame)
+		spectrum := d.averageSpectrum(buffer)
+		if d.captured != nil {
+			d.captureSpectrum(spectrum, buffer.SampleRate)
+		}
+		return spectrum, nil
+	}
+
+	// Resize the scratch buffers if the frame length changed
+	d.prepareBuffers(len(buffer.Samples))
+
+	d.applyWindow(buffer.Samples)
+
+	// Keep the previous frame's spectrum before the transform overwrites it
+	if d.phaseRefinement {
+		d.rememberFrame(buffer)
+	}
+
+	// Perform the real-input FFT
+	spectrum := d.transform.transform(d.padded)
+	if d.captured != nil {
+		d.captureSpectrum(spectrum, buffer.SampleRate)
+	}
+	return spectrum, nil
+}
 
-	// Convert from []float32 to []complex128 for the FFT
-	complexSamples := make([]complex128, len(windowedSamples))
-	for i, sample := range windowedSamples {
-		complexSamples[i] = complex(float64(sample), 0)
+// spectralConfidence returns the share of the spectral energy in the detection
+// range that lies around the fundamental and its harmonics. A clean harmonic
+// tone scores close to 1.0 while broadband noise scores close to 0.0.
+func (d *FFTDetector) spectralConfidence(spectrum []complex128, fundamental float64, sampleRate int) float64 {
+	harmonicEnergy, totalEnergy := d.harmonicEnergy(spectrum, fundamental, sampleRate, confidenceLobeBins)
+	if totalEnergy == 0 {
+		return 0
 	}
 
-	// Perform FFT
-	spectrum := fft.FFT(complexSamples)
+	return harmonicEnergy / totalEnergy
+}
 
-	// Find the fundamental frequency using peak detection
-	peakFreq := d.findFundamentalFrequency(spectrum, buffer.SampleRate)
+// harmonicEnergy returns the spectral energy within lobeBins unpadded bins of
+// the fundamental and its first confidenceHarmonics harmonics, and the total
+// energy of the detection range plus room for those harmonics
+func (d *FFTDetector) harmonicEnergy(spectrum []complex128, fundamental float64, sampleRate int, lobeBins float64) (float64, float64) {
+	spectrumHalf := spectrum
+	binSizeHz := spectrumBinSize(spectrum, sampleRate)
 
-	// If the detected frequency is too low or too high, it's likely noise
-	if peakFreq < d.minFrequency || peakFreq > d.maxFrequency {
-		return nil, ErrVolumeThreshold
+	// Consider the detection range plus room for the harmonics
+	minBin := int(d.minFrequency / binSizeHz)
+	if minBin < 1 {
+		minBin = 1
+	}
+	maxBin := int(d.maxFrequency * confidenceHarmonics / binSizeHz)
+	if maxBin >= len(spectrumHalf) {
+		maxBin = len(spectrumHalf) - 1
 	}
 
-	// Convert frequency to note
-	return frequencyToNote(peakFreq), nil
+	totalEnergy := 0.0
+	harmonicEnergy := 0.0
+	for i := minBin; i <= maxBin; i++ {
+		energy := real(spectrumHalf[i])*real(spectrumHalf[i]) + imag(spectrumHalf[i])*imag(spectrumHalf[i])
+		totalEnergy += energy
+
+		// Count bins close enough to a harmonic
+		harmonic := math.Round(float64(i) * binSizeHz / fundamental)
+		if harmonic >= 1 && harmonic <= confidenceHarmonics &&
+			math.Abs(float64(i)*binSizeHz-harmonic*fundamental) <= lobeBins*binSizeHz*float64(d.paddingFactor) {
+			harmonicEnergy += energy
+		}
+	}
+
+	return harmonicEnergy, totalEnergy
 }
 
-// applyHannWindow applies a Hann window to the audio samples
-func applyHannWindow(samples []float32) []float32 {
-	windowedSamples := make([]float32, len(samples))
-	for i, sample := range samples {
-		// Hann window coefficient
-		windowCoeff := 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(len(samples)-1)))
-		windowedSamples[i] = sample * float32(windowCoeff)
+// spectrumBinSize returns the frequency resolution (Hz per bin) of a
+// one-sided spectrum
+func spectrumBinSize(spectrum []complex128, sampleRate int) float64 {
+	return float64(sampleRate) / float64(2*(len(spectrum)-1))
+}
+
+// maxMagnitudeIn returns the largest magnitude of the bins from low to high,
+// limited to the spectrum (excluding DC)
+func maxMagnitudeIn(spectrum []complex128, low, high int) float64 {
+	low = max(low, 1)
+	high = min(high, len(spectrum)-1)
+
+	largest := 0.0
+	for i := low; i <= high; i++ {
+		largest = math.Max(largest, cmplx.Abs(spectrum[i]))
 	}
-	return windowedSamples
+	return largest
 }
 
 // Peak represents a peak in the frequency spectrum
@@ -111,13 +413,14 @@ type Peak struct {
 	Frequency float64
 }
 
-// findFundamentalFrequency finds the fundamental frequency using improved peak detection
-func (d *FFTDetector) findFundamentalFrequency(spectrum []complex128, sampleRate int) float64 {
-	// We only need to look at the first half of the spectrum (Nyquist theorem)
-	spectrumHalf := spectrum[:len(spectrum)/2]
+// findFundamentalFrequency finds the fundamental frequency using improved peak detection.
+// It returns ErrNoPitchDetected when the spectrum has no usable peak.
+func (d *FFTDetector) findFundamentalFrequency(spectrum []complex128, sampleRate int) (float64, error) {
+	// The spectrum is one-sided, only covering frequencies up to Nyquist
+	spectrumHalf := spectrum
 
 	// Calculate frequency resolution (Hz per bin)
-	binSizeHz := float64(sampleRate) / float64(len(spectrum))
+	binSizeHz := spectrumBinSize(spectrum, sampleRate)
 
 	// Calculate min/max bin numbers based on frequency range
 	minBin := int(d.minFrequency / binSizeHz)
@@ -125,7 +428,8 @@ func (d *FFTDetector) findFundamentalFrequency(spectrum []complex128, sampleRate
 		minBin = 1 // Avoid DC component
 	}
 
-	maxBin := int(d.maxFrequency / binSizeHz)
+	// Round up so a peak just below the top of the range is still searched
+	maxBin := int(math.Ceil(d.maxFrequency / binSizeHz))
 	if maxBin >= len(spectrumHalf) {
 		maxBin = len(spectrumHalf) - 1
 	}
@@ -139,61 +443,235 @@ func (d *FFTDetector) findFundamentalFrequency(spectrum []complex128, sampleRate
 		}
 	}
 
-	// Don't process further if signal is too weak
-	if maxMagnitude < d.noiseFloor {
-		return 440.0 // Return A4 as default if no clear signal
+	// Don't process further if the strongest peak doesn't stand out from the noise
+	if maxMagnitude < d.updateNoiseEstimate(spectrumHalf, minBin, maxBin) {
+		return 0, ErrNoPitchDetected
 	}
 
-	// Find all peaks
-	var peaks []Peak
-	for i := minBin + 1; i < maxBin; i++ {
+	// A tone just outside the range leaks sidelobes into its edges, which
+	// must not pass for tones of their own
+	reach := sidelobeReachBins * d.paddingFactor
+	lowerLeak := maxMagnitudeIn(spectrumHalf, minBin-reach, minBin-1)
+	upperLeak := maxMagnitudeIn(spectrumHalf, maxBin+1, maxBin+reach)
+
+	// Find all peaks, including those on the edge bins of the range. The
+	// spectrum continues beyond the range, so edge bins are compared with
+	// their real neighbors; only the Nyquist bin lacks one above.
+	peaks := d.peaks[:0]
+	for i := minBin; i <= maxBin; i++ {
 		magnitude := cmplx.Abs(spectrumHalf[i])
+		prev := cmplx.Abs(spectrumHalf[i-1])
+		next := 0.0
+		if i+1 < len(spectrumHalf) {
+			next = cmplx.Abs(spectrumHalf[i+1])
+		}
 
-		// Check if this bin is a peak (higher than adjacent bins)
-		if magnitude > cmplx.Abs(spectrumHalf[i-1]) &&
-			magnitude > cmplx.Abs(spectrumHalf[i+1]) &&
-			magnitude > maxMagnitude*d.peakThreshold {
+		threshold := maxMagnitude * d.peakThreshold
+		if i-minBin < reach {
+			threshold = math.Max(threshold, lowerLeak*d.peakThreshold)
+		}
+		if maxBin-i < reach {
+			threshold = math.Max(threshold, upperLeak*d.peakThreshold)
+		}
 
+		// Check if this bin is a peak (higher than adjacent bins)
+		if magnitude > prev && magnitude > next && magnitude > threshold {
 			// Use quadratic interpolation for more accurate peak location
 			// x = 0.5 * (R[k-1] - R[k+1]) / (R[k-1] - 2*R[k] + R[k+1]) + k
 			// where R[n] is the magnitude at bin n
-			prev := cmplx.Abs(spectrumHalf[i-1])
-			current := magnitude
-			next := cmplx.Abs(spectrumHalf[i+1])
-
-			// Avoid division by zero
-			if prev-2*current+next != 0 {
-				delta := 0.5 * (prev - next) / (prev - 2*current + next)
-
-				// Calculate interpolated frequency
-				freq := (float64(i) + delta) * binSizeHz
-
-				peaks = append(peaks, Peak{
-					Bin:       i,
-					Magnitude: magnitude,
-					Frequency: freq,
-				})
-			} else {
-				// Just use the bin frequency if we can't interpolate
-				peaks = append(peaks, Peak{
-					Bin:       i,
-					Magnitude: magnitude,
-					Frequency: float64(i) * binSizeHz,
-				})
+			delta := 0.0
+			if i+1 < len(spectrumHalf) {
+				delta = parabolicOffset(prev, magnitude, next)
 			}
+
+			peaks = append(peaks, Peak{
+				Bin:       i,
+				Magnitude: magnitude,
+				Frequency: (float64(i) + delta) * binSizeHz,
+			})
 		}
 	}
 
-	// If no peaks found, return default
+	d.peaks = peaks
+
+	// If no peaks found, there is no pitch to report
 	if len(peaks) == 0 {
-		return 440.0
+		return 0, ErrNoPitchDetected
 	}
 
 	// Sort peaks by magnitude (descending)
-	sort.Slice(peaks, func(i, j int) bool {
-		return peaks[i].Magnitude > peaks[j].Magnitude
+	slices.SortFunc(peaks, func(a, b Peak) int {
+		return cmp.Compare(b.Magnitude, a.Magnitude)
 	})
 
 	// The highest peak is our candidate for fundamental frequency
-	return peaks[0].Frequency
+	fundamental := peaks[0]
+
+	// The highest peak is often the 2nd harmonic, so let the harmonic product
+	// spectrum pick the fundamental and confirm it against the raw peak list
+	if d.harmonics > 1 {
+		fundamental = d.harmonicProductPeak(spectrumHalf, peaks, minBin, maxBin, binSizeHz, maxMagnitude)
+	}
+
+	// Catch remaining octave-too-high picks by looking below the winner
+	if d.subharmonicCheck {
+		fundamental = d.validateSubharmonics(spectrumHalf, fundamental, minBin, binSizeHz)
+	}
+
+	return fundamental.Frequency, nil
+}
+
+// harmonicProductPeak returns the raw peak best supported by the harmonic
+// product spectrum, falling back to the highest peak
+func (d *FFTDetector) harmonicProductPeak(spectrumHalf []complex128, peaks []Peak, minBin, maxBin int, binSizeHz, maxMagnitude float64) Peak {
+	fundamental := peaks[0]
+	hpsFreq := d.harmonicProductFrequency(spectrumHalf, minBin, maxBin, binSizeHz, maxMagnitude)
+
+	// Prefer a peak near half the raw winner if the product spectrum supports it
+	tolerance := 2 * binSizeHz * float64(d.paddingFactor)
+	if math.Abs(hpsFreq-fundamental.Frequency/2) <= tolerance {
+		for _, peak := range peaks {
+			if math.Abs(peak.Frequency-fundamental.Frequency/2) <= tolerance {
+				return peak
+			}
+		}
+	}
+
+	// Otherwise use the raw peak closest to the product spectrum's winner
+	for _, peak := range peaks {
+		if math.Abs(peak.Frequency-hpsFreq) <= tolerance {
+			return peak
+		}
+	}
+
+	return fundamental
+}
+
+// validateSubharmonics checks the spectrum near f/2 and f/3 of the chosen
+// peak. If a peak there reaches subharmonicRatio of the winner's magnitude,
+// the winner was most likely a harmonic and the lower candidate is preferred.
+func (d *FFTDetector) validateSubharmonics(spectrumHalf []complex128, winner Peak, minBin int, binSizeHz float64) Peak {
+	best := winner
+	found := false
+	for _, divisor := range []float64{2, 3} {
+		candidate, ok := peakNear(spectrumHalf, winner.Frequency/divisor, minBin, binSizeHz, d.paddingFactor)
+		if !ok || candidate.Magnitude < winner.Magnitude*d.subharmonicRatio {
+			continue
+		}
+
+		// Keep the strongest qualifying subharmonic
+		if !found || candidate.Magnitude > best.Magnitude {
+			best = candidate
+			found = true
+		}
+	}
+
+	return best
+}
+
+// parabolicOffset returns the position of the vertex of the parabola through
+// three equally spaced values, relative to the middle one. It is kept within
+// half a step either way, and is 0 when the values are flat or not finite, so
+// interpolation can never move a peak off its bin or produce NaN.
+func parabolicOffset(prev, current, next float64) float64 {
+	denominator := prev - 2*current + next
+	if denominator == 0 {
+		return 0
+	}
+
+	delta := 0.5 * (prev - next) / denominator
+	if math.IsNaN(delta) || math.IsInf(delta, 0) {
+		return 0
+	}
+	return math.Max(-0.5, math.Min(0.5, delta))
+}
+
+// peakNear looks for a local maximum within half a semitone (and at least
+// one unpadded bin) of the target frequency and returns it interpolated
+func peakNear(spectrumHalf []complex128, target float64, minBin int, binSizeHz float64, paddingFactor int) (Peak, bool) {
+	tolerance := math.Max(target*(math.Pow(2, 1.0/24)-1), binSizeHz*float64(paddingFactor))
+
+	lowBin := int(math.Floor((target - tolerance) / binSizeHz))
+	if lowBin < minBin {
+		lowBin = minBin
+	}
+	if lowBin < 1 {
+		lowBin = 1
+	}
+	highBin := int(math.Ceil((target + tolerance) / binSizeHz))
+	if highBin > len(spectrumHalf)-2 {
+		highBin = len(spectrumHalf) - 2
+	}
+
+	// Find the strongest local maximum in the window
+	bestBin := -1
+	bestMagnitude := 0.0
+	for i := lowBin; i <= highBin; i++ {
+		magnitude := cmplx.Abs(spectrumHalf[i])
+		if magnitude > cmplx.Abs(spectrumHalf[i-1]) &&
+			magnitude > cmplx.Abs(spectrumHalf[i+1]) &&
+			magnitude > bestMagnitude {
+			bestBin = i
+			bestMagnitude = magnitude
+		}
+	}
+	if bestBin < 0 {
+		return Peak{}, false
+	}
+
+	// Refine the location and height with quadratic interpolation
+	prev := cmplx.Abs(spectrumHalf[bestBin-1])
+	next := cmplx.Abs(spectrumHalf[bestBin+1])
+	delta := parabolicOffset(prev, bestMagnitude, next)
+	frequency := (float64(bestBin) + delta) * binSizeHz
+	magnitude := bestMagnitude - 0.25*(prev-next)*delta
+
+	return Peak{
+		Bin:       bestBin,
+		Magnitude: magnitude,
+		Frequency: frequency,
+	}, true
+}
+
+// harmonicProductFrequency returns the frequency of the strongest bin in the
+// harmonic product spectrum, which multiplies the magnitude spectrum with
+// copies of itself downsampled by factors 2 through d.harmonics
+func (d *FFTDetector) harmonicProductFrequency(spectrumHalf []complex128, minBin, maxBin int, binSizeHz, maxMagnitude float64) float64 {
+	// Floor each factor so a single missing harmonic doesn't zero the product
+	floor := maxMagnitude * 0.01
+
+	products := d.products[:maxBin+1]
+	bestBin := minBin
+	for i := minBin; i <= maxBin; i++ {
+		product := math.Max(cmplx.Abs(spectrumHalf[i]), floor)
+		for h := 2; h <= d.harmonics; h++ {
+			magnitude := floor
+			if i*h < len(spectrumHalf) {
+				magnitude = math.Max(cmplx.Abs(spectrumHalf[i*h]), floor)
+			}
+			product *= magnitude
+		}
+		products[i] = product
+
+		if product > products[bestBin] {
+			bestBin = i
+		}
+	}
+
+	// The product spectrum is prone to picking an octave too low when the
+	// signal has few harmonics, so move up while the octave above is comparable
+	for {
+		upper := -1
+		for i := 2*bestBin - 1; i <= 2*bestBin+1 && i <= maxBin; i++ {
+			if upper < 0 || products[i] > products[upper] {
+				upper = i
+			}
+		}
+		if upper < 0 || products[upper] < products[bestBin]*0.5 {
+			break
+		}
+		bestBin = upper
+	}
+
+	return float64(bestBin) * binSizeHz
 }
@@ -0,0 +1,289 @@
+package pitch
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+// guitarTone holds the relative overtone levels of a plucked string, rich
+// enough that the harmonic product spectrum has work to do
+var guitarTone = []float64{1, 0.6, 0.4, 0.25, 0.15, 0.1}
+
+// maxDetectAllocs is the most allocations a detection may make: the note it
+// returns
+const maxDetectAllocs = 1
+
+// benchWindows and benchRates are the window sizes and sample rates
+// detection is measured at
+var (
+	benchWindows = []int{2048, 4096, 8192}
+	benchRates   = []int{44100, 48000}
+)
+
+// benchPadding is the application's zero-padding of the FFT input
+const benchPadding = 4
+
+// benchDetector returns a detector for a window size, padded like the
+// application's, and a G3 played into it with a guitar's overtones
+func benchDetector(tb testing.TB, windowSize, sampleRate int) (*FFTDetector, *audio.AudioBuffer) {
+	tb.Helper()
+	detector, err := NewFFTDetector(windowSize)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	detector.SetPaddingFactor(benchPadding)
+	return detector, audio.SynthesizeTone(196, guitarTone, windowSize, sampleRate)
+}
+
+func BenchmarkDetectPitch(b *testing.B) {
+	for _, windowSize := range benchWindows {
+		for _, sampleRate := range benchRates {
+			b.Run(fmt.Sprintf("%d@%d", windowSize, sampleRate), func(b *testing.B) {
+				detector, buffer := benchDetector(b, windowSize, sampleRate)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := detector.DetectPitch(buffer); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkSpectrum(b *testing.B) {
+	detector, buffer := benchDetector(b, 4096, 44100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := detector.Spectrum(buffer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkApplyWindow(b *testing.B) {
+	for _, windowSize := range benchWindows {
+		b.Run(fmt.Sprint(windowSize), func(b *testing.B) {
+			detector, buffer := benchDetector(b, windowSize, 44100)
+			detector.prepareBuffers(windowSize)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				detector.applyWindow(buffer.Samples)
+			}
+		})
+	}
+}
+
+func BenchmarkFrequencyToNote(b *testing.B) {
+	converter := NewNoteConverter()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		converter.FromFrequency(80 + float64(i%1120))
+	}
+}
+
+func TestDetectPitchAllocs(t *testing.T) {
+	for _, windowSize := range benchWindows {
+		for _, sampleRate := range benchRates {
+			detector, buffer := benchDetector(t, windowSize, sampleRate)
+			allocs := testing.AllocsPerRun(20, func() {
+				if _, err := detector.DetectPitch(buffer); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if allocs > maxDetectAllocs {
+				t.Errorf("DetectPitch made %.1f allocations per call on %d samples at %d Hz, want at most %d",
+					allocs, windowSize, sampleRate, maxDetectAllocs)
+			}
+		}
+	}
+}
+
+func TestDetectPitchAtRangeEdges(t *testing.T) {
+	// Sines at and just inside the default 80-1200 Hz range are measured,
+	// those more than a bin outside it rejected
+	tests := []struct {
+		frequency float64
+		detected  bool
+	}{
+		{65, false},
+		{80, true},
+		{81, true},
+		{1190, true},
+		{1200, true},
+		{1230, false},
+	}
+	for _, sampleRate := range []int{44100, 48000} {
+		for _, tt := range tests {
+			detector, err := NewFFTDetector(4096)
+			if err != nil {
+				t.Fatal(err)
+			}
+			note, err := detector.DetectPitch(audio.SynthesizeTone(tt.frequency, []float64{1}, 4096, sampleRate))
+			switch {
+			case !tt.detected && err == nil:
+				t.Errorf("%v Hz at %d Hz: detected %.2f Hz outside the range", tt.frequency, sampleRate, note.Frequency)
+			case tt.detected && err != nil:
+				t.Errorf("%v Hz at %d Hz: %v", tt.frequency, sampleRate, err)
+			case tt.detected && math.Abs(note.Frequency-tt.frequency) > tt.frequency*0.01:
+				t.Errorf("%v Hz at %d Hz: detected %.2f Hz", tt.frequency, sampleRate, note.Frequency)
+			}
+		}
+	}
+}
+
+func TestHPSReportsFundamentalOctave(t *testing.T) {
+	// The 2nd harmonic at twice the fundamental's level is the tallest peak;
+	// the product spectrum must still land on the fundamental's octave
+	overtones := []float64{1, 2, 0.8, 0.5, 0.3}
+	for _, sampleRate := range []int{44100, 48000} {
+		for _, str := range guitarStrings {
+			detector, err := NewFFTDetector(4096)
+			if err != nil {
+				t.Fatal(err)
+			}
+			detector.SetSubharmonicCheck(false)
+			note, err := detector.DetectPitch(audio.SynthesizeTone(str.frequency, overtones, 4096, sampleRate))
+			if err != nil {
+				t.Errorf("%s at %d Hz: %v", str.note, sampleRate, err)
+				continue
+			}
+			if got := fmt.Sprintf("%s%d", note.Name, note.Octave); got != str.note {
+				t.Errorf("%s at %d Hz: detected %s (%.2f Hz)", str.note, sampleRate, got, note.Frequency)
+			}
+		}
+	}
+}
+
+func TestRawPeakReportsOctaveUp(t *testing.T) {
+	// Without the product spectrum or the subharmonic check the same tone
+	// reads an octave high, which is what the HPS stage is there to fix
+	detector, err := NewFFTDetector(4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	detector.SetHarmonics(1)
+	detector.SetSubharmonicCheck(false)
+	note, err := detector.DetectPitch(audio.SynthesizeTone(329.63, []float64{1, 2, 0.8, 0.5, 0.3}, 4096, 44100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprintf("%s%d", note.Name, note.Octave); got != "E5" {
+		t.Errorf("raw peak picking detected %s, want the octave error E5", got)
+	}
+}
+
+func TestConfidenceOfSinesAndNoise(t *testing.T) {
+	for _, frequency := range []float64{82.41, 220, 440, 1000} {
+		detector, err := NewFFTDetector(4096)
+		if err != nil {
+			t.Fatal(err)
+		}
+		note, err := detector.DetectPitch(audio.SynthesizeTone(frequency, []float64{1}, 4096, 44100))
+		if err != nil {
+			t.Fatalf("%v Hz: %v", frequency, err)
+		}
+		if note.Confidence < 0.99 {
+			t.Errorf("%v Hz sine: confidence %.3f, want near 1", frequency, note.Confidence)
+		}
+	}
+
+	// Noise spreads its energy across the band, so whichever frequency is
+	// taken as the fundamental holds a small share of it
+	for seed := int64(1); seed <= 5; seed++ {
+		detector, err := NewFFTDetector(4096)
+		if err != nil {
+			t.Fatal(err)
+		}
+		spectrum, err := detector.Spectrum(whiteNoise(0.1, 4096, 44100, seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, frequency := range []float64{100, 220, 440} {
+			if confidence := detector.spectralConfidence(spectrum, frequency, 44100); confidence >= detector.minConfidence/2 {
+				t.Errorf("white noise %d at %v Hz: confidence %.3f, want well below the %.2f floor",
+					seed, frequency, confidence, detector.minConfidence)
+			}
+		}
+	}
+}
+
+func TestNoPitchInNoiseOrSilence(t *testing.T) {
+	// Frames with no usable pitch must come back as an error, never as the
+	// A4 the detector used to fall back to
+	check := func(name string, buffer *audio.AudioBuffer, want ...error) {
+		t.Helper()
+		detector, err := NewFFTDetector(4096)
+		if err != nil {
+			t.Fatal(err)
+		}
+		note, err := detector.DetectPitch(buffer)
+		if err == nil {
+			t.Errorf("%s: detected %s%d (%.2f Hz), want an error", name, note.Name, note.Octave, note.Frequency)
+			return
+		}
+		if note != nil {
+			t.Errorf("%s: returned a note alongside %v", name, err)
+		}
+		for _, target := range want {
+			if errors.Is(err, target) {
+				return
+			}
+		}
+		t.Errorf("%s: error %v, want one of %v", name, err, want)
+	}
+
+	check("silence", &audio.AudioBuffer{Samples: make([]float32, 4096), SampleRate: 44100}, ErrVolumeThreshold)
+
+	quiet := audio.SynthesizeTone(440, []float64{1}, 4096, 44100)
+	for i := range quiet.Samples {
+		quiet.Samples[i] *= 0.005 // RMS ~0.0018, under the 0.005 threshold
+	}
+	check("sub-threshold A4", quiet, ErrVolumeThreshold)
+
+	for seed := int64(1); seed <= 5; seed++ {
+		for _, level := range []float64{0.02, 0.1, 0.3} {
+			check(fmt.Sprintf("white noise %d at %v", seed, level), whiteNoise(level, 4096, 44100, seed),
+				ErrNoPitchDetected, ErrLowConfidence)
+		}
+	}
+}
+
+func TestSubharmonicCheckFindsWeakFundamental(t *testing.T) {
+	// A low string's fundamental is often well under its 2nd harmonic; with
+	// the product spectrum off, only the subharmonic check can recover it
+	weakFundamental := []float64{0.4, 1, 0.6, 0.4, 0.2, 0.1}
+	for _, sampleRate := range []int{44100, 48000} {
+		for _, str := range guitarStrings {
+			for _, check := range []bool{true, false} {
+				detector, err := NewFFTDetector(4096)
+				if err != nil {
+					t.Fatal(err)
+				}
+				detector.SetHarmonics(1)
+				detector.SetSubharmonicCheck(check)
+				note, err := detector.DetectPitch(audio.SynthesizeTone(str.frequency, weakFundamental, 4096, sampleRate))
+				if err != nil {
+					t.Errorf("%s at %d Hz: %v", str.note, sampleRate, err)
+					continue
+				}
+
+				// Disabled, the check leaves the 2nd harmonic as the winner
+				want := str.note
+				if !check {
+					want = fmt.Sprintf("%s%d", str.note[:1], str.note[1]-'0'+1)
+				}
+				if got := fmt.Sprintf("%s%d", note.Name, note.Octave); got != want {
+					t.Errorf("%s at %d Hz, subharmonic check %v: detected %s, want %s", str.note, sampleRate, check, got, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package pitch
+
+import (
+	"math"
+	"time"
+)
+
+// Glissando detection settings
+const (
+	maxGlideStep  = 90.0 // Largest move in one frame, in cents; larger ones are steps to a new note
+	minGlideSpan  = 100  // Cents a slide must cover to be a glissando
+	minGlideMoves = 3    // Frames a slide must move on to be a glissando
+)
+
+// Results of following a detection for glissandi
+const (
+	glideNone    = iota // Not sliding
+	glideSliding        // Sliding; note changes are held back until it lands
+	glideLanded         // A glissando just ended on the detection
+)
+
+// SetGlideRate sets how fast, in cents per 100ms, the pitch must move from
+// frame to frame for the movement to count as a slide
+func (t *NoteTracker) SetGlideRate(centsPer100ms float64) {
+	t.glideRate = math.Max(1, centsPer100ms)
+}
+
+// followGlide follows the pitch of a detection for slides. While the pitch
+// moves steadily in one direction from a stable note it reports
+// glideSliding. Once such a run has covered more than a semitone and the
+// pitch stops, it records the glissando and makes the detection the stable
+// note, reporting glideLanded. Without a stable note there is nothing to
+// slide from, so the detection goes on to be tracked as usual.
+func (t *NoteTracker) followGlide(note *Note, at time.Time) int {
+	// The same captured block, with the same timestamp, may be analyzed twice
+	if !at.After(t.lastAt) {
+		if t.glideMoves > 0 {
+			return glideSliding
+		}
+		return glideNone
+	}
+
+	pitch := 1200 * math.Log2(note.Frequency/DefaultReferenceA4)
+	lastPitch, lastAt := t.lastPitch, t.lastAt
+	t.lastPitch, t.lastAt = pitch, at
+	if lastAt.IsZero() {
+		return glideNone
+	}
+
+	move := pitch - lastPitch
+	rate := math.Abs(move) / at.Sub(lastAt).Seconds() / 10
+	steady := t.stable != nil && rate >= t.glideRate && math.Abs(move) < maxGlideStep
+	if steady && (t.glideMoves == 0 || (move > 0) == (t.glideSpan > 0)) {
+		if t.glideMoves == 0 {
+			t.glideFrom = t.stable
+			t.glideStart = lastAt
+		}
+		t.glideSpan += move
+		t.glideMoves++
+		return glideSliding
+	}
+
+	// The run is over: report it if it was a glissando
+	from, start := t.glideFrom, t.glideStart
+	landed := from != nil && math.Abs(t.glideSpan) > minGlideSpan && t.glideMoves >= minGlideMoves
+	t.stopGlide()
+	if !landed {
+		return glideNone
+	}
+
+	t.end(start)
+	t.events = append(t.events, Glissando{From: *from, To: *note, At: start, Duration: at.Sub(start)})
+
+	// Start over from the note landed on
+	t.window = append(t.window[:0], note)
+	t.candidate = -1
+	t.candidateFrames = 0
+	t.center = noteCenter(note)
+	t.stable = t.smooth(note, true, at)
+	t.startedAt = at
+	on := t.noteOn(at)
+	on.Glide = true
+	t.events = append(t.events, on)
+	return glideLanded
+}
+
+// stopGlide forgets the current slide, if any
+func (t *NoteTracker) stopGlide() {
+	t.glideFrom = nil
+	t.glideSpan = 0
+	t.glideMoves = 0
+}
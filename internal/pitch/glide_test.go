@@ -0,0 +1,165 @@
+package pitch
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// trackFrames feeds the tracker one detection per frequency, frameInterval
+// apart from start, and returns the events they produced. Zero frequencies
+// are frames without a detection.
+func trackFrames(t *testing.T, tracker *NoteTracker, start time.Time, frequencies []float64) []NoteEvent {
+	t.Helper()
+	converter := NewNoteConverter()
+	for i, frequency := range frequencies {
+		var note *Note
+		if frequency > 0 {
+			note = converter.FromFrequency(frequency)
+		}
+		tracker.UpdateAt(note, start.Add(time.Duration(i)*frameInterval))
+	}
+	return tracker.Events()
+}
+
+// frameInterval is the processing loop's time between detections
+const frameInterval = 50 * time.Millisecond
+
+// ramp returns frames gliding linearly in cents from one frequency to
+// another over the given time, then holding the last one for hold frames
+func ramp(from, to float64, over time.Duration, hold int) []float64 {
+	steps := int(over / frameInterval)
+	span := 1200 * math.Log2(to/from)
+	frames := make([]float64, 0, steps+1+hold)
+	for i := 0; i <= steps; i++ {
+		frames = append(frames, from*math.Pow(2, span*float64(i)/float64(steps)/1200))
+	}
+	for i := 0; i < hold; i++ {
+		frames = append(frames, to)
+	}
+	return frames
+}
+
+// held returns frames holding a frequency
+func held(frequency float64, frames int) []float64 {
+	out := make([]float64, frames)
+	for i := range out {
+		out[i] = frequency
+	}
+	return out
+}
+
+func TestTrackerRampIsOneGlissando(t *testing.T) {
+	g3, a3 := 196.0, 220.0
+	for _, tc := range []struct {
+		name     string
+		from, to float64
+	}{
+		{"up", g3, a3},
+		{"down", a3, g3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			frames := append(held(tc.from, 3), ramp(tc.from, tc.to, 400*time.Millisecond, 4)...)
+			events := trackFrames(t, NewNoteTracker(), time.Now(), frames)
+
+			var glides []Glissando
+			var ons []NoteOn
+			for _, event := range events {
+				switch event := event.(type) {
+				case Glissando:
+					glides = append(glides, event)
+				case NoteOn:
+					ons = append(ons, event)
+				}
+			}
+
+			if len(glides) != 1 {
+				t.Fatalf("got %d glissandi, want 1: %v", len(glides), events)
+			}
+			converter := NewNoteConverter()
+			from, to := converter.FromFrequency(tc.from), converter.FromFrequency(tc.to)
+			if glides[0].From.MIDINote != from.MIDINote || glides[0].To.MIDINote != to.MIDINote {
+				t.Errorf("glissando %s%d→%s%d, want %s%d→%s%d", glides[0].From.Name, glides[0].From.Octave,
+					glides[0].To.Name, glides[0].To.Octave, from.Name, from.Octave, to.Name, to.Octave)
+			}
+			if glides[0].Duration < 300*time.Millisecond || glides[0].Duration > 500*time.Millisecond {
+				t.Errorf("glissando lasted %v, want about 400ms", glides[0].Duration)
+			}
+
+			// The semitone passed on the way has no note of its own
+			if len(ons) != 2 || ons[0].Glide || !ons[1].Glide {
+				t.Fatalf("got note ons %v, want the start note then the landing", ons)
+			}
+		})
+	}
+}
+
+func TestTrackerFollowsVibrato(t *testing.T) {
+	// ±30 cents at 5.5 Hz moves fast enough to look like sliding from frame
+	// to frame, but the held note's cents must keep following it
+	tracker := NewNoteTracker()
+	converter := NewNoteConverter()
+	start := time.Now()
+
+	var last *Note
+	frozen := 0
+	for i := 0; i < 60; i++ {
+		at := time.Duration(i) * frameInterval
+		cents := 30 * math.Sin(2*math.Pi*5.5*at.Seconds())
+		stable, _ := tracker.UpdateAt(converter.FromFrequency(440*math.Pow(2, cents/1200)), start.Add(at))
+		if i >= 6 && stable == last {
+			frozen++
+		}
+		last = stable
+	}
+	if frozen > 0 {
+		t.Errorf("stable note's cents froze on %d of 54 frames", frozen)
+	}
+
+	var ons int
+	for _, event := range tracker.Events() {
+		switch event := event.(type) {
+		case Glissando:
+			t.Fatalf("vibrato reported as a glissando: %v", event)
+		case NoteOn:
+			ons++
+		}
+	}
+	if ons != 1 || last == nil || last.Name != "A" {
+		t.Errorf("got %d note ons ending on %v, want one A4", ons, last)
+	}
+}
+
+func TestTrackerStepIsNoteChange(t *testing.T) {
+	frames := append(held(196, 6), held(220, 6)...)
+	events := trackFrames(t, NewNoteTracker(), time.Now(), frames)
+
+	var ons []NoteOn
+	for _, event := range events {
+		switch event := event.(type) {
+		case Glissando:
+			t.Fatalf("step reported as a glissando: %v", event)
+		case NoteOn:
+			ons = append(ons, event)
+		}
+	}
+	if len(ons) != 2 || ons[1].Glide || ons[1].Note.Name != "A" {
+		t.Fatalf("got note ons %v, want G3 then an attacked A3", ons)
+	}
+}
+
+func TestTrackerNoSlideFromSilence(t *testing.T) {
+	// A move within the slide range right after a frame without a detection
+	// starts a note rather than sliding from nothing
+	tracker := NewNoteTracker()
+	converter := NewNoteConverter()
+	start := time.Now()
+
+	tracker.UpdateAt(converter.FromFrequency(440), start)
+	tracker.UpdateAt(nil, start.Add(frameInterval))
+	note := converter.FromFrequency(440 * math.Pow(2, 34.0/1200))
+	stable, _ := tracker.UpdateAt(note, start.Add(frameInterval+60*time.Millisecond))
+	if stable == nil {
+		t.Fatal("tracker reported no note for a detection after silence")
+	}
+}
@@ -0,0 +1,208 @@
+package pitch
+
+import (
+	"math"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+// Goertzel tuner settings
+const (
+	tunerRangeCents  = 100.0 // Search this far either side of the target
+	tunerCoarseCents = 10.0  // Spacing of the first pass over the whole range
+	tunerFineCents   = 2.0   // Spacing of the second pass around the best coarse step
+	tunerHarmonics   = 8     // Harmonics counted towards the concentration
+)
+
+// TunerReading is the result of measuring a frame against a target note
+type TunerReading struct {
+	Frequency     float64 // Measured frequency in Hz
+	Cents         float64 // Offset from the target
+	Concentration float64 // Share of the frame's energy in the measured tone and its harmonics (0.0-1.0)
+	Locked        bool    // Whether the frame is dominated by a single tone near the target
+}
+
+// GoertzelTuner measures how far a tone is from a known target frequency. It
+// evaluates Goertzel filters only at frequencies within ±100 cents of the
+// target, first every 10 cents and then every 2 cents around the best one,
+// and interpolates between them. That is cheaper than a full FFT and resolves
+// the offset to a fraction of a cent, so it can run on every block once the
+// note being tuned is known. The window should hold at least eight cycles of
+// the target (see RequiredWindowSize); shorter ones let the harmonics bias the
+// reading. It is not safe for concurrent use.
+type GoertzelTuner struct {
+	target          float64 // Frequency being tuned to (Hz)
+	lockThreshold   float64 // Minimum concentration for a reading to be locked
+	volumeThreshold float64 // Minimum RMS volume level for a measurement
+
+	// Scratch buffers reused across calls
+	window    []float64 // Hann window coefficients for the current frame length
+	windowed  []float64 // Windowed frame
+	powers    []float64 // Coarse pass frequencies, then their powers
+	fine      []float64 // Fine pass frequencies, then their powers
+	harmonics []float64 // Harmonic frequencies, then their powers
+}
+
+// NewGoertzelTuner creates a tuner for the given target frequency in Hz
+func NewGoertzelTuner(target float64) (*GoertzelTuner, error) {
+	tuner := &GoertzelTuner{
+		lockThreshold:   0.5,   // Over half the energy in one tone
+		volumeThreshold: 0.005, // Same as the FFT detector default
+	}
+	if err := tuner.SetTarget(target); err != nil {
+		return nil, err
+	}
+	return tuner, nil
+}
+
+// SetTarget sets the frequency being tuned to, in Hz
+func (t *GoertzelTuner) SetTarget(target float64) error {
+	if !(target >= MinNoteFrequency && target <= MaxNoteFrequency) {
+		return ErrInvalidTarget
+	}
+
+	t.target = target
+	return nil
+}
+
+// Target returns the frequency being tuned to, in Hz
+func (t *GoertzelTuner) Target() float64 {
+	return t.target
+}
+
+// Measure finds the strongest frequency within ±100 cents of the target. It
+// returns ErrVolumeThreshold for quiet frames and ErrOutOfRange when the
+// strongest response lies at the edge of the search range, meaning the tone
+// is further from the target than that.
+func (t *GoertzelTuner) Measure(buffer *audio.AudioBuffer) (TunerReading, error) {
+	if buffer == nil || len(buffer.Samples) == 0 {
+		return TunerReading{}, ErrEmptyBuffer
+	}
+	if t.target >= float64(buffer.SampleRate)/2 {
+		return TunerReading{}, ErrAboveNyquist
+	}
+
+	// Window the frame, measuring its level on the way
+	t.prepareWindow(len(buffer.Samples))
+	sumSquares := 0.0
+	energy := 0.0
+	for i, sample := range buffer.Samples {
+		value := float64(sample)
+		sumSquares += value * value
+		t.windowed[i] = value * t.window[i]
+		energy += t.windowed[i] * t.windowed[i]
+	}
+	if math.Sqrt(sumSquares/float64(len(buffer.Samples))) < t.volumeThreshold {
+		return TunerReading{}, ErrVolumeThreshold
+	}
+
+	// Coarse pass over the whole range
+	coarse := t.powers[:0]
+	for cents := -tunerRangeCents; cents <= tunerRangeCents; cents += tunerCoarseCents {
+		coarse = append(coarse, t.target*math.Pow(2, cents/1200))
+	}
+	t.powersAt(coarse, buffer.SampleRate)
+	t.powers = coarse
+	best := 0
+	for i, power := range coarse {
+		if power > coarse[best] {
+			best = i
+		}
+	}
+	if best == 0 || best == len(coarse)-1 {
+		return TunerReading{}, ErrOutOfRange
+	}
+	bestCents := -tunerRangeCents + float64(best)*tunerCoarseCents
+
+	// Fine pass between the neighboring coarse steps, whose powers are known
+	steps := int(2 * tunerCoarseCents / tunerFineCents)
+	fine := t.fine[:0]
+	for i := 0; i <= steps; i++ {
+		fine = append(fine, t.target*math.Pow(2, (bestCents-tunerCoarseCents+float64(i)*tunerFineCents)/1200))
+	}
+	t.powersAt(fine[1:steps], buffer.SampleRate)
+	fine[0], fine[steps] = coarse[best-1], coarse[best+1]
+	peak := 1
+	for i := 1; i < len(fine)-1; i++ {
+		if fine[i] > fine[peak] {
+			peak = i
+		}
+	}
+
+	// Interpolate between the fine steps on the magnitude, whose peak is
+	// closer to a parabola than the power's
+	offset := parabolicOffset(math.Sqrt(fine[peak-1]), math.Sqrt(fine[peak]), math.Sqrt(fine[peak+1]))
+	cents := bestCents - tunerCoarseCents + (float64(peak)+offset)*tunerFineCents
+	frequency := t.target * math.Pow(2, cents/1200)
+
+	// A harmonic tone puts nearly all of the windowed energy at its
+	// frequency and the first few multiples of it
+	harmonics := t.harmonics[:0]
+	for h := 1; h <= tunerHarmonics && float64(h)*frequency < float64(buffer.SampleRate)/2; h++ {
+		harmonics = append(harmonics, float64(h)*frequency)
+	}
+	t.powersAt(harmonics, buffer.SampleRate)
+	t.fine, t.harmonics = fine, harmonics
+	concentration := 0.0
+	if energy > 0 {
+		sumWindow, sumWindowSquares := 0.0, 0.0
+		for _, w := range t.window {
+			sumWindow += w
+			sumWindowSquares += w * w
+		}
+		for _, power := range harmonics {
+			concentration += power * 2 * sumWindowSquares / (sumWindow * sumWindow * energy)
+		}
+		concentration = math.Min(1, concentration)
+	}
+
+	return TunerReading{
+		Frequency:     frequency,
+		Cents:         cents,
+		Concentration: concentration,
+		Locked:        concentration >= t.lockThreshold,
+	}, nil
+}
+
+// prepareWindow sizes the window and scratch buffer for frames of the given
+// length, keeping the existing ones when they already fit
+func (t *GoertzelTuner) prepareWindow(frameLength int) {
+	if len(t.window) == frameLength {
+		return
+	}
+
+	t.window = make([]float64, frameLength)
+	t.windowed = make([]float64, frameLength)
+	for i := range t.window {
+		// Hann window coefficient
+		t.window[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(frameLength-1)))
+	}
+}
+
+// powersAt runs a Goertzel filter over the windowed frame for each of the
+// frequencies, which needn't fall on FFT bins, and replaces each with its
+// squared magnitude. Filters run four at a time, as each one alone spends
+// most of its time waiting on its own previous step.
+func (t *GoertzelTuner) powersAt(frequencies []float64, sampleRate int) {
+	for start := 0; start < len(frequencies); start += 4 {
+		var coefficients [4]float64
+		group := frequencies[start:min(start+4, len(frequencies))]
+		for i, frequency := range group {
+			coefficients[i] = 2 * math.Cos(2*math.Pi*frequency/float64(sampleRate))
+		}
+
+		var a1, a2, b1, b2, c1, c2, d1, d2 float64
+		for _, value := range t.windowed {
+			a1, a2 = value+coefficients[0]*a1-a2, a1
+			b1, b2 = value+coefficients[1]*b1-b2, b1
+			c1, c2 = value+coefficients[2]*c1-c2, c1
+			d1, d2 = value+coefficients[3]*d1-d2, d1
+		}
+
+		states := [4][2]float64{{a1, a2}, {b1, b2}, {c1, c2}, {d1, d2}}
+		for i := range group {
+			s1, s2 := states[i][0], states[i][1]
+			group[i] = s1*s1 + s2*s2 - coefficients[i]*s1*s2
+		}
+	}
+}
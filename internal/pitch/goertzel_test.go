@@ -0,0 +1,106 @@
+package pitch
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+func TestGoertzelTunerOffsets(t *testing.T) {
+	// Tones a known number of cents off each string, at the 4096-sample
+	// window the guitar preset uses
+	offsets := []float64{-40, -12.5, -3, -0.5, 0, 1.5, 7, 25}
+	tones := []struct {
+		name      string
+		overtones []float64
+		snrDB     float64
+	}{
+		{"sine", []float64{1}, math.Inf(1)},
+		{"guitar", guitarTone, math.Inf(1)},
+		{"noisy guitar", guitarTone, 20},
+	}
+	for _, sampleRate := range []int{44100, 48000} {
+		for _, str := range guitarStrings {
+			tuner, err := NewGoertzelTuner(str.frequency)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, tone := range tones {
+				for _, offset := range offsets {
+					buffer := audio.SynthesizeTone(str.frequency*math.Pow(2, offset/1200), tone.overtones, 4096, sampleRate)
+					if !math.IsInf(tone.snrDB, 1) {
+						addNoise(buffer, tone.snrDB, 1)
+					}
+					reading, err := tuner.Measure(buffer)
+					if err != nil {
+						t.Errorf("%s %+v¢ %s at %d Hz: %v", str.note, offset, tone.name, sampleRate, err)
+						continue
+					}
+					if math.Abs(reading.Cents-offset) > 1 {
+						t.Errorf("%s %+v¢ %s at %d Hz: measured %+.2f¢", str.note, offset, tone.name, sampleRate, reading.Cents)
+					}
+					if !reading.Locked {
+						t.Errorf("%s %+v¢ %s at %d Hz: not locked, concentration %.2f",
+							str.note, offset, tone.name, sampleRate, reading.Concentration)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGoertzelTunerRejects(t *testing.T) {
+	tuner, err := NewGoertzelTuner(110)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		buffer *audio.AudioBuffer
+		want   error
+	}{
+		{"empty", &audio.AudioBuffer{SampleRate: 44100}, ErrEmptyBuffer},
+		{"silence", &audio.AudioBuffer{Samples: make([]float32, 4096), SampleRate: 44100}, ErrVolumeThreshold},
+		{"a tone away", audio.SynthesizeTone(110*math.Pow(2, 2.0/12), []float64{1}, 4096, 44100), ErrOutOfRange},
+		{"above Nyquist", audio.SynthesizeTone(40, []float64{1}, 4096, 200), ErrAboveNyquist},
+	}
+	for _, tt := range tests {
+		if _, err := tuner.Measure(tt.buffer); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	if _, err := NewGoertzelTuner(0); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("target 0 Hz: got %v, want %v", err, ErrInvalidTarget)
+	}
+}
+
+func TestGoertzelTunerNoiseNotLocked(t *testing.T) {
+	tuner, err := NewGoertzelTuner(196)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seed := int64(1); seed <= 5; seed++ {
+		reading, err := tuner.Measure(whiteNoise(0.1, 4096, 44100, seed))
+		if err == nil && reading.Locked {
+			t.Errorf("white noise %d: locked at %+.1f¢, concentration %.2f", seed, reading.Cents, reading.Concentration)
+		}
+	}
+}
+
+func BenchmarkGoertzelTuner(b *testing.B) {
+	tuner, err := NewGoertzelTuner(196)
+	if err != nil {
+		b.Fatal(err)
+	}
+	buffer := audio.SynthesizeTone(196, guitarTone, 4096, 44100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := tuner.Measure(buffer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
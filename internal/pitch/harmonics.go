@@ -0,0 +1,90 @@
+package pitch
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Number of harmonics measured by FFTDetector.Analyze
+const analysisHarmonics = 8
+
+// Harmonic-to-noise ratio reported for spectra with no energy outside the
+// harmonics (dB)
+const maxHNR = 100.0
+
+// Half-width, in unpadded bins, of the region counted as a harmonic for the
+// harmonic-to-noise ratio. This reaches past the Hann main lobe so a clean
+// tone's sidelobes aren't mistaken for noise.
+const hnrLobeBins = 4
+
+// Harmonic describes one harmonic of a detected note
+type Harmonic struct {
+	Number    int     // 1 for the fundamental, 2 for the first overtone, etc.
+	Frequency float64 // Measured frequency in Hz
+	LevelDB   float64 // Magnitude relative to the fundamental in dB
+}
+
+// Detection is an extended detection result carrying analysis beyond the note
+type Detection struct {
+	Note       *Note
+	Harmonics  []Harmonic // Harmonic levels, fundamental first
+	Confidence float64    // Same as Note.Confidence
+	HNR        float64    // Harmonic-to-noise ratio in dB; low values mean a noisy signal
+}
+
+// measureHarmonics finds the peaks near integer multiples of the fundamental
+// and reports their levels relative to the fundamental
+func (d *FFTDetector) measureHarmonics(spectrum []complex128, fundamental float64, sampleRate int) []Harmonic {
+	spectrumHalf := spectrum
+	binSizeHz := spectrumBinSize(spectrum, sampleRate)
+
+	harmonics := make([]Harmonic, 0, analysisHarmonics)
+	reference := 0.0
+	for h := 1; h <= analysisHarmonics; h++ {
+		target := fundamental * float64(h)
+		bin := int(math.Round(target / binSizeHz))
+		if bin >= len(spectrumHalf)-1 {
+			break
+		}
+
+		// Search a small window around the expected position, falling back
+		// to the expected bin when there is no peak (harmonic absent)
+		peak, ok := peakNear(spectrumHalf, target, 1, binSizeHz, d.paddingFactor)
+		if !ok {
+			peak = Peak{Bin: bin, Magnitude: cmplx.Abs(spectrumHalf[bin]), Frequency: target}
+		}
+
+		if h == 1 {
+			reference = peak.Magnitude
+		}
+
+		levelDB := -100.0
+		if reference > 0 && peak.Magnitude > 0 {
+			levelDB = math.Max(20*math.Log10(peak.Magnitude/reference), -100)
+		}
+
+		harmonics = append(harmonics, Harmonic{
+			Number:    h,
+			Frequency: peak.Frequency,
+			LevelDB:   levelDB,
+		})
+	}
+
+	return harmonics
+}
+
+// harmonicToNoise returns the ratio, in dB, of the energy around the
+// fundamental and its first harmonics to the energy everywhere else in the
+// analyzed band. It tells a noisy signal apart from out-of-tune playing.
+func (d *FFTDetector) harmonicToNoise(spectrum []complex128, fundamental float64, sampleRate int) float64 {
+	harmonicEnergy, totalEnergy := d.harmonicEnergy(spectrum, fundamental, sampleRate, hnrLobeBins)
+	noiseEnergy := totalEnergy - harmonicEnergy
+	if harmonicEnergy == 0 {
+		return -maxHNR
+	}
+	if noiseEnergy <= harmonicEnergy*math.Pow(10, -maxHNR/10) {
+		return maxHNR
+	}
+
+	return 10 * math.Log10(harmonicEnergy/noiseEnergy)
+}
@@ -0,0 +1,47 @@
+package pitch
+
+import (
+	"math"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+// hnr returns the harmonic-to-noise ratio the detector measures on a buffer
+func hnr(t *testing.T, buffer *audio.AudioBuffer) float64 {
+	t.Helper()
+	detector, err := NewFFTDetector(len(buffer.Samples))
+	if err != nil {
+		t.Fatal(err)
+	}
+	detection, err := detector.Analyze(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return detection.HNR
+}
+
+func TestHNRCleanSine(t *testing.T) {
+	for _, frequency := range []float64{110, 440, 880} {
+		// Only the window's far sidelobes count as noise
+		if got := hnr(t, audio.SynthesizeTone(frequency, []float64{1}, 4096, 44100)); got < 40 {
+			t.Errorf("clean %v Hz sine: HNR %.1f dB, want at least 40 dB", frequency, got)
+		}
+	}
+}
+
+func TestHNRFollowsSNR(t *testing.T) {
+	for _, sampleRate := range []int{44100, 48000} {
+		for _, frequency := range []float64{110, 440, 880} {
+			previous := math.Inf(-1)
+			for _, snrDB := range []float64{0, 5, 10, 20, 30, 40} {
+				got := hnr(t, addNoise(audio.SynthesizeTone(frequency, []float64{1}, 4096, sampleRate), snrDB, 1))
+				if got <= previous {
+					t.Errorf("%v Hz at %d Hz: HNR %.1f dB at %v dB SNR, not above %.1f dB at the lower SNR",
+						frequency, sampleRate, got, snrDB, previous)
+				}
+				previous = got
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package pitch
+
+import "math"
+
+// Settings for the inharmonicity fit
+const (
+	inharmonicityPartials = 8    // Highest partial measured for the fit
+	inharmonicityMinFit   = 3    // Partials needed before the fit is trusted
+	inharmonicityMinLevel = 0.02 // Weakest partial used, as fraction of the fundamental's magnitude
+	inharmonicityMaxB     = 0.02 // Largest plausible coefficient; anything above is a bad fit
+)
+
+// WithInharmonicityCorrection enables correcting the detected frequency for
+// string stiffness. Stiff strings such as a piano's have partials at
+// f_n = n·f0·sqrt(1+B·n²), so even the first partial is sharp of the true
+// fundamental f0. When enabled, the detector measures the first few partials,
+// fits B and f0, and reports f0. It costs several extra peak searches per frame.
+func WithInharmonicityCorrection(enabled bool) Option {
+	return func(d *FFTDetector) error {
+		d.inharmonicity = enabled
+		return nil
+	}
+}
+
+// correctInharmonicity fits the stiff-string model to the partials of the
+// detected fundamental and returns the corrected fundamental frequency, along
+// with the fitted inharmonicity coefficient B. The measured frequency is
+// returned unchanged when too few partials can be found.
+func (d *FFTDetector) correctInharmonicity(spectrum []complex128, measured float64, sampleRate int) (float64, float64) {
+	binSizeHz := spectrumBinSize(spectrum, sampleRate)
+	nyquist := float64(sampleRate) / 2
+
+	fundamental, ok := peakNear(spectrum, measured, 1, binSizeHz, d.paddingFactor)
+	if !ok {
+		return measured, 0
+	}
+	minMagnitude := fundamental.Magnitude * inharmonicityMinLevel
+
+	// Running sums for the least-squares line (f_n/n)² = f0² + f0²·B·n²
+	var sumX, sumY, sumXX, sumXY float64
+	count := 0
+	add := func(n int, frequency float64) {
+		x := float64(n * n)
+		y := (frequency / float64(n)) * (frequency / float64(n))
+		sumX += x
+		sumY += y
+		sumXX += x * x
+		sumXY += x * y
+		count++
+	}
+	add(1, fundamental.Frequency)
+
+	// Stretched partials drift out of a fixed-ratio search window, so predict
+	// each one from the fit of the partials found so far
+	f0, b := fundamental.Frequency, 0.0
+	for n := 2; n <= inharmonicityPartials; n++ {
+		target := float64(n) * f0 * math.Sqrt(1+b*float64(n*n))
+		if target >= nyquist-binSizeHz {
+			break
+		}
+
+		partial, ok := peakNear(spectrum, target, 1, binSizeHz, d.paddingFactor)
+		if !ok || partial.Magnitude < minMagnitude {
+			continue
+		}
+		add(n, partial.Frequency)
+
+		if count >= 2 {
+			if fitF0, fitB, ok := fitInharmonicity(sumX, sumY, sumXX, sumXY, count); ok {
+				f0, b = fitF0, fitB
+			}
+		}
+	}
+
+	if count < inharmonicityMinFit {
+		return measured, 0
+	}
+	return f0, b
+}
+
+// fitInharmonicity solves the least-squares line from its running sums and
+// converts it back to f0 and B. It reports false for implausible fits.
+func fitInharmonicity(sumX, sumY, sumXX, sumXY float64, count int) (float64, float64, bool) {
+	n := float64(count)
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, 0, false
+	}
+
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+	if intercept <= 0 {
+		return 0, 0, false
+	}
+
+	b := slope / intercept
+	if b < 0 || b > inharmonicityMaxB {
+		return 0, 0, false
+	}
+	return math.Sqrt(intercept), b, true
+}
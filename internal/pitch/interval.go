@@ -0,0 +1,50 @@
+package pitch
+
+import "fmt"
+
+// Conventional names of the intervals up to two octaves, by semitones
+var intervalNames = [25]string{
+	"P1", "m2", "M2", "m3", "M3", "P4", "TT", "P5", "m6", "M6", "m7", "M7",
+	"P8", "m9", "M9", "m10", "M10", "P11", "A11", "P12", "m13", "M13", "m14", "M14",
+	"P15",
+}
+
+// Interval is the distance between two notes
+type Interval struct {
+	Semitones int // Positive when the second note is higher
+}
+
+// IntervalBetween returns the interval from one note to another
+func IntervalBetween(from, to Note) Interval {
+	return Interval{Semitones: noteNumber(&to) - noteNumber(&from)}
+}
+
+// Name returns the conventional name of the interval's size regardless of
+// direction, e.g. "P5" or "m10". Intervals beyond two octaves are named by
+// the compound interval within two octaves plus the extra octaves, e.g.
+// "M10+1oct" for three octaves and a major third.
+func (i Interval) Name() string {
+	size := i.Semitones
+	if size < 0 {
+		size = -size
+	}
+
+	if size < len(intervalNames) {
+		return intervalNames[size]
+	}
+
+	// Fold wider intervals down to a compound interval within two octaves
+	octaves := (size - 13) / 12
+	return fmt.Sprintf("%s+%doct", intervalNames[size-12*octaves], octaves)
+}
+
+// String returns the interval with its direction, e.g. "↑ P5" or "↓ m3"
+func (i Interval) String() string {
+	switch {
+	case i.Semitones > 0:
+		return "↑ " + i.Name()
+	case i.Semitones < 0:
+		return "↓ " + i.Name()
+	}
+	return i.Name()
+}
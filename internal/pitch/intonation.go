@@ -0,0 +1,83 @@
+package pitch
+
+import (
+	"math"
+	"sort"
+)
+
+// IntonationSummary describes how one note was played over a session
+type IntonationSummary struct {
+	Note   Note    // Most recent detection of the note, for naming it
+	Count  int     // Number of samples
+	Mean   float64 // Mean cents deviation
+	StdDev float64 // Standard deviation of the cents
+	Worst  float64 // Largest deviation either way, with its sign
+}
+
+// intonationTally accumulates the cents of one note with Welford's algorithm
+type intonationTally struct {
+	note  Note
+	count int
+	mean  float64
+	m2    float64 // Sum of squared differences from the mean
+	worst float64
+}
+
+// IntonationStats aggregates the cents deviations of the stable notes played
+// over a session, per sounding note, to show which notes are consistently
+// played sharp or flat
+type IntonationStats struct {
+	tallies map[int]*intonationTally // By sounding MIDI note
+}
+
+// NewIntonationStats creates an empty set of intonation statistics
+func NewIntonationStats() *IntonationStats {
+	return &IntonationStats{tallies: make(map[int]*intonationTally)}
+}
+
+// Add records one sample of a note's deviation, its RawCents
+func (s *IntonationStats) Add(note Note) {
+	tally, ok := s.tallies[note.MIDINote]
+	if !ok {
+		tally = &intonationTally{}
+		s.tallies[note.MIDINote] = tally
+	}
+
+	cents := note.RawCents
+	tally.note = note
+	tally.count++
+	delta := cents - tally.mean
+	tally.mean += delta / float64(tally.count)
+	tally.m2 += delta * (cents - tally.mean)
+	if math.Abs(cents) > math.Abs(tally.worst) {
+		tally.worst = cents
+	}
+}
+
+// Report returns a summary per note, the furthest off on average first
+func (s *IntonationStats) Report() []IntonationSummary {
+	report := make([]IntonationSummary, 0, len(s.tallies))
+	for _, tally := range s.tallies {
+		report = append(report, IntonationSummary{
+			Note:   tally.note,
+			Count:  tally.count,
+			Mean:   tally.mean,
+			StdDev: math.Sqrt(tally.m2 / float64(tally.count)),
+			Worst:  tally.worst,
+		})
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		a, b := math.Abs(report[i].Mean), math.Abs(report[j].Mean)
+		if a != b {
+			return a > b
+		}
+		return report[i].Note.MIDINote < report[j].Note.MIDINote
+	})
+	return report
+}
+
+// Reset forgets every sample, e.g. when the note history is cleared
+func (s *IntonationStats) Reset() {
+	clear(s.tallies)
+}
@@ -0,0 +1,79 @@
+package pitch
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// played returns the detection of a note played the given cents off
+func played(converter *NoteConverter, frequency, cents float64) Note {
+	return *converter.FromFrequency(frequency * math.Pow(2, cents/1200))
+}
+
+func TestIntonationAggregates(t *testing.T) {
+	converter := NewNoteConverter()
+	stats := NewIntonationStats()
+
+	// A4 with exactly known values
+	for _, cents := range []float64{-2, 4, 10, -6} {
+		stats.Add(played(converter, 440, cents))
+	}
+
+	// E4 played flat, G3 played sharp, both normally distributed
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 4000; i++ {
+		stats.Add(played(converter, 329.63, -8+3*rng.NormFloat64()))
+		stats.Add(played(converter, 196, 15+6*rng.NormFloat64()))
+	}
+
+	report := stats.Report()
+	tests := []struct {
+		name         string
+		count        int
+		mean, stdDev float64
+		tolerance    float64
+	}{
+		// Furthest off on average first
+		{"G", 4000, 15, 6, 0.3},
+		{"E", 4000, -8, 3, 0.15},
+		{"A", 4, 1.5, math.Sqrt(36.75), 1e-9},
+	}
+	if len(report) != len(tests) {
+		t.Fatalf("report has %d notes, want %d", len(report), len(tests))
+	}
+	for i, tt := range tests {
+		got := report[i]
+		if got.Note.Name != tt.name || got.Count != tt.count {
+			t.Errorf("row %d: %s with %d samples, want %s with %d", i, got.Note.Name, got.Count, tt.name, tt.count)
+		}
+		if math.Abs(got.Mean-tt.mean) > tt.tolerance || math.Abs(got.StdDev-tt.stdDev) > tt.tolerance {
+			t.Errorf("%s: mean %+.2f¢ σ %.2f¢, want %+.2f¢ σ %.2f¢", tt.name, got.Mean, got.StdDev, tt.mean, tt.stdDev)
+		}
+	}
+	if worst := report[2].Worst; math.Abs(worst-10) > 1e-9 {
+		t.Errorf("A: worst %+.2f¢, want +10¢", worst)
+	}
+	if worst := report[1].Worst; worst > -8-3*3 {
+		t.Errorf("E: worst %+.2f¢, want a flat excursion beyond three σ over 4000 samples", worst)
+	}
+}
+
+func TestIntonationReset(t *testing.T) {
+	converter := NewNoteConverter()
+	stats := NewIntonationStats()
+	for _, cents := range []float64{-20, -25, -30} {
+		stats.Add(played(converter, 440, cents))
+	}
+	stats.Reset()
+	if report := stats.Report(); len(report) != 0 {
+		t.Fatalf("report after reset has %d notes", len(report))
+	}
+
+	// Samples after the reset start a new tally
+	stats.Add(played(converter, 440, 5))
+	report := stats.Report()
+	if len(report) != 1 || report[0].Count != 1 || math.Abs(report[0].Mean-5) > 1e-9 || math.Abs(report[0].Worst-5) > 1e-9 {
+		t.Errorf("after reset and one +5¢ sample: %+v", report)
+	}
+}
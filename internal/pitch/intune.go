@@ -0,0 +1,61 @@
+package pitch
+
+import (
+	"math"
+	"time"
+)
+
+// Default in-tune lock settings
+const (
+	DefaultInTuneTolerance = 5.0                    // Cents either side of the note
+	DefaultInTuneDwell     = 500 * time.Millisecond // Time the note must stay within the tolerance
+)
+
+// InTuneDetector tells when a note has settled in tune: its cents have stayed
+// within a tolerance of the note for a dwell time. Leaving the tolerance or
+// changing note starts the dwell over.
+type InTuneDetector struct {
+	tolerance float64       // Cents either side of the note counted as in tune
+	dwell     time.Duration // Time within the tolerance before locking
+
+	midiNote int       // Note being measured
+	since    time.Time // When the cents last came within the tolerance, zero while outside it
+	locked   bool
+}
+
+// NewInTuneDetector creates an in-tune detector with the given tolerance in
+// cents and dwell time
+func NewInTuneDetector(tolerance float64, dwell time.Duration) *InTuneDetector {
+	return &InTuneDetector{tolerance: math.Abs(tolerance), dwell: max(0, dwell)}
+}
+
+// Add feeds the note measured at the given time and reports whether it is
+// locked in tune, and whether it has just locked
+func (d *InTuneDetector) Add(note Note, at time.Time) (locked, justLocked bool) {
+	if note.MIDINote != d.midiNote || math.Abs(note.Cents) > d.tolerance {
+		d.midiNote = note.MIDINote
+		d.since = time.Time{}
+		d.locked = false
+	}
+	if d.since.IsZero() && math.Abs(note.Cents) <= d.tolerance {
+		d.since = at
+	}
+	return d.Check(at)
+}
+
+// Check reports whether the note is locked in tune at the given time, and
+// whether it has just locked, so the lock comes as soon as the dwell is over
+// even between measurements
+func (d *InTuneDetector) Check(at time.Time) (locked, justLocked bool) {
+	if d.locked || d.since.IsZero() || at.Sub(d.since) < d.dwell {
+		return d.locked, false
+	}
+	d.locked = true
+	return true, true
+}
+
+// Reset forgets the note, e.g. when the sound stops
+func (d *InTuneDetector) Reset() {
+	d.since = time.Time{}
+	d.locked = false
+}
@@ -0,0 +1,79 @@
+package pitch
+
+import (
+	"testing"
+	"time"
+)
+
+// tick is the UI's time between checks of the lock
+const tick = 100 * time.Millisecond
+
+// a4 returns A4 measured the given cents off
+func a4(cents float64) Note {
+	return Note{Name: "A", Octave: 4, MIDINote: 69, Cents: cents}
+}
+
+func TestInTuneLocksAfterDwell(t *testing.T) {
+	detector := NewInTuneDetector(DefaultInTuneTolerance, DefaultInTuneDwell)
+	start := time.Now()
+	if locked, _ := detector.Add(a4(3), start); locked {
+		t.Fatal("locked before the dwell")
+	}
+
+	// Only ticks follow the one reading, as between detections
+	var lockedAt time.Duration
+	for at := tick; at <= time.Second; at += tick {
+		locked, justLocked := detector.Check(start.Add(at))
+		if justLocked {
+			if lockedAt != 0 {
+				t.Fatalf("locked again at %v after locking at %v", at, lockedAt)
+			}
+			lockedAt = at
+		}
+		if locked != (at >= DefaultInTuneDwell) {
+			t.Errorf("locked %v at %v", locked, at)
+		}
+	}
+	if lockedAt != DefaultInTuneDwell {
+		t.Errorf("locked at %v, want %v", lockedAt, DefaultInTuneDwell)
+	}
+}
+
+func TestInTuneStartsOver(t *testing.T) {
+	tests := []struct {
+		name      string
+		interrupt func(d *InTuneDetector, at time.Time)
+	}{
+		{"leaving the tolerance", func(d *InTuneDetector, at time.Time) { d.Add(a4(DefaultInTuneTolerance+1), at) }},
+		{"changing note", func(d *InTuneDetector, at time.Time) {
+			d.Add(Note{Name: "A#", Octave: 4, MIDINote: 70}, at)
+		}},
+		{"reset", func(d *InTuneDetector, at time.Time) { d.Reset() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := NewInTuneDetector(DefaultInTuneTolerance, DefaultInTuneDwell)
+			start := time.Now()
+			detector.Add(a4(-DefaultInTuneTolerance), start)
+			if locked, _ := detector.Check(start.Add(DefaultInTuneDwell)); !locked {
+				t.Fatal("not locked at the edge of the tolerance")
+			}
+
+			broken := start.Add(DefaultInTuneDwell + tick)
+			tt.interrupt(detector, broken)
+			if locked, _ := detector.Check(broken.Add(tick)); locked {
+				t.Fatal("still locked")
+			}
+
+			// Back in tune, the dwell starts over from the new reading
+			back := broken.Add(2 * tick)
+			detector.Add(a4(0), back)
+			if locked, _ := detector.Check(back.Add(DefaultInTuneDwell - tick)); locked {
+				t.Error("locked again before a full dwell")
+			}
+			if _, justLocked := detector.Check(back.Add(DefaultInTuneDwell)); !justLocked {
+				t.Error("didn't lock again after a full dwell")
+			}
+		})
+	}
+}
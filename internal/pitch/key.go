@@ -0,0 +1,105 @@
+package pitch
+
+import (
+	"math"
+	"time"
+)
+
+// Krumhansl-Kessler key profiles: how well each pitch class, counted up from
+// the tonic, fits a major or minor key
+var (
+	majorKeyProfile = [12]float64{6.35, 2.23, 3.48, 2.33, 4.38, 4.09, 2.52, 5.19, 2.39, 3.66, 2.29, 2.88}
+	minorKeyProfile = [12]float64{6.33, 2.68, 3.52, 5.38, 2.60, 3.53, 2.54, 4.75, 3.98, 2.69, 3.34, 3.17}
+)
+
+// Conventional key names by tonic pitch class, favoring the spelling with
+// the fewest accidentals in the key signature
+var (
+	majorKeyNames = [12]string{"C", "Db", "D", "Eb", "E", "F", "F#", "G", "Ab", "A", "Bb", "B"}
+	minorKeyNames = [12]string{"C", "C#", "D", "Eb", "E", "F", "F#", "G", "G#", "A", "Bb", "B"}
+)
+
+// KeyEstimator estimates the key of the music played so far with the
+// Krumhansl-Schmuckler algorithm: it builds a histogram of how long each pitch
+// class sounded and correlates it against the major and minor key profiles
+// for every tonic. The best-correlated key wins.
+type KeyEstimator struct {
+	histogram [12]float64 // Accumulated weight per pitch class (0 = C)
+}
+
+// NewKeyEstimator creates a new key estimator
+func NewKeyEstimator() *KeyEstimator {
+	return &KeyEstimator{}
+}
+
+// AddNote adds a played note to the histogram, weighted by its duration, or
+// counted once when the duration isn't known
+func (k *KeyEstimator) AddNote(note Note, duration time.Duration) {
+	weight := duration.Seconds()
+	if weight <= 0 {
+		weight = 1
+	}
+
+	k.Add(note.PitchClass, weight)
+}
+
+// Add adds weight to a pitch class (0 = C) in the histogram
+func (k *KeyEstimator) Add(pitchClass int, weight float64) {
+	if weight <= 0 {
+		return
+	}
+
+	k.histogram[((pitchClass%12)+12)%12] += weight
+}
+
+// Estimate returns the most likely key, e.g. "G" and "major", along with the
+// correlation of the played notes with that key's profile as confidence
+// (0.0-1.0). Without notes, or when every pitch class is equally likely, it
+// returns empty names and zero confidence.
+func (k *KeyEstimator) Estimate() (key string, mode string, confidence float64) {
+	best := 0.0
+	for tonic := 0; tonic < 12; tonic++ {
+		if r := k.correlation(majorKeyProfile, tonic); r > best {
+			key, mode, best = majorKeyNames[tonic], "major", r
+		}
+		if r := k.correlation(minorKeyProfile, tonic); r > best {
+			key, mode, best = minorKeyNames[tonic], "minor", r
+		}
+	}
+
+	return key, mode, best
+}
+
+// Reset clears the histogram, e.g. when the note history is cleared
+func (k *KeyEstimator) Reset() {
+	k.histogram = [12]float64{}
+}
+
+// correlation returns the Pearson correlation between the histogram and a key
+// profile rotated onto the given tonic
+func (k *KeyEstimator) correlation(profile [12]float64, tonic int) float64 {
+	meanHistogram := 0.0
+	meanProfile := 0.0
+	for i := range k.histogram {
+		meanHistogram += k.histogram[i]
+		meanProfile += profile[i]
+	}
+	meanHistogram /= 12
+	meanProfile /= 12
+
+	covariance := 0.0
+	varianceHistogram := 0.0
+	varianceProfile := 0.0
+	for pitchClass, weight := range k.histogram {
+		x := weight - meanHistogram
+		y := profile[(pitchClass-tonic+12)%12] - meanProfile
+		covariance += x * y
+		varianceHistogram += x * x
+		varianceProfile += y * y
+	}
+
+	if varianceHistogram == 0 || varianceProfile == 0 {
+		return 0
+	}
+	return covariance / math.Sqrt(varianceHistogram*varianceProfile)
+}
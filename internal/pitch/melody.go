@@ -0,0 +1,277 @@
+package pitch
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Melody scoring settings
+const (
+	defaultMelodyTempo = 60.0                   // Beats per minute when a melody doesn't set one
+	melodyTolerance    = 25.0                   // Cents from the target still counted as in tune
+	maxMelodySampleGap = 150 * time.Millisecond // Most time a single pitch sample accounts for
+)
+
+// MelodyNote is one note (or rest) of a target melody
+type MelodyNote struct {
+	Note     *Note         // Target note at A4 = 440 Hz, nil for a rest
+	Start    time.Duration // Offset from the start of the melody
+	Duration time.Duration
+}
+
+// MelodyTarget is a melody to sing or play along to
+type MelodyTarget struct {
+	Name  string
+	Notes []MelodyNote
+}
+
+// Length returns how long the melody lasts
+func (m *MelodyTarget) Length() time.Duration {
+	if len(m.Notes) == 0 {
+		return 0
+	}
+	last := m.Notes[len(m.Notes)-1]
+	return last.Start + last.Duration
+}
+
+// At returns the index of the note sounding at an offset from the start of
+// the melody, or false before the start and after the end
+func (m *MelodyTarget) At(offset time.Duration) (int, bool) {
+	for i, note := range m.Notes {
+		if offset >= note.Start && offset < note.Start+note.Duration {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// LoadMelody reads a target melody from a file; see ParseMelody for the format
+func LoadMelody(path string) (*MelodyTarget, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	melody, err := ParseMelody(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	melody.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return melody, nil
+}
+
+// ParseMelody parses a melody written one note per line as a note name with
+// its octave and a length in beats, e.g. "C#4 1.5", or "rest" and a length.
+// A "tempo" line sets the beats per minute for the notes after it (60 until
+// then). Blank lines and anything after a "#" are ignored. Errors name the
+// offending line.
+func ParseMelody(r io.Reader) (*MelodyTarget, error) {
+	melody := &MelodyTarget{}
+	tempo := defaultMelodyTempo
+	var start time.Duration
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%w: line %d: expected a note and a length", ErrInvalidMelody, lineNumber)
+		}
+
+		value, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil || !(value > 0) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("%w: line %d: invalid number %q", ErrInvalidMelody, lineNumber, fields[1])
+		}
+
+		if strings.EqualFold(fields[0], "tempo") {
+			tempo = value
+			continue
+		}
+
+		var note *Note
+		if !strings.EqualFold(fields[0], "rest") {
+			if note, err = ParseNote(fields[0]); err != nil {
+				return nil, fmt.Errorf("%w: line %d: invalid note %q", ErrInvalidMelody, lineNumber, fields[0])
+			}
+		}
+
+		duration := time.Duration(value * 60 / tempo * float64(time.Second))
+		melody.Notes = append(melody.Notes, MelodyNote{Note: note, Start: start, Duration: duration})
+		start += duration
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(melody.Notes) == 0 {
+		return nil, fmt.Errorf("%w: no notes", ErrInvalidMelody)
+	}
+	return melody, nil
+}
+
+// MelodyResult is how well one target note was matched
+type MelodyResult struct {
+	Target      MelodyNote
+	Samples     int     // Pitch samples taken during the note
+	MedianCents float64 // Median deviation from the target, zero without samples
+	InTune      float64 // Fraction of the note's length spent within ±25 cents (0.0-1.0)
+}
+
+// MelodyScorer scores a sung or played pitch contour against a target
+// melody. Each target note is scored by the median cents deviation of the
+// pitch samples taken while it should sound, and by the fraction of its
+// length spent within ±25 cents. Deviations are measured to the nearest
+// octave of the target, so singing in another octave isn't penalized.
+type MelodyScorer struct {
+	target *MelodyTarget
+	a4     float64 // Reference pitch the target notes are tuned to (Hz)
+
+	start  time.Time       // When the melody started
+	cents  [][]float64     // Deviations sampled per target note
+	inTune []time.Duration // Time spent in tune per target note
+
+	// Previous sample, credited with the time until the next one
+	lastAt     time.Time
+	lastIndex  int  // Target note it was taken during
+	lastInTune bool // Whether it was in tune, false outside the melody
+}
+
+// NewMelodyScorer creates a scorer for a melody tuned to the given A4
+// reference pitch
+func NewMelodyScorer(target *MelodyTarget, a4 float64) *MelodyScorer {
+	return &MelodyScorer{
+		target: target,
+		a4:     a4,
+		cents:  make([][]float64, len(target.Notes)),
+		inTune: make([]time.Duration, len(target.Notes)),
+	}
+}
+
+// Start starts the melody at the given time, forgetting any earlier samples
+func (s *MelodyScorer) Start(at time.Time) {
+	s.start = at
+	s.lastAt = time.Time{}
+	s.lastInTune = false
+	for i := range s.cents {
+		s.cents[i] = s.cents[i][:0]
+		s.inTune[i] = 0
+	}
+}
+
+// Target returns the melody being scored
+func (s *MelodyScorer) Target() *MelodyTarget {
+	return s.target
+}
+
+// Current returns the index of the target note at the given time, or false
+// before the start, after the end and before Start is called
+func (s *MelodyScorer) Current(at time.Time) (int, bool) {
+	if s.start.IsZero() {
+		return 0, false
+	}
+	return s.target.At(at.Sub(s.start))
+}
+
+// Done reports whether the melody has ended by the given time
+func (s *MelodyScorer) Done(at time.Time) bool {
+	return !s.start.IsZero() && at.Sub(s.start) >= s.target.Length()
+}
+
+// Add records a pitch sample and returns its deviation in cents from the
+// target note sounding at that time, or false during rests and outside the
+// melody. Samples must be added in order. Each one accounts for the time
+// until the next, up to 150ms and the end of its note, so gaps such as a late
+// entry count as out of tune.
+func (s *MelodyScorer) Add(frequency float64, at time.Time) (float64, bool) {
+	if !at.After(s.lastAt) {
+		return 0, false
+	}
+
+	// Credit the previous sample now that its length is known
+	if s.lastInTune {
+		note := s.target.Notes[s.lastIndex]
+		end := s.start.Add(note.Start + note.Duration)
+		s.inTune[s.lastIndex] += min(at.Sub(s.lastAt), maxMelodySampleGap, end.Sub(s.lastAt))
+	}
+	s.lastAt = at
+	s.lastInTune = false
+
+	index, ok := s.Current(at)
+	if !ok || s.target.Notes[index].Note == nil || !(frequency > 0) {
+		return 0, false
+	}
+
+	target := s.target.Notes[index].Note.Frequency * s.a4 / DefaultReferenceA4
+	cents := 1200 * math.Log2(frequency/target)
+	cents -= 1200 * math.Round(cents/1200)
+
+	s.cents[index] = append(s.cents[index], cents)
+	s.lastIndex = index
+	s.lastInTune = math.Abs(cents) <= melodyTolerance
+	return cents, true
+}
+
+// Results returns how well each target note was matched, rests included
+// with no samples
+func (s *MelodyScorer) Results() []MelodyResult {
+	results := make([]MelodyResult, len(s.target.Notes))
+	for i, note := range s.target.Notes {
+		results[i] = MelodyResult{
+			Target:      note,
+			Samples:     len(s.cents[i]),
+			MedianCents: median(s.cents[i]),
+			InTune:      math.Min(1, s.inTune[i].Seconds()/note.Duration.Seconds()),
+		}
+	}
+	return results
+}
+
+// Score returns the mean in-tune fraction of the target notes that have
+// started by the given time (0.0-1.0), or zero before the first one
+func (s *MelodyScorer) Score(at time.Time) float64 {
+	if s.start.IsZero() {
+		return 0
+	}
+
+	total := 0.0
+	count := 0
+	for _, result := range s.Results() {
+		if result.Target.Note == nil || at.Sub(s.start) < result.Target.Start {
+			continue
+		}
+		total += result.InTune
+		count++
+	}
+
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+// median returns the median of the values, or zero when there are none
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
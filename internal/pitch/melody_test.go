@@ -0,0 +1,79 @@
+package pitch
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// singMelody sings a melody into a scorer, sampling every 50ms from start to
+// end: each note comes in late by the given delay, after silence, and is then
+// held off its target by cents, in the given octave relative to it
+func singMelody(melody *MelodyTarget, late time.Duration, cents float64, octave int) *MelodyScorer {
+	scorer := NewMelodyScorer(melody, DefaultReferenceA4)
+	start := time.Unix(0, 0)
+	scorer.Start(start)
+	for offset := time.Duration(0); offset <= melody.Length(); offset += frameInterval {
+		frequency := 0.0
+		if index, ok := melody.At(offset); ok && melody.Notes[index].Note != nil && offset-melody.Notes[index].Start >= late {
+			frequency = melody.Notes[index].Note.Frequency * math.Pow(2, float64(octave)+cents/1200)
+		}
+		scorer.Add(frequency, start.Add(offset))
+	}
+	return scorer
+}
+
+func TestMelodyScorerContours(t *testing.T) {
+	melody, err := ParseMelody(strings.NewReader("tempo 120\nC4 1\nD4 1\nrest 0.5\nE4 2\nG4 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		late   time.Duration
+		cents  float64
+		octave int
+	}{
+		{"correct", 0, 0, 0},
+		{"octave below", 0, 0, -1},
+		{"slightly sharp", 0, 15, 0},
+		{"sharp", 0, 40, 0},
+		{"flat", 0, -30, 0},
+		{"late", 250 * time.Millisecond, 0, 0},
+		{"late and sharp", 250 * time.Millisecond, 40, 0},
+	}
+	for _, tt := range tests {
+		scorer := singMelody(melody, tt.late, tt.cents, tt.octave)
+
+		// In tune for all of each note but the late entry, if within ±25¢
+		totalInTune, notes := 0.0, 0
+		for i, result := range scorer.Results() {
+			if result.Target.Note == nil {
+				if result.Samples != 0 {
+					t.Errorf("%s: rest %d has %d samples", tt.name, i, result.Samples)
+				}
+				continue
+			}
+
+			wantInTune := 0.0
+			if math.Abs(tt.cents) <= melodyTolerance {
+				wantInTune = 1 - tt.late.Seconds()/result.Target.Duration.Seconds()
+			}
+			if math.Abs(result.MedianCents-tt.cents) > 1e-6 {
+				t.Errorf("%s: note %d median %+.2f¢, want %+.2f¢", tt.name, i, result.MedianCents, tt.cents)
+			}
+			if math.Abs(result.InTune-wantInTune) > 0.05 {
+				t.Errorf("%s: note %d in tune %.0f%% of the time, want %.0f%%", tt.name, i, 100*result.InTune, 100*wantInTune)
+			}
+			totalInTune += wantInTune
+			notes++
+		}
+
+		wantScore := totalInTune / float64(notes)
+		if score := scorer.Score(time.Unix(0, 0).Add(melody.Length())); math.Abs(score-wantScore) > 0.05 {
+			t.Errorf("%s: score %.2f, want %.2f", tt.name, score, wantScore)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package pitch
+
+import (
+	"math"
+	"time"
+)
+
+// Metronome tempo limits
+const (
+	MinMetronomeBPM = 30.0
+	MaxMetronomeBPM = 300.0
+)
+
+// Accent is how strongly a beat of the bar is accented
+type Accent int
+
+const (
+	AccentNone   Accent = iota // An unaccented beat
+	AccentMedium               // The first beat of a group after the first, e.g. beat 4 of 6/8
+	AccentStrong               // The downbeat
+)
+
+// Meter groups the beats of a bar, which accents them
+type Meter struct {
+	Name   string
+	Groups []int // Beats per group; the bar's downbeat is strong and the other groups' first beats medium
+}
+
+// Built-in meters
+var (
+	Meter2_4 = Meter{Name: "2/4", Groups: []int{2}}
+	Meter3_4 = Meter{Name: "3/4", Groups: []int{3}}
+	Meter4_4 = Meter{Name: "4/4", Groups: []int{2, 2}}
+	Meter5_4 = Meter{Name: "5/4", Groups: []int{3, 2}}
+	Meter6_8 = Meter{Name: "6/8", Groups: []int{3, 3}}
+	Meter7_8 = Meter{Name: "7/8", Groups: []int{2, 2, 3}}
+)
+
+// Meters lists the built-in meters in the order the UI cycles through them
+var Meters = []Meter{Meter4_4, Meter3_4, Meter2_4, Meter6_8, Meter5_4, Meter7_8}
+
+// Beats returns the number of beats in a bar
+func (m Meter) Beats() int {
+	beats := 0
+	for _, group := range m.Groups {
+		beats += group
+	}
+	return beats
+}
+
+// Accent returns the accent of a beat, counting from the first downbeat
+func (m Meter) Accent(beat int) Accent {
+	beats := m.Beats()
+	if beats == 0 {
+		return AccentNone
+	}
+
+	position := ((beat % beats) + beats) % beats
+	if position == 0 {
+		return AccentStrong
+	}
+	for _, group := range m.Groups {
+		position -= group
+		if position <= 0 {
+			break
+		}
+	}
+	if position == 0 {
+		return AccentMedium
+	}
+	return AccentNone
+}
+
+// Metronome schedules beats at a steady tempo. Every beat's time is worked
+// out from a fixed anchor rather than from the previous beat, so scheduling
+// error doesn't build up however long it runs.
+type Metronome struct {
+	bpm   float64
+	meter Meter
+
+	anchor      time.Time // When beat anchorIndex falls
+	anchorIndex int
+}
+
+// NewMetronome creates a metronome at a tempo in beats per minute, clamped
+// to MinMetronomeBPM-MaxMetronomeBPM, with its first downbeat at the given
+// time
+func NewMetronome(bpm float64, meter Meter, start time.Time) *Metronome {
+	return &Metronome{bpm: clampBPM(bpm), meter: meter, anchor: start}
+}
+
+// clampBPM keeps a tempo within the metronome's limits
+func clampBPM(bpm float64) float64 {
+	return math.Max(MinMetronomeBPM, math.Min(bpm, MaxMetronomeBPM))
+}
+
+// BPM returns the tempo in beats per minute
+func (m *Metronome) BPM() float64 {
+	return m.bpm
+}
+
+// Meter returns the meter accenting the beats
+func (m *Metronome) Meter() Meter {
+	return m.meter
+}
+
+// Period returns the time between beats
+func (m *Metronome) Period() time.Duration {
+	return time.Duration(float64(time.Minute) / m.bpm)
+}
+
+// Beat returns when a beat falls
+func (m *Metronome) Beat(index int) time.Time {
+	return m.anchor.Add(time.Duration(float64(index-m.anchorIndex) * float64(time.Minute) / m.bpm))
+}
+
+// NextBeat returns the first beat after the given time and its index
+func (m *Metronome) NextBeat(after time.Time) (time.Time, int) {
+	beats := float64(after.Sub(m.anchor)) * m.bpm / float64(time.Minute)
+	index := m.anchorIndex + int(math.Floor(beats)) + 1
+
+	// Rounding may land a beat on or just before the given time
+	for !m.Beat(index).After(after) {
+		index++
+	}
+	return m.Beat(index), index
+}
+
+// NearestBeat returns the beat nearest the given time, and how far the time
+// is from it: negative ahead of the beat, positive behind it
+func (m *Metronome) NearestBeat(at time.Time) (index int, offset time.Duration) {
+	next, index := m.NextBeat(at)
+	previous := m.Beat(index - 1)
+	if at.Sub(previous) <= next.Sub(at) {
+		return index - 1, at.Sub(previous)
+	}
+	return index, at.Sub(next)
+}
+
+// Beats returns the times of the beats from one time up to another
+func (m *Metronome) Beats(from, to time.Time) []time.Time {
+	var beats []time.Time
+	beat, index := m.NextBeat(from.Add(-1))
+	for !beat.After(to) {
+		beats = append(beats, beat)
+		index++
+		beat = m.Beat(index)
+	}
+	return beats
+}
+
+// Accent returns the accent of a beat
+func (m *Metronome) Accent(index int) Accent {
+	return m.meter.Accent(index)
+}
+
+// SetBPM changes the tempo from the first beat after the given time on,
+// clamped to MinMetronomeBPM-MaxMetronomeBPM, keeping the beats counted
+// through the bar
+func (m *Metronome) SetBPM(bpm float64, at time.Time) {
+	m.anchor, m.anchorIndex = m.NextBeat(at)
+	m.bpm = clampBPM(bpm)
+}
+
+// SetMeter changes the meter, starting its bar on the first beat after the
+// given time
+func (m *Metronome) SetMeter(meter Meter, at time.Time) {
+	m.anchor, _ = m.NextBeat(at)
+	m.anchorIndex = 0
+	m.meter = meter
+}
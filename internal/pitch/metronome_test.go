@@ -0,0 +1,144 @@
+package pitch
+
+import (
+	"testing"
+	"time"
+)
+
+// tickBeats schedules beats from one time up to another the way the UI
+// does, each tick arriving a little late and asking for the beat after it,
+// and returns the beats it was handed
+func tickBeats(metronome *Metronome, from, to time.Time, latency time.Duration) []time.Time {
+	var beats []time.Time
+	beat, _ := metronome.NextBeat(from.Add(-1))
+	for !beat.After(to) {
+		beats = append(beats, beat)
+		beat, _ = metronome.NextBeat(beat.Add(latency))
+	}
+	return beats
+}
+
+func TestMetronomeDoesNotDrift(t *testing.T) {
+	start := time.Unix(0, 0)
+	for _, bpm := range []float64{60, 97, 120, 133.3, 300} {
+		metronome := NewMetronome(bpm, Meter4_4, start)
+		beats := tickBeats(metronome, start, start.Add(5*time.Minute), 7*time.Millisecond)
+
+		want := int(5*bpm) + 1
+		if len(beats) != want {
+			t.Errorf("%v BPM: %d beats in five minutes, want %d", bpm, len(beats), want)
+			continue
+		}
+		for i, beat := range beats {
+			ideal := start.Add(time.Duration(float64(i) * float64(time.Minute) / bpm))
+			if off := beat.Sub(ideal).Abs(); off > time.Microsecond {
+				t.Errorf("%v BPM: beat %d off by %v", bpm, i, off)
+				break
+			}
+		}
+	}
+}
+
+func TestMetronomeTempoChangeKeepsSchedule(t *testing.T) {
+	start := time.Unix(0, 0)
+	metronome := NewMetronome(100, Meter3_4, start)
+	metronome.SetBPM(150, start.Add(time.Minute+time.Millisecond))
+
+	// The change takes effect from the beat after it, 101 beats in
+	changed := start.Add(101 * 600 * time.Millisecond)
+	beats := tickBeats(metronome, changed, changed.Add(4*time.Minute), 11*time.Millisecond)
+	if len(beats) != 601 {
+		t.Fatalf("%d beats in four minutes at 150 BPM, want 601", len(beats))
+	}
+	for i, beat := range beats {
+		ideal := changed.Add(time.Duration(i) * 400 * time.Millisecond)
+		if off := beat.Sub(ideal).Abs(); off > time.Microsecond {
+			t.Fatalf("beat %d after the change off by %v", i, off)
+		}
+	}
+
+	// Counting through the bar carries on across the change
+	if _, index := metronome.NextBeat(changed.Add(-time.Nanosecond)); index != 101 {
+		t.Errorf("first beat at the new tempo is beat %d, want 101", index)
+	}
+}
+
+func TestMeterAccents(t *testing.T) {
+	const (
+		S = AccentStrong
+		M = AccentMedium
+		o = AccentNone
+	)
+	tests := []struct {
+		meter Meter
+		want  []Accent
+	}{
+		{Meter2_4, []Accent{S, o}},
+		{Meter3_4, []Accent{S, o, o}},
+		{Meter4_4, []Accent{S, o, M, o}},
+		{Meter5_4, []Accent{S, o, o, M, o}},
+		{Meter6_8, []Accent{S, o, o, M, o, o}},
+		{Meter7_8, []Accent{S, o, M, o, M, o, o}},
+	}
+	for _, test := range tests {
+		if beats := test.meter.Beats(); beats != len(test.want) {
+			t.Errorf("%s: %d beats, want %d", test.meter.Name, beats, len(test.want))
+			continue
+		}
+
+		// Three bars, and the bar before the first downbeat
+		for beat := -len(test.want); beat < 3*len(test.want); beat++ {
+			want := test.want[(beat+len(test.want))%len(test.want)]
+			if accent := test.meter.Accent(beat); accent != want {
+				t.Errorf("%s beat %d: accent %d, want %d", test.meter.Name, beat, accent, want)
+			}
+		}
+	}
+}
+
+func TestMetronomeMeterChangeStartsBar(t *testing.T) {
+	start := time.Unix(0, 0)
+	metronome := NewMetronome(120, Meter4_4, start)
+	metronome.SetMeter(Meter3_4, start.Add(2*time.Second+time.Millisecond))
+
+	// Beat 5 of the old bar becomes the new downbeat
+	beat, index := metronome.NextBeat(start.Add(2 * time.Second))
+	if !beat.Equal(start.Add(2500*time.Millisecond)) || metronome.Accent(index) != AccentStrong {
+		t.Errorf("beat at %v has accent %d, want the downbeat at 2.5s", beat.Sub(start), metronome.Accent(index))
+	}
+	for i, want := range []Accent{AccentNone, AccentNone, AccentStrong} {
+		if accent := metronome.Accent(index + 1 + i); accent != want {
+			t.Errorf("beat %d of the new bar: accent %d, want %d", i+2, accent, want)
+		}
+	}
+}
+
+func TestMetronomeNearestBeat(t *testing.T) {
+	start := time.Unix(0, 0)
+	metronome := NewMetronome(120, Meter4_4, start)
+	tests := []struct {
+		at     time.Duration
+		index  int
+		offset time.Duration
+	}{
+		{0, 0, 0},
+		{20 * time.Millisecond, 0, 20 * time.Millisecond},
+		{480 * time.Millisecond, 1, -20 * time.Millisecond},
+		{250 * time.Millisecond, 0, 250 * time.Millisecond},
+		{time.Minute + 5*time.Millisecond, 120, 5 * time.Millisecond},
+	}
+	for _, test := range tests {
+		index, offset := metronome.NearestBeat(start.Add(test.at))
+		if index != test.index || offset != test.offset {
+			t.Errorf("at %v: beat %d offset %v, want beat %d offset %v", test.at, index, offset, test.index, test.offset)
+		}
+	}
+}
+
+func TestMetronomeClampsTempo(t *testing.T) {
+	for _, test := range []struct{ bpm, want float64 }{{10, MinMetronomeBPM}, {120, 120}, {900, MaxMetronomeBPM}} {
+		if bpm := NewMetronome(test.bpm, Meter4_4, time.Time{}).BPM(); bpm != test.want {
+			t.Errorf("NewMetronome(%v): %v BPM, want %v", test.bpm, bpm, test.want)
+		}
+	}
+}
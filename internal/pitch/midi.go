@@ -0,0 +1,41 @@
+package pitch
+
+import "math"
+
+// MIDI note number limits
+const (
+	MinMIDINote = 0
+	MaxMIDINote = 127
+	midiNoteA4  = 69
+)
+
+// MIDIToFrequency returns the frequency of a MIDI note number for the given
+// A4 reference pitch. Note numbers outside 0-127 are clamped.
+func MIDIToFrequency(n int, a4 float64) float64 {
+	n = clampMIDINote(n)
+	return a4 * math.Pow(2, float64(n-midiNoteA4)/12)
+}
+
+// NoteFromMIDI returns the note for a MIDI note number at A4 = 440 Hz, named
+// with sharps. Note numbers outside 0-127 are clamped.
+func NoteFromMIDI(n int) *Note {
+	return NewNoteConverter().FromMIDI(n)
+}
+
+// FromMIDI returns the note for a MIDI note number using the converter's
+// reference pitch, transposition and naming. Note numbers outside 0-127 are
+// clamped.
+func (c *NoteConverter) FromMIDI(n int) *Note {
+	return c.FromFrequency(MIDIToFrequency(n, c.A4))
+}
+
+// clampMIDINote limits a note number to the MIDI range
+func clampMIDINote(n int) int {
+	if n < MinMIDINote {
+		return MinMIDINote
+	}
+	if n > MaxMIDINote {
+		return MaxMIDINote
+	}
+	return n
+}
@@ -0,0 +1,162 @@
+package pitch
+
+import (
+	"math"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+// MPMDetector implements the McLeod Pitch Method, which picks the period from
+// the normalized square difference function (NSDF) of the signal
+type MPMDetector struct {
+	minFrequency     float64 // Lowest frequency to detect (Hz)
+	maxFrequency     float64 // Highest frequency to detect (Hz)
+	volumeThreshold  float64 // Minimum RMS volume level for note detection
+	cutoff           float64 // Fraction of the highest key maximum a peak must reach (0.8-0.93)
+	clarityThreshold float64 // Minimum clarity (NSDF peak height) to accept a result
+
+	converter *NoteConverter // Converts detected frequencies into notes
+}
+
+// NewMPMDetector creates a new McLeod Pitch Method detector
+func NewMPMDetector() *MPMDetector {
+	return &MPMDetector{
+		converter:        NewNoteConverter(),
+		minFrequency:     80.0,   // E2 on guitar is ~82 Hz
+		maxFrequency:     1200.0, // Same range as the FFT detector
+		volumeThreshold:  0.005,  // Same silence handling as the FFT detector
+		cutoff:           0.93,   // Value recommended by McLeod & Wyvill
+		clarityThreshold: 0.6,    // Below this the signal is mostly noise
+	}
+}
+
+// SetConverter sets the converter used to turn frequencies into notes
+func (d *MPMDetector) SetConverter(converter *NoteConverter) {
+	d.converter = converter
+}
+
+// DetectPitch analyzes an audio buffer and returns the detected note
+func (d *MPMDetector) DetectPitch(buffer *audio.AudioBuffer) (*Note, error) {
+	note, _, err := d.DetectPitchWithClarity(buffer)
+	return note, err
+}
+
+// DetectPitchWithClarity analyzes an audio buffer and returns the detected
+// note along with its clarity (0.0-1.0), which serves as a confidence score
+func (d *MPMDetector) DetectPitchWithClarity(buffer *audio.AudioBuffer) (*Note, float64, error) {
+	if buffer == nil || len(buffer.Samples) == 0 {
+		return nil, 0, ErrEmptyBuffer
+	}
+
+	samples := buffer.Samples
+	n := len(samples)
+
+	// Skip silent frames
+	if math.Sqrt(autocorrelate(samples, 0)/float64(n)) < d.volumeThreshold {
+		return nil, 0, ErrVolumeThreshold
+	}
+
+	// Convert the frequency range into a lag range (in samples)
+	minLag := int(float64(buffer.SampleRate) / d.maxFrequency)
+	if minLag < 1 {
+		minLag = 1
+	}
+	maxLag := int(math.Ceil(float64(buffer.SampleRate) / d.minFrequency))
+	if maxLag > n-2 {
+		maxLag = n - 2
+	}
+	if minLag >= maxLag {
+		return nil, 0, ErrLowConfidence
+	}
+
+	nsdf := normalizedSquareDifference(samples, maxLag+1)
+
+	// Find the key maxima: the highest point between each positive-going
+	// and the following negative-going zero crossing
+	var keyMaxima []int
+	highest := 0.0
+	lag := 1
+	// Skip the zero-lag lobe
+	for lag <= maxLag && nsdf[lag] > 0 {
+		lag++
+	}
+	for lag <= maxLag {
+		// Wait for a positive-going zero crossing (NaN, from non-finite
+		// samples, counts as non-positive so the scan always advances)
+		for lag <= maxLag && !(nsdf[lag] > 0) {
+			lag++
+		}
+
+		// Track the highest point until the next negative-going zero crossing
+		best := -1
+		for lag <= maxLag && nsdf[lag] > 0 {
+			if lag >= minLag && (best < 0 || nsdf[lag] > nsdf[best]) {
+				best = lag
+			}
+			lag++
+		}
+
+		if best >= 0 {
+			keyMaxima = append(keyMaxima, best)
+			if nsdf[best] > highest {
+				highest = nsdf[best]
+			}
+		}
+	}
+
+	if len(keyMaxima) == 0 {
+		return nil, 0, ErrLowConfidence
+	}
+
+	// Choose the first key maximum that reaches the cutoff
+	chosen := keyMaxima[0]
+	for _, keyMax := range keyMaxima {
+		if nsdf[keyMax] >= highest*d.cutoff {
+			chosen = keyMax
+			break
+		}
+	}
+
+	// Refine the period and clarity with parabolic interpolation
+	prev, current, next := nsdf[chosen-1], nsdf[chosen], nsdf[chosen+1]
+	delta := parabolicOffset(prev, current, next)
+	period := float64(chosen) + delta
+	clarity := current - 0.25*(prev-next)*delta
+	clarity = math.Min(clarity, 1.0)
+
+	// Reject frames where the signal is not clearly periodic
+	if clarity < d.clarityThreshold {
+		return nil, clarity, ErrLowConfidence
+	}
+
+	frequency := float64(buffer.SampleRate) / period
+	if frequency < d.minFrequency || frequency > d.maxFrequency {
+		return nil, clarity, ErrOutOfRange
+	}
+
+	note := d.converter.FromFrequency(frequency)
+	if note == nil {
+		return nil, clarity, ErrNoPitchDetected
+	}
+	note.Confidence = clarity
+	return note, clarity, nil
+}
+
+// normalizedSquareDifference computes the NSDF for lags 0 through maxLag
+func normalizedSquareDifference(samples []float32, maxLag int) []float64 {
+	nsdf := make([]float64, maxLag+1)
+	for lag := 0; lag <= maxLag; lag++ {
+		acf := 0.0
+		energy := 0.0
+		for i := 0; i+lag < len(samples); i++ {
+			a := float64(samples[i])
+			b := float64(samples[i+lag])
+			acf += a * b
+			energy += a*a + b*b
+		}
+		if energy > 0 {
+			nsdf[lag] = 2 * acf / energy
+		}
+	}
+	return nsdf
+}
@@ -0,0 +1,116 @@
+package pitch
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+var _ Detector = (*MPMDetector)(nil)
+
+// guitarStrings are the open strings of a guitar in standard tuning
+var guitarStrings = []struct {
+	frequency float64
+	note      string
+}{
+	{82.41, "E2"},
+	{110, "A2"},
+	{146.83, "D3"},
+	{196, "G3"},
+	{246.94, "B3"},
+	{329.63, "E4"},
+}
+
+// addNoise adds white noise to a buffer at the given signal-to-noise ratio
+// in dB, seeded so the result is reproducible
+func addNoise(buffer *audio.AudioBuffer, snrDB float64, seed int64) *audio.AudioBuffer {
+	power := 0.0
+	for _, sample := range buffer.Samples {
+		power += float64(sample) * float64(sample)
+	}
+	sigma := math.Sqrt(power / float64(len(buffer.Samples)) / math.Pow(10, snrDB/10))
+
+	rng := rand.New(rand.NewSource(seed))
+	for i := range buffer.Samples {
+		buffer.Samples[i] += float32(rng.NormFloat64() * sigma)
+	}
+	return buffer
+}
+
+// whiteNoise returns a buffer of white noise at the given RMS level, seeded
+// so the result is reproducible
+func whiteNoise(level float64, samples, sampleRate int, seed int64) *audio.AudioBuffer {
+	buffer := &audio.AudioBuffer{Samples: make([]float32, samples), SampleRate: sampleRate}
+	rng := rand.New(rand.NewSource(seed))
+	for i := range buffer.Samples {
+		buffer.Samples[i] = float32(level * rng.NormFloat64())
+	}
+	return buffer
+}
+
+func TestMPMDetectorGuitarStrings(t *testing.T) {
+	tests := []struct {
+		snrDB      float64
+		maxCents   float64 // Largest error allowed
+		minClarity float64
+	}{
+		{math.Inf(1), 0.5, 0.99},
+		{30, 1, 0.99},
+		{20, 2, 0.95},
+		{10, 10, 0.85},
+	}
+	detector := NewMPMDetector()
+	for _, tt := range tests {
+		for i, str := range guitarStrings {
+			t.Run(fmt.Sprintf("%s at %v dB", str.note, tt.snrDB), func(t *testing.T) {
+				buffer := audio.SynthesizeTone(str.frequency, guitarTone, 4096, 44100)
+				if !math.IsInf(tt.snrDB, 1) {
+					addNoise(buffer, tt.snrDB, int64(i))
+				}
+
+				note, clarity, err := detector.DetectPitchWithClarity(buffer)
+				if err != nil {
+					t.Fatalf("%v (clarity %.2f)", err, clarity)
+				}
+				if got := fmt.Sprintf("%s%d", note.Name, note.Octave); got != str.note {
+					t.Errorf("read as %s", got)
+				}
+				if cents := 1200 * math.Log2(note.Frequency/str.frequency); math.Abs(cents) > tt.maxCents {
+					t.Errorf("read %.2f Hz, %+.1f cents off", note.Frequency, cents)
+				}
+				if clarity < tt.minClarity {
+					t.Errorf("clarity %.3f, want at least %v", clarity, tt.minClarity)
+				}
+				if note.Confidence != clarity {
+					t.Errorf("confidence %.3f differs from clarity %.3f", note.Confidence, clarity)
+				}
+			})
+		}
+	}
+}
+
+func TestMPMDetectorClarityFallsWithNoise(t *testing.T) {
+	detector := NewMPMDetector()
+	previous := 1.0
+	for _, snrDB := range []float64{40, 20, 10, 5} {
+		buffer := addNoise(audio.SynthesizeTone(110, guitarTone, 4096, 44100), snrDB, 1)
+		_, clarity, err := detector.DetectPitchWithClarity(buffer)
+		if err != nil {
+			t.Fatalf("at %v dB: %v", snrDB, err)
+		}
+		if clarity >= previous {
+			t.Errorf("clarity %.3f at %v dB isn't below %.3f at a better SNR", clarity, snrDB, previous)
+		}
+		previous = clarity
+	}
+}
+
+func TestMPMDetectorRejectsNoise(t *testing.T) {
+	noise := whiteNoise(0.1, 4096, 44100, 7)
+	if note, clarity, err := NewMPMDetector().DetectPitchWithClarity(noise); err == nil {
+		t.Errorf("detected %s%d with clarity %.2f in white noise", note.Name, note.Octave, clarity)
+	}
+}
@@ -0,0 +1,88 @@
+package pitch
+
+// NamingScheme selects the syllables or letters used for note names
+type NamingScheme int
+
+// Naming schemes
+const (
+	NamingLetters   NamingScheme = iota // C, D, E, ...
+	NamingFixedDo                       // Do is always C: Do, Ré, Mi, ...
+	NamingMovableDo                     // Do is the tonic of the key signature
+)
+
+// NamingSchemes lists the schemes in the order the UI cycles through them
+var NamingSchemes = []NamingScheme{NamingLetters, NamingFixedDo, NamingMovableDo}
+
+// String returns the display name of the naming scheme
+func (n NamingScheme) String() string {
+	switch n {
+	case NamingFixedDo:
+		return "Fixed do"
+	case NamingMovableDo:
+		return "Movable do"
+	default:
+		return "Letters"
+	}
+}
+
+// Fixed-do syllables in chromatic order, starting from C
+var (
+	fixedDoSharpNames = []string{"Do", "Do#", "Ré", "Ré#", "Mi", "Fa", "Fa#", "Sol", "Sol#", "La", "La#", "Si"}
+	fixedDoFlatNames  = []string{"Do", "Réb", "Ré", "Mib", "Mi", "Fa", "Solb", "Sol", "Lab", "La", "Sib", "Si"}
+)
+
+// Movable-do chromatic syllables in order, starting from the tonic
+var (
+	movableDoSharpNames = []string{"Do", "Di", "Re", "Ri", "Mi", "Fa", "Fi", "Sol", "Si", "La", "Li", "Ti"}
+	movableDoFlatNames  = []string{"Do", "Ra", "Re", "Me", "Mi", "Fa", "Se", "Sol", "Le", "La", "Te", "Ti"}
+)
+
+// SetNaming sets the naming scheme used for note names
+func (c *NoteConverter) SetNaming(naming NamingScheme) {
+	c.Naming = naming
+}
+
+// KeySignatureTonic returns the pitch class (0 = C) of the major key with the
+// given number of sharps (positive) or flats (negative)
+func KeySignatureTonic(fifths int) int {
+	// Each sharp moves the tonic up a fifth (7 semitones)
+	return ((fifths*7)%12 + 12) % 12
+}
+
+// PitchClassName returns the letter name of a pitch class (0 = C), spelled with sharps
+func PitchClassName(pitchClass int) string {
+	return noteNames[((pitchClass%12)+12)%12]
+}
+
+// Rename returns the note named from its pitch classes in the current naming
+// scheme and spelling, e.g. for a note converted before either changed
+func (c *NoteConverter) Rename(note Note) Note {
+	note.Name = c.noteName(note.PitchClass)
+	note.ConcertName = c.noteName(note.ConcertPitchClass)
+	return note
+}
+
+// noteName returns the name of a pitch class (0 = C) in the current naming
+// scheme and spelling
+func (c *NoteConverter) noteName(pitchClass int) string {
+	flats := c.usesFlats()
+
+	switch c.Naming {
+	case NamingFixedDo:
+		if flats {
+			return fixedDoFlatNames[pitchClass]
+		}
+		return fixedDoSharpNames[pitchClass]
+	case NamingMovableDo:
+		degree := (pitchClass - KeySignatureTonic(c.KeySignature) + 12) % 12
+		if flats {
+			return movableDoFlatNames[degree]
+		}
+		return movableDoSharpNames[degree]
+	default:
+		if flats {
+			return flatNoteNames[pitchClass]
+		}
+		return noteNames[pitchClass]
+	}
+}
@@ -0,0 +1,73 @@
+package pitch
+
+import (
+	"math"
+	"math/cmplx"
+	"slices"
+)
+
+// Weight of each new frame in the noise estimate when the noise gets quieter
+const noiseFallRate = 0.5
+
+// Peak magnitude taken for digital silence while there is no noise estimate
+const silentMagnitude = 1e-9
+
+// WithNoiseMargin sets how far, in dB, the strongest peak must rise above the
+// adaptive noise estimate for a frame to be analyzed
+func WithNoiseMargin(dB float64) Option {
+	return func(d *FFTDetector) error {
+		if dB < 0 {
+			return ErrInvalidNoiseMargin
+		}
+
+		d.noiseMargin = dB
+		return nil
+	}
+}
+
+// NoiseEstimate returns the current estimate of the noise level: the median
+// spectral magnitude in the detection range, smoothed across recent frames.
+// It is 0 until the first frame has been analyzed.
+func (d *FFTDetector) NoiseEstimate() float64 {
+	return d.noiseEstimate
+}
+
+// ResetNoiseEstimate discards the noise estimate so it is rebuilt from the
+// next frame, e.g. after switching inputs or changing the input gain
+func (d *FFTDetector) ResetNoiseEstimate() {
+	d.noiseEstimate = 0
+}
+
+// updateNoiseEstimate folds the median magnitude of the frame's detection
+// range into the noise estimate and returns the level peaks must exceed. A
+// tone only occupies a few bins, so the median follows the noise even while
+// a note is sounding.
+func (d *FFTDetector) updateNoiseEstimate(spectrum []complex128, minBin, maxBin int) float64 {
+	if maxBin >= minBin {
+		magnitudes := d.noiseScratch[:0]
+		for i := minBin; i <= maxBin; i++ {
+			magnitudes = append(magnitudes, cmplx.Abs(spectrum[i]))
+		}
+		slices.Sort(magnitudes)
+		median := magnitudes[len(magnitudes)/2]
+		d.noiseScratch = magnitudes
+
+		// Rise slowly so a burst doesn't mask the notes after it, but fall
+		// quickly so quiet passages aren't held to a stale, louder floor
+		switch {
+		case d.noiseEstimate == 0:
+			d.noiseEstimate = median
+		case median > d.noiseEstimate:
+			d.noiseEstimate += d.noiseSmoothing * (median - d.noiseEstimate)
+		default:
+			d.noiseEstimate += noiseFallRate * (median - d.noiseEstimate)
+		}
+	}
+
+	// Without noise to measure against, as in digital silence, only reject
+	// peaks that are themselves silence
+	if d.noiseEstimate == 0 {
+		return silentMagnitude
+	}
+	return d.noiseEstimate * math.Pow(10, d.noiseMargin/20)
+}
@@ -0,0 +1,111 @@
+package pitch
+
+import (
+	"math"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+// flatSpectrum returns a spectrum of bins all the given magnitude
+func flatSpectrum(magnitude float64) []complex128 {
+	spectrum := make([]complex128, 64)
+	for i := range spectrum {
+		spectrum[i] = complex(magnitude, 0)
+	}
+	return spectrum
+}
+
+func TestNoiseThresholdFollowsEstimate(t *testing.T) {
+	// However quiet the noise, peaks need only clear it by the margin
+	for _, noise := range []float64{1e-6, 1e-3, 0.5, 20} {
+		detector, err := NewFFTDetector(4096)
+		if err != nil {
+			t.Fatal(err)
+		}
+		threshold := detector.updateNoiseEstimate(flatSpectrum(noise), 1, 63)
+		if want := noise * math.Pow(10, detector.noiseMargin/20); math.Abs(threshold-want) > want*1e-9 {
+			t.Errorf("threshold over noise at %v is %v, want %v", noise, threshold, want)
+		}
+	}
+}
+
+func TestNoiseThresholdInSilence(t *testing.T) {
+	detector, err := NewFFTDetector(4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if threshold := detector.updateNoiseEstimate(flatSpectrum(0), 1, 63); threshold != silentMagnitude {
+			t.Fatalf("threshold in frame %d of digital silence is %v, want %v", i+1, threshold, silentMagnitude)
+		}
+	}
+}
+
+// scaled returns a copy of a buffer with its gain changed by the given dB
+func scaled(buffer *audio.AudioBuffer, dB float64) *audio.AudioBuffer {
+	gain := float32(math.Pow(10, dB/20))
+	out := &audio.AudioBuffer{Samples: make([]float32, len(buffer.Samples)), SampleRate: buffer.SampleRate}
+	for i, sample := range buffer.Samples {
+		out.Samples[i] = sample * gain
+	}
+	return out
+}
+
+func TestDetectionSurvivesGainChange(t *testing.T) {
+	// The same noisy A3 played 20 dB quieter, then back at full level, with
+	// the detector left as it was configured
+	tone := addNoise(audio.SynthesizeTone(220, guitarTone, 4096, 44100), 30, 1)
+	for _, change := range []struct {
+		name     string
+		from, to float64
+	}{
+		{"quieter", 0, -20},
+		{"louder", -20, 0},
+	} {
+		t.Run(change.name, func(t *testing.T) {
+			detector, err := NewFFTDetector(4096)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, dB := range []float64{change.from, change.from, change.from, change.to, change.to, change.to} {
+				note, err := detector.DetectPitch(scaled(tone, dB))
+				if err != nil {
+					t.Fatalf("frame %d at %v dB: %v", i+1, dB, err)
+				}
+				if note.Name != "A" || note.Octave != 3 {
+					t.Errorf("frame %d at %v dB read as %s%d", i+1, dB, note.Name, note.Octave)
+				}
+			}
+		})
+	}
+}
+
+func TestResetNoiseEstimate(t *testing.T) {
+	detector, err := NewFFTDetector(4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detector.NoiseEstimate() != 0 {
+		t.Fatal("noise estimate before the first frame")
+	}
+
+	tone := addNoise(audio.SynthesizeTone(220, guitarTone, 4096, 44100), 30, 1)
+	for i := 0; i < 5; i++ {
+		detector.DetectPitch(tone)
+	}
+	loud := detector.NoiseEstimate()
+	if loud <= 0 {
+		t.Fatal("no noise estimate after analyzing frames")
+	}
+
+	// Rebuilt from the next frame alone, rather than falling from the old one
+	detector.ResetNoiseEstimate()
+	if detector.NoiseEstimate() != 0 {
+		t.Fatal("estimate kept after a reset")
+	}
+	detector.DetectPitch(scaled(tone, -20))
+	if ratio := detector.NoiseEstimate() / loud; math.Abs(ratio-0.1) > 0.02 {
+		t.Errorf("estimate after a reset is %.3f of the old one, want 0.1 for a frame 20 dB quieter", ratio)
+	}
+}
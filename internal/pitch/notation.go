@@ -0,0 +1,101 @@
+package pitch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Notation selects how a note and its octave are written for display
+type Notation int
+
+// Notations
+const (
+	NotationScientific Notation = iota // C4, C#4, Db4
+	NotationHelmholtz                  // C, c, c′, c″: case and marks give the octave
+	NotationGerman                     // H for B, B for B♭, Cis/Des for the accidentals
+)
+
+// Notations lists the notations in the order the UI cycles through them
+var Notations = []Notation{NotationScientific, NotationHelmholtz, NotationGerman}
+
+// String returns the display name of the notation
+func (n Notation) String() string {
+	switch n {
+	case NotationHelmholtz:
+		return "Helmholtz"
+	case NotationGerman:
+		return "German"
+	default:
+		return "Scientific"
+	}
+}
+
+// German note names in chromatic order, starting from C
+var (
+	germanSharpNames = []string{"C", "Cis", "D", "Dis", "E", "F", "Fis", "G", "Gis", "A", "Ais", "H"}
+	germanFlatNames  = []string{"C", "Des", "D", "Es", "E", "F", "Ges", "G", "As", "A", "B", "H"}
+)
+
+// Helmholtz octave marks: primes above the small octave (octave 3), commas
+// below the great octave (octave 2)
+const (
+	helmholtzPrimes = "′″‴⁗"
+	helmholtzComma  = "͵"
+)
+
+// Name returns the note's name in the notation without any octave number.
+// Helmholtz names carry their octave in their case and marks.
+func (n Notation) Name(note *Note) string {
+	switch n {
+	case NotationHelmholtz:
+		return n.Format(note)
+	case NotationGerman:
+		if note.Name == "" {
+			return note.Name
+		}
+		// Follow the spelling of the note's own name, so key-aware spelling
+		// carries over
+		if strings.ContainsAny(note.Name[1:], "b♭") {
+			return germanFlatNames[note.PitchClass]
+		}
+		return germanSharpNames[note.PitchClass]
+	default:
+		return note.Name
+	}
+}
+
+// Format returns the note's name with its octave in the notation, e.g.
+// "C#4", "c#′" or "Cis4"
+func (n Notation) Format(note *Note) string {
+	switch n {
+	case NotationHelmholtz:
+		return helmholtzName(note.Name, note.Octave)
+	case NotationGerman:
+		return fmt.Sprintf("%s%d", n.Name(note), note.Octave)
+	default:
+		return fmt.Sprintf("%s%d", note.Name, note.Octave)
+	}
+}
+
+// helmholtzName writes a name in Helmholtz notation: upper case with commas
+// from the great octave (2) down, lower case with primes from the small
+// octave (3) up. An empty name stays empty.
+func helmholtzName(name string, octave int) string {
+	if len(name) == 0 {
+		return name
+	}
+	if octave <= 2 {
+		return strings.ToUpper(name[:1]) + name[1:] + strings.Repeat(helmholtzComma, 2-octave)
+	}
+
+	name = strings.ToLower(name[:1]) + name[1:]
+	primes := []rune(helmholtzPrimes)
+	switch marks := octave - 3; {
+	case marks == 0:
+		return name
+	case marks <= len(primes):
+		return name + string(primes[marks-1])
+	default:
+		return name + strings.Repeat(string(primes[0]), marks)
+	}
+}
@@ -0,0 +1,52 @@
+package pitch
+
+import "testing"
+
+func TestNotationFormat(t *testing.T) {
+	converter := NewNoteConverter()
+	tests := []struct {
+		frequency  float64
+		flats      bool
+		scientific string
+		helmholtz  string
+		german     string
+	}{
+		{32.7, false, "C1", "C͵", "C1"},
+		{65.41, false, "C2", "C", "C2"},
+		{130.81, false, "C3", "c", "C3"},
+		{277.18, false, "C#4", "c#′", "Cis4"},
+		{277.18, true, "Db4", "db′", "Des4"},
+		{233.08, true, "Bb3", "bb", "B3"},
+		{246.94, false, "B3", "b", "H3"},
+		{1046.5, false, "C6", "c‴", "C6"},
+	}
+	for _, tt := range tests {
+		converter.SetSpelling(SpellingSharps)
+		if tt.flats {
+			converter.SetSpelling(SpellingFlats)
+		}
+		note := converter.FromFrequency(tt.frequency)
+		for notation, want := range map[Notation]string{
+			NotationScientific: tt.scientific,
+			NotationHelmholtz:  tt.helmholtz,
+			NotationGerman:     tt.german,
+		} {
+			if got := notation.Format(note); got != want {
+				t.Errorf("%v writes %v Hz as %q, want %q", notation, tt.frequency, got, want)
+			}
+		}
+	}
+}
+
+func TestNotationEmptyName(t *testing.T) {
+	for _, notation := range Notations {
+		if name := notation.Name(&Note{Octave: 4}); name != "" {
+			t.Errorf("%v names a note without a name %q", notation, name)
+		}
+	}
+	for _, octave := range []int{1, 3, 5} {
+		if name := helmholtzName("", octave); name != "" {
+			t.Errorf("helmholtzName(%q, %d) = %q", "", octave, name)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package pitch
+
+import (
+	"math/cmplx"
+	"slices"
+	"time"
+)
+
+// Onset marks the start of a new note
+type Onset struct {
+	Time     time.Time // When the frame containing the onset was analyzed
+	Strength float64   // Spectral flux of the frame (0.0-1.0)
+}
+
+// OnsetDetector finds note onsets using spectral flux: the summed increase in
+// magnitude of every bin since the previous frame, relative to the frame's
+// total magnitude. A frame is an onset when its flux rises above an adaptive
+// threshold derived from the median of recent frames. It works on spectra
+// from FFTDetector.Spectrum, so one FFT feeds both onset and pitch detection.
+type OnsetDetector struct {
+	historySize int           // Number of recent flux values used for the threshold
+	factor      float64       // Multiple of the median flux an onset must exceed
+	minFlux     float64       // Flux an onset must exceed regardless of the median
+	minInterval time.Duration // Minimum time between two onsets
+
+	previous  []float64 // Magnitude spectrum of the previous frame
+	history   []float64 // Recent flux values, oldest first
+	sorted    []float64 // Scratch buffer for the median
+	lastOnset time.Time // Time of the most recent onset
+}
+
+// NewOnsetDetector creates a new spectral-flux onset detector
+func NewOnsetDetector() *OnsetDetector {
+	return &OnsetDetector{
+		historySize: 10,                     // ~500ms of frames at the processing loop's rate
+		factor:      1.5,                    // Flux must clearly stand out from recent frames
+		minFlux:     0.3,                    // A note must add 30% of the frame's energy
+		minInterval: 100 * time.Millisecond, // The attack keeps entering the window for a few frames
+		history:     make([]float64, 0, 10),
+		sorted:      make([]float64, 0, 10),
+	}
+}
+
+// Process computes the spectral flux of a frame and reports whether the
+// frame contains an onset. Frames are expected in order; after a gap (silence)
+// call Reset so the next sound is measured against silence.
+func (o *OnsetDetector) Process(spectrum []complex128, at time.Time) (Onset, bool) {
+	if len(o.previous) != len(spectrum) {
+		// No comparable previous frame: measure against silence
+		o.previous = make([]float64, len(spectrum))
+	}
+
+	// Sum the magnitude increases, storing this frame for the next call
+	rising := 0.0
+	total := 0.0
+	for i, bin := range spectrum {
+		magnitude := cmplx.Abs(bin)
+		if increase := magnitude - o.previous[i]; increase > 0 {
+			rising += increase
+		}
+		total += magnitude
+		o.previous[i] = magnitude
+	}
+
+	flux := 0.0
+	if total > 0 {
+		flux = rising / total
+	}
+
+	// Compare against the threshold from the frames before this one
+	threshold := max(o.median()*o.factor, o.minFlux)
+	o.addHistory(flux)
+
+	if flux <= threshold {
+		return Onset{}, false
+	}
+	if !o.lastOnset.IsZero() && at.Sub(o.lastOnset) < o.minInterval {
+		return Onset{}, false
+	}
+
+	o.lastOnset = at
+	return Onset{Time: at, Strength: flux}, true
+}
+
+// Reset clears the detector state, e.g. when the sound stops
+func (o *OnsetDetector) Reset() {
+	for i := range o.previous {
+		o.previous[i] = 0
+	}
+	o.history = o.history[:0]
+	o.lastOnset = time.Time{}
+}
+
+// addHistory appends a flux value, dropping the oldest one when full
+func (o *OnsetDetector) addHistory(flux float64) {
+	if len(o.history) == o.historySize {
+		copy(o.history, o.history[1:])
+		o.history = o.history[:len(o.history)-1]
+	}
+	o.history = append(o.history, flux)
+}
+
+// median returns the median of the recent flux values, or 0 without history
+func (o *OnsetDetector) median() float64 {
+	if len(o.history) == 0 {
+		return 0
+	}
+
+	o.sorted = append(o.sorted[:0], o.history...)
+	slices.Sort(o.sorted)
+	return o.sorted[len(o.sorted)/2]
+}
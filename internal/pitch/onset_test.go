@@ -0,0 +1,94 @@
+package pitch
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+// Framing of the onset tests: a 4096-sample window moved on by half of it
+const (
+	onsetWindow = 4096
+	onsetHop    = 2048
+)
+
+// pluck adds a guitar-like tone decaying over tau, starting at a sample
+// offset, to a buffer
+func pluck(buffer *audio.AudioBuffer, frequency float64, offset int, tau time.Duration) {
+	tone := audio.SynthesizeTone(frequency, guitarTone, len(buffer.Samples)-offset, buffer.SampleRate)
+	for i, sample := range tone.Samples {
+		decay := math.Exp(-float64(i) / float64(buffer.SampleRate) / tau.Seconds())
+		buffer.Samples[offset+i] += sample * float32(decay)
+	}
+}
+
+// detectOnsets runs a buffer through the onset detector frame by frame and
+// returns the sample offsets of the centers of the frames in which onsets
+// were found, the instant a Hann-windowed spectrum stands for. The first
+// frame is measured against silence and always rises, so it only primes the
+// detector.
+func detectOnsets(t *testing.T, buffer *audio.AudioBuffer) []int {
+	t.Helper()
+	detector, err := NewFFTDetector(onsetWindow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	onsets := NewOnsetDetector()
+	start := time.Unix(0, 0)
+
+	var found []int
+	for end := onsetWindow; end <= len(buffer.Samples); end += onsetHop {
+		frame := &audio.AudioBuffer{Samples: buffer.Samples[end-onsetWindow : end], SampleRate: buffer.SampleRate}
+		spectrum, err := detector.Spectrum(frame)
+		if err != nil {
+			t.Fatalf("frame ending at %d: %v", end, err)
+		}
+		at := start.Add(time.Duration(end) * time.Second / time.Duration(buffer.SampleRate))
+		if _, ok := onsets.Process(spectrum, at); ok && end > onsetWindow {
+			found = append(found, end-onsetWindow/2)
+		}
+	}
+	return found
+}
+
+func TestOnsetsOfPlucks(t *testing.T) {
+	for _, sampleRate := range []int{44100, 48000} {
+		// Three plucks over a quiet noise floor, so every frame has a spectrum
+		buffer := whiteNoise(0.01, 5*sampleRate, sampleRate, 1)
+		plucks := []struct {
+			frequency float64
+			offset    int
+		}{
+			{196, sampleRate},
+			{261.63, sampleRate * 23 / 10},
+			{329.63, sampleRate * 37 / 10},
+		}
+		for _, p := range plucks {
+			pluck(buffer, p.frequency, p.offset, 400*time.Millisecond)
+		}
+
+		found := detectOnsets(t, buffer)
+		if len(found) != len(plucks) {
+			t.Fatalf("%d Hz: found onsets at samples %v, want one for each pluck at %v", sampleRate, found, plucks)
+		}
+		for i, p := range plucks {
+			if off := found[i] - p.offset; off < -onsetHop || off > onsetHop {
+				t.Errorf("%d Hz: pluck at sample %d detected in the frame centered on %d, want within one hop",
+					sampleRate, p.offset, found[i])
+			}
+		}
+	}
+}
+
+func TestSteadyToneNoOnsets(t *testing.T) {
+	for _, frequency := range []float64{82.41, 196, 440, 987.77} {
+		for _, overtones := range [][]float64{{1}, guitarTone} {
+			buffer := audio.SynthesizeTone(frequency, overtones, 3*44100, 44100)
+			if found := detectOnsets(t, buffer); len(found) > 0 {
+				t.Errorf("steady %v Hz tone with %d overtones re-triggered at samples %v", frequency, len(overtones), found)
+			}
+		}
+	}
+}
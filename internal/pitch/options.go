@@ -0,0 +1,105 @@
+package pitch
+
+import "fmt"
+
+// Option configures an FFTDetector at construction time
+type Option func(*FFTDetector) error
+
+// WithFrequencyRange sets the lowest and highest frequencies to detect (Hz).
+// The lowest may go down to MinDetectableFrequency; use RequiredWindowSize to
+// pick a window long enough for it, and ValidateConfig to check the result.
+func WithFrequencyRange(min, max float64) Option {
+	return func(d *FFTDetector) error {
+		if min < MinDetectableFrequency || max <= min {
+			return ErrInvalidFrequencyRange
+		}
+
+		d.minFrequency = min
+		d.maxFrequency = max
+		return nil
+	}
+}
+
+// WithPeakThreshold sets the minimum peak height as a fraction of the highest peak
+func WithPeakThreshold(threshold float64) Option {
+	return func(d *FFTDetector) error {
+		if threshold < 0 || threshold > 1 {
+			return ErrInvalidThreshold
+		}
+
+		d.peakThreshold = threshold
+		return nil
+	}
+}
+
+// WithVolumeThreshold sets the minimum RMS volume level for note detection
+func WithVolumeThreshold(threshold float64) Option {
+	return func(d *FFTDetector) error {
+		if threshold < 0 || threshold > 1 {
+			return ErrInvalidThreshold
+		}
+
+		d.volumeThreshold = threshold
+		return nil
+	}
+}
+
+// WindowSize returns the number of samples analyzed per frame
+func (d *FFTDetector) WindowSize() int {
+	return d.windowSize
+}
+
+// MinFrequency returns the lowest frequency the detector reports (Hz)
+func (d *FFTDetector) MinFrequency() float64 {
+	return d.minFrequency
+}
+
+// MaxFrequency returns the highest frequency the detector reports (Hz)
+func (d *FFTDetector) MaxFrequency() float64 {
+	return d.maxFrequency
+}
+
+// PeakThreshold returns the minimum peak height as a fraction of the highest peak
+func (d *FFTDetector) PeakThreshold() float64 {
+	return d.peakThreshold
+}
+
+// VolumeThreshold returns the minimum RMS volume level for note detection
+func (d *FFTDetector) VolumeThreshold() float64 {
+	return d.volumeThreshold
+}
+
+// ValidateConfig checks that the detector can work on audio at the given
+// sample rate: the frequency range must be ordered, its top must lie below
+// the Nyquist frequency, and each analyzed frame must hold at least four
+// cycles of its bottom. Call it at startup so misconfiguration fails fast.
+func (d *FFTDetector) ValidateConfig(sampleRate int) error {
+	if sampleRate <= 0 {
+		return ErrInvalidSampleRate
+	}
+	if d.maxFrequency <= d.minFrequency {
+		return fmt.Errorf("%w: highest frequency %g Hz is not above lowest %g Hz",
+			ErrInvalidFrequencyRange, d.maxFrequency, d.minFrequency)
+	}
+
+	nyquist := float64(sampleRate) / 2
+	if d.maxFrequency > nyquist {
+		return fmt.Errorf("%w: %g Hz exceeds %g Hz at %d Hz sampling",
+			ErrAboveNyquist, d.maxFrequency, nyquist, sampleRate)
+	}
+
+	// Averaging analyzes sub-frames rather than the whole window
+	frameLength := d.windowSize
+	if d.averages(frameLength) {
+		frameLength = d.subFrame
+	}
+
+	cycles := float64(frameLength) * d.minFrequency / float64(sampleRate)
+	if cycles < minWindowCycles {
+		return fmt.Errorf("%w: %d samples at %d Hz hold %.1f cycles of %g Hz, a window of at least %d samples is needed",
+			ErrWindowTooShort, frameLength, sampleRate, cycles, d.minFrequency,
+			windowSizeFor(minWindowCycles, d.minFrequency, sampleRate))
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package pitch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithFrequencyRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max float64
+		want     error
+	}{
+		{"below the detectable minimum", MinDetectableFrequency - 1, 1200, ErrInvalidFrequencyRange},
+		{"empty", 200, 200, ErrInvalidFrequencyRange},
+		{"reversed", 1200, 80, ErrInvalidFrequencyRange},
+		{"at the detectable minimum", MinDetectableFrequency, 400, nil},
+	}
+	for _, tt := range tests {
+		if _, err := NewFFTDetector(8192, WithFrequencyRange(tt.min, tt.max)); !errors.Is(err, tt.want) {
+			t.Errorf("%s (%v-%v Hz): got %v, want %v", tt.name, tt.min, tt.max, err, tt.want)
+		}
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		windowSize int
+		opts       []Option
+		sampleRate int
+		want       error
+		message    string // Part of the error text, if any
+	}{
+		{"no sample rate", 4096, nil, 0, ErrInvalidSampleRate, ""},
+		{"above Nyquist", 4096, []Option{WithFrequencyRange(80, 30000)}, 44100, ErrAboveNyquist, "22050 Hz"},
+		{"at Nyquist", 4096, []Option{WithFrequencyRange(80, 22050)}, 44100, nil, ""},
+		{"window too short", 2048, []Option{WithFrequencyRange(50, 1200)}, 44100, ErrWindowTooShort, "at least 4096 samples"},
+		{"four cycles exactly", 4096, []Option{WithFrequencyRange(44100*4/4096.0, 1200)}, 44100, nil, ""},
+		{"sub-frames too short", 8192, []Option{WithFrequencyRange(50, 1200), WithSpectrumAveraging(2048)}, 44100, ErrWindowTooShort, "2048 samples"},
+		{"defaults", 4096, nil, 48000, nil, ""},
+	}
+	for _, tt := range tests {
+		detector, err := NewFFTDetector(tt.windowSize, tt.opts...)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		err = detector.ValidateConfig(tt.sampleRate)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+			continue
+		}
+		if err != nil && !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("%s: %q doesn't mention %q", tt.name, err, tt.message)
+		}
+	}
+
+	// The options can't build a reversed range, but a preset applied later
+	// could leave one behind
+	detector, err := NewFFTDetector(4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	detector.minFrequency, detector.maxFrequency = 400, 300
+	if err := detector.ValidateConfig(44100); !errors.Is(err, ErrInvalidFrequencyRange) {
+		t.Errorf("reversed range: got %v, want %v", err, ErrInvalidFrequencyRange)
+	}
+}
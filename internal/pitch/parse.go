@@ -0,0 +1,88 @@
+package pitch
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Pitch classes of the natural note letters
+var letterPitchClasses = map[byte]int{'C': 0, 'D': 2, 'E': 4, 'F': 5, 'G': 7, 'A': 9, 'B': 11}
+
+// NoteToFrequency returns the equal-tempered frequency of a note, e.g. "C#"
+// in octave 4, for the given A4 reference pitch. Names are a letter followed
+// by any number of sharps (#, ♯) or flats (b, ♭). The octave goes with the
+// letter, so "Cb" 4 is the B just below C4 and "B#" 3 is C4.
+func NoteToFrequency(name string, octave int, a4 float64) (float64, error) {
+	if a4 <= 0 {
+		return 0, ErrInvalidReferencePitch
+	}
+
+	pitchClass, accidentals, err := parseNoteName(name)
+	if err != nil {
+		return 0, err
+	}
+
+	midiNote := (octave+1)*12 + pitchClass + accidentals
+	return a4 * math.Pow(2, float64(midiNote-midiNoteA4)/12), nil
+}
+
+// ParseNote parses a note with its octave, e.g. "C#4", "Eb2" or "A-1", and
+// returns it at A4 = 440 Hz. Flat names are returned spelled with flats and
+// everything else with sharps, so the canonical names from FromFrequency
+// round-trip; other spellings (e.g. "B#3", "Fb4") come back as their
+// enharmonic equivalent.
+func ParseNote(text string) (*Note, error) {
+	// Split the name from the (possibly negative) octave number
+	split := strings.IndexAny(text, "-0123456789")
+	if split <= 0 {
+		return nil, ErrInvalidNoteName
+	}
+	name := text[:split]
+	octave, err := strconv.Atoi(text[split:])
+	if err != nil {
+		return nil, ErrInvalidNoteName
+	}
+
+	frequency, err := NoteToFrequency(name, octave, DefaultReferenceA4)
+	if err != nil {
+		return nil, err
+	}
+
+	converter := NewNoteConverter()
+	if _, accidentals, _ := parseNoteName(name); accidentals < 0 {
+		converter.SetSpelling(SpellingFlats)
+	}
+	note := converter.FromFrequency(frequency)
+	if note == nil {
+		return nil, ErrInvalidNoteName
+	}
+	return note, nil
+}
+
+// parseNoteName returns the pitch class of a note name's letter and the
+// semitones its accidentals add (negative for flats)
+func parseNoteName(name string) (int, int, error) {
+	if name == "" {
+		return 0, 0, ErrInvalidNoteName
+	}
+
+	pitchClass, ok := letterPitchClasses[strings.ToUpper(name[:1])[0]]
+	if !ok {
+		return 0, 0, ErrInvalidNoteName
+	}
+
+	accidentals := 0
+	for _, r := range name[1:] {
+		switch r {
+		case '#', '♯':
+			accidentals++
+		case 'b', '♭':
+			accidentals--
+		default:
+			return 0, 0, ErrInvalidNoteName
+		}
+	}
+
+	return pitchClass, accidentals, nil
+}
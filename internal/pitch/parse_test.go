@@ -0,0 +1,76 @@
+package pitch
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestNoteToFrequencyRoundTrip(t *testing.T) {
+	// Any frequency is its note's frequency moved by its cents
+	rng := rand.New(rand.NewSource(1))
+	for _, a4 := range []float64{415, 440, 442} {
+		converter := NewNoteConverter()
+		converter.SetReferenceA4(a4)
+		for i := 0; i < 5000; i++ {
+			frequency := 20 * math.Pow(2, 8*rng.Float64()) // 20 Hz to 5 kHz, spread evenly in pitch
+			note := converter.FromFrequency(frequency)
+			base, err := NoteToFrequency(note.Name, note.Octave, a4)
+			if err != nil {
+				t.Fatalf("%v Hz: %s%d: %v", frequency, note.Name, note.Octave, err)
+			}
+			if got := base * math.Pow(2, note.Cents/1200); math.Abs(got-frequency) > frequency*1e-9 {
+				t.Fatalf("A4 = %v: %v Hz read as %s%d %+.4f¢, which is %v Hz", a4, frequency, note.Name, note.Octave, note.Cents, got)
+			}
+			if note.Cents < -50 || note.Cents >= 50 {
+				t.Fatalf("A4 = %v: %v Hz read as %+.6f cents, outside [-50, +50)", a4, frequency, note.Cents)
+			}
+		}
+	}
+}
+
+func TestParseNoteRoundTrip(t *testing.T) {
+	for _, names := range [][]string{noteNames, flatNoteNames} {
+		for octave := -1; octave <= 9; octave++ {
+			for pitchClass, name := range names {
+				text := fmt.Sprintf("%s%d", name, octave)
+				note, err := ParseNote(text)
+				if err != nil {
+					t.Errorf("ParseNote(%q): %v", text, err)
+					continue
+				}
+				if got := fmt.Sprintf("%s%d", note.Name, note.Octave); got != text {
+					t.Errorf("ParseNote(%q) formats back as %q", text, got)
+				}
+				if want := (octave+1)*12 + pitchClass; note.MIDINote != want || math.Abs(note.Cents) > 1e-9 {
+					t.Errorf("ParseNote(%q) = MIDI %d %+.2g¢, want MIDI %d in tune", text, note.MIDINote, note.Cents, want)
+				}
+			}
+		}
+	}
+}
+
+func TestCentsBoundary(t *testing.T) {
+	// Exactly halfway belongs to the upper note, at -50; either side of it
+	// the name and the sign of the cents agree
+	tests := []struct {
+		cents     float64 // Above A4
+		name      string
+		wantCents float64
+	}{
+		{49.9999, "A4", 49.9999},
+		{50, "A#4", -50},
+		{50.0001, "A#4", -49.9999},
+		{-49.9999, "A4", -49.9999},
+		{-50, "A4", -50},
+		{-50.0001, "G#4", 49.9999},
+	}
+	converter := NewNoteConverter()
+	for _, tt := range tests {
+		note := converter.FromFrequency(440 * math.Pow(2, tt.cents/1200))
+		if got := fmt.Sprintf("%s%d", note.Name, note.Octave); got != tt.name || math.Abs(note.Cents-tt.wantCents) > 1e-6 {
+			t.Errorf("A4 %+v¢ read as %s %+.6f¢, want %s %+.4f¢", tt.cents, got, note.Cents, tt.name, tt.wantCents)
+		}
+	}
+}
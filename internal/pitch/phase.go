@@ -0,0 +1,81 @@
+package pitch
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+// Largest level change of the peak bin between two frames for them to be
+// treated as the same tone
+const phaseMaxLevelChange = 2.0
+
+// WithPhaseRefinement enables refining the detected frequency from the phase
+// advance of the peak between successive frames. For a steady tone the phase
+// advances by exactly 2π·f·hop/sampleRate, which pins the frequency down far
+// more precisely than interpolating magnitudes. It needs buffers carrying
+// their stream Position, so the hop between frames is known; frames without
+// one (or whose previous frame doesn't hold the same tone) fall back to
+// interpolation.
+func WithPhaseRefinement(enabled bool) Option {
+	return func(d *FFTDetector) error {
+		d.phaseRefinement = enabled
+		return nil
+	}
+}
+
+// rememberFrame keeps the spectrum of the previously transformed frame, which
+// is still in the transform's output buffer, and the hop from it to this one
+func (d *FFTDetector) rememberFrame(buffer *audio.AudioBuffer) {
+	d.hop = 0
+	if d.hasFrame && buffer.Position > d.framePosition && len(d.previous) == len(d.transform.spectrum) {
+		copy(d.previous, d.transform.spectrum)
+		d.hop = buffer.Position - d.framePosition
+	}
+
+	d.framePosition = buffer.Position
+	d.hasFrame = true
+}
+
+// refinePhase returns the frequency of the peak near the estimate derived
+// from its phase advance since the previous frame, or the estimate itself
+// when there is no matching previous frame
+func (d *FFTDetector) refinePhase(spectrum []complex128, estimate float64, sampleRate int) float64 {
+	if d.hop <= 0 || len(d.previous) != len(spectrum) {
+		return estimate
+	}
+
+	binSizeHz := spectrumBinSize(spectrum, sampleRate)
+	bin := int(math.Round(estimate / binSizeHz))
+	if bin < 1 || bin >= len(spectrum)-1 {
+		return estimate
+	}
+
+	// The previous frame must contain the same tone at a similar level
+	current := cmplx.Abs(spectrum[bin])
+	previous := cmplx.Abs(d.previous[bin])
+	if current == 0 || previous == 0 ||
+		current > previous*phaseMaxLevelChange || previous > current*phaseMaxLevelChange {
+		return estimate
+	}
+
+	// Compare the measured phase advance with the one the estimate predicts;
+	// the wrapped difference is the frequency error times 2π·hop/sampleRate
+	hop := float64(d.hop)
+	expected := 2 * math.Pi * estimate * hop / float64(sampleRate)
+	deviation := wrapPhase(cmplx.Phase(spectrum[bin]) - cmplx.Phase(d.previous[bin]) - expected)
+	refined := estimate + deviation*float64(sampleRate)/(2*math.Pi*hop)
+
+	// Interpolation is accurate to well within a bin, so a larger correction
+	// means the frames don't match after all
+	if math.Abs(refined-estimate) > binSizeHz {
+		return estimate
+	}
+	return refined
+}
+
+// wrapPhase maps a phase into the range [-π, π]
+func wrapPhase(phase float64) float64 {
+	return phase - 2*math.Pi*math.Round(phase/(2*math.Pi))
+}
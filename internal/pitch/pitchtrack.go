@@ -0,0 +1,103 @@
+package pitch
+
+import "math"
+
+// PitchTrack enforces continuity on the per-frame frequency stream. A frame
+// that leaps further than maxInterval from the current pitch is treated as a
+// likely harmonic error and held back; only if the leap persists for
+// jumpFrames consecutive frames is it accepted as a genuine new note. This is
+// a two-state decision (continue the track, or jump) that costs a few frames
+// of latency on large leaps in exchange for suppressing one-frame spikes.
+type PitchTrack struct {
+	maxInterval float64 // Largest leap in semitones accepted immediately
+	jumpFrames  int     // Consecutive frames a larger leap must persist
+
+	frequency float64 // Last accepted frequency (Hz), 0 when there is none
+	pending   float64 // Frequency of the leap waiting to be accepted (Hz)
+	frames    int     // Consecutive frames the pending leap has been seen
+}
+
+// NewPitchTrack creates a new pitch track
+func NewPitchTrack() *PitchTrack {
+	return &PitchTrack{
+		maxInterval: 11, // Octave errors (12 semitones ± 1) and anything wider must persist
+		jumpFrames:  3,  // ~150ms at the processing loop's rate
+	}
+}
+
+// SetMaxInterval sets the largest leap, in semitones, accepted without delay
+func (p *PitchTrack) SetMaxInterval(semitones float64) {
+	// Ensure at least small pitch changes are always followed
+	if semitones < 1 {
+		semitones = 1
+	}
+
+	p.maxInterval = semitones
+}
+
+// SetJumpFrames sets how many consecutive frames a large leap must persist
+func (p *PitchTrack) SetJumpFrames(frames int) {
+	// Ensure a leap is accepted at some point
+	if frames < 1 {
+		frames = 1
+	}
+
+	p.jumpFrames = frames
+}
+
+// Update feeds a frame's frequency into the track and returns the corrected
+// frequency, along with whether a large leap was accepted on this frame.
+// While a leap is pending, the previous frequency is returned.
+func (p *PitchTrack) Update(frequency float64) (corrected float64, jumped bool) {
+	if frequency <= 0 {
+		return p.frequency, false
+	}
+
+	// The first frame starts the track
+	if p.frequency == 0 {
+		p.frequency = frequency
+		return frequency, false
+	}
+
+	// Small moves continue the track right away
+	if math.Abs(semitonesBetween(p.frequency, frequency)) <= p.maxInterval {
+		p.frequency = frequency
+		p.pending = 0
+		p.frames = 0
+		return frequency, false
+	}
+
+	// A large leap must land on the same pitch for several frames in a row
+	if p.pending > 0 && math.Abs(semitonesBetween(p.pending, frequency)) <= 1 {
+		p.frames++
+	} else {
+		p.frames = 1
+	}
+	p.pending = frequency
+
+	if p.frames < p.jumpFrames {
+		return p.frequency, false
+	}
+
+	p.frequency = frequency
+	p.pending = 0
+	p.frames = 0
+	return frequency, true
+}
+
+// Frequency returns the last accepted frequency, or 0 if there is none
+func (p *PitchTrack) Frequency() float64 {
+	return p.frequency
+}
+
+// Reset clears the track, e.g. when the sound stops or a new note is struck
+func (p *PitchTrack) Reset() {
+	p.frequency = 0
+	p.pending = 0
+	p.frames = 0
+}
+
+// semitonesBetween returns the interval from one frequency to another in semitones
+func semitonesBetween(from, to float64) float64 {
+	return 12 * math.Log2(to/from)
+}
@@ -0,0 +1,70 @@
+package pitch
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPitchTrackSuppressesSpikes(t *testing.T) {
+	// A steady A3 with single-frame jumps to its octave, its twelfth and the
+	// octave below, and one lasting two frames, still short of the three
+	// a jump needs
+	frames := held(220, 40)
+	for i, spike := range map[int]float64{5: 440, 12: 660, 20: 110, 30: 440, 31: 440} {
+		frames[i] = spike
+	}
+
+	track := NewPitchTrack()
+	for i, frequency := range frames {
+		corrected, jumped := track.Update(frequency)
+		if jumped {
+			t.Errorf("frame %d (%v Hz): accepted a spike as a jump", i, frequency)
+		}
+		if corrected != 220 {
+			t.Errorf("frame %d (%v Hz): corrected to %v Hz, want the held 220 Hz", i, frequency, corrected)
+		}
+	}
+}
+
+func TestPitchTrackAcceptsSustainedJump(t *testing.T) {
+	for _, jumpFrames := range []int{1, 3, 5} {
+		t.Run(fmt.Sprint(jumpFrames), func(t *testing.T) {
+			track := NewPitchTrack()
+			track.SetJumpFrames(jumpFrames)
+			frames := append(held(220, 10), held(880, 10)...)
+
+			jumps := 0
+			for i, frequency := range frames {
+				corrected, jumped := track.Update(frequency)
+
+				// The leap is held back until its jumpFrames-th frame
+				want := 220.0
+				if i >= 10+jumpFrames-1 {
+					want = 880
+				}
+				if corrected != want {
+					t.Errorf("frame %d: corrected to %v Hz, want %v Hz", i, corrected, want)
+				}
+				if jumped {
+					jumps++
+					if i != 10+jumpFrames-1 {
+						t.Errorf("jump accepted on frame %d, want frame %d", i, 10+jumpFrames-1)
+					}
+				}
+			}
+			if jumps != 1 {
+				t.Errorf("%d jumps reported, want 1", jumps)
+			}
+		})
+	}
+}
+
+func TestPitchTrackFollowsSmallMoves(t *testing.T) {
+	// Steps within the interval, here a fifth, are followed right away
+	track := NewPitchTrack()
+	for _, frequency := range []float64{220, 246.94, 329.63, 220} {
+		if corrected, jumped := track.Update(frequency); corrected != frequency || jumped {
+			t.Errorf("%v Hz: corrected to %v Hz (jump %v), want it followed", frequency, corrected, jumped)
+		}
+	}
+}
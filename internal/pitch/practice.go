@@ -0,0 +1,77 @@
+package pitch
+
+// Judgment is how a played note compares with a practice target
+type Judgment int
+
+const (
+	JudgmentHit         Judgment = iota // Right pitch class in the right octave
+	JudgmentWrongOctave                 // Right pitch class in another octave
+	JudgmentMiss                        // Another pitch class
+)
+
+// String returns a short description of the judgment
+func (j Judgment) String() string {
+	switch j {
+	case JudgmentHit:
+		return "hit"
+	case JudgmentWrongOctave:
+		return "wrong octave"
+	}
+	return "miss"
+}
+
+// TargetPractice judges played notes against a target note and counts the
+// hits and misses. Notes are compared as written, so a transposing
+// instrument's target is the note on its part.
+type TargetPractice struct {
+	target       Note
+	hits, misses int
+}
+
+// NewTargetPractice starts practicing a target note
+func NewTargetPractice(target Note) *TargetPractice {
+	return &TargetPractice{target: target}
+}
+
+// Target returns the note being practiced
+func (p *TargetPractice) Target() Note {
+	return p.target
+}
+
+// Judge judges a played note and counts it, returning the interval from the
+// note up (or down) to the target. Only hits count as hits; a note in the
+// wrong octave is a miss.
+func (p *TargetPractice) Judge(note Note) (Judgment, Interval) {
+	interval := Interval{Semitones: writtenNumber(&p.target) - writtenNumber(&note)}
+
+	judgment := JudgmentMiss
+	switch {
+	case interval.Semitones == 0:
+		judgment = JudgmentHit
+	case interval.Semitones%12 == 0:
+		judgment = JudgmentWrongOctave
+	}
+
+	if judgment == JudgmentHit {
+		p.hits++
+	} else {
+		p.misses++
+	}
+	return judgment, interval
+}
+
+// Score returns the hits and misses so far
+func (p *TargetPractice) Score() (hits, misses int) {
+	return p.hits, p.misses
+}
+
+// Reset forgets the hits and misses, keeping the target
+func (p *TargetPractice) Reset() {
+	p.hits, p.misses = 0, 0
+}
+
+// writtenNumber returns a note's written position in semitones, for
+// comparing notes as written
+func writtenNumber(note *Note) int {
+	return note.Octave*12 + note.PitchClass
+}
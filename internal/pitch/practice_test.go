@@ -0,0 +1,50 @@
+package pitch
+
+import "testing"
+
+func TestTargetPracticeJudgments(t *testing.T) {
+	target, err := ParseNote("C#4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	practice := NewTargetPractice(*target)
+
+	tests := []struct {
+		played    string
+		judgment  Judgment
+		semitones int // From the played note to the target
+	}{
+		{"C#4", JudgmentHit, 0},
+		{"Db4", JudgmentHit, 0}, // Spelled either way
+		{"C#5", JudgmentWrongOctave, -12},
+		{"C#2", JudgmentWrongOctave, 24},
+		{"D4", JudgmentMiss, -1},
+		{"A3", JudgmentMiss, 4},
+		{"C4", JudgmentMiss, 1},
+	}
+	for _, tt := range tests {
+		played, err := ParseNote(tt.played)
+		if err != nil {
+			t.Fatal(err)
+		}
+		judgment, interval := practice.Judge(*played)
+		if judgment != tt.judgment || interval.Semitones != tt.semitones {
+			t.Errorf("%s against C#4: %v, %d semitones; want %v, %d", tt.played, judgment, interval.Semitones, tt.judgment, tt.semitones)
+		}
+	}
+
+	// Wrong octaves count as misses
+	if hits, misses := practice.Score(); hits != 2 || misses != 5 {
+		t.Errorf("score %d hits, %d misses; want 2, 5", hits, misses)
+	}
+	practice.Reset()
+	if hits, misses := practice.Score(); hits != 0 || misses != 0 || practice.Target().Name != "C#" || practice.Target().Octave != 4 {
+		t.Errorf("after Reset: %d hits, %d misses, target %s%d", hits, misses, practice.Target().Name, practice.Target().Octave)
+	}
+
+	for judgment, want := range map[Judgment]string{JudgmentHit: "hit", JudgmentWrongOctave: "wrong octave", JudgmentMiss: "miss"} {
+		if got := judgment.String(); got != want {
+			t.Errorf("%d.String() = %q, want %q", judgment, got, want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package pitch
+
+import (
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+// Precision mode settings
+const (
+	precisionFrames   = 4                      // Captured frames concatenated into the long window
+	precisionPadding  = 4                      // Zero-padding of the long window
+	precisionHoldTime = 500 * time.Millisecond // How long a note must be held before refining
+)
+
+// PrecisionAnalyzer refines the cents of sustained notes. It keeps the most
+// recent contiguous samples of the capture stream, and once a note has been
+// held long enough analyzes a window several frames long, which resolves the
+// frequency far more finely than a single frame. Buffers must carry their
+// stream Position so contiguous frames can be recognized; the window is only
+// refilled once every sample in it belongs to the held note.
+type PrecisionAnalyzer struct {
+	window   int           // Samples in the long window
+	history  []float32     // Recent contiguous samples, oldest first
+	next     int64         // Stream position just past the last sample in history
+	rate     int           // Sample rate of the samples in history
+	holdTime time.Duration // How long a note must be held before refining
+
+	midiNote  int       // Note being held, -1 when none
+	heldSince time.Time // When the held note began
+
+	detector  *FFTDetector   // Transforms the long window
+	converter *NoteConverter // Converts refined frequencies into cents
+}
+
+// NewPrecisionAnalyzer creates a precision analyzer for frames of the given
+// length, as delivered by the audio capturer
+func NewPrecisionAnalyzer(frameLength int) (*PrecisionAnalyzer, error) {
+	window := frameLength * precisionFrames
+	detector, err := NewFFTDetector(window)
+	if err != nil {
+		return nil, err
+	}
+	detector.SetPaddingFactor(precisionPadding)
+
+	return &PrecisionAnalyzer{
+		window:    window,
+		history:   make([]float32, 0, 2*window),
+		holdTime:  precisionHoldTime,
+		midiNote:  -1,
+		detector:  detector,
+		converter: NewNoteConverter(),
+	}, nil
+}
+
+// SetConverter sets the converter used to measure the refined cents
+func (p *PrecisionAnalyzer) SetConverter(converter *NoteConverter) {
+	p.converter = converter
+}
+
+// Add appends the new samples of a captured buffer to the history. A buffer
+// that doesn't continue the stream (a missed block, or no position at all)
+// starts the history over.
+func (p *PrecisionAnalyzer) Add(buffer *audio.AudioBuffer) {
+	if buffer == nil || len(buffer.Samples) == 0 {
+		return
+	}
+
+	end := buffer.Position + int64(len(buffer.Samples))
+	if buffer.SampleRate != p.rate || buffer.Position > p.next || buffer.Position == 0 {
+		p.history = append(p.history[:0], buffer.Samples...)
+		p.next = end
+		p.rate = buffer.SampleRate
+		return
+	}
+
+	// The same buffer may be read more than once
+	if end <= p.next {
+		return
+	}
+	p.history = append(p.history, buffer.Samples[p.next-buffer.Position:]...)
+	p.next = end
+
+	// Drop samples older than the window, moving the rest down only once the
+	// spare capacity is used up
+	if len(p.history) > p.window && cap(p.history)-len(p.history) < len(buffer.Samples) {
+		p.history = append(p.history[:0], p.history[len(p.history)-p.window:]...)
+	}
+}
+
+// Reset forgets the held note, so the next one must be held anew before it
+// is refined, e.g. after a silence or a re-attack of the same note
+func (p *PrecisionAnalyzer) Reset() {
+	p.midiNote = -1
+}
+
+// Refine returns the note with its frequency and cents measured over the long
+// window, and true, once the note has been held long enough for the whole
+// window to contain it. Otherwise it returns the note unchanged and false.
+// Pass nil when no note is sounding.
+func (p *PrecisionAnalyzer) Refine(note *Note, at time.Time) (*Note, bool) {
+	if note == nil {
+		p.Reset()
+		return nil, false
+	}
+	if note.MIDINote != p.midiNote {
+		p.midiNote = note.MIDINote
+		p.heldSince = at
+		return note, false
+	}
+
+	// Wait until the window holds nothing but the held note
+	if len(p.history) < p.window || p.rate <= 0 {
+		return note, false
+	}
+	windowDuration := time.Duration(float64(p.window) / float64(p.rate) * float64(time.Second))
+	if at.Sub(p.heldSince) < max(p.holdTime, windowDuration) {
+		return note, false
+	}
+
+	spectrum, err := p.detector.Spectrum(&audio.AudioBuffer{
+		Samples:    p.history[len(p.history)-p.window:],
+		SampleRate: p.rate,
+	})
+	if err != nil {
+		return note, false
+	}
+
+	// Measure the peak of the held note in the finely resolved spectrum
+	peak, ok := peakNear(spectrum, note.Frequency, 1, spectrumBinSize(spectrum, p.rate), precisionPadding)
+	if !ok {
+		return note, false
+	}
+	refined := p.converter.FromFrequency(peak.Frequency)
+	if refined == nil || refined.MIDINote != note.MIDINote {
+		return note, false
+	}
+
+	result := *note
+	result.Frequency = peak.Frequency
+	result.Cents = refined.Cents
+	result.RawCents = refined.Cents
+	result.Precise = true
+	return &result, true
+}
@@ -0,0 +1,77 @@
+package pitch
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+func TestPrecisionModeSteadiesCents(t *testing.T) {
+	// 440.5 Hz at 10 dB SNR, captured in 2048-sample frames, is +1.97¢ from
+	// A4: single frames scatter around that, the refined reading doesn't
+	const frameLength, frames = 2048, 40
+	want := 1200 * math.Log2(440.5/440)
+
+	for _, sampleRate := range []int{44100, 48000} {
+		stream := addNoise(audio.SynthesizeTone(440.5, []float64{1}, frameLength*frames, sampleRate), 10, 1)
+		detector, err := NewFFTDetector(frameLength)
+		if err != nil {
+			t.Fatal(err)
+		}
+		detector.SetPaddingFactor(benchPadding)
+		precision, err := NewPrecisionAnalyzer(frameLength)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var single, refined []float64
+		start := time.Unix(0, 0)
+		for i := 0; i < frames; i++ {
+			buffer := &audio.AudioBuffer{
+				Samples:    stream.Samples[i*frameLength : (i+1)*frameLength],
+				SampleRate: sampleRate,
+				Position:   int64((i + 1) * frameLength), // 0 would mean unknown
+			}
+			precision.Add(buffer)
+			note, err := detector.DetectPitch(buffer)
+			if err != nil {
+				t.Fatalf("%d Hz frame %d: %v", sampleRate, i, err)
+			}
+			single = append(single, note.Cents)
+
+			at := start.Add(time.Duration(i*frameLength) * time.Second / time.Duration(sampleRate))
+			if note, ok := precision.Refine(note, at); ok {
+				if !note.Precise {
+					t.Errorf("%d Hz frame %d: refined note not marked precise", sampleRate, i)
+				}
+				refined = append(refined, note.Cents)
+			}
+		}
+
+		// Refining starts once the note has been held for half a second
+		if len(refined) < frames/2 {
+			t.Fatalf("%d Hz: %d of %d frames refined, want at least half", sampleRate, len(refined), frames)
+		}
+		for _, cents := range refined {
+			if math.Abs(cents-want) > 0.5 {
+				t.Errorf("%d Hz: refined reading %+.2f¢, want %+.2f¢ within 0.5¢", sampleRate, cents, want)
+			}
+		}
+		if spread(refined) > spread(single)/4 {
+			t.Errorf("%d Hz: refined readings spread over %.2f¢, want well under the single frames' %.2f¢",
+				sampleRate, spread(refined), spread(single))
+		}
+	}
+}
+
+// spread returns the difference between the largest and smallest value
+func spread(values []float64) float64 {
+	lowest, highest := math.Inf(1), math.Inf(-1)
+	for _, value := range values {
+		lowest = math.Min(lowest, value)
+		highest = math.Max(highest, value)
+	}
+	return highest - lowest
+}
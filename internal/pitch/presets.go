@@ -0,0 +1,178 @@
+package pitch
+
+import (
+	"math"
+	"strings"
+)
+
+// MinDetectableFrequency is the lowest frequency a detector can be configured
+// for (Hz), just below the B0 (~30.9 Hz) of a 5-string bass
+const MinDetectableFrequency = 25.0
+
+// Number of cycles of the lowest note an analysis window should hold
+const windowCycles = 8
+
+// Fewest cycles of the lowest note a window must hold for detection to work
+const minWindowCycles = 4
+
+// Preset bundles detector settings tuned for a particular instrument
+type Preset struct {
+	Name            string
+	MinFrequency    float64 // Lowest frequency to detect (Hz)
+	MaxFrequency    float64 // Highest frequency to detect (Hz)
+	WindowSize      int     // Recommended analysis window (samples at 44.1/48 kHz)
+	PeakThreshold   float64 // Minimum peak height as fraction of highest peak
+	VolumeThreshold float64 // Minimum RMS volume level for note detection
+	Inharmonicity   bool    // Whether to correct for stiff-string inharmonicity
+	PaddingFactor   int     // Zero-padding of the FFT input, see SetPaddingFactor; 0 for none
+
+	// Relative level change per frame below which a note's attack has
+	// passed, see AttackGate; higher for instruments that settle quickly
+	AttackSettle float64
+}
+
+// Instrument presets
+var (
+	PresetGuitar = Preset{
+		Name:            "Guitar",
+		MinFrequency:    75.0,   // Just below low E2 (~82 Hz)
+		MaxFrequency:    1400.0, // E6 on the 24th fret is ~1319 Hz
+		WindowSize:      4096,
+		PeakThreshold:   0.2,
+		VolumeThreshold: 0.005,
+		AttackSettle:    0.2, // Plucked strings decay smoothly after the pick
+	}
+	PresetBass = Preset{
+		Name:            "Bass",
+		MinFrequency:    28.0,  // Low B0 on a 5-string is ~30.9 Hz
+		MaxFrequency:    400.0, // G4 on the 20th fret is ~392 Hz
+		WindowSize:      16384, // Eight cycles of B0, see RequiredWindowSize
+		PeakThreshold:   0.2,
+		VolumeThreshold: 0.005,
+		PaddingFactor:   2,   // Bins of ~2.7 Hz are over a semitone wide at the low strings
+		AttackSettle:    0.2, // Plucked strings decay smoothly after the pick
+	}
+	PresetUkulele = Preset{
+		Name:            "Ukulele",
+		MinFrequency:    250.0,  // C4 is ~262 Hz
+		MaxFrequency:    1100.0, // A5 on the 12th fret is ~880 Hz
+		WindowSize:      2048,
+		PeakThreshold:   0.2,
+		VolumeThreshold: 0.005,
+		AttackSettle:    0.25, // Short, bright plucks settle almost at once
+	}
+	PresetViolin = Preset{
+		Name:            "Violin",
+		MinFrequency:    180.0,  // Open G3 is ~196 Hz
+		MaxFrequency:    3600.0, // Upper register reaches A7 (~3520 Hz)
+		WindowSize:      2048,
+		PeakThreshold:   0.2,
+		VolumeThreshold: 0.005,
+		AttackSettle:    0.1, // Bowed notes swell for a while before they hold
+	}
+	PresetVoice = Preset{
+		Name:            "Voice",
+		MinFrequency:    75.0,   // Bass voices reach ~E2
+		MaxFrequency:    1100.0, // Sopranos reach ~C6
+		WindowSize:      4096,
+		PeakThreshold:   0.25,  // Breathy voices have strong noise peaks
+		VolumeThreshold: 0.008, // Require a bit more level than instruments
+		AttackSettle:    0.1,   // Sung notes swell into their pitch
+	}
+	PresetPiano = Preset{
+		Name:            "Piano",
+		MinFrequency:    27.0,   // A0 is 27.5 Hz
+		MaxFrequency:    4200.0, // C8 is ~4186 Hz
+		WindowSize:      8192,
+		PeakThreshold:   0.2,
+		VolumeThreshold: 0.005,
+		Inharmonicity:   true, // Piano partials are stretched, most of all in the bass
+		AttackSettle:    0.3,  // The hammer attack is over within ~50ms, then the level falls quickly
+	}
+	PresetChromatic = Preset{
+		Name:            "Chromatic",
+		MinFrequency:    80.0, // Same as the FFT detector defaults
+		MaxFrequency:    1200.0,
+		WindowSize:      4096,
+		PeakThreshold:   0.2,
+		VolumeThreshold: 0.005,
+		AttackSettle:    0.15, // Between plucked and bowed instruments
+	}
+)
+
+// Presets lists every built-in preset
+var Presets = []Preset{
+	PresetGuitar,
+	PresetBass,
+	PresetUkulele,
+	PresetViolin,
+	PresetVoice,
+	PresetPiano,
+	PresetChromatic,
+}
+
+// RequiredWindowSize returns the window size, in samples, needed to hold at
+// least eight cycles of the given frequency. It is rounded up to a power of
+// two, which the FFT handles fastest.
+func RequiredWindowSize(minFreq float64, sampleRate int) int {
+	return windowSizeFor(windowCycles, minFreq, sampleRate)
+}
+
+// windowSizeFor returns the smallest power-of-two window holding the given
+// number of cycles of a frequency
+func windowSizeFor(cycles, minFreq float64, sampleRate int) int {
+	if minFreq <= 0 || sampleRate <= 0 {
+		return 0
+	}
+
+	samples := int(math.Ceil(cycles * float64(sampleRate) / minFreq))
+	size := 1
+	for size < samples {
+		size <<= 1
+	}
+	return size
+}
+
+// PresetByName returns the built-in preset with the given name (case-insensitive)
+func PresetByName(name string) (Preset, bool) {
+	for _, preset := range Presets {
+		if strings.EqualFold(preset.Name, name) {
+			return preset, true
+		}
+	}
+	return Preset{}, false
+}
+
+// NewFFTDetectorWithPreset creates a new FFT-based pitch detector configured
+// for an instrument, using the preset's recommended window size. Further
+// options are applied after the preset's settings.
+func NewFFTDetectorWithPreset(preset Preset, opts ...Option) (*FFTDetector, error) {
+	presetOpts := []Option{
+		WithFrequencyRange(preset.MinFrequency, preset.MaxFrequency),
+		WithPeakThreshold(preset.PeakThreshold),
+		WithVolumeThreshold(preset.VolumeThreshold),
+		WithInharmonicityCorrection(preset.Inharmonicity),
+	}
+	detector, err := NewFFTDetector(preset.WindowSize, append(presetOpts, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	detector.SetPaddingFactor(preset.PaddingFactor)
+	return detector, nil
+}
+
+// ApplyPreset configures the detector for an instrument. It returns the buffer
+// length the preset needs, so the audio capturer can be set up to match.
+func (d *FFTDetector) ApplyPreset(preset Preset) int {
+	d.minFrequency = preset.MinFrequency
+	d.maxFrequency = preset.MaxFrequency
+	d.peakThreshold = preset.PeakThreshold
+	d.volumeThreshold = preset.VolumeThreshold
+	d.inharmonicity = preset.Inharmonicity
+
+	// Resize the scratch buffers for the new window
+	d.windowSize = preset.WindowSize
+	d.SetPaddingFactor(preset.PaddingFactor)
+
+	return d.windowSize
+}
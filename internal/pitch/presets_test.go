@@ -0,0 +1,63 @@
+package pitch
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+func TestBassPresetLowStrings(t *testing.T) {
+	tests := []struct {
+		frequency float64
+		note      string
+	}{
+		{30.87, "B0"},
+		{41.2, "E1"},
+		{55, "A1"},
+	}
+	for _, sampleRate := range []int{44100, 48000} {
+		for _, tt := range tests {
+			detector, err := NewFFTDetectorWithPreset(PresetBass)
+			if err != nil {
+				t.Fatal(err)
+			}
+			note, err := detector.DetectPitch(audio.SynthesizeTone(tt.frequency, guitarTone, PresetBass.WindowSize, sampleRate))
+			if err != nil {
+				t.Errorf("%s at %d Hz: %v", tt.note, sampleRate, err)
+				continue
+			}
+			if got := fmt.Sprintf("%s%d", note.Name, note.Octave); got != tt.note {
+				t.Errorf("%s at %d Hz read as %s", tt.note, sampleRate, got)
+			}
+			if cents := 1200 * math.Log2(note.Frequency/tt.frequency); math.Abs(cents) > 5 {
+				t.Errorf("%s at %d Hz read %.2f Hz, %+.1f cents off", tt.note, sampleRate, note.Frequency, cents)
+			}
+		}
+	}
+}
+
+func TestPresetsDetectRangeExtremes(t *testing.T) {
+	// Unpadded windows resolve the lowest notes to a quarter of a semitone
+	const maxCents = 25
+	for _, preset := range Presets {
+		for _, sampleRate := range []int{44100, 48000} {
+			for _, frequency := range []float64{preset.MinFrequency, preset.MaxFrequency} {
+				detector, err := NewFFTDetectorWithPreset(preset)
+				if err != nil {
+					t.Fatalf("%s: %v", preset.Name, err)
+				}
+				note, err := detector.DetectPitch(audio.SynthesizeTone(frequency, guitarTone, preset.WindowSize, sampleRate))
+				if err != nil {
+					t.Errorf("%s at %v Hz, %d Hz sampling: %v", preset.Name, frequency, sampleRate, err)
+					continue
+				}
+				if cents := 1200 * math.Log2(note.Frequency/frequency); math.Abs(cents) > maxCents {
+					t.Errorf("%s at %v Hz, %d Hz sampling: read %.2f Hz, %+.1f cents off",
+						preset.Name, frequency, sampleRate, note.Frequency, cents)
+				}
+			}
+		}
+	}
+}
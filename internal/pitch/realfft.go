@@ -0,0 +1,104 @@
+package pitch
+
+import (
+	"math"
+	"math/bits"
+	"math/cmplx"
+
+	"github.com/mjibson/go-dsp/fft"
+)
+
+// realFFT computes the one-sided spectrum (n/2+1 bins) of real input. For
+// power-of-two lengths it packs the even and odd samples into a half-length
+// complex FFT, so only half the work of a full complex transform is done.
+// All buffers are allocated once and reused, so transforms don't allocate.
+type realFFT struct {
+	n        int          // Input length
+	packed   []complex128 // Sample pairs packed as complex values, transformed in place
+	twiddles []complex128 // exp(-2πik/n) for k = 0..n/2
+	spectrum []complex128 // One-sided output spectrum
+}
+
+// newRealFFT creates a real-input FFT for inputs of length n
+func newRealFFT(n int) *realFFT {
+	r := &realFFT{
+		n:        n,
+		spectrum: make([]complex128, n/2+1),
+	}
+
+	// Lengths that aren't a power of two fall back to go-dsp
+	if !isPowerOfTwo(n) || n < 4 {
+		return r
+	}
+
+	half := n / 2
+	r.packed = make([]complex128, half)
+	r.twiddles = make([]complex128, half+1)
+	for k := range r.twiddles {
+		angle := -2 * math.Pi * float64(k) / float64(n)
+		r.twiddles[k] = complex(math.Cos(angle), math.Sin(angle))
+	}
+	return r
+}
+
+// transform returns the one-sided spectrum of the input, which must have
+// length n. The returned slice is reused by the next call.
+func (r *realFFT) transform(input []float64) []complex128 {
+	if r.packed == nil {
+		copy(r.spectrum, fft.FFTReal(input))
+		return r.spectrum
+	}
+
+	half := r.n / 2
+
+	// Pack even samples into the real part and odd samples into the imaginary part
+	for k := 0; k < half; k++ {
+		r.packed[k] = complex(input[2*k], input[2*k+1])
+	}
+	r.fftInPlace()
+
+	// Untangle the even and odd spectra and combine them into the real spectrum
+	for k := 0; k <= half; k++ {
+		z := r.packed[k%half]
+		zMirror := cmplx.Conj(r.packed[(half-k)%half])
+
+		even := (z + zMirror) * 0.5
+		odd := (z - zMirror) * complex(0, -0.5)
+		r.spectrum[k] = even + r.twiddles[k]*odd
+	}
+
+	return r.spectrum
+}
+
+// fftInPlace runs an iterative radix-2 FFT over the packed buffer
+func (r *realFFT) fftInPlace() {
+	data := r.packed
+	size := len(data)
+
+	// Reorder into bit-reversed index order
+	shift := uint(bits.UintSize - bits.TrailingZeros(uint(size)))
+	for i := range data {
+		j := int(bits.Reverse(uint(i)) >> shift)
+		if i < j {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+
+	// Butterflies; the half-length FFT's twiddles are every other entry
+	// of the full-length table
+	for length := 2; length <= size; length <<= 1 {
+		step := r.n / length
+		for start := 0; start < size; start += length {
+			for k := 0; k < length/2; k++ {
+				t := r.twiddles[k*step] * data[start+k+length/2]
+				data[start+k+length/2] = data[start+k] - t
+				data[start+k] += t
+			}
+		}
+	}
+}
+
+// isPowerOfTwo reports whether n is a positive power of two
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
@@ -0,0 +1,145 @@
+package pitch
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LoadScala reads a tuning system from a Scala (.scl) file. Scales with any
+// number of degrees per period are supported; see ParseScala.
+func LoadScala(path string) (*Temperament, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	temperament, err := ParseScala(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	// Scales without a description are named after their file
+	if temperament.Name == "" {
+		temperament.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return temperament, nil
+}
+
+// ParseScala parses a scale in the Scala file format: lines starting with
+// "!" are comments, the first other line describes the scale, the second
+// holds the number of pitches and each of the following lines one pitch
+// above the implicit 1/1, either in cents (with a period, e.g. "701.955") or
+// as a ratio ("3/2", or a whole number such as "2"). The last pitch is the
+// period the scale repeats at, usually 2/1. Anything after the value on a
+// line is ignored. Errors name the offending line.
+func ParseScala(r io.Reader) (*Temperament, error) {
+	var (
+		description string
+		count       = -1
+		pitches     []float64
+		pitchLines  []int // Line number of each pitch
+		lineNumber  int
+		seen        int // Non-comment lines so far
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "!") {
+			continue
+		}
+		seen++
+
+		switch {
+		case seen == 1:
+			description = line
+		case seen == 2:
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("%w: line %d: missing number of pitches", ErrInvalidScala, lineNumber)
+			}
+			n, err := strconv.Atoi(fields[0])
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: line %d: invalid number of pitches %q", ErrInvalidScala, lineNumber, fields[0])
+			}
+			count = n
+		default:
+			// Blank lines after the pitches are harmless
+			if line == "" && len(pitches) == count {
+				continue
+			}
+			if len(pitches) == count {
+				return nil, fmt.Errorf("%w: line %d: more than the %d pitches declared", ErrInvalidScala, lineNumber, count)
+			}
+			cents, err := parseScalaPitch(line)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidScala, lineNumber, err)
+			}
+			pitches = append(pitches, cents)
+			pitchLines = append(pitchLines, lineNumber)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if count < 0 {
+		return nil, fmt.Errorf("%w: line %d: missing number of pitches", ErrInvalidScala, lineNumber)
+	}
+	if len(pitches) < count {
+		return nil, fmt.Errorf("%w: line %d: %d pitches declared but %d found", ErrInvalidScala, lineNumber, count, len(pitches))
+	}
+
+	// The period must lie above every other degree
+	period := pitches[count-1]
+	for i, cents := range pitches[:count-1] {
+		if cents >= period {
+			return nil, fmt.Errorf("%w: line %d: pitch of %.3f cents is not below the period of %.3f cents",
+				ErrInvalidScala, pitchLines[i], cents, period)
+		}
+	}
+	degrees := append([]float64{0}, pitches[:count-1]...)
+
+	return &Temperament{
+		Name:    description,
+		Degrees: degrees,
+		Period:  period,
+	}, nil
+}
+
+// parseScalaPitch converts the pitch on a Scala file line into cents
+func parseScalaPitch(line string) (float64, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("missing pitch")
+	}
+	value := fields[0]
+
+	// Cents are written with a period, ratios without one
+	if strings.Contains(value, ".") {
+		cents, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsInf(cents, 0) || cents <= 0 {
+			return 0, fmt.Errorf("invalid cents value %q", value)
+		}
+		return cents, nil
+	}
+
+	numerator, denominator, found := strings.Cut(value, "/")
+	if !found {
+		denominator = "1"
+	}
+	n, errN := strconv.ParseUint(numerator, 10, 64)
+	d, errD := strconv.ParseUint(denominator, 10, 64)
+	if errN != nil || errD != nil || n == 0 || d == 0 || n <= d {
+		return 0, fmt.Errorf("invalid ratio %q", value)
+	}
+	return 1200 * math.Log2(float64(n)/float64(d)), nil
+}
@@ -0,0 +1,92 @@
+package pitch
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadScalaFixtures(t *testing.T) {
+	c4 := 440 * math.Pow(2, -9.0/12)
+	tests := []struct {
+		file    string
+		name    string
+		degrees int
+		// A pitch this many cents above C4, the tonic, and the degree and
+		// deviation it reads as
+		above  float64
+		degree int
+		cents  float64
+	}{
+		{"12-tet.scl", "12-tone equal temperament", 12, 900, 9, 0},
+		{"12-tet.scl", "12-tone equal temperament", 12, 1200 + 712, 7, 12},
+		{"19-edo.scl", "19-tone equal division of the octave", 19, 5 * 1200.0 / 19, 5, 0},
+		{"19-edo.scl", "19-tone equal division of the octave", 19, 400, 6, 400 - 6*1200.0/19},
+		{"19-edo.scl", "19-tone equal division of the octave", 19, -1200.0 / 19, 18, 0},
+		{"just-major.scl", "Just intonation major scale", 7, 1200 * math.Log2(5.0/4), 2, 0},
+		{"just-major.scl", "Just intonation major scale", 7, 400, 2, 400 - 1200*math.Log2(5.0/4)},
+		{"just-major.scl", "Just intonation major scale", 7, 1200 * math.Log2(15.0/8), 6, 0},
+	}
+	for _, tt := range tests {
+		temperament, err := LoadScala(filepath.Join("testdata", tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if temperament.Name != tt.name || len(temperament.Degrees) != tt.degrees || math.Abs(temperament.Period-1200) > 1e-9 {
+			t.Errorf("%s: %q with %d degrees repeating at %.3f¢, want %q with %d at 1200¢",
+				tt.file, temperament.Name, len(temperament.Degrees), temperament.Period, tt.name, tt.degrees)
+		}
+
+		converter := NewNoteConverter()
+		converter.SetTemperament(*temperament)
+		note := converter.FromFrequency(c4 * math.Pow(2, tt.above/1200))
+		// The fixtures give cents to five decimals
+		if note.Degree != tt.degree || math.Abs(note.Cents-tt.cents) > 1e-4 {
+			t.Errorf("%s: %+.3f¢ above C4 read as degree %d %+.3f¢, want degree %d %+.3f¢",
+				tt.file, tt.above, note.Degree, note.Cents, tt.degree, tt.cents)
+		}
+	}
+}
+
+func TestScalaReference(t *testing.T) {
+	// A 1/1 of 432 Hz puts the just fifth at 648 Hz
+	temperament, err := LoadScala(filepath.Join("testdata", "just-major.scl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	temperament.Reference = 432
+	converter := NewNoteConverter()
+	converter.SetTemperament(*temperament)
+	if note := converter.FromFrequency(648); note.Degree != 4 || math.Abs(note.Cents) > 1e-6 {
+		t.Errorf("648 Hz over a 432 Hz 1/1 read as degree %d %+.3f¢, want degree 4 in tune", note.Degree, note.Cents)
+	}
+}
+
+func TestParseScalaErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		line string // Line the error must name
+	}{
+		{"bad count", "Scale\nseven\n", "line 2"},
+		{"missing count", "! only a description\nScale\n", "line 2"},
+		{"bad ratio", "Scale\n 2\n 3/0\n 2/1\n", "line 3"},
+		{"ratio below unison", "Scale\n 2\n 2/3\n 2/1\n", "line 3"},
+		{"bad cents", "Scale\n 2\n 7o1.9\n 2/1\n", "line 3"},
+		{"too few", "! comment\nScale\n 3\n 9/8\n 2/1\n", "line 5"},
+		{"too many", "Scale\n 1\n 2/1\n 3/1\n", "line 4"},
+		{"above the period", "Scale\n 2\n 1300.0\n 2/1\n", "line 3"},
+	}
+	for _, tt := range tests {
+		_, err := ParseScala(strings.NewReader(tt.text))
+		if !errors.Is(err, ErrInvalidScala) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, ErrInvalidScala)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.line+":") {
+			t.Errorf("%s: %q doesn't name %s", tt.name, err, tt.line)
+		}
+	}
+}
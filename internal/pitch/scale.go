@@ -0,0 +1,215 @@
+package pitch
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Scale is a scale to practice, as semitones above its root
+type Scale struct {
+	Name       string
+	Ascending  []int // Steps up from the root, starting with 0 and leaving out the octave
+	Descending []int // Steps on the way down when they differ (melodic minor), nil otherwise
+	Relative   int   // Semitones from the root up to the major key whose signature spells the scale
+}
+
+// Built-in scales
+var (
+	ScaleMajor           = Scale{Name: "Major", Ascending: []int{0, 2, 4, 5, 7, 9, 11}}
+	ScaleNaturalMinor    = Scale{Name: "Natural Minor", Ascending: []int{0, 2, 3, 5, 7, 8, 10}, Relative: 3}
+	ScaleHarmonicMinor   = Scale{Name: "Harmonic Minor", Ascending: []int{0, 2, 3, 5, 7, 8, 11}, Relative: 3}
+	ScaleMelodicMinor    = Scale{Name: "Melodic Minor", Ascending: []int{0, 2, 3, 5, 7, 9, 11}, Descending: []int{0, 2, 3, 5, 7, 8, 10}, Relative: 3}
+	ScaleMajorPentatonic = Scale{Name: "Major Pentatonic", Ascending: []int{0, 2, 4, 7, 9}}
+	ScaleMinorPentatonic = Scale{Name: "Minor Pentatonic", Ascending: []int{0, 3, 5, 7, 10}, Relative: 3}
+	ScaleBlues           = Scale{Name: "Blues", Ascending: []int{0, 3, 5, 6, 7, 10}, Relative: 3}
+	ScaleChromatic       = Scale{Name: "Chromatic", Ascending: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}}
+)
+
+// Scales lists the built-in scales
+var Scales = []Scale{
+	ScaleMajor,
+	ScaleNaturalMinor,
+	ScaleHarmonicMinor,
+	ScaleMelodicMinor,
+	ScaleMajorPentatonic,
+	ScaleMinorPentatonic,
+	ScaleBlues,
+	ScaleChromatic,
+}
+
+// ScaleByName returns the built-in scale with the given name, ignoring case,
+// spaces and hyphens ("harmonic-minor" finds "Harmonic Minor")
+func ScaleByName(name string) (Scale, bool) {
+	compact := strings.NewReplacer(" ", "", "-", "")
+	for _, scale := range Scales {
+		if strings.EqualFold(compact.Replace(scale.Name), compact.Replace(name)) {
+			return scale, true
+		}
+	}
+	return Scale{}, false
+}
+
+// ScaleMove is what a played note did to a scale session
+type ScaleMove int
+
+const (
+	ScaleIgnored   ScaleMove = iota // A repeat of the previous note, a forgiven passing note, or a note after the end
+	ScaleAdvanced                   // The expected note, moving on to the next
+	ScaleCompleted                  // The last note of the scale
+	ScaleMistake                    // Another note, staying on the expected one
+)
+
+// ScaleSession walks through a scale from its root up an octave and back
+// down, advancing as each expected note is played. Notes are compared as
+// written, so a transposing instrument practices the scale on its part.
+type ScaleSession struct {
+	scale    Scale
+	sequence []Note
+	position int // Index in sequence of the expected note
+
+	strict     bool // Whether passing notes count as mistakes
+	octaveFold bool // Whether any octave of the expected note counts
+
+	mistakes          int
+	started, finished time.Time // First and last correct note, zero until played
+}
+
+// NewScaleSession starts a scale at a root note, e.g. G3 for G major
+// starting on G3. Strict sessions count every wrong note as a mistake;
+// others forgive notes passed on the way from the previous note to the
+// expected one, as when sliding into a note.
+func NewScaleSession(scale Scale, root Note, strict bool) *ScaleSession {
+	s := &ScaleSession{scale: scale, strict: strict}
+
+	// Spell the notes from the key signature of the scale's major key, in
+	// flats when the root is spelled with one
+	fifths := ((root.PitchClass+scale.Relative)%12*7%12 + 12) % 12
+	if fifths > 6 || fifths == 6 && strings.Contains(root.Name, "b") {
+		fifths -= 12
+	}
+	converter := NewNoteConverter()
+	converter.SetSpelling(SpellingAuto)
+	converter.SetKeySignature(fifths)
+
+	descending := scale.Descending
+	if descending == nil {
+		descending = scale.Ascending
+	}
+	steps := append(append([]int{}, scale.Ascending...), 12)
+	for i := len(descending) - 1; i >= 0; i-- {
+		steps = append(steps, descending[i])
+	}
+
+	rootNumber := writtenNumber(&root)
+	for _, step := range steps {
+		// Written number 0 is C0, MIDI note 12
+		midi := rootNumber + step + 12
+		frequency := DefaultReferenceA4 * math.Pow(2, float64(midi-midiNoteA4)/12)
+		s.sequence = append(s.sequence, *converter.FromFrequency(frequency))
+	}
+	return s
+}
+
+// Scale returns the scale being practiced
+func (s *ScaleSession) Scale() Scale {
+	return s.scale
+}
+
+// Sequence returns every note of the scale in the order they're played
+func (s *ScaleSession) Sequence() []Note {
+	return s.sequence
+}
+
+// Position returns the index in Sequence of the expected note, or its
+// length once the scale is done
+func (s *ScaleSession) Position() int {
+	return s.position
+}
+
+// Done reports whether every note of the scale has been played
+func (s *ScaleSession) Done() bool {
+	return s.position == len(s.sequence)
+}
+
+// Mistakes returns the number of wrong notes played
+func (s *ScaleSession) Mistakes() int {
+	return s.mistakes
+}
+
+// Strict reports whether passing notes count as mistakes
+func (s *ScaleSession) Strict() bool {
+	return s.strict
+}
+
+// SetStrict sets whether passing notes count as mistakes
+func (s *ScaleSession) SetStrict(strict bool) {
+	s.strict = strict
+}
+
+// SetOctaveFold sets whether the expected note may be played in any octave
+func (s *ScaleSession) SetOctaveFold(fold bool) {
+	s.octaveFold = fold
+}
+
+// Elapsed returns the time from the first correct note to the last one, or
+// to the given time while the scale is still being played
+func (s *ScaleSession) Elapsed(at time.Time) time.Duration {
+	switch {
+	case s.started.IsZero():
+		return 0
+	case s.Done():
+		return s.finished.Sub(s.started)
+	}
+	return at.Sub(s.started)
+}
+
+// Add judges a note played at the given time, moving on when it's the
+// expected one
+func (s *ScaleSession) Add(note Note, at time.Time) ScaleMove {
+	if s.Done() {
+		return ScaleIgnored
+	}
+
+	played := writtenNumber(&note)
+	expected := writtenNumber(&s.sequence[s.position])
+	if s.octaveFold {
+		// Take the played note in the octave nearest the expected one
+		played += 12 * int(math.Round(float64(expected-played)/12))
+	}
+
+	if played == expected {
+		if s.started.IsZero() {
+			s.started = at
+		}
+		s.position++
+		if s.Done() {
+			s.finished = at
+			return ScaleCompleted
+		}
+		return ScaleAdvanced
+	}
+
+	if s.position > 0 {
+		// Playing the previous note again doesn't count, e.g. a reattack
+		previous := writtenNumber(&s.sequence[s.position-1])
+		if played == previous {
+			return ScaleIgnored
+		}
+
+		// Neither do notes passed on the way to the expected one
+		if !s.strict && played > min(previous, expected) && played < max(previous, expected) {
+			return ScaleIgnored
+		}
+	}
+
+	s.mistakes++
+	return ScaleMistake
+}
+
+// Restart starts the scale over
+func (s *ScaleSession) Restart() {
+	s.position = 0
+	s.mistakes = 0
+	s.started, s.finished = time.Time{}, time.Time{}
+}
@@ -0,0 +1,120 @@
+package pitch
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scaleNames returns the names of a scale session's notes, e.g. "G3 A3 B3"
+func scaleNames(session *ScaleSession) string {
+	names := make([]string, len(session.Sequence()))
+	for i, note := range session.Sequence() {
+		names[i] = fmt.Sprintf("%s%d", note.Name, note.Octave)
+	}
+	return strings.Join(names, " ")
+}
+
+func TestScaleSequences(t *testing.T) {
+	tests := []struct {
+		root  string
+		scale Scale
+		want  string
+	}{
+		{"G3", ScaleMajor, "G3 A3 B3 C4 D4 E4 F#4 G4 F#4 E4 D4 C4 B3 A3 G3"},
+		{"F3", ScaleMajor, "F3 G3 A3 Bb3 C4 D4 E4 F4 E4 D4 C4 Bb3 A3 G3 F3"},
+		{"A3", ScaleMelodicMinor, "A3 B3 C4 D4 E4 F#4 G#4 A4 G4 F4 E4 D4 C4 B3 A3"},
+		{"D4", ScaleNaturalMinor, "D4 E4 F4 G4 A4 Bb4 C5 D5 C5 Bb4 A4 G4 F4 E4 D4"},
+		{"E2", ScaleMinorPentatonic, "E2 G2 A2 B2 D3 E3 D3 B2 A2 G2 E2"},
+		{"Bb2", ScaleMajorPentatonic, "Bb2 C3 D3 F3 G3 Bb3 G3 F3 D3 C3 Bb2"},
+	}
+	for _, tt := range tests {
+		root, err := ParseNote(tt.root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := scaleNames(NewScaleSession(tt.scale, *root, false)); got != tt.want {
+			t.Errorf("%s %s:\n got %s\nwant %s", tt.root, tt.scale.Name, got, tt.want)
+		}
+	}
+}
+
+// playScale plays notes by name a second apart, returning the moves
+func playScale(t *testing.T, session *ScaleSession, start time.Time, names ...string) []ScaleMove {
+	t.Helper()
+	var moves []ScaleMove
+	for i, name := range names {
+		note, err := ParseNote(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		moves = append(moves, session.Add(*note, start.Add(time.Duration(i)*time.Second)))
+	}
+	return moves
+}
+
+func TestScaleProgression(t *testing.T) {
+	root, _ := ParseNote("C4")
+	start := time.Unix(0, 0)
+	tests := []struct {
+		name     string
+		strict   bool
+		fold     bool
+		played   []string
+		moves    []ScaleMove
+		position int
+		mistakes int
+	}{
+		{"in order", false, false, []string{"C4", "D4", "E4"}, []ScaleMove{ScaleAdvanced, ScaleAdvanced, ScaleAdvanced}, 3, 0},
+		{"wrong note", false, false, []string{"C4", "F4", "D4"}, []ScaleMove{ScaleAdvanced, ScaleMistake, ScaleAdvanced}, 2, 1},
+		{"reattack", false, false, []string{"C4", "C4", "D4"}, []ScaleMove{ScaleAdvanced, ScaleIgnored, ScaleAdvanced}, 2, 0},
+		{"lenient passing note", false, false, []string{"C4", "C#4", "D4"}, []ScaleMove{ScaleAdvanced, ScaleIgnored, ScaleAdvanced}, 2, 0},
+		{"strict passing note", true, false, []string{"C4", "C#4", "D4"}, []ScaleMove{ScaleAdvanced, ScaleMistake, ScaleAdvanced}, 2, 1},
+		{"wrong first note", false, false, []string{"D4", "C4"}, []ScaleMove{ScaleMistake, ScaleAdvanced}, 1, 1},
+		{"other octave", false, false, []string{"C5"}, []ScaleMove{ScaleMistake}, 0, 1},
+		{"other octave folded", false, true, []string{"C5", "D3"}, []ScaleMove{ScaleAdvanced, ScaleAdvanced}, 2, 0},
+	}
+	for _, tt := range tests {
+		session := NewScaleSession(ScaleMajor, *root, tt.strict)
+		session.SetOctaveFold(tt.fold)
+		moves := playScale(t, session, start, tt.played...)
+		for i := range moves {
+			if moves[i] != tt.moves[i] {
+				t.Errorf("%s: note %d (%s) moved %d, want %d", tt.name, i, tt.played[i], moves[i], tt.moves[i])
+			}
+		}
+		if session.Position() != tt.position || session.Mistakes() != tt.mistakes || session.Done() {
+			t.Errorf("%s: at %d with %d mistakes, want %d with %d", tt.name, session.Position(), session.Mistakes(), tt.position, tt.mistakes)
+		}
+	}
+}
+
+func TestScaleCompletion(t *testing.T) {
+	root, _ := ParseNote("C4")
+	session := NewScaleSession(ScaleMajor, *root, false)
+	start := time.Unix(0, 0)
+	if session.Elapsed(start.Add(time.Hour)) != 0 {
+		t.Error("time counted before the first note")
+	}
+
+	// A wrong note first, then the scale up and down from two seconds in
+	up := []string{"C4", "D4", "E4", "F4", "G4", "A4", "B4", "C5"}
+	down := []string{"B4", "A4", "G4", "F4", "E4", "D4", "C4"}
+	playScale(t, session, start, "G2")
+	moves := playScale(t, session, start.Add(2*time.Second), append(up, down...)...)
+	if session.Elapsed(start.Add(time.Hour)) != 14*time.Second {
+		t.Errorf("elapsed %v, want 14s from the first correct note to the last", session.Elapsed(start.Add(time.Hour)))
+	}
+	if moves[len(moves)-1] != ScaleCompleted || !session.Done() || session.Position() != 15 || session.Mistakes() != 1 {
+		t.Errorf("after the last note: moved %d, done %v, at %d with %d mistakes", moves[len(moves)-1], session.Done(), session.Position(), session.Mistakes())
+	}
+	if move := playScale(t, session, start.Add(time.Minute), "D4")[0]; move != ScaleIgnored || session.Mistakes() != 1 {
+		t.Errorf("note after the end moved %d", move)
+	}
+
+	session.Restart()
+	if session.Done() || session.Position() != 0 || session.Mistakes() != 0 || session.Elapsed(start.Add(time.Hour)) != 0 {
+		t.Errorf("after Restart: at %d with %d mistakes", session.Position(), session.Mistakes())
+	}
+}
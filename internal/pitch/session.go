@@ -0,0 +1,78 @@
+package pitch
+
+import "time"
+
+// SessionSummary sums up the notes played over a session
+type SessionSummary struct {
+	Notes           int           // Notes played
+	Distinct        int           // Different sounding notes played, telling octaves apart
+	MostPlayed      Note          // Note played most often, the lowest of equals
+	MostPlayedPlays int           // Times MostPlayed was played
+	Duration        time.Duration // Time from the first note to the latest
+	PitchClasses    [12]int       // Notes played per written pitch class (0 = C)
+}
+
+// sessionTally counts the plays of one note
+type sessionTally struct {
+	note  Note
+	plays int
+}
+
+// SessionStats counts the notes played over a session, for a summary of
+// what was practiced. Unlike the timeline, it keeps counting however long
+// the session runs.
+type SessionStats struct {
+	first, latest time.Time
+	notes         int
+	tallies       map[int]*sessionTally // By sounding MIDI note
+	pitchClasses  [12]int
+}
+
+// NewSessionStats creates empty session statistics
+func NewSessionStats() *SessionStats {
+	return &SessionStats{tallies: make(map[int]*sessionTally)}
+}
+
+// Add records a note played at the given time
+func (s *SessionStats) Add(note Note, at time.Time) {
+	if s.notes == 0 {
+		s.first = at
+	}
+	s.latest = at
+	s.notes++
+
+	tally, ok := s.tallies[note.MIDINote]
+	if !ok {
+		tally = &sessionTally{}
+		s.tallies[note.MIDINote] = tally
+	}
+	tally.note = note
+	tally.plays++
+	s.pitchClasses[((note.PitchClass%12)+12)%12]++
+}
+
+// Summary returns the statistics of the notes played so far
+func (s *SessionStats) Summary() SessionSummary {
+	summary := SessionSummary{
+		Notes:        s.notes,
+		Distinct:     len(s.tallies),
+		Duration:     s.latest.Sub(s.first),
+		PitchClasses: s.pitchClasses,
+	}
+	for midi, tally := range s.tallies {
+		if tally.plays > summary.MostPlayedPlays ||
+			tally.plays == summary.MostPlayedPlays && midi < summary.MostPlayed.MIDINote {
+			summary.MostPlayed = tally.note
+			summary.MostPlayedPlays = tally.plays
+		}
+	}
+	return summary
+}
+
+// Reset forgets every note, e.g. when the note history is cleared
+func (s *SessionStats) Reset() {
+	s.first, s.latest = time.Time{}, time.Time{}
+	s.notes = 0
+	clear(s.tallies)
+	s.pitchClasses = [12]int{}
+}
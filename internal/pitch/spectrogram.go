@@ -0,0 +1,57 @@
+package pitch
+
+import "math"
+
+// SpectrogramScale divides a frequency range into bands of equal width in
+// cents, for drawing a spectrum with the pitch axis a musician expects
+type SpectrogramScale struct {
+	Bands int     // Number of bands
+	Low   float64 // Lower edge of the lowest band (Hz)
+	High  float64 // Upper edge of the highest band (Hz)
+}
+
+// DefaultSpectrogramScale covers most instruments' fundamentals, from just
+// below E2 up to C7, in bands a little over a semitone wide
+var DefaultSpectrogramScale = SpectrogramScale{Bands: 40, Low: 80, High: 2000}
+
+// Band returns the band holding a frequency, or -1 outside the scale's range
+func (s SpectrogramScale) Band(frequency float64) int {
+	if !(frequency >= s.Low && frequency < s.High) {
+		return -1
+	}
+	return min(s.Bands-1, int(float64(s.Bands)*math.Log(frequency/s.Low)/math.Log(s.High/s.Low)))
+}
+
+// Frequency returns the lower edge of a band in Hz
+func (s SpectrogramScale) Frequency(band int) float64 {
+	return s.Low * math.Pow(s.High/s.Low, float64(band)/float64(s.Bands))
+}
+
+// Levels downsamples a one-sided spectrum from FFTDetector.Spectrum to the
+// scale's bands, lowest first, as the level of each band's strongest bin in
+// dB relative to the strongest band. Bands narrower than a bin take the
+// nearest bin.
+func (s SpectrogramScale) Levels(spectrum []complex128, sampleRate int) []float64 {
+	levels := make([]float64, s.Bands)
+	if len(spectrum) < 2 || sampleRate <= 0 {
+		return levels
+	}
+
+	binSize := spectrumBinSize(spectrum, sampleRate)
+	loudest := 0.0
+	for band := range levels {
+		low := int(math.Round(s.Frequency(band) / binSize))
+		high := max(low, int(math.Round(s.Frequency(band+1)/binSize))-1)
+		levels[band] = maxMagnitudeIn(spectrum, low, high)
+		loudest = math.Max(loudest, levels[band])
+	}
+
+	for band, magnitude := range levels {
+		if magnitude <= 0 || loudest <= 0 {
+			levels[band] = math.Inf(-1)
+			continue
+		}
+		levels[band] = 20 * math.Log10(magnitude/loudest)
+	}
+	return levels
+}
@@ -0,0 +1,79 @@
+package pitch
+
+// Spelling selects how notes between the natural notes are named
+type Spelling int
+
+// Spelling modes
+const (
+	SpellingSharps Spelling = iota // C#, D#, F#, G#, A#
+	SpellingFlats                  // Db, Eb, Gb, Ab, Bb
+	SpellingAuto                   // Follow the key signature: flats in flat keys, sharps otherwise
+)
+
+// Spellings lists the modes in the order the UI cycles through them
+var Spellings = []Spelling{SpellingSharps, SpellingFlats, SpellingAuto}
+
+// String returns the display name of the spelling mode
+func (s Spelling) String() string {
+	switch s {
+	case SpellingFlats:
+		return "Flats"
+	case SpellingAuto:
+		return "Auto"
+	default:
+		return "Sharps"
+	}
+}
+
+// Key signature limits, as positions on the circle of fifths
+const (
+	MinKeySignature = -7 // Seven flats (C♭ major)
+	MaxKeySignature = 7  // Seven sharps (C♯ major)
+)
+
+// Major key names indexed by key signature + 7
+var keySignatureNames = []string{
+	"C♭", "G♭", "D♭", "A♭", "E♭", "B♭", "F",
+	"C",
+	"G", "D", "A", "E", "B", "F♯", "C♯",
+}
+
+// KeySignatureName returns the name of the major key with the given number of
+// sharps (positive) or flats (negative)
+func KeySignatureName(fifths int) string {
+	if fifths < MinKeySignature || fifths > MaxKeySignature {
+		return "?"
+	}
+	return keySignatureNames[fifths-MinKeySignature]
+}
+
+// SetSpelling sets how accidentals are named
+func (c *NoteConverter) SetSpelling(spelling Spelling) {
+	c.Spelling = spelling
+}
+
+// SetKeySignature sets the key signature used by SpellingAuto, as the number
+// of sharps (positive) or flats (negative)
+func (c *NoteConverter) SetKeySignature(fifths int) {
+	// Keep the key on the circle of fifths
+	if fifths < MinKeySignature {
+		fifths = MinKeySignature
+	}
+	if fifths > MaxKeySignature {
+		fifths = MaxKeySignature
+	}
+
+	c.KeySignature = fifths
+}
+
+// usesFlats reports whether accidentals are currently spelled with flats
+func (c *NoteConverter) usesFlats() bool {
+	switch c.Spelling {
+	case SpellingFlats:
+		return true
+	case SpellingAuto:
+		return c.KeySignature < 0
+	default:
+		return false
+	}
+}
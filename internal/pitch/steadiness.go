@@ -0,0 +1,107 @@
+package pitch
+
+import (
+	"math"
+	"time"
+)
+
+// Steadiness describes how steadily a held note's pitch is sustained
+type Steadiness struct {
+	Score  float64 // 0-100, 100 for an unwavering pitch
+	StdDev float64 // Standard deviation of the cents over the window
+	Drift  float64 // Linear trend of the pitch in cents per second, positive when rising
+}
+
+// SteadinessMeter rates how steadily a held note is sustained, for long-tone
+// practice. It keeps the last two seconds of a note's pitch track and maps
+// the standard deviation of its cents onto a score, falling linearly from 100
+// for a constant pitch to 0 at 20 cents, and fits a line through it for the
+// drift.
+type SteadinessMeter struct {
+	minDuration time.Duration // Pitch track needed before rating
+	maxDuration time.Duration // Length of pitch track rated
+	zeroScore   float64       // Standard deviation scoring zero (cents)
+
+	reference float64         // Frequency of the first measurement (Hz)
+	samples   []vibratoSample // Pitch track, oldest first
+}
+
+// NewSteadinessMeter creates a new steadiness meter
+func NewSteadinessMeter() *SteadinessMeter {
+	return &SteadinessMeter{
+		minDuration: 500 * time.Millisecond, // Long enough for a meaningful spread
+		maxDuration: 2 * time.Second,        // Rates the recent part of a long tone
+		zeroScore:   20.0,                   // A fifth of a semitone wobble is clearly unsteady
+	}
+}
+
+// Add feeds the frequency measured at the given time and returns the current
+// rating once the note has been held long enough. Call Reset when the note
+// changes so the track only ever covers one note.
+func (s *SteadinessMeter) Add(frequency float64, at time.Time) (Steadiness, bool) {
+	if frequency <= 0 {
+		return Steadiness{}, false
+	}
+
+	if len(s.samples) == 0 {
+		s.reference = frequency
+	}
+	s.samples = append(s.samples, vibratoSample{
+		at:    at,
+		cents: 1200 * math.Log2(frequency/s.reference),
+	})
+
+	// Drop measurements that have fallen out of the window
+	drop := 0
+	for drop < len(s.samples) && at.Sub(s.samples[drop].at) > s.maxDuration {
+		drop++
+	}
+	s.samples = s.samples[drop:]
+
+	if len(s.samples) < 3 || at.Sub(s.samples[0].at) < s.minDuration {
+		return Steadiness{}, false
+	}
+
+	return s.rate(), true
+}
+
+// Reset clears the pitch track, e.g. when the note changes or stops
+func (s *SteadinessMeter) Reset() {
+	s.samples = s.samples[:0]
+	s.reference = 0
+}
+
+// rate computes the rating of the current pitch track
+func (s *SteadinessMeter) rate() Steadiness {
+	// Means of time (seconds from the first sample) and cents
+	n := float64(len(s.samples))
+	meanT, meanC := 0.0, 0.0
+	for _, sample := range s.samples {
+		meanT += sample.at.Sub(s.samples[0].at).Seconds()
+		meanC += sample.cents
+	}
+	meanT /= n
+	meanC /= n
+
+	// Spread of the cents, and the least-squares slope over time
+	varianceC, covariance, varianceT := 0.0, 0.0, 0.0
+	for _, sample := range s.samples {
+		dt := sample.at.Sub(s.samples[0].at).Seconds() - meanT
+		dc := sample.cents - meanC
+		varianceC += dc * dc
+		covariance += dt * dc
+		varianceT += dt * dt
+	}
+	stdDev := math.Sqrt(varianceC / n)
+
+	drift := 0.0
+	if varianceT > 0 {
+		drift = covariance / varianceT
+	}
+
+	return Steadiness{
+		Score:  100 * math.Max(0, 1-stdDev/s.zeroScore),
+		StdDev: stdDev,
+		Drift:  drift,
+	}
+}
@@ -0,0 +1,112 @@
+package pitch
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+// rateSteadiness feeds one frequency per frame interval and returns the
+// rating after the last
+func rateSteadiness(frequencies []float64) (Steadiness, bool) {
+	meter := NewSteadinessMeter()
+	var steadiness Steadiness
+	var ok bool
+	for i, frequency := range frequencies {
+		steadiness, ok = meter.Add(frequency, time.Unix(0, 0).Add(time.Duration(i)*frameInterval))
+	}
+	return steadiness, ok
+}
+
+func TestSteadinessOfConstantTone(t *testing.T) {
+	// Detected pitches of a steady sine, every 20ms for three seconds
+	const windowSize = 2048
+	detector, err := NewFFTDetector(windowSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	detector.SetPaddingFactor(4)
+
+	for _, frequency := range []float64{110, 440} {
+		buffer := audio.SynthesizeTone(frequency, []float64{1}, 3*44100, 44100)
+		meter := NewSteadinessMeter()
+		var steadiness Steadiness
+		var ok bool
+		for end := windowSize; end <= len(buffer.Samples); end += 882 {
+			note, err := detector.DetectPitch(&audio.AudioBuffer{Samples: buffer.Samples[end-windowSize : end], SampleRate: 44100})
+			if err != nil {
+				t.Fatalf("%v Hz frame ending at %d: %v", frequency, end, err)
+			}
+			steadiness, ok = meter.Add(note.Frequency, time.Unix(0, 0).Add(time.Duration(end)*time.Second/44100))
+		}
+		if !ok || steadiness.Score < 99 || math.Abs(steadiness.Drift) > 0.5 {
+			t.Errorf("%v Hz: %+v, %v, want a score of at least 99 and no drift", frequency, steadiness, ok)
+		}
+	}
+}
+
+func TestSteadinessFallsWithJitter(t *testing.T) {
+	// Random pitch wobble of a known spread around 440 Hz; the score must
+	// fall steadily and track 100 × (1 - spread/20)
+	previous := 100.0
+	for _, jitter := range []float64{1, 3, 6, 10, 15, 30} {
+		rng := rand.New(rand.NewSource(int64(jitter)))
+		frequencies := make([]float64, 40) // The full two-second window
+		for i := range frequencies {
+			frequencies[i] = 440 * math.Pow(2, jitter*rng.NormFloat64()/1200)
+		}
+
+		steadiness, ok := rateSteadiness(frequencies)
+		if !ok {
+			t.Errorf("±%v¢: no rating", jitter)
+			continue
+		}
+		want := 100 * math.Max(0, 1-steadiness.StdDev/20)
+		if math.Abs(steadiness.StdDev-jitter) > 0.3*jitter || math.Abs(steadiness.Score-want) > 1e-9 {
+			t.Errorf("±%v¢: spread %.2f¢, score %.1f, want a spread near %v¢ and a score of %.1f", jitter, steadiness.StdDev, steadiness.Score, jitter, want)
+		}
+		if steadiness.Score >= previous && previous > 0 {
+			t.Errorf("±%v¢: score %.1f didn't fall below %.1f", jitter, steadiness.Score, previous)
+		}
+		previous = steadiness.Score
+	}
+	if previous != 0 {
+		t.Errorf("±30¢: score %.1f, want 0", previous)
+	}
+}
+
+func TestSteadinessDriftFollowsRamp(t *testing.T) {
+	// A pitch gliding at a constant rate must report that rate as its drift
+	for _, rate := range []float64{-12, -3, 3, 12} {
+		frequencies := make([]float64, 40)
+		for i := range frequencies {
+			seconds := float64(i) * frameInterval.Seconds()
+			frequencies[i] = 330 * math.Pow(2, rate*seconds/1200)
+		}
+
+		steadiness, ok := rateSteadiness(frequencies)
+		if !ok || math.Abs(steadiness.Drift-rate) > 0.01 {
+			t.Errorf("%+v¢/s ramp: drift %+.3f¢/s, %v", rate, steadiness.Drift, ok)
+		}
+	}
+}
+
+func TestSteadinessNeedsHeldNote(t *testing.T) {
+	// Under half a second of track is too short to rate, and Reset starts over
+	if _, ok := rateSteadiness(held(440, 10)); ok {
+		t.Error("rated 450ms of track, want at least 500ms")
+	}
+
+	meter := NewSteadinessMeter()
+	start := time.Unix(0, 0)
+	for i := 0; i < 20; i++ {
+		meter.Add(440, start.Add(time.Duration(i)*frameInterval))
+	}
+	meter.Reset()
+	if _, ok := meter.Add(440, start.Add(20*frameInterval)); ok {
+		t.Error("rated straight after Reset")
+	}
+}
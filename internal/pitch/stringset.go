@@ -0,0 +1,253 @@
+package pitch
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+// Farthest a pitch may be from a string's target for that string to be
+// selected automatically (cents)
+const maxStringDistance = 150.0
+
+// StringSet is the open strings of a stringed instrument in one tuning
+type StringSet struct {
+	Name    string
+	Strings []Note // Open string notes at A4 = 440 Hz, lowest first
+}
+
+// Built-in guitar tunings
+var (
+	StringSetStandard   = mustStringSet("Standard", "E2 A2 D3 G3 B3 E4")
+	StringSetDropD      = mustStringSet("Drop D", "D2 A2 D3 G3 B3 E4")
+	StringSetDADGAD     = mustStringSet("DADGAD", "D2 A2 D3 G3 A3 D4")
+	StringSetOpenG      = mustStringSet("Open G", "D2 G2 D3 G3 B3 D4")
+	StringSetEbStandard = mustStringSet("E♭ Standard", "Eb2 Ab2 Db3 Gb3 Bb3 Eb4") // Half a step down
+)
+
+// StringSets lists the built-in tunings in the order the UI cycles through them
+var StringSets = []StringSet{
+	StringSetStandard,
+	StringSetDropD,
+	StringSetDADGAD,
+	StringSetOpenG,
+	StringSetEbStandard,
+}
+
+// StringSetByName returns the built-in string set with the given name,
+// ignoring case, spaces and hyphens ("drop-d" finds "Drop D")
+func StringSetByName(name string) (StringSet, bool) {
+	return FindStringSet(StringSets, name)
+}
+
+// FindStringSet returns the string set with the given name from a list, such
+// as one loaded with LoadStringSets, matching names as StringSetByName does
+func FindStringSet(sets []StringSet, name string) (StringSet, bool) {
+	for _, set := range sets {
+		if strings.EqualFold(compactName(set.Name), compactName(name)) {
+			return set, true
+		}
+	}
+	return StringSet{}, false
+}
+
+// compactName removes the spaces and hyphens from a name and spells flats
+// with a "b", so "eb-standard" finds "E♭ Standard"
+func compactName(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "♭", "b").Replace(name)
+}
+
+// LoadStringSets reads custom tunings from a file; see ParseStringSets for
+// the format
+func LoadStringSets(path string) ([]StringSet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sets, err := ParseStringSets(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return sets, nil
+}
+
+// ParseStringSets parses tunings written one per line as a name, a colon and
+// the open strings as for ParseStringSet, e.g. "Open C: C2 G2 C3 G3 C4 E4".
+// Blank lines and anything after a "#" are ignored. Errors name the
+// offending line.
+func ParseStringSets(r io.Reader) ([]StringSet, error) {
+	var sets []StringSet
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		name, notes, found := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("%w: line %d: expected a name, a colon and the strings", ErrInvalidStringSet, lineNumber)
+		}
+		open, err := parseStrings(notes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidStringSet, lineNumber, err)
+		}
+		sets = append(sets, StringSet{Name: name, Strings: open})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(sets) == 0 {
+		return nil, fmt.Errorf("%w: no tunings", ErrInvalidStringSet)
+	}
+	return sets, nil
+}
+
+// ParseStringSet builds a string set from a list of notes with their
+// octaves, lowest string first, separated by spaces or commas, e.g.
+// "D2 G2 D3 G3 B3 D4"
+func ParseStringSet(name, text string) (StringSet, error) {
+	notes, err := parseStrings(text)
+	if err != nil {
+		return StringSet{}, fmt.Errorf("%w: %v", ErrInvalidStringSet, err)
+	}
+	return StringSet{Name: name, Strings: notes}, nil
+}
+
+// parseStrings parses a list of open string notes for ParseStringSet
+func parseStrings(text string) ([]Note, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no strings")
+	}
+
+	notes := make([]Note, len(fields))
+	for i, field := range fields {
+		note, err := ParseNote(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid note %q", field)
+		}
+		notes[i] = *note
+	}
+	return notes, nil
+}
+
+// mustStringSet parses a built-in string set
+func mustStringSet(name, text string) StringSet {
+	set, err := ParseStringSet(name, text)
+	if err != nil {
+		panic(err)
+	}
+	return set
+}
+
+// StringMatch is a pitch measured against one string of a set
+type StringMatch struct {
+	Number int     // String number, counted from the highest string as 1
+	String Note    // Open string note at A4 = 440 Hz
+	Target float64 // Frequency the string tunes to at the current reference pitch (Hz)
+	Cents  float64 // Offset of the pitch from Target, negative when flat
+	Locked bool    // Whether the string was chosen by hand rather than by pitch
+}
+
+// StringSelector picks which string of a set is being tuned: the one whose
+// target lies nearest the detected pitch, within ±150 cents, unless a string
+// has been locked by hand
+type StringSelector struct {
+	set    StringSet
+	a4     float64 // Reference pitch the strings are tuned to (Hz)
+	locked int     // Locked string number, zero to select by pitch
+}
+
+// NewStringSelector creates a selector for the given strings at A4 = 440 Hz
+func NewStringSelector(set StringSet) *StringSelector {
+	return &StringSelector{
+		set: set,
+		a4:  DefaultReferenceA4,
+	}
+}
+
+// SetStringSet switches to another set of strings, releasing any lock
+func (s *StringSelector) SetStringSet(set StringSet) {
+	s.set = set
+	s.locked = 0
+}
+
+// StringSet returns the strings being tuned
+func (s *StringSelector) StringSet() StringSet {
+	return s.set
+}
+
+// SetReferenceA4 sets the reference pitch the strings are tuned to, in Hz
+func (s *StringSelector) SetReferenceA4(hz float64) error {
+	if hz <= 0 {
+		return ErrInvalidReferencePitch
+	}
+
+	s.a4 = hz
+	return nil
+}
+
+// Lock selects a string by number (1 = highest) whatever the pitch, or
+// returns to selecting by pitch for zero. Numbers beyond the set's strings
+// are ignored.
+func (s *StringSelector) Lock(number int) {
+	if number < 0 || number > len(s.set.Strings) {
+		return
+	}
+	s.locked = number
+}
+
+// Locked returns the locked string number, or zero when selecting by pitch
+func (s *StringSelector) Locked() int {
+	return s.locked
+}
+
+// Select measures a frequency against the locked string, or against the
+// string nearest to it. It returns false when nothing is locked and no
+// string lies within ±150 cents. Midway between two strings the lower one
+// wins.
+func (s *StringSelector) Select(frequency float64) (StringMatch, bool) {
+	if !(frequency > 0) || len(s.set.Strings) == 0 {
+		return StringMatch{}, false
+	}
+
+	if s.locked > 0 {
+		match := s.measure(len(s.set.Strings)-s.locked, frequency)
+		match.Locked = true
+		return match, true
+	}
+
+	// Strings are checked lowest first, and a higher one must be nearer by
+	// more than rounding error to win, so the midpoint goes to the lower one
+	best := StringMatch{}
+	found := false
+	for i := range s.set.Strings {
+		match := s.measure(i, frequency)
+		if math.Abs(match.Cents) <= maxStringDistance && (!found || math.Abs(match.Cents) < math.Abs(best.Cents)-1e-9) {
+			best, found = match, true
+		}
+	}
+	return best, found
+}
+
+// measure measures a frequency against the string at the given index
+func (s *StringSelector) measure(index int, frequency float64) StringMatch {
+	open := s.set.Strings[index]
+	target := open.Frequency * s.a4 / DefaultReferenceA4
+	return StringMatch{
+		Number: len(s.set.Strings) - index,
+		String: open,
+		Target: target,
+		Cents:  1200 * math.Log2(frequency/target),
+	}
+}
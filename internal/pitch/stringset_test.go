@@ -0,0 +1,183 @@
+package pitch
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestStringSelectorNearestString(t *testing.T) {
+	// Open string frequencies, lowest first, and a pitch cents away from one
+	standard, dadgad, dropD := StringSetStandard.Strings, StringSetDADGAD.Strings, StringSetDropD.Strings
+	off := func(open Note, cents float64) float64 { return open.Frequency * math.Pow(2, cents/1200) }
+
+	tests := []struct {
+		name      string
+		set       StringSet
+		frequency float64
+		number    int // Zero for no string
+		cents     float64
+	}{
+		// Near each string of standard tuning, flat and sharp
+		{"low E flat", StringSetStandard, off(standard[0], -30), 6, -30},
+		{"A sharp", StringSetStandard, off(standard[1], 12), 5, 12},
+		{"D in tune", StringSetStandard, off(standard[2], 0), 4, 0},
+		{"G flat", StringSetStandard, off(standard[3], -140), 3, -140},
+		{"B sharp", StringSetStandard, off(standard[4], 45), 2, 45},
+		{"high E flat", StringSetStandard, off(standard[5], -5), 1, -5},
+
+		// Midway between strings a fourth apart is beyond ±150 cents of both
+		{"midway E-A", StringSetStandard, off(standard[0], 250), 0, 0},
+		{"midway B-E", StringSetStandard, off(standard[4], 250), 0, 0},
+		// Strings a tone apart share their midpoint; the lower one wins, and
+		// just past it the upper
+		{"midway G-A", StringSetDADGAD, off(dadgad[3], 100), 3, 100},
+		{"past midway G-A", StringSetDADGAD, off(dadgad[3], 100.5), 2, -99.5},
+		{"far below", StringSetStandard, off(standard[0], -200), 0, 0},
+		{"far above", StringSetStandard, off(standard[5], 200), 0, 0},
+
+		// Drop D tunes the low string a tone down, out of the low E's reach
+		{"D2 in standard", StringSetStandard, off(dropD[0], 0), 0, 0},
+		{"D2 in drop D", StringSetDropD, off(dropD[0], 0), 6, 0},
+		{"sharp D2 in drop D", StringSetDropD, off(dropD[0], 60), 6, 60},
+		{"E2 in drop D", StringSetDropD, off(standard[0], 0), 0, 0},
+		{"flat E2 in standard", StringSetStandard, off(dropD[0], 140), 6, -60},
+		{"A2 in drop D", StringSetDropD, off(dropD[1], 0), 5, 0},
+	}
+	for _, tt := range tests {
+		match, ok := NewStringSelector(tt.set).Select(tt.frequency)
+		if tt.number == 0 {
+			if ok {
+				t.Errorf("%s: selected string %d at %+.1f¢, want none", tt.name, match.Number, match.Cents)
+			}
+			continue
+		}
+		if !ok || match.Number != tt.number || math.Abs(match.Cents-tt.cents) > 1e-6 || match.Locked {
+			t.Errorf("%s: got string %d at %+.1f¢ (found %v, locked %v), want string %d at %+.1f¢",
+				tt.name, match.Number, match.Cents, ok, match.Locked, tt.number, tt.cents)
+		}
+	}
+}
+
+func TestStringSelectorLock(t *testing.T) {
+	selector := NewStringSelector(StringSetStandard)
+
+	// A locked string is measured against whatever is played
+	selector.Lock(1)
+	match, ok := selector.Select(82.41)
+	if !ok || match.Number != 1 || !match.Locked || math.Abs(match.Cents+2400) > 0.1 {
+		t.Errorf("low E against locked string 1: got string %d at %+.1f¢ (locked %v)", match.Number, match.Cents, match.Locked)
+	}
+
+	// Numbers past the strings are ignored, zero releases the lock
+	selector.Lock(7)
+	if selector.Locked() != 1 {
+		t.Errorf("locking string 7 of 6 changed the lock to %d", selector.Locked())
+	}
+	selector.Lock(0)
+	if match, _ := selector.Select(82.41); match.Number != 6 || match.Locked {
+		t.Errorf("after unlocking: got string %d (locked %v), want 6 by pitch", match.Number, match.Locked)
+	}
+
+	// Switching tunings releases the lock too
+	selector.Lock(3)
+	selector.SetStringSet(StringSetDropD)
+	if selector.Locked() != 0 {
+		t.Errorf("lock %d kept across a change of tuning", selector.Locked())
+	}
+}
+
+func TestStringSelectorReference(t *testing.T) {
+	selector := NewStringSelector(StringSetStandard)
+	if err := selector.SetReferenceA4(442); err != nil {
+		t.Fatal(err)
+	}
+	match, ok := selector.Select(110.5)
+	if !ok || match.Number != 5 || math.Abs(match.Target-110.5) > 1e-9 || math.Abs(match.Cents) > 1e-6 {
+		t.Errorf("A2 at A4 = 442: got string %d target %.3f Hz at %+.2f¢", match.Number, match.Target, match.Cents)
+	}
+	if err := selector.SetReferenceA4(0); err == nil {
+		t.Error("reference pitch 0 accepted")
+	}
+}
+
+func TestBuiltInStringSetTargets(t *testing.T) {
+	// Frequencies at A4 = 440 Hz, lowest string first
+	tests := []struct {
+		set   StringSet
+		hertz []float64
+	}{
+		{StringSetStandard, []float64{82.41, 110.00, 146.83, 196.00, 246.94, 329.63}},
+		{StringSetDropD, []float64{73.42, 110.00, 146.83, 196.00, 246.94, 329.63}},
+		{StringSetDADGAD, []float64{73.42, 110.00, 146.83, 196.00, 220.00, 293.66}},
+		{StringSetOpenG, []float64{73.42, 98.00, 146.83, 196.00, 246.94, 293.66}},
+		{StringSetEbStandard, []float64{77.78, 103.83, 138.59, 185.00, 233.08, 311.13}},
+	}
+	for _, tt := range tests {
+		if len(tt.set.Strings) != len(tt.hertz) {
+			t.Errorf("%s: %d strings, want %d", tt.set.Name, len(tt.set.Strings), len(tt.hertz))
+			continue
+		}
+		selector := NewStringSelector(tt.set)
+		for i, want := range tt.hertz {
+			match, ok := selector.Select(want)
+			if !ok || match.Number != len(tt.hertz)-i || math.Abs(match.Target-want) > 0.01 {
+				t.Errorf("%s string %d: target %.2f Hz, want %.2f Hz", tt.set.Name, len(tt.hertz)-i, match.Target, want)
+			}
+		}
+		if found, ok := StringSetByName(tt.set.Name); !ok || found.Name != tt.set.Name {
+			t.Errorf("StringSetByName(%q) didn't find it", tt.set.Name)
+		}
+	}
+
+	for _, name := range []string{"drop-d", "DROP D", "eb-standard", "open g"} {
+		if _, ok := StringSetByName(name); !ok {
+			t.Errorf("StringSetByName(%q) found nothing", name)
+		}
+	}
+}
+
+func TestParseStringSetsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		line string // Line the error must name, empty for none
+	}{
+		{"no colon", "Open C C2 G2 C3 G3 C4 E4\n", "line 1"},
+		{"no name", ": C2 G2 C3\n", "line 1"},
+		{"no strings", "# tunings\nEmpty:\n", "line 2"},
+		{"no octave", "Open C: C2 G2 C G3 C4 E4\n", "line 1"},
+		{"bad letter", "Good: E2 A2\nBad: H2 A2\n", "line 2"},
+		{"no tunings", "# nothing here\n\n", ""},
+	}
+	for _, tt := range tests {
+		_, err := ParseStringSets(strings.NewReader(tt.text))
+		if !errors.Is(err, ErrInvalidStringSet) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, ErrInvalidStringSet)
+			continue
+		}
+		if tt.line != "" && !strings.Contains(err.Error(), tt.line+":") {
+			t.Errorf("%s: %q doesn't name %s", tt.name, err, tt.line)
+		}
+	}
+
+	if _, err := ParseStringSet("Broken", "E2 A2 X3"); !errors.Is(err, ErrInvalidStringSet) {
+		t.Errorf("ParseStringSet with a bad note: got %v, want %v", err, ErrInvalidStringSet)
+	}
+
+	sets, err := ParseStringSets(strings.NewReader("# custom\nOpen C: C2 G2 C3 G3 C4 E4\nNashville, Bb3, F4 # comma separated\n"))
+	if err == nil {
+		t.Errorf("tuning without a colon accepted: %v", sets)
+	}
+	sets, err = ParseStringSets(strings.NewReader("# custom\nOpen C: C2 G2 C3 G3 C4 E4\n\nBass: E1, A1, D2, G2 # commas\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != 2 || sets[0].Name != "Open C" || len(sets[0].Strings) != 6 || sets[1].Name != "Bass" || len(sets[1].Strings) != 4 {
+		t.Errorf("parsed %+v", sets)
+	}
+	if bass, ok := FindStringSet(sets, "bass"); !ok || bass.Strings[0].Name != "E" || bass.Strings[0].Octave != 1 {
+		t.Errorf("FindStringSet(bass) = %+v, %v", bass, ok)
+	}
+}
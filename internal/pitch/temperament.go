@@ -0,0 +1,148 @@
+package pitch
+
+import "math"
+
+// Temperament describes a tuning system as the size, in cents, of each of the
+// twelve chromatic degrees above its tonic. Temperaments loaded from Scala
+// files may have any number of degrees and repeat at any period; they list
+// them in Degrees instead.
+type Temperament struct {
+	Name  string
+	Cents [12]float64
+
+	// Scales loaded from Scala files
+	Degrees   []float64 // Cents of each degree above the 1/1, starting with 0; nil for twelve-note temperaments
+	Period    float64   // Cents of the interval the degrees repeat at
+	Reference float64   // Frequency of the 1/1 in Hz; zero tunes it to the tonic, equal-tempered from A4
+}
+
+// Built-in temperaments
+var (
+	TemperamentEqual = Temperament{
+		Name:  "Equal",
+		Cents: [12]float64{0, 100, 200, 300, 400, 500, 600, 700, 800, 900, 1000, 1100},
+	}
+	TemperamentJustMajor = Temperament{
+		Name: "Just",
+		Cents: ratiosToCents([12][2]float64{
+			{1, 1}, {16, 15}, {9, 8}, {6, 5}, {5, 4}, {4, 3},
+			{45, 32}, {3, 2}, {8, 5}, {5, 3}, {9, 5}, {15, 8},
+		}),
+	}
+	TemperamentPythagorean = Temperament{
+		Name: "Pythagorean",
+		Cents: ratiosToCents([12][2]float64{
+			{1, 1}, {256, 243}, {9, 8}, {32, 27}, {81, 64}, {4, 3},
+			{729, 512}, {3, 2}, {128, 81}, {27, 16}, {16, 9}, {243, 128},
+		}),
+	}
+	TemperamentQuarterCommaMeantone = Temperament{
+		Name: "Meantone",
+		// Fifths narrowed by a quarter syntonic comma, from E♭ to G♯
+		Cents: fifthsToCents(1200*math.Log2(5)/4, -3),
+	}
+)
+
+// Temperaments lists the built-in temperaments in the order the UI cycles through them
+var Temperaments = []Temperament{
+	TemperamentEqual,
+	TemperamentJustMajor,
+	TemperamentPythagorean,
+	TemperamentQuarterCommaMeantone,
+}
+
+// ratiosToCents converts frequency ratios above the tonic into cents
+func ratiosToCents(ratios [12][2]float64) [12]float64 {
+	var cents [12]float64
+	for i, ratio := range ratios {
+		cents[i] = 1200 * math.Log2(ratio[0]/ratio[1])
+	}
+	return cents
+}
+
+// fifthsToCents builds a temperament from a chain of twelve fifths of the
+// given size, starting the given number of fifths below the tonic
+func fifthsToCents(fifth float64, lowest int) [12]float64 {
+	var cents [12]float64
+	for position := lowest; position < lowest+12; position++ {
+		degree := ((position*7)%12 + 12) % 12
+		cents[degree] = math.Mod(float64(position)*fifth, 1200)
+		if cents[degree] < 0 {
+			cents[degree] += 1200
+		}
+	}
+	return cents
+}
+
+// SetTemperament sets the temperament cents deviations are measured against
+func (c *NoteConverter) SetTemperament(temperament Temperament) {
+	c.Temperament = temperament
+}
+
+// SetTonic sets the pitch class (0 = C) the temperament is built on
+func (c *NoteConverter) SetTonic(pitchClass int) {
+	c.Tonic = ((pitchClass % 12) + 12) % 12
+}
+
+// nearestScaleDegree finds the degree of a temperament loaded from a Scala
+// file closest to a frequency. It returns the equal-tempered note nearest
+// that degree's pitch, as a whole number of semitones from A4, along with the
+// deviation from the degree in cents and the degree itself.
+func (c *NoteConverter) nearestScaleDegree(frequency float64) (float64, float64, int) {
+	reference := c.Temperament.Reference
+	if reference <= 0 {
+		reference = c.A4 * math.Pow(2, float64(c.Tonic-9)/12)
+	}
+
+	// Work in cents above the closest 1/1 below the pitch, with the 1/1 of
+	// the next period as a candidate too
+	period := c.Temperament.Period
+	cents := 1200 * math.Log2(frequency/reference)
+	periods := math.Floor(cents / period)
+	within := cents - periods*period
+
+	bestDegree := 0
+	bestCents := period
+	for degree, degreeCents := range c.Temperament.Degrees {
+		if math.Abs(within-degreeCents) < math.Abs(within-bestCents) {
+			bestDegree = degree
+			bestCents = degreeCents
+		}
+	}
+
+	degreePitch := periods*period + bestCents
+	semitones := math.Round(12*math.Log2(reference/c.A4) + degreePitch/100)
+	return semitones, within - bestCents, bestDegree
+}
+
+// nearestDegree finds the temperament degree closest to a pitch given in
+// (fractional) equal-tempered semitones from A4. It returns that degree's
+// whole-semitone distance from A4 and the deviation from it in cents. The
+// tonic itself is tuned to equal temperament relative to A4.
+func (c *NoteConverter) nearestDegree(semitones float64) (float64, float64) {
+	// Work in cents above the closest tonic below the pitch
+	tonic := float64(c.Tonic - 9) // Semitones from A4 to the tonic in octave 4
+	relative := semitones - tonic
+	octave := math.Floor(relative / 12)
+	cents := (relative - 12*octave) * 100
+
+	// Snap away floating-point noise so a pitch exactly halfway between two
+	// notes is treated the same however it was computed
+	cents = math.Round(cents*1e6) / 1e6
+
+	// The tonic of the next octave (1200 cents) is a candidate too. A pitch
+	// exactly halfway belongs to the upper note, so deviations always fall
+	// within [-50, +50) cents in equal temperament.
+	bestDegree := 12
+	bestDeviation := cents - 1200
+	for degree, degreeCents := range c.Temperament.Cents {
+		deviation := cents - degreeCents
+		if math.Abs(deviation) < math.Abs(bestDeviation) ||
+			(math.Abs(deviation) == math.Abs(bestDeviation) && deviation < bestDeviation) {
+			bestDegree = degree
+			bestDeviation = deviation
+		}
+	}
+
+	return tonic + 12*octave + float64(bestDegree), bestDeviation
+}
@@ -0,0 +1,144 @@
+package pitch
+
+import (
+	"math"
+	"time"
+)
+
+// Tempo estimation settings
+const (
+	tempoBinWidth    = 5 * time.Millisecond    // Resolution of the inter-onset interval histogram
+	maxOnsetInterval = 2500 * time.Millisecond // Longer intervals are pauses, not beats
+	minTempo         = 40.0                    // Slowest tempo considered (BPM)
+	maxTempo         = 240.0                   // Fastest tempo considered (BPM)
+	maxBeatsPerGap   = 4                       // Most beats an interval may span (missed onsets)
+
+	// Tempos outside this range must explain the intervals clearly better
+	// than one inside it to be chosen
+	preferredMinTempo = 70.0
+	preferredMaxTempo = 180.0
+	tempoPreference   = 0.9 // Score weight of tempos outside the preferred range
+)
+
+// TempoEstimator estimates the tempo from note onsets. It keeps a histogram
+// of the intervals between consecutive onsets, fading older ones out
+// exponentially, and scores every candidate beat period by how well the
+// intervals fit whole numbers of beats; an interval spanning several beats
+// (a missed onset) counts for less. Ties between a tempo and its half or
+// double are broken in favor of the 70-180 BPM range.
+type TempoEstimator struct {
+	histogram  []float64     // Weight per interval bin, tempoBinWidth wide
+	forgetTime time.Duration // Time constant of the exponential forgetting
+	tolerance  time.Duration // Timing spread of an interval still fitting a beat
+	minWeight  float64       // Histogram weight needed for an estimate
+
+	lastOnset time.Time // Most recent onset
+	fadedAt   time.Time // When the histogram was last faded
+}
+
+// NewTempoEstimator creates a new tempo estimator
+func NewTempoEstimator() *TempoEstimator {
+	return &TempoEstimator{
+		histogram:  make([]float64, int(maxOnsetInterval/tempoBinWidth)+1),
+		forgetTime: 10 * time.Second,      // Follows a tempo change within a few bars
+		tolerance:  30 * time.Millisecond, // Covers the timing of a human player
+		minWeight:  3,                     // At least three intervals
+	}
+}
+
+// AddOnset adds a note onset. Onsets must be added in order.
+func (t *TempoEstimator) AddOnset(at time.Time) {
+	previous := t.lastOnset
+	t.lastOnset = at
+	if previous.IsZero() || !at.After(previous) {
+		return
+	}
+
+	// Fade out the older intervals
+	if !t.fadedAt.IsZero() {
+		fade := math.Exp(-at.Sub(t.fadedAt).Seconds() / t.forgetTime.Seconds())
+		for i := range t.histogram {
+			t.histogram[i] *= fade
+		}
+	}
+	t.fadedAt = at
+
+	if interval := at.Sub(previous); interval <= maxOnsetInterval {
+		t.histogram[int((interval+tempoBinWidth/2)/tempoBinWidth)]++
+	}
+}
+
+// Estimate returns the tempo in BPM along with how much of the interval
+// histogram it explains as confidence (0.0-1.0). Until enough onsets have
+// been added it returns zero for both.
+func (t *TempoEstimator) Estimate() (bpm float64, confidence float64) {
+	total := 0.0
+	for _, weight := range t.histogram {
+		total += weight
+	}
+	if total < t.minWeight {
+		return 0, 0
+	}
+
+	// Score candidate tempos a BPM apart, preferring the common range when a
+	// tempo and its half or double explain the intervals about as well
+	bestScore := 0.0
+	bestPeriod := 0.0
+	for tempo := minTempo; tempo <= maxTempo; tempo++ {
+		period := 60 / tempo
+		score, _ := t.fit(period)
+		if tempo < preferredMinTempo || tempo > preferredMaxTempo {
+			score *= tempoPreference
+		}
+		if score > bestScore {
+			bestScore, bestPeriod = score, period
+		}
+	}
+	if bestPeriod == 0 {
+		return 0, 0
+	}
+
+	// Refine the period from the intervals that fit it, twice, as the first
+	// refinement may move it by most of a step
+	score, period := t.fit(bestPeriod)
+	score, period = t.fit(period)
+	return 60 / period, math.Min(1, score/total)
+}
+
+// Reset forgets every onset, e.g. when the note history is cleared
+func (t *TempoEstimator) Reset() {
+	clear(t.histogram)
+	t.lastOnset = time.Time{}
+	t.fadedAt = time.Time{}
+}
+
+// fit scores how well the intervals in the histogram fit whole numbers of
+// beats of the given period (in seconds), and returns the period that fits
+// them best: the weighted mean of the intervals divided by their beat counts
+func (t *TempoEstimator) fit(period float64) (score float64, refined float64) {
+	tolerance := t.tolerance.Seconds()
+	sumWeights := 0.0
+	sumPeriods := 0.0
+	for bin, weight := range t.histogram {
+		if weight < 1e-3 {
+			continue
+		}
+
+		interval := float64(bin) * tempoBinWidth.Seconds()
+		beats := math.Round(interval / period)
+		if beats < 1 || beats > maxBeatsPerGap {
+			continue
+		}
+
+		deviation := interval - beats*period
+		fit := weight * math.Exp(-deviation*deviation/(2*tolerance*tolerance))
+		score += fit / beats
+		sumWeights += fit
+		sumPeriods += fit * interval / beats
+	}
+
+	if sumWeights == 0 {
+		return 0, period
+	}
+	return score, sumPeriods / sumWeights
+}
@@ -0,0 +1,74 @@
+package pitch
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// onsetTrain returns onsets at a tempo, each jittered by up to ±jitter, with
+// about one in dropEvery left out
+func onsetTrain(bpm float64, beats int, jitter time.Duration, dropEvery int, seed int64) []time.Time {
+	rng := rand.New(rand.NewSource(seed))
+	start := time.Unix(0, 0)
+	period := time.Duration(float64(time.Minute) / bpm)
+	var onsets []time.Time
+	for beat := 0; beat < beats; beat++ {
+		if dropEvery > 0 && rng.Intn(dropEvery) == 0 {
+			continue
+		}
+		offset := time.Duration((2*rng.Float64() - 1) * float64(jitter))
+		onsets = append(onsets, start.Add(time.Duration(beat)*period+offset))
+	}
+	return onsets
+}
+
+func TestTempoEstimates(t *testing.T) {
+	for _, bpm := range []float64{60, 100, 140} {
+		for _, dropEvery := range []int{0, 8} {
+			for seed := int64(1); seed <= 5; seed++ {
+				estimator := NewTempoEstimator()
+				for _, onset := range onsetTrain(bpm, 32, 20*time.Millisecond, dropEvery, seed) {
+					estimator.AddOnset(onset)
+				}
+				estimate, confidence := estimator.Estimate()
+				if math.Abs(estimate-bpm) > 2 {
+					t.Errorf("%v BPM, dropping 1 in %d, seed %d: estimated %.1f BPM", bpm, dropEvery, seed, estimate)
+				}
+				if confidence <= 0 || confidence > 1 {
+					t.Errorf("%v BPM, dropping 1 in %d, seed %d: confidence %.2f", bpm, dropEvery, seed, confidence)
+				}
+			}
+		}
+	}
+}
+
+func TestTempoNeedsOnsets(t *testing.T) {
+	estimator := NewTempoEstimator()
+	for i, onset := range onsetTrain(100, 3, 0, 0, 1) {
+		estimator.AddOnset(onset)
+		if bpm, _ := estimator.Estimate(); bpm != 0 {
+			t.Errorf("estimated %.1f BPM from %d onsets", bpm, i+1)
+		}
+	}
+}
+
+func TestTempoReset(t *testing.T) {
+	estimator := NewTempoEstimator()
+	for _, onset := range onsetTrain(140, 16, 0, 0, 1) {
+		estimator.AddOnset(onset)
+	}
+	estimator.Reset()
+	if bpm, confidence := estimator.Estimate(); bpm != 0 || confidence != 0 {
+		t.Errorf("after reset: %.1f BPM at confidence %.2f, want none", bpm, confidence)
+	}
+
+	// A new tempo after the reset owes nothing to the old one
+	for _, onset := range onsetTrain(80, 16, 0, 0, 1) {
+		estimator.AddOnset(onset.Add(time.Hour))
+	}
+	if bpm, _ := estimator.Estimate(); math.Abs(bpm-80) > 2 {
+		t.Errorf("after reset and 80 BPM: estimated %.1f BPM", bpm)
+	}
+}
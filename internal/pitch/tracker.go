@@ -0,0 +1,351 @@
+package pitch
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// NoteTracker consumes raw detections and emits stable notes. It takes the
+// median note over a short window of recent detections and requires a new
+// note to persist for several consecutive frames before switching to it, so
+// a pitch sitting near a semitone boundary doesn't flip the displayed note.
+// The cents of the stable note are smoothed with an exponential moving
+// average that restarts whenever the note changes. Every change of stable
+// note is also recorded as NoteOn/NoteOff events, collected with Events;
+// NoteOn carries the interval from the previous stable note, even across a
+// silence, and a silence of at least the minimum rest length is recorded as
+// a Rest once the next note starts. With octave folding, notes that differ
+// only by octaves count as the same note, so an octave flip neither switches
+// the stable note's name nor starts a new one. A pitch drifting across the
+// boundary to a neighboring note keeps the stable note's name until it is
+// more than the boundary hysteresis past the boundary, its cents reading
+// beyond ±50. A pitch sliding steadily over more than a semitone is reported
+// as a single Glissando rather than a note for every semitone passed.
+type NoteTracker struct {
+	windowSize int     // Number of recent detections used for the median
+	holdFrames int     // Consecutive frames a new note must persist before switching
+	window     []*Note // Recent raw detections, oldest first
+
+	stable          *Note // Currently reported note
+	candidate       int   // Note number (or pitch class) waiting to replace the stable note
+	candidateFrames int   // Consecutive frames the candidate has been the median
+
+	smoothingTime time.Duration    // Time constant of the cents moving average (0 disables it)
+	smoothedCents float64          // Current moving average of the cents
+	smoothedAt    time.Time        // When the moving average was last updated
+	clock         func() time.Time // Source of the current time
+
+	startedAt time.Time   // When the stable note began
+	events    []NoteEvent // Lifecycle events not yet collected
+	previous  *Note       // Last stable note, kept across silences for intervals
+
+	octaveFold bool // Whether notes are compared by pitch class alone
+
+	minRest     time.Duration // Shortest silence recorded as a rest
+	silentSince time.Time     // When the last note ended in silence, zero when none did
+
+	hysteresis float64 // Cents past a semitone boundary before a held note is renamed
+	center     float64 // Frequency the stable note's cents are measured from (Hz)
+
+	glideRate  float64   // Cents per 100ms a pitch must move to be sliding
+	lastPitch  float64   // Cents from A4 of the previous detection
+	lastAt     time.Time // When the previous detection was made
+	glideFrom  *Note     // Stable note when the current slide began, nil when not sliding
+	glideStart time.Time // When the current slide began
+	glideSpan  float64   // Cents the current slide has moved, signed
+	glideMoves int       // Frames the current slide has moved on
+}
+
+// NewNoteTracker creates a new note tracker
+func NewNoteTracker() *NoteTracker {
+	return &NoteTracker{
+		windowSize: 5, // ~250ms of detections at the processing loop's rate
+		holdFrames: 3, // A new note must be the median for 3 frames in a row
+		window:     make([]*Note, 0, 5),
+		candidate:  -1,
+
+		smoothingTime: 200 * time.Millisecond, // Steadies the readout without lagging behind tuning
+		clock:         time.Now,
+
+		minRest: 250 * time.Millisecond, // Gaps between detached notes are shorter
+
+		hysteresis: 10, // A warbling pitch at the boundary keeps its name
+
+		glideRate: 20, // Well above drift while holding a note
+	}
+}
+
+// SetSmoothingTime sets the time constant of the cents moving average.
+// Zero or a negative duration disables smoothing.
+func (t *NoteTracker) SetSmoothingTime(smoothingTime time.Duration) {
+	if smoothingTime < 0 {
+		smoothingTime = 0
+	}
+
+	t.smoothingTime = smoothingTime
+}
+
+// SetMinRest sets the shortest silence between two notes that is recorded as
+// a Rest. Shorter gaps, e.g. between detached notes, leave no trace.
+func (t *NoteTracker) SetMinRest(minRest time.Duration) {
+	t.minRest = minRest
+}
+
+// SetBoundaryHysteresis sets how many cents past the semitone boundary a held
+// note's pitch must move before the note is renamed, so the stable note
+// changes only beyond ±(50 + cents) from its center. Zero renames it right
+// at the boundary.
+func (t *NoteTracker) SetBoundaryHysteresis(cents float64) {
+	t.hysteresis = math.Max(0, cents)
+}
+
+// SetOctaveFold sets whether notes an octave or more apart with the same pitch
+// class count as the same note. The stable note still carries the frequency
+// and octave of the latest detection.
+func (t *NoteTracker) SetOctaveFold(fold bool) {
+	t.octaveFold = fold
+}
+
+// OctaveFold reports whether notes are compared by pitch class alone
+func (t *NoteTracker) OctaveFold() bool {
+	return t.octaveFold
+}
+
+// Matches reports whether a detection is of the stable note, ignoring the
+// octave when folding
+func (t *NoteTracker) Matches(note *Note) bool {
+	return t.stable != nil && note != nil && t.key(note) == t.key(t.stable)
+}
+
+// Update feeds a raw detection into the tracker and returns the stable note,
+// along with whether it changed to a different note. Passing nil (silence)
+// ends the current note and resets the tracker.
+func (t *NoteTracker) Update(note *Note) (stable *Note, changed bool) {
+	return t.UpdateAt(note, t.clock())
+}
+
+// UpdateAt is like Update, but timestamps events and smoothing with the time
+// the frame was captured rather than the current time
+func (t *NoteTracker) UpdateAt(note *Note, at time.Time) (stable *Note, changed bool) {
+	if note == nil {
+		changed = t.stable != nil
+		if changed {
+			t.silentSince = at
+		}
+		t.end(at)
+		t.clear()
+		return nil, changed
+	}
+
+	// Report a whole slide once it lands
+	glide := t.followGlide(note, at)
+	if glide == glideLanded {
+		return t.stable, true
+	}
+
+	// Add the detection to the window, dropping the oldest one when full
+	if len(t.window) == t.windowSize {
+		copy(t.window, t.window[1:])
+		t.window = t.window[:len(t.window)-1]
+	}
+	t.window = append(t.window, note)
+
+	median := t.medianNote()
+	medianKey := t.key(median)
+
+	// Still on the stable note: refresh its frequency and cents
+	if t.Matches(median) {
+		t.center = noteCenter(median)
+		t.stable = t.smooth(median, false, at)
+		t.candidate = -1
+		t.candidateFrames = 0
+		return t.stable, false
+	}
+
+	// Just across the boundary: keep the stable note, with the cents
+	// measured from its center
+	if held, ok := t.hold(median); ok {
+		t.stable = t.smooth(held, false, at)
+		t.candidate = -1
+		t.candidateFrames = 0
+		return t.stable, false
+	}
+
+	// While the pitch slides, a different note waits for it to land
+	if glide == glideSliding {
+		return t.stable, false
+	}
+
+	// A different note must persist before it replaces the stable one
+	if medianKey == t.candidate {
+		t.candidateFrames++
+	} else {
+		t.candidate = medianKey
+		t.candidateFrames = 1
+	}
+
+	// The very first note only needs to win the median once
+	if t.stable != nil && t.candidateFrames < t.holdFrames {
+		return t.stable, false
+	}
+
+	t.end(at)
+	t.center = noteCenter(median)
+	t.stable = t.smooth(median, true, at)
+	t.startedAt = at
+	t.rest(at)
+	t.events = append(t.events, t.noteOn(at))
+	t.candidate = -1
+	t.candidateFrames = 0
+	return t.stable, true
+}
+
+// Stable returns the currently reported note, or nil if there is none
+func (t *NoteTracker) Stable() *Note {
+	return t.stable
+}
+
+// Events returns the note lifecycle events since the last call, oldest first
+func (t *NoteTracker) Events() []NoteEvent {
+	events := t.events
+	t.events = nil
+	return events
+}
+
+// Reset ends the current note and clears the tracker state, including the
+// previous note intervals are measured from
+func (t *NoteTracker) Reset() {
+	t.end(t.clock())
+	t.clear()
+	t.previous = nil
+	t.silentSince = time.Time{}
+}
+
+// noteOn builds the NoteOn event for the new stable note, with the interval
+// from the previous stable note, and makes the new note the previous one
+func (t *NoteTracker) noteOn(at time.Time) NoteOn {
+	event := NoteOn{Note: *t.stable, At: at}
+	if t.previous != nil {
+		event.Previous = t.previous
+		event.Interval = IntervalBetween(*t.previous, *t.stable)
+	}
+
+	current := *t.stable
+	t.previous = &current
+	return event
+}
+
+// hold returns the detection renamed as the stable note, and true, when its
+// pitch lies within the boundary hysteresis of the stable note
+func (t *NoteTracker) hold(note *Note) (*Note, bool) {
+	if t.stable == nil || t.center <= 0 || note.Frequency <= 0 {
+		return nil, false
+	}
+
+	cents := 1200 * math.Log2(note.Frequency/t.center)
+	if math.Abs(cents) > 50+t.hysteresis {
+		return nil, false
+	}
+
+	held := *t.stable
+	held.Frequency = note.Frequency
+	held.RawCents = cents
+	held.Confidence = note.Confidence
+	held.Precise = false
+	return &held, true
+}
+
+// noteCenter returns the frequency a detection's cents are measured from
+func noteCenter(note *Note) float64 {
+	return note.Frequency / math.Pow(2, note.RawCents/1200)
+}
+
+// rest records a Rest for the silence before a note starting at the given
+// time, if there was one long enough
+func (t *NoteTracker) rest(at time.Time) {
+	if t.silentSince.IsZero() {
+		return
+	}
+
+	if duration := at.Sub(t.silentSince); duration >= t.minRest {
+		t.events = append(t.events, Rest{At: t.silentSince, Duration: duration})
+	}
+	t.silentSince = time.Time{}
+}
+
+// end records a NoteOff event for the stable note, if there is one
+func (t *NoteTracker) end(at time.Time) {
+	if t.stable == nil {
+		return
+	}
+
+	t.events = append(t.events, NoteOff{
+		Note:     *t.stable,
+		At:       at,
+		Duration: at.Sub(t.startedAt),
+	})
+}
+
+// clear resets the tracking state, keeping uncollected events. The pitch
+// followed for slides is forgotten too, so the next note doesn't count as
+// sliding from the last one.
+func (t *NoteTracker) clear() {
+	t.window = t.window[:0]
+	t.stable = nil
+	t.candidate = -1
+	t.candidateFrames = 0
+	t.smoothedAt = time.Time{}
+	t.lastPitch = 0
+	t.lastAt = time.Time{}
+	t.stopGlide()
+}
+
+// smooth returns a copy of the note with its cents replaced by the moving
+// average of the raw cents, starting a new average when restart is set
+func (t *NoteTracker) smooth(note *Note, restart bool, now time.Time) *Note {
+	if restart || t.smoothingTime <= 0 || t.smoothedAt.IsZero() {
+		t.smoothedCents = note.RawCents
+	} else {
+		// Weight the new value by the time elapsed since the last one, so the
+		// time constant holds regardless of the frame rate
+		alpha := 1 - math.Exp(-now.Sub(t.smoothedAt).Seconds()/t.smoothingTime.Seconds())
+		t.smoothedCents += alpha * (note.RawCents - t.smoothedCents)
+	}
+	t.smoothedAt = now
+
+	smoothed := *note
+	smoothed.Cents = t.smoothedCents
+	return &smoothed
+}
+
+// medianNote returns the most recent detection of the median note in the window
+func (t *NoteTracker) medianNote() *Note {
+	numbers := make([]int, len(t.window))
+	for i, note := range t.window {
+		numbers[i] = noteNumber(note)
+	}
+	sort.Ints(numbers)
+	median := numbers[len(numbers)/2]
+
+	for i := len(t.window) - 1; i >= 0; i-- {
+		if noteNumber(t.window[i]) == median {
+			return t.window[i]
+		}
+	}
+	return t.window[len(t.window)-1]
+}
+
+// key returns the number notes are told apart by: the note number, or the
+// pitch class alone when folding octaves
+func (t *NoteTracker) key(note *Note) int {
+	if t.octaveFold {
+		return note.ConcertPitchClass
+	}
+	return noteNumber(note)
+}
+
+// noteNumber returns the number of semitones between C0 and the sounding note
+func noteNumber(note *Note) int {
+	return note.ConcertOctave*12 + note.ConcertPitchClass
+}
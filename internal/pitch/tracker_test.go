@@ -0,0 +1,250 @@
+package pitch
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// countChanges feeds the tracker one detection per frequency, frameInterval
+// apart, and returns how many times the stable note changed
+func countChanges(tracker *NoteTracker, frequencies []float64) int {
+	converter := NewNoteConverter()
+	start := time.Unix(0, 0)
+	changes := 0
+	for i, frequency := range frequencies {
+		if _, changed := tracker.UpdateAt(converter.FromFrequency(frequency), start.Add(time.Duration(i)*frameInterval)); changed {
+			changes++
+		}
+	}
+	return changes
+}
+
+func TestTrackerBorderlineAlternation(t *testing.T) {
+	cSharp4 := 277.18
+	cents := func(c float64) float64 { return cSharp4 * math.Pow(2, c/1200) }
+
+	tests := []struct {
+		name       string
+		hysteresis float64
+		a, b       float64
+	}{
+		// Either side of the C#4/D4 boundary, named C#4 and D4 in turn
+		{"across the boundary", 10, cents(48), cents(52)},
+		// Squarely on each note, leaving only the median and hold frames
+		{"neighbouring notes", 0, cents(0), cents(100)},
+	}
+	for _, tt := range tests {
+		for _, first := range []bool{true, false} {
+			frames := make([]float64, 60)
+			for i := range frames {
+				if (i%2 == 0) == first {
+					frames[i] = tt.a
+				} else {
+					frames[i] = tt.b
+				}
+			}
+
+			tracker := NewNoteTracker()
+			tracker.SetBoundaryHysteresis(tt.hysteresis)
+			if changes := countChanges(tracker, frames); changes > 1 {
+				t.Errorf("%s: stable note changed %d times over %d alternating frames, want at most once",
+					tt.name, changes, len(frames))
+			}
+		}
+	}
+}
+
+func TestTrackerSmoothsCents(t *testing.T) {
+	// An EMA weighting each frame by alpha leaves alpha/(2-alpha) of white
+	// noise's variance: about an eighth with 50ms frames and a 200ms constant
+	alpha := 1 - math.Exp(-float64(frameInterval)/float64(200*time.Millisecond))
+	wantRatio := alpha / (2 - alpha)
+
+	rng := rand.New(rand.NewSource(1))
+	converter := NewNoteConverter()
+	tracker := NewNoteTracker()
+	start := time.Unix(0, 0)
+	var raw, smoothed []float64
+	for i := 0; i < 2000; i++ {
+		note := converter.FromFrequency(440 * math.Pow(2, 5*rng.NormFloat64()/1200))
+		stable, _ := tracker.UpdateAt(note, start.Add(time.Duration(i)*frameInterval))
+		if i >= 20 { // Past the average's start-up
+			raw = append(raw, stable.RawCents)
+			smoothed = append(smoothed, stable.Cents)
+		}
+	}
+
+	ratio := variance(smoothed) / variance(raw)
+	if math.Abs(ratio-wantRatio) > wantRatio/4 {
+		t.Errorf("smoothing left %.3f of the cents variance, want %.3f", ratio, wantRatio)
+	}
+}
+
+func TestTrackerSmoothingRestartsOnNoteChange(t *testing.T) {
+	converter := NewNoteConverter()
+	tracker := NewNoteTracker()
+	start := time.Unix(0, 0)
+
+	// Settle the average on A4 at +20¢, then play B4 at -20¢
+	var frames []*Note
+	for i := 0; i < 20; i++ {
+		frames = append(frames, converter.FromFrequency(440*math.Pow(2, 20.0/1200)))
+	}
+	for i := 0; i < 10; i++ {
+		frames = append(frames, converter.FromFrequency(493.88*math.Pow(2, -20.0/1200)))
+	}
+
+	for i, note := range frames {
+		stable, changed := tracker.UpdateAt(note, start.Add(time.Duration(i)*frameInterval))
+		if i < 20 || !changed {
+			continue
+		}
+		if stable.Name != "B" {
+			t.Fatalf("changed to %s%d, want B4", stable.Name, stable.Octave)
+		}
+		if math.Abs(stable.Cents-stable.RawCents) > 1e-9 {
+			t.Errorf("B4 starts at %+.2f¢, want its own %+.2f¢ rather than A4's average", stable.Cents, stable.RawCents)
+		}
+		return
+	}
+	t.Fatal("stable note never changed to B4")
+}
+
+// variance returns the population variance of values
+func variance(values []float64) float64 {
+	mean := 0.0
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+
+	sum := 0.0
+	for _, value := range values {
+		sum += (value - mean) * (value - mean)
+	}
+	return sum / float64(len(values))
+}
+
+func TestTrackerScriptedEvents(t *testing.T) {
+	// C4 for 800ms, 200ms of silence, E4 for 400ms, then silence
+	var frames []float64
+	frames = append(frames, held(261.63, 16)...)
+	frames = append(frames, held(0, 4)...)
+	frames = append(frames, held(329.63, 8)...)
+	frames = append(frames, 0)
+
+	type want struct {
+		kind     string
+		name     string
+		at       time.Duration
+		duration time.Duration
+	}
+	noteEvents := []want{
+		{"on", "C", 0, 0},
+		{"off", "C", 800 * time.Millisecond, 800 * time.Millisecond},
+		{"on", "E", 1000 * time.Millisecond, 0},
+		{"off", "E", 1400 * time.Millisecond, 400 * time.Millisecond},
+	}
+
+	tests := []struct {
+		name    string
+		minRest time.Duration
+		want    []want
+	}{
+		// The default minimum rest is longer than the gap
+		{"short gap", 250 * time.Millisecond, noteEvents},
+		{"rest", 150 * time.Millisecond, append(append(noteEvents[:2:2],
+			want{"rest", "", 800 * time.Millisecond, 200 * time.Millisecond}), noteEvents[2:]...)},
+	}
+	for _, tt := range tests {
+		start := time.Unix(0, 0)
+		tracker := NewNoteTracker()
+		tracker.SetMinRest(tt.minRest)
+		events := trackFrames(t, tracker, start, frames)
+
+		var got []want
+		for _, event := range events {
+			switch event := event.(type) {
+			case NoteOn:
+				got = append(got, want{"on", event.Note.Name, event.At.Sub(start), 0})
+			case NoteOff:
+				got = append(got, want{"off", event.Note.Name, event.At.Sub(start), event.Duration})
+			case Rest:
+				got = append(got, want{"rest", "", event.At.Sub(start), event.Duration})
+			default:
+				got = append(got, want{kind: fmt.Sprintf("%T", event)})
+			}
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got events %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i].kind != tt.want[i].kind || got[i].name != tt.want[i].name ||
+				absDuration(got[i].at-tt.want[i].at) > frameInterval ||
+				absDuration(got[i].duration-tt.want[i].duration) > frameInterval {
+				t.Errorf("%s: event %d is %v, want %v within a frame", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+// absDuration returns the magnitude of a duration
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+func TestTrackerBoundaryHysteresisSweep(t *testing.T) {
+	// Sweep half a cent per frame, far too slowly to count as a slide, from
+	// A4 up past A#4 and back down again
+	const step = 0.5
+	var sweep []float64
+	for cents := 0.0; cents <= 110; cents += step {
+		sweep = append(sweep, cents)
+	}
+	for cents := 110.0; cents >= -10; cents -= step {
+		sweep = append(sweep, cents)
+	}
+
+	for _, hysteresis := range []float64{0, 10, 20} {
+		converter := NewNoteConverter()
+		tracker := NewNoteTracker()
+		tracker.SetBoundaryHysteresis(hysteresis)
+		start := time.Unix(0, 0)
+
+		// Renaming waits for the new name to reach the median of the window
+		// and hold there for the tracker's hold frames
+		slack := float64(tracker.windowSize/2+tracker.holdFrames) * step
+		var renames []float64
+		widest := 0.0
+		for i, cents := range sweep {
+			stable, changed := tracker.UpdateAt(converter.FromFrequency(440*math.Pow(2, cents/1200)), start.Add(time.Duration(i)*frameInterval))
+			if changed && i > 0 {
+				renames = append(renames, cents)
+			}
+			widest = math.Max(widest, math.Abs(stable.Cents))
+		}
+
+		if len(renames) != 2 {
+			t.Errorf("hysteresis %v¢: renamed at %v¢ above A4, want once each way", hysteresis, renames)
+			continue
+		}
+		if up := renames[0]; up <= 50+hysteresis || up > 50+hysteresis+slack {
+			t.Errorf("hysteresis %v¢: renamed A#4 at %+.1f¢, want just past %+v¢", hysteresis, up, 50+hysteresis)
+		}
+		if down := renames[1]; down >= 50-hysteresis || down < 50-hysteresis-slack {
+			t.Errorf("hysteresis %v¢: renamed A4 at %+.1f¢, want just past %+v¢", hysteresis, down, 50-hysteresis)
+		}
+
+		// The held note's cents read past ±50 before it is renamed
+		if hysteresis > 0 && widest <= 50 {
+			t.Errorf("hysteresis %v¢: cents never read past ±50, widest %.1f¢", hysteresis, widest)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package pitch
+
+import (
+	"math"
+	"time"
+)
+
+// Vibrato describes a periodic pitch modulation of a held note
+type Vibrato struct {
+	Rate  float64 // Modulation rate in Hz
+	Depth float64 // Peak deviation from the center pitch in cents
+}
+
+// vibratoSample is one pitch measurement of the held note
+type vibratoSample struct {
+	at    time.Time
+	cents float64 // Pitch relative to the first measurement
+}
+
+// VibratoAnalyzer estimates vibrato from the per-frame frequencies of a held
+// note. Once enough of the pitch track has been collected, it resamples the
+// track onto a uniform grid, removes the linear trend, finds the modulation
+// period with autocorrelation, and fits a sinusoid at that rate for the depth.
+type VibratoAnalyzer struct {
+	minDuration  time.Duration // Pitch track needed before estimating
+	maxDuration  time.Duration // Length of pitch track kept for the estimate
+	resampleRate float64       // Rate of the uniform grid the track is resampled to (Hz)
+	minRate      float64       // Slowest modulation reported as vibrato (Hz)
+	maxRate      float64       // Fastest modulation reported as vibrato (Hz)
+	minDepth     float64       // Shallowest modulation reported as vibrato (cents)
+	minPeriodic  float64       // Minimum normalized autocorrelation of the modulation
+
+	reference float64         // Frequency of the first measurement (Hz)
+	samples   []vibratoSample // Pitch track, oldest first
+}
+
+// NewVibratoAnalyzer creates a new vibrato analyzer
+func NewVibratoAnalyzer() *VibratoAnalyzer {
+	return &VibratoAnalyzer{
+		minDuration:  time.Second,     // Enough for several cycles of a typical 5-7 Hz vibrato
+		maxDuration:  2 * time.Second, // Follows changes in the vibrato within a couple of seconds
+		resampleRate: 50.0,            // Well above the fastest vibrato
+		minRate:      3.0,             // Slower modulations are pitch drift
+		maxRate:      10.0,            // Faster modulations are trills or noise
+		minDepth:     3.0,             // Smaller deviations are inaudible
+		minPeriodic:  0.5,             // Below this the deviation isn't periodic
+	}
+}
+
+// Add feeds the frequency measured at the given time and returns the current
+// vibrato estimate, if the pitch track shows one. Call Reset when the note
+// changes so the track only ever covers one note.
+func (v *VibratoAnalyzer) Add(frequency float64, at time.Time) (Vibrato, bool) {
+	if frequency <= 0 {
+		return Vibrato{}, false
+	}
+
+	if len(v.samples) == 0 {
+		v.reference = frequency
+	}
+	v.samples = append(v.samples, vibratoSample{
+		at:    at,
+		cents: 1200 * math.Log2(frequency/v.reference),
+	})
+
+	// Drop measurements that have fallen out of the analysis window
+	drop := 0
+	for drop < len(v.samples) && at.Sub(v.samples[drop].at) > v.maxDuration {
+		drop++
+	}
+	v.samples = v.samples[drop:]
+
+	if len(v.samples) < 2 || at.Sub(v.samples[0].at) < v.minDuration {
+		return Vibrato{}, false
+	}
+
+	return v.estimate()
+}
+
+// Reset clears the pitch track, e.g. when the note changes or stops
+func (v *VibratoAnalyzer) Reset() {
+	v.samples = v.samples[:0]
+	v.reference = 0
+}
+
+// estimate runs the rate and depth estimation on the current pitch track
+func (v *VibratoAnalyzer) estimate() (Vibrato, bool) {
+	track := v.resample()
+	detrend(track)
+
+	// Skip tracks that are essentially flat
+	energy := 0.0
+	for _, cents := range track {
+		energy += cents * cents
+	}
+	if math.Sqrt(2*energy/float64(len(track))) < v.minDepth {
+		return Vibrato{}, false
+	}
+
+	// Convert the rate range into a lag range on the uniform grid
+	minLag := int(v.resampleRate / v.maxRate)
+	if minLag < 1 {
+		minLag = 1
+	}
+	maxLag := int(math.Ceil(v.resampleRate / v.minRate))
+	if maxLag > len(track)-2 {
+		maxLag = len(track) - 2
+	}
+	if minLag >= maxLag {
+		return Vibrato{}, false
+	}
+
+	// Unbiased autocorrelation, normalized by the track energy
+	acf := make([]float64, maxLag+2)
+	for lag := range acf {
+		sum := 0.0
+		for i := 0; i+lag < len(track); i++ {
+			sum += track[i] * track[i+lag]
+		}
+		acf[lag] = sum / energy * float64(len(track)) / float64(len(track)-lag)
+	}
+
+	// Collect the autocorrelation peaks in range
+	var peakLags []int
+	highest := 0.0
+	for lag := minLag; lag <= maxLag; lag++ {
+		if acf[lag] > acf[lag-1] && acf[lag] >= acf[lag+1] {
+			peakLags = append(peakLags, lag)
+			highest = math.Max(highest, acf[lag])
+		}
+	}
+
+	// The modulation period is the shortest lag nearly as periodic as the
+	// best one, since multiples of the period correlate just as well
+	bestLag := -1
+	for _, lag := range peakLags {
+		if acf[lag] >= highest*0.9 {
+			bestLag = lag
+			break
+		}
+	}
+	if bestLag < 0 || acf[bestLag] < v.minPeriodic {
+		return Vibrato{}, false
+	}
+
+	// Refine the period with parabolic interpolation
+	prev, current, next := acf[bestLag-1], acf[bestLag], acf[bestLag+1]
+	period := float64(bestLag) + parabolicOffset(prev, current, next)
+	rate := v.resampleRate / period
+
+	// Linear interpolation flattens the peaks of a sparsely sampled track,
+	// so measure the depth by fitting a sinusoid at the found rate to the
+	// original measurements instead
+	depth := v.fitDepth(rate)
+	if depth < v.minDepth {
+		return Vibrato{}, false
+	}
+
+	return Vibrato{Rate: rate, Depth: depth}, true
+}
+
+// fitDepth returns the amplitude of the least-squares fit of a sinusoid at
+// the given rate (plus a linear trend) to the pitch track
+func (v *VibratoAnalyzer) fitDepth(rate float64) float64 {
+	// Normal equations for cents ≈ a·sin + b·cos + c + d·t
+	var ata [4][4]float64
+	var atb [4]float64
+	start := v.samples[0].at
+	for _, sample := range v.samples {
+		t := sample.at.Sub(start).Seconds()
+		row := [4]float64{math.Sin(2 * math.Pi * rate * t), math.Cos(2 * math.Pi * rate * t), 1, t}
+		for i := range row {
+			for j := range row {
+				ata[i][j] += row[i] * row[j]
+			}
+			atb[i] += row[i] * sample.cents
+		}
+	}
+
+	coefficients, ok := solve4(ata, atb)
+	if !ok {
+		return 0
+	}
+	return math.Hypot(coefficients[0], coefficients[1])
+}
+
+// solve4 solves a 4x4 linear system with Gaussian elimination and partial
+// pivoting. It reports false when the system is singular.
+func solve4(a [4][4]float64, b [4]float64) ([4]float64, bool) {
+	for col := 0; col < 4; col++ {
+		// Move the row with the largest pivot into place
+		pivot := col
+		for row := col + 1; row < 4; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return [4]float64{}, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		// Eliminate the column from the rows below
+		for row := col + 1; row < 4; row++ {
+			factor := a[row][col] / a[col][col]
+			for k := col; k < 4; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+			b[row] -= factor * b[col]
+		}
+	}
+
+	// Back substitution
+	var x [4]float64
+	for row := 3; row >= 0; row-- {
+		sum := b[row]
+		for k := row + 1; k < 4; k++ {
+			sum -= a[row][k] * x[k]
+		}
+		x[row] = sum / a[row][row]
+	}
+	return x, true
+}
+
+// resample linearly interpolates the pitch track onto a uniform grid
+func (v *VibratoAnalyzer) resample() []float64 {
+	start := v.samples[0].at
+	span := v.samples[len(v.samples)-1].at.Sub(start).Seconds()
+	track := make([]float64, int(span*v.resampleRate)+1)
+
+	j := 0
+	for i := range track {
+		t := float64(i) / v.resampleRate
+
+		// Advance to the pair of measurements surrounding t
+		for j < len(v.samples)-2 && v.samples[j+1].at.Sub(start).Seconds() < t {
+			j++
+		}
+
+		t0 := v.samples[j].at.Sub(start).Seconds()
+		t1 := v.samples[j+1].at.Sub(start).Seconds()
+		if t1 <= t0 {
+			track[i] = v.samples[j].cents
+			continue
+		}
+
+		fraction := math.Min(math.Max((t-t0)/(t1-t0), 0), 1)
+		track[i] = v.samples[j].cents + fraction*(v.samples[j+1].cents-v.samples[j].cents)
+	}
+
+	return track
+}
+
+// detrend removes the least-squares line from the values, leaving only the
+// deviation around a (possibly drifting) center pitch
+func detrend(values []float64) {
+	n := float64(len(values))
+	meanX := (n - 1) / 2
+	meanY := 0.0
+	for _, value := range values {
+		meanY += value
+	}
+	meanY /= n
+
+	covariance := 0.0
+	variance := 0.0
+	for i, value := range values {
+		dx := float64(i) - meanX
+		covariance += dx * (value - meanY)
+		variance += dx * dx
+	}
+
+	slope := 0.0
+	if variance > 0 {
+		slope = covariance / variance
+	}
+
+	for i := range values {
+		values[i] -= meanY + slope*(float64(i)-meanX)
+	}
+}
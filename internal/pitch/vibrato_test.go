@@ -0,0 +1,73 @@
+package pitch
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+)
+
+// vibratoTone returns a sine whose pitch swings by depth cents around a
+// center frequency at the given rate
+func vibratoTone(center, rate, depth float64, samples, sampleRate int) *audio.AudioBuffer {
+	buffer := &audio.AudioBuffer{Samples: make([]float32, samples), SampleRate: sampleRate}
+	phase := 0.0
+	for i := range buffer.Samples {
+		t := float64(i) / float64(sampleRate)
+		frequency := center * math.Pow(2, depth*math.Sin(2*math.Pi*rate*t)/1200)
+		phase += 2 * math.Pi * frequency / float64(sampleRate)
+		buffer.Samples[i] = float32(0.5 * math.Sin(phase))
+	}
+	return buffer
+}
+
+// analyzeVibrato detects the pitch of a buffer every 20ms and returns the
+// vibrato estimate after the last frame
+func analyzeVibrato(t *testing.T, buffer *audio.AudioBuffer) (Vibrato, bool) {
+	t.Helper()
+	const windowSize = 2048 // Short enough not to average out the modulation
+	detector, err := NewFFTDetector(windowSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	detector.SetPaddingFactor(4)
+
+	analyzer := NewVibratoAnalyzer()
+	hop := buffer.SampleRate / 50
+	var vibrato Vibrato
+	var ok bool
+	for end := windowSize; end <= len(buffer.Samples); end += hop {
+		note, err := detector.DetectPitch(&audio.AudioBuffer{Samples: buffer.Samples[end-windowSize : end], SampleRate: buffer.SampleRate})
+		if err != nil {
+			t.Fatalf("frame ending at %d: %v", end, err)
+		}
+		at := time.Unix(0, 0).Add(time.Duration(end) * time.Second / time.Duration(buffer.SampleRate))
+		vibrato, ok = analyzer.Add(note.Frequency, at)
+	}
+	return vibrato, ok
+}
+
+func TestVibratoOfModulatedSine(t *testing.T) {
+	for _, sampleRate := range []int{44100, 48000} {
+		for _, center := range []float64{220, 440} {
+			vibrato, ok := analyzeVibrato(t, vibratoTone(center, 6, 30, 3*sampleRate, sampleRate))
+			if !ok {
+				t.Errorf("%v Hz at %d Hz: no vibrato found in 6 Hz ±30¢ modulation", center, sampleRate)
+				continue
+			}
+			if math.Abs(vibrato.Rate-6) > 0.6 {
+				t.Errorf("%v Hz at %d Hz: rate %.2f Hz, want 6 Hz within 10%%", center, sampleRate, vibrato.Rate)
+			}
+			if math.Abs(vibrato.Depth-30) > 5 {
+				t.Errorf("%v Hz at %d Hz: depth ±%.1f¢, want ±30¢ within 5¢", center, sampleRate, vibrato.Depth)
+			}
+		}
+	}
+}
+
+func TestNoVibratoOnSteadySine(t *testing.T) {
+	if vibrato, ok := analyzeVibrato(t, vibratoTone(440, 6, 0, 3*44100, 44100)); ok {
+		t.Errorf("steady sine reported vibrato %.2f Hz ±%.1f¢", vibrato.Rate, vibrato.Depth)
+	}
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// infoBannerDuration is how long an informational banner stays up
+const infoBannerDuration = 5 * time.Second
+
+// Severity is how serious the problem an ErrorMsg reports is
+type Severity int
+
+const (
+	SeverityInfo    Severity = iota // Goes away by itself after infoBannerDuration
+	SeverityWarning                 // Stays up until dismissed with Esc
+	SeverityError                   // Stays up until dismissed with Esc
+)
+
+// ErrorMsg is a message to show a problem, such as an audio error, in the
+// banner under the title. A newer one replaces the one shown.
+type ErrorMsg struct {
+	Err      error
+	Severity Severity
+}
+
+// Banner styles and labels by severity
+var (
+	bannerStyles = map[Severity]lipgloss.Style{
+		SeverityInfo:    lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#2A7BBA")).Padding(0, 1),
+		SeverityWarning: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#1A1A1A")).Background(lipgloss.Color("#E5C07B")).Padding(0, 1),
+		SeverityError:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#D9534F")).Padding(0, 1),
+	}
+	bannerLabels = map[Severity]string{
+		SeverityInfo:    "Info",
+		SeverityWarning: "Warning",
+		SeverityError:   "Error",
+	}
+)
+
+// showBanner puts a problem up in the banner in place of the one shown
+func (m Model) showBanner(msg ErrorMsg, at time.Time) Model {
+	if msg.Err == nil {
+		return m
+	}
+	m.banner = msg
+	m.bannerAt = at
+	return m
+}
+
+// expireBanner takes an informational banner down once it has been up for
+// infoBannerDuration
+func (m Model) expireBanner(now time.Time) Model {
+	if m.banner.Err != nil && m.banner.Severity == SeverityInfo && now.Sub(m.bannerAt) >= infoBannerDuration {
+		m.banner = ErrorMsg{}
+	}
+	return m
+}
+
+// renderBanner renders the problem shown in the banner on one line, "" for
+// none. Those that stay up say how to dismiss them.
+func (m Model) renderBanner() string {
+	if m.banner.Err == nil {
+		return ""
+	}
+
+	label := bannerLabels[m.banner.Severity] + ": "
+	text := m.banner.Err.Error()
+	hint := ""
+	if m.banner.Severity != SeverityInfo {
+		hint = " (Esc to dismiss)"
+	}
+
+	// Cut the problem short on a narrow terminal, keeping how to dismiss it
+	style := bannerStyles[m.banner.Severity]
+	if width := m.columnWidth(); width > 0 {
+		room := width - style.GetHorizontalFrameSize() - lipgloss.Width(label+hint)
+		text = ansi.Truncate(text, max(1, room), "…")
+		style = style.MaxWidth(width)
+	}
+	return style.Render(label + text + hint)
+}
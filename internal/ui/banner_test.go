@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBannerShowsProblem(t *testing.T) {
+	m := updateModel(sizedModel(100, 30), ErrorMsg{Err: errors.New("reading audio: device lost"), Severity: SeverityError})
+	view := m.View()
+	if !strings.Contains(view, "Error: reading audio: device lost (Esc to dismiss)") {
+		t.Errorf("no error banner in:\n%s", view)
+	}
+
+	// A message without an error leaves the banner alone
+	if m = updateModel(m, ErrorMsg{Severity: SeverityInfo}); !strings.Contains(m.View(), "device lost") {
+		t.Error("empty message took the banner down")
+	}
+}
+
+func TestInfoBannerExpires(t *testing.T) {
+	m := updateModel(sizedModel(100, 30), ErrorMsg{Err: errors.New("switched to the default input"), Severity: SeverityInfo})
+	shown := m.bannerAt
+
+	if m = updateModel(m, TickMsg(shown.Add(infoBannerDuration-time.Millisecond))); m.banner.Err == nil {
+		t.Fatal("info banner went down before its time")
+	}
+	if strings.Contains(m.View(), "Esc to dismiss") {
+		t.Error("info banner asks to be dismissed")
+	}
+	if m = updateModel(m, TickMsg(shown.Add(infoBannerDuration))); strings.Contains(m.View(), "default input") {
+		t.Error("info banner still up after 5 seconds")
+	}
+
+	// Warnings stay until dismissed
+	m = updateModel(m, ErrorMsg{Err: errors.New("clipping"), Severity: SeverityWarning})
+	if m = updateModel(m, TickMsg(m.bannerAt.Add(time.Minute))); m.banner.Err == nil {
+		t.Error("warning banner expired by itself")
+	}
+}
+
+func TestEscDismissesBanner(t *testing.T) {
+	m := updateModel(sizedModel(100, 30), ErrorMsg{Err: errors.New("clipping"), Severity: SeverityWarning})
+	m = updateModel(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.banner.Err != nil || strings.Contains(m.View(), "Warning:") {
+		t.Error("Esc left the banner up")
+	}
+}
+
+func TestNewerBannerReplacesOlder(t *testing.T) {
+	m := sizedModel(100, 30)
+	m = updateModel(m, ErrorMsg{Err: errors.New("first problem"), Severity: SeverityError})
+	m = updateModel(m, ErrorMsg{Err: errors.New("second problem"), Severity: SeverityInfo})
+
+	view := m.View()
+	if strings.Contains(view, "first problem") || !strings.Contains(view, "Info: second problem") {
+		t.Errorf("banner doesn't show just the newer problem:\n%s", view)
+	}
+}
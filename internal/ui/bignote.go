@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// bigFontRows is the height of the big note's letters
+const bigFontRows = 5
+
+// bigFont draws the characters of note names in block letters, for reading
+// the note from across a stage. Flats are drawn the same whether named with
+// ♭ or b.
+var bigFont = map[rune][bigFontRows]string{
+	'A': {" ███ ", "█   █", "█████", "█   █", "█   █"},
+	'B': {"████ ", "█   █", "████ ", "█   █", "████ "},
+	'C': {" ████", "█    ", "█    ", "█    ", " ████"},
+	'D': {"████ ", "█   █", "█   █", "█   █", "████ "},
+	'E': {"█████", "█    ", "████ ", "█    ", "█████"},
+	'F': {"█████", "█    ", "████ ", "█    ", "█    "},
+	'G': {" ████", "█    ", "█  ██", "█   █", " ████"},
+	'#': {" █ █ ", "█████", " █ █ ", "█████", " █ █ "},
+	'♯': {" █ █ ", "█████", " █ █ ", "█████", " █ █ "},
+	'♭': {"█   ", "█   ", "███ ", "█  █", "███ "},
+	'b': {"█   ", "█   ", "███ ", "█  █", "███ "},
+	'-': {"    ", "    ", "████", "    ", "    "},
+	'0': {" ███ ", "█  ██", "█ █ █", "██  █", " ███ "},
+	'1': {"  █  ", " ██  ", "  █  ", "  █  ", " ███ "},
+	'2': {" ███ ", "█   █", "  ██ ", " █   ", "█████"},
+	'3': {"████ ", "    █", " ███ ", "    █", "████ "},
+	'4': {"█   █", "█   █", "█████", "    █", "    █"},
+	'5': {"█████", "█    ", "████ ", "    █", "████ "},
+	'6': {" ███ ", "█    ", "████ ", "█   █", " ███ "},
+	'7': {"█████", "    █", "   █ ", "  █  ", "  █  "},
+	'8': {" ███ ", "█   █", " ███ ", "█   █", " ███ "},
+	'9': {" ███ ", "█   █", " ████", "    █", " ███ "},
+}
+
+// bigText draws text in the big font, a column apart, reporting false if
+// the font lacks any of its characters
+func bigText(text string) ([bigFontRows]string, bool) {
+	var rows [bigFontRows]string
+	for i, r := range []rune(text) {
+		glyph, ok := bigFont[r]
+		if !ok {
+			return rows, false
+		}
+		for row := range rows {
+			if i > 0 {
+				rows[row] += " "
+			}
+			rows[row] += glyph[row]
+		}
+	}
+	return rows, true
+}
+
+// renderBigNote renders the current note's name and octave in block letters
+// in its color, reporting false when the font can't draw the name (e.g.
+// solfège) or it doesn't fit the terminal's width
+func (m Model) renderBigNote() (string, bool) {
+	rows, ok := bigText(m.noteLabel(m.currentNote))
+	if !ok {
+		return "", false
+	}
+	if width := m.columnWidth(); width > 0 && lipgloss.Width(rows[0]) > width {
+		return "", false
+	}
+
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color(m.styles.noteColor(m.currentNote))).MarginBottom(1)
+	if m.currentNote.Confidence < marginalConfidence || m.paused || !m.releaseUntil.IsZero() {
+		style = style.Faint(true)
+	}
+	return style.Render(strings.Join(rows[:], "\n")), true
+}
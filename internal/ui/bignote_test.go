@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// bigKey is the key that toggles the big note
+var bigKey = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")}
+
+// bigNoteModel returns a model on a terminal of the given size sounding F#3
+// in big mode
+func bigNoteModel(width, height int) Model {
+	m := updateModel(sizedModel(width, height), bigKey)
+	return updateModel(m, UpdateNoteMsg(*pitch.NewNoteConverter().FromFrequency(185)))
+}
+
+func TestBigTextAlignsGlyphs(t *testing.T) {
+	for _, text := range []string{"F#3", "F♯3", "B♭0", "Cb9"} {
+		rows, ok := bigText(text)
+		if !ok {
+			t.Errorf("%s: font lacks a character", text)
+			continue
+		}
+		if len(rows) < 5 || len(rows) > 7 {
+			t.Errorf("%s: %d rows, want 5 to 7", text, len(rows))
+		}
+
+		// Each glyph takes the same columns on every row, a blank column
+		// apart from the next
+		column := 0
+		for i, r := range []rune(text) {
+			glyph := bigFont[r]
+			width := len([]rune(glyph[0]))
+			for row, line := range rows {
+				cells := []rune(line)
+				if len(cells) < column+width {
+					t.Fatalf("%s: row %d is %d wide, short of glyph %d", text, row, len(cells), i)
+				}
+				if got := string(cells[column : column+width]); got != glyph[row] {
+					t.Errorf("%s: row %d has %q for %q, want %q", text, row, got, r, glyph[row])
+				}
+				if i > 0 && cells[column-1] != ' ' {
+					t.Errorf("%s: row %d has %q between glyphs %d and %d", text, row, cells[column-1], i-1, i)
+				}
+			}
+			column += width + 1
+		}
+		for row, line := range rows {
+			if got := len([]rune(line)); got != column-1 {
+				t.Errorf("%s: row %d is %d wide, want %d", text, row, got, column-1)
+			}
+		}
+	}
+
+	if _, ok := bigText("Fa3"); ok {
+		t.Error("bigText drew solfège the font has no glyphs for")
+	}
+}
+
+func TestBigNoteView(t *testing.T) {
+	m := debugSnapshot(60, 60)
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "Gate:") {
+		t.Fatalf("debug panel not shown before big mode:\n%s", view)
+	}
+	m = updateModel(m, bigKey)
+	m = updateModel(m, UpdateNoteMsg(*pitch.NewNoteConverter().FromFrequency(185)))
+	if !m.bigMode || m.tab != tabTuner {
+		t.Fatalf("b left big mode %v on tab %v", m.bigMode, m.tab)
+	}
+
+	// The rows of F#3 sit on consecutive lines, starting in the same column
+	rows, _ := bigText("F#3")
+	view := ansi.Strip(m.View())
+	lines := strings.Split(view, "\n")
+	first := -1
+	for i, line := range lines {
+		if strings.Contains(line, rows[0]) {
+			first = i
+			break
+		}
+	}
+	if first < 0 || first+len(rows) > len(lines) {
+		t.Fatalf("big F#3 not in view:\n%s", view)
+	}
+	column := strings.Index(lines[first], rows[0])
+	for row := range rows {
+		if got := strings.Index(lines[first+row], rows[row]); got != column {
+			t.Errorf("row %d of the big note at byte %d, want %d:\n%s", row, got, column, view)
+		}
+	}
+	if strings.Contains(view, "Gate:") || strings.Contains(view, "│    F    │") {
+		t.Errorf("big mode still shows the debug panel or note box:\n%s", view)
+	}
+
+	// Toggling off brings both back
+	m = updateModel(m, bigKey)
+	view = ansi.Strip(m.View())
+	if strings.Contains(view, rows[0]) || !strings.Contains(view, "Gate:") || !strings.Contains(view, "#3") {
+		t.Errorf("after leaving big mode:\n%s", view)
+	}
+}
+
+func TestBigNoteFallsBack(t *testing.T) {
+	rows, _ := bigText("F#3")
+	tests := []struct {
+		name  string
+		model func() Model
+	}{
+		{"solfège", func() Model {
+			m := bigNoteModel(60, 60)
+			for m.naming != pitch.NamingFixedDo {
+				m = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+			}
+			return m
+		}},
+		{"short terminal", func() Model {
+			// The string tuner and metronome can't be hidden to make room
+			m := bigNoteModel(60, 16).WithStringSet(pitch.StringSetStandard)
+			return updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("B")})
+		}},
+	}
+	for _, test := range tests {
+		m := test.model()
+		view := ansi.Strip(m.View())
+		if strings.Contains(view, rows[0]) || !strings.Contains(view, "╭") {
+			t.Errorf("%s: no note box in place of the big note:\n%s", test.name, view)
+		}
+		if !m.bigMode {
+			t.Errorf("%s: left big mode", test.name)
+		}
+	}
+
+	// Narrower than the letters, the note is left to the box
+	m := bigNoteModel(60, 60)
+	m.width = len([]rune(rows[0])) - 1
+	if _, ok := m.renderBigNote(); ok {
+		t.Errorf("big note drawn %d wide in %d columns", len([]rune(rows[0])), m.width)
+	}
+	m.width++
+	if _, ok := m.renderBigNote(); !ok {
+		t.Errorf("big note not drawn in the %d columns it needs", m.width)
+	}
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Chord view settings
+const (
+	maxChordNotes = 4  // Notes of a chord shown side by side
+	chordBoxWidth = 10 // Widest a chord note's box gets, inside its border
+)
+
+// setChord shows a new set of simultaneous notes, naming their chord unless
+// the symbol is given, and adds the chord to the timeline when it changes.
+// It goes before the notes are shown, to tell a new chord from one still
+// sounding.
+func (m Model) setChord(notes []pitch.Note, symbol string, at time.Time) Model {
+	notes = notes[:min(len(notes), maxChordNotes)]
+	if symbol == "" && len(notes) > 1 {
+		named := make([]*pitch.Note, len(notes))
+		for i := range notes {
+			named[i] = &notes[i]
+		}
+		if chord, ok := m.chordNamer.Identify(named); ok {
+			symbol = chord.Symbol
+		}
+	}
+
+	// The same chord carries on unless it had stopped sounding
+	sounding := m.currentNote != nil && m.releaseUntil.IsZero()
+	previous := m.chordSymbol
+	m.chordNotes = notes
+	m.chordSymbol = symbol
+	if (sounding && symbol == previous) || m.timelineFrozen {
+		return m
+	}
+
+	m = m.endChord(at)
+	if symbol != "" {
+		strongest := notes[0]
+		m.timeline = appendTimeline(m.timeline, []TimelineEntry{{Note: &strongest, Chord: symbol, Timestamp: at}}, m.timelineLength)
+	}
+	return m
+}
+
+// endChord sets the length of the chord last added to the timeline once it
+// stops sounding
+func (m Model) endChord(at time.Time) Model {
+	if last := len(m.timeline) - 1; last >= 0 && m.timeline[last].Chord != "" && m.timeline[last].Duration == 0 {
+		m.timeline[last].Duration = at.Sub(m.timeline[last].Timestamp)
+	}
+	return m
+}
+
+// renderChord renders the chord's notes side by side as small boxes in their
+// colors, strongest first, under the chord's symbol. The boxes shrink to fit
+// the terminal's width.
+func (m Model) renderChord() string {
+	cells := chordBoxWidth
+	if width := m.columnWidth(); width > 0 {
+		// Each box takes its border, and a space separates it from the next
+		count := len(m.chordNotes)
+		cells = max(1, min(cells, (width-(count-1))/count-2))
+	}
+
+	boxes := make([]string, 0, 2*len(m.chordNotes))
+	for i, note := range m.chordNotes {
+		style := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color(m.styles.theme.NoteText)).
+			Background(lipgloss.Color(m.styles.noteColor(&note))).
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#333333")).
+			Width(cells).
+			Align(lipgloss.Center)
+		if note.Confidence < marginalConfidence || m.paused {
+			style = m.styles.dimNote(style)
+		}
+		if !m.releaseUntil.IsZero() {
+			style = m.styles.releaseNote(style)
+		}
+		if i > 0 {
+			boxes = append(boxes, " ")
+		}
+		boxes = append(boxes, style.Render(m.noteLabel(&note)))
+	}
+
+	symbol := "?"
+	if m.chordSymbol != "" {
+		symbol = m.chordSymbol
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.styles.chord.Padding(0, 1).Render(symbol),
+		lipgloss.NewStyle().MarginBottom(1).Render(lipgloss.JoinHorizontal(lipgloss.Top, boxes...)))
+}
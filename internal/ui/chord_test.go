@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// chordNotes returns confident notes at the given frequencies
+func chordNotes(frequencies ...float64) []pitch.Note {
+	converter := pitch.NewNoteConverter()
+	notes := make([]pitch.Note, len(frequencies))
+	for i, frequency := range frequencies {
+		notes[i] = *converter.FromFrequency(frequency)
+		notes[i].Confidence = 0.9
+	}
+	return notes
+}
+
+func TestChordView(t *testing.T) {
+	cmaj7 := chordNotes(261.63, 329.63, 392, 493.88)
+	symbols := []string{"", "?", "C", "Cmaj7"}
+	for count := 1; count <= maxChordNotes; count++ {
+		labels := []string{"C4", "E4", "G4", "B4"}[:count]
+		for _, width := range []int{40, 50, 60} {
+			m := updateModel(sizedModel(width, 60), UpdateChordMsg{Notes: cmaj7[:count]})
+			view := m.View()
+			checkFits(t, view, width, 60)
+
+			// The boxes sit side by side, with the chord's symbol above them
+			lines := strings.Split(ansi.Strip(view), "\n")
+			top := slices.IndexFunc(lines, func(line string) bool { return strings.Contains(line, "╭") })
+			if top < 1 {
+				t.Fatalf("%d notes at %d wide: no note boxes", count, width)
+			}
+			if boxes := strings.Count(lines[top], "╭"); boxes != count {
+				t.Errorf("%d notes at %d wide: %d boxes", count, width, boxes)
+			}
+			label := top + 1
+			for strings.Trim(lines[label], "│ ") == "" {
+				label++
+			}
+			if got := strings.Fields(strings.ReplaceAll(lines[label], "│", " ")); !slices.Equal(got, labels) {
+				t.Errorf("%d notes at %d wide: boxes %q, want %q", count, width, got, labels)
+			}
+			if symbol := strings.TrimSpace(lines[top-1]); count > 1 && symbol != symbols[count-1] {
+				t.Errorf("%d notes at %d wide: symbol %q, want %q", count, width, symbol, symbols[count-1])
+			}
+
+			// A single note keeps the full-size box
+			if count == 1 && label == top+1 {
+				t.Errorf("%d notes at %d wide: single note box lost its padding", count, width)
+			}
+		}
+	}
+}
+
+func TestTimelineChordBlock(t *testing.T) {
+	withTrueColor(t)
+	m := sizedModel(100, 40)
+	entries := len(m.timeline)
+
+	// A chord goes into the timeline once however long it sounds, and a new
+	// chord gets its own block
+	m = updateModel(m, UpdateChordMsg{Notes: chordNotes(261.63, 329.63, 392)})
+	m = updateModel(m, UpdateChordMsg{Notes: chordNotes(261.63, 329.63, 392)})
+	m = updateModel(m, UpdateChordMsg{Notes: chordNotes(261.63, 329.63, 392, 493.88)})
+	var chords []string
+	for _, entry := range m.timeline[entries:] {
+		chords = append(chords, entry.Chord)
+	}
+	if !slices.Equal(chords, []string{"C", "Cmaj7"}) {
+		t.Fatalf("timeline chords %q, want [C Cmaj7]", chords)
+	}
+	if m.timeline[entries].Duration == 0 {
+		t.Errorf("the C chord was never ended")
+	}
+
+	// Unnamed notes stay out of the timeline
+	m = updateModel(m, UpdateChordMsg{Notes: chordNotes(261.63, 277.18)})
+	if len(m.timeline) != entries+2 {
+		t.Errorf("%d timeline entries after an unnamed chord, want %d", len(m.timeline), entries+2)
+	}
+
+	// The symbols render in the chord style rather than a note's color
+	m = m.switchTab(tabTimeline)
+	chordColor := background(m.styles.theme.TitleBackground)
+	for _, line := range strings.Split(m.View(), "\n") {
+		text := ansi.Strip(line)
+		at := strings.Index(text, "Cmaj7")
+		if at < 0 || !strings.Contains(text, "G3") {
+			continue
+		}
+		columns := styledColumns(line, chordColor)
+		for _, symbol := range []string{" C ", "Cmaj7"} {
+			column := ansi.StringWidth(text[:strings.Index(text, symbol)]) + len(symbol)/2
+			if !slices.Contains(columns, column) {
+				t.Errorf("%q not in the chord style: %q", strings.TrimSpace(symbol), line)
+			}
+		}
+		return
+	}
+	t.Errorf("no timeline line with the chords")
+}
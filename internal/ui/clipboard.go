@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// CopiedMsg reports the outcome of copying text to a file on terminals that
+// can't set the clipboard
+type CopiedMsg struct {
+	What string // What was copied, e.g. "the note"
+	Path string // File the text went to
+	Err  error  // Why writing the file failed, nil on success
+}
+
+// copyText copies text to the clipboard with an OSC 52 sequence, which
+// reaches the local clipboard over SSH too. On terminals without OSC 52 the
+// text goes to a temporary file instead.
+func (m Model) copyText(text, what string) (Model, tea.Cmd) {
+	if supportsOSC52(os.Getenv("TERM")) {
+		m, sent := m.sendSequence(ansi.SetSystemClipboard(text))
+		return m.withStatus("Copied "+what+" to the clipboard", false), sent
+	}
+
+	return m, func() tea.Msg {
+		file, err := os.CreateTemp("", "tunenote-*.txt")
+		if err != nil {
+			return CopiedMsg{What: what, Err: err}
+		}
+		defer file.Close()
+		if _, err := file.WriteString(text + "\n"); err != nil {
+			return CopiedMsg{What: what, Err: err}
+		}
+		return CopiedMsg{What: what, Path: file.Name()}
+	}
+}
+
+// supportsOSC52 reports whether a terminal, named as in $TERM, is likely to
+// set the clipboard from an OSC 52 sequence. The Linux console and dumb
+// terminals don't.
+func supportsOSC52(term string) bool {
+	switch term {
+	case "", "dumb", "linux":
+		return false
+	}
+	return true
+}
+
+// noteSummary writes a note for pasting, e.g. "A3 −12¢, 218.9 Hz"
+func noteSummary(label string, note *pitch.Note) string {
+	return fmt.Sprintf("%s %s¢, %.1f Hz", label, formatCents(note.Cents), note.Frequency)
+}
+
+// formatCents writes whole cents with their sign, with a true minus sign
+// for flat ones, e.g. "+3" or "−12"
+func formatCents(cents float64) string {
+	rounded := int(math.Round(cents))
+	switch {
+	case rounded < 0:
+		return fmt.Sprintf("−%d", -rounded)
+	case rounded > 0:
+		return fmt.Sprintf("+%d", rounded)
+	default:
+		return "0"
+	}
+}
+
+// timelineText writes timeline entries as a sequence for pasting, e.g.
+// "C4 D4 – Am G3→B3": notes by name, rests as dashes, chords by symbol,
+// glissandi by their two notes and the gaps a filter left as dots
+func timelineText(entries []TimelineEntry, notation pitch.Notation) string {
+	words := make([]string, len(entries))
+	for i, entry := range entries {
+		switch {
+		case entry.elided > 0:
+			words[i] = "·"
+		case entry.Note == nil:
+			words[i] = "–"
+		case entry.Chord != "":
+			words[i] = entry.Chord
+		case entry.From != nil:
+			words[i] = glissandoText(entry, notation)
+		case entry.Repeats > 1:
+			words[i] = fmt.Sprintf("%s×%d", notation.Format(entry.Note), entry.Repeats)
+		default:
+			words[i] = notation.Format(entry.Note)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// copyNote copies a summary of the current note
+func (m Model) copyNote() (Model, tea.Cmd) {
+	if m.currentNote == nil {
+		return m.withStatus("No note to copy", true), nil
+	}
+	return m.copyText(noteSummary(m.noteLabel(m.currentNote), m.currentNote), "the note")
+}
+
+// copyTimeline copies the notes the Timeline tab shows
+func (m Model) copyTimeline() (Model, tea.Cmd) {
+	if len(m.timeline) == 0 {
+		return m.withStatus("No notes to copy yet", true), nil
+	}
+	shown := m
+	shown.tab = tabTimeline
+	entries, start, end, _ := shown.timelineWindow()
+	return m.copyText(timelineText(entries[start:end], m.notation), "the timeline")
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestFormatCents(t *testing.T) {
+	tests := []struct {
+		cents float64
+		want  string
+	}{
+		{0, "0"},
+		{0.4, "0"},
+		{-0.4, "0"},
+		{3, "+3"},
+		{2.5, "+3"},
+		{-12.2, "−12"},
+		{-49.6, "−50"},
+	}
+	for _, tt := range tests {
+		if got := formatCents(tt.cents); got != tt.want {
+			t.Errorf("formatCents(%v) = %q, want %q", tt.cents, got, tt.want)
+		}
+	}
+}
+
+func TestNoteSummary(t *testing.T) {
+	note := pitch.NewNoteConverter().FromFrequency(218.5)
+	if got, want := noteSummary("A3", note), "A3 −12¢, 218.5 Hz"; got != want {
+		t.Errorf("noteSummary = %q, want %q", got, want)
+	}
+}
+
+func TestTimelineText(t *testing.T) {
+	converter := pitch.NewNoteConverter()
+	c4, d4, g3, b3 := converter.FromFrequency(261.63), converter.FromFrequency(293.66),
+		converter.FromFrequency(196), converter.FromFrequency(246.94)
+	entries := []TimelineEntry{
+		{Note: c4},
+		{Note: d4, Repeats: 3},
+		{}, // rest
+		{Note: converter.FromFrequency(220), Chord: "Am"},
+		{Note: b3, From: g3},
+		{elided: 4},
+		{Note: c4},
+	}
+
+	tests := []struct {
+		notation pitch.Notation
+		want     string
+	}{
+		{pitch.NotationScientific, "C4 D4×3 – Am G3→B3 · C4"},
+		{pitch.NotationHelmholtz, "c′ d′×3 – Am g→b · c′"},
+	}
+	for _, tt := range tests {
+		if got := timelineText(entries, tt.notation); got != tt.want {
+			t.Errorf("timelineText in %v = %q, want %q", tt.notation, got, tt.want)
+		}
+	}
+}
+
+func TestCopyTextSendsSequenceWithView(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	m := sizedModel(100, 30)
+
+	m, cmd := m.copyText("C4 D4", "the timeline")
+	sequence := ansi.SetSystemClipboard("C4 D4")
+	if !strings.HasPrefix(m.View(), sequence) {
+		t.Fatal("View doesn't send the clipboard sequence")
+	}
+	if !strings.Contains(m.View(), "Copied the timeline to the clipboard") {
+		t.Error("no status for the copy")
+	}
+
+	// A later copy takes over, so the first one's takedown leaves it alone
+	m, next := m.copyText("E4", "the note")
+	m = updateModel(m, cmd())
+	if !strings.Contains(m.View(), ansi.SetSystemClipboard("E4")) {
+		t.Fatal("earlier copy's takedown dropped a later sequence")
+	}
+
+	m = updateModel(m, next())
+	if strings.Contains(m.View(), "\x1b]52") {
+		t.Error("View still sends the clipboard sequence once it's sent")
+	}
+}
+
+// updateModel applies a message, dropping the command it returns
+func updateModel(m Model, msg tea.Msg) Model {
+	next, _ := m.Update(msg)
+	return next.(Model)
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// foreground returns the SGR parameters a color terminal is sent for a
+// foreground color
+func foreground(color lipgloss.TerminalColor) string {
+	return termenv.TrueColor.Color(string(color.(lipgloss.Color))).Sequence(false)
+}
+
+// noteBoxLines returns the lines of a view that hold the note box's text
+func noteBoxLines(view, text string) []string {
+	var lines []string
+	for _, line := range strings.Split(view, "\n") {
+		if stripped := ansi.Strip(line); strings.Contains(stripped, "│") && strings.Contains(stripped, text) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestConfidenceLevels(t *testing.T) {
+	withTrueColor(t)
+	tests := []struct {
+		name       string
+		confidence float64
+		indicator  string
+		color      lipgloss.TerminalColor
+		dimmed     bool
+	}{
+		{"high", 0.95, "Confidence ●●●●● 95%", gaugeInTuneStyle.GetForeground(), false},
+		{"reliable", highConfidence, "Confidence ●●●●● 90%", gaugeInTuneStyle.GetForeground(), false},
+		{"medium", 0.8, "Confidence ●●●●○ 80%", gaugeCloseStyle.GetForeground(), false},
+		{"marginal", marginalConfidence, "Confidence ●●●●○ 75%", gaugeCloseStyle.GetForeground(), false},
+		{"low", 0.4, "Confidence ●●○○○ 40%", gaugeOffStyle.GetForeground(), true},
+	}
+	dim := foreground(lipgloss.Color("#AAAAAA"))
+	converter := pitch.NewNoteConverter()
+	for _, test := range tests {
+		indicator := renderConfidence(newStyles(ThemeDefault, false), test.confidence)
+		if got := ansi.Strip(indicator); got != test.indicator {
+			t.Errorf("%s: indicator %q, want %q", test.name, got, test.indicator)
+		}
+		if !strings.Contains(indicator, foreground(test.color)+"m●") {
+			t.Errorf("%s: dots not colored %v: %q", test.name, test.color, indicator)
+		}
+
+		// Natural notes share one box, and sharps split theirs in two
+		for _, frequency := range []float64{440, 466.16} {
+			note := *converter.FromFrequency(frequency)
+			note.Confidence = test.confidence
+			m := updateModel(sizedModel(100, 50), UpdateNoteMsg(note))
+			view := m.View()
+			if !strings.Contains(ansi.Strip(view), test.indicator) {
+				t.Errorf("%s %s: view lacks %q", test.name, note.Name, test.indicator)
+			}
+
+			lines := noteBoxLines(view, m.noteLabel(&note)[len(note.Name)-1:])
+			if len(lines) != 1 {
+				t.Fatalf("%s %s: %d note box text lines", test.name, note.Name, len(lines))
+			}
+			if dimmed := len(styledColumns(lines[0], dim)) > 0 && len(styledColumns(lines[0], "2;")) > 0; dimmed != test.dimmed {
+				t.Errorf("%s %s: note box dimmed %v, want %v: %q", test.name, note.Name, dimmed, test.dimmed, lines[0])
+			}
+		}
+	}
+}
+
+func TestConfidenceClearsWithSilence(t *testing.T) {
+	note := *pitch.NewNoteConverter().FromFrequency(440)
+	note.Confidence = 0.9
+	m := sizedModel(100, 50).WithNoteHold(0)
+	m = updateModel(m, UpdateNoteMsg(note))
+	m = updateModel(m, UpdateHarmonicsMsg{Harmonics: []pitch.Harmonic{{Number: 1, Frequency: 440}}, HNR: 18})
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "Confidence ●●●●● 90% | SNR 18 dB") {
+		t.Fatalf("sounding view lacks confidence and SNR:\n%s", view)
+	}
+
+	m = updateModel(m, ClearNoteMsg{})
+	if view := ansi.Strip(m.View()); strings.Contains(view, "Confidence") || strings.Contains(view, "SNR") {
+		t.Errorf("silent view still shows confidence or SNR:\n%s", view)
+	}
+	if m.hnr != 0 || m.harmonics != nil {
+		t.Errorf("silence left SNR %v and %d harmonics", m.hnr, len(m.harmonics))
+	}
+}
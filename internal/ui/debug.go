@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UpdateDebugMsg is a message to update the debug panel with the internals of
+// the processing loop
+type UpdateDebugMsg struct {
+	Gate          string        // "open", "closed" on silence or "hold" during a note's attack
+	Detector      string        // Detector in use, e.g. "FFT, Guitar preset"
+	LastError     string        // Why the latest frame without a detection had none, "" until one
+	DetectionTime time.Duration // How long the latest detection took
+	Frames        int64         // Captured blocks processed
+	Dropped       int64         // Captured blocks replaced before they were processed
+
+	SilenceDB       float64 // Frames quieter than this (dB) count as silence
+	ConfidenceFloor float64 // Detections below this confidence are dropped
+	VolumeThreshold float64 // Detector's lowest RMS level for a detection
+	PeakThreshold   float64 // Detector's lowest spectral peak, as a share of the highest
+}
+
+// renderDebug renders the debug panel: the audio level, then unless space is
+// tight the state of the processing loop and the signal's overtones
+func (m Model) renderDebug() string {
+	s := m.styles.debug.Render(fmt.Sprintf("Audio Level: RMS=%.6f, dB=%.1f, Peak=%.1f dB, Noise=%.1f dB",
+		m.audioRMS, m.audioDB, max(heldPeak(m.peakDB, time.Since(m.peakAt)), -100), m.noiseDB))
+	s += "\n"
+	if m.paneHidden(paneDebugDetails) {
+		return s
+	}
+
+	if info := m.debugInfo; info.Detector != "" {
+		lastError := info.LastError
+		if lastError == "" {
+			lastError = "none"
+		}
+		s += m.styles.debug.Render(fmt.Sprintf("Gate: %s | Detector: %s | Last error: %s", info.Gate, info.Detector, lastError))
+		s += "\n"
+		s += m.styles.debug.Render(fmt.Sprintf("Detection: %.1f ms | Frames: %d processed, %d dropped",
+			float64(info.DetectionTime.Microseconds())/1000, info.Frames, info.Dropped))
+		s += "\n"
+		s += m.styles.debug.Render(fmt.Sprintf("Thresholds: silence %.0f dB | confidence %.2f | volume %.3f RMS | peaks %.0f%%",
+			info.SilenceDB, info.ConfidenceFloor, info.VolumeThreshold, info.PeakThreshold*100))
+		s += "\n"
+	}
+
+	// Show how clean the signal is, so a wobbly reading can be put down
+	// to noise rather than playing
+	if m.harmonics != nil {
+		s += m.styles.debug.Render(fmt.Sprintf("SNR: %.0f dB", m.hnr))
+		s += "\n"
+	}
+
+	// Show the first few overtone levels relative to the fundamental
+	if len(m.harmonics) > 1 {
+		levels := make([]string, 0, debugHarmonics)
+		for _, harmonic := range m.harmonics[1:] {
+			if len(levels) == debugHarmonics {
+				break
+			}
+			levels = append(levels, fmt.Sprintf("H%d %.1f dB", harmonic.Number, harmonic.LevelDB))
+		}
+		s += m.styles.debug.Render("Harmonics: " + strings.Join(levels, " | "))
+		s += "\n"
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// debugSnapshot returns a model holding a full set of the processing loop's
+// figures, sized to the given terminal
+func debugSnapshot(width, height int) Model {
+	m := sizedModel(width, height)
+	m.showDebug = true
+	m = updateModel(m, UpdateAudioLevelMsg{RMS: 0.0123456, DB: -38.2, NoiseDB: -61.4, PeakDB: -12.5})
+	m = updateModel(m, UpdateHarmonicsMsg{HNR: 24.4, Harmonics: []pitch.Harmonic{
+		{Number: 1, Frequency: 445},
+		{Number: 2, Frequency: 890, LevelDB: -3.2},
+		{Number: 3, Frequency: 1335, LevelDB: -9.75},
+		{Number: 4, Frequency: 1780, LevelDB: -14},
+		{Number: 5, Frequency: 2225, LevelDB: -20.5},
+		{Number: 6, Frequency: 2670, LevelDB: -31},
+	}})
+	return updateModel(m, UpdateDebugMsg{
+		Gate:            "hold",
+		Detector:        "FFT, Guitar preset",
+		LastError:       "volume below threshold",
+		DetectionTime:   1840 * time.Microsecond,
+		Frames:          12345,
+		Dropped:         17,
+		SilenceDB:       -45,
+		ConfidenceFloor: 0.6,
+		VolumeThreshold: 0.004,
+		PeakThreshold:   0.25,
+	})
+}
+
+func TestDebugPanelSnapshot(t *testing.T) {
+	m := debugSnapshot(120, 200)
+	want := strings.Join([]string{
+		"Audio Level: RMS=0.012346, dB=-38.2, Peak=-12.5 dB, Noise=-61.4 dB",
+		"Gate: hold | Detector: FFT, Guitar preset | Last error: volume below threshold",
+		"Detection: 1.8 ms | Frames: 12345 processed, 17 dropped",
+		"Thresholds: silence -45 dB | confidence 0.60 | volume 0.004 RMS | peaks 25%",
+		"SNR: 24 dB",
+		"Harmonics: H2 -3.2 dB | H3 -9.8 dB | H4 -14.0 dB | H5 -20.5 dB",
+		"",
+	}, "\n")
+	if got := ansi.Strip(m.renderDebug()); got != want {
+		t.Errorf("debug panel:\n%s\nwant\n%s", got, want)
+	}
+
+	// Until the processing loop reports, only the level and signal show
+	m.debugInfo = UpdateDebugMsg{}
+	if got := ansi.Strip(m.renderDebug()); strings.Contains(got, "Gate:") || !strings.Contains(got, "SNR: 24 dB") {
+		t.Errorf("debug panel before a report:\n%s", got)
+	}
+	m.debugInfo.Detector = "ACF"
+	if got := ansi.Strip(m.renderDebug()); !strings.Contains(got, "Last error: none") {
+		t.Errorf("debug panel without an error:\n%s", got)
+	}
+}
+
+func TestDebugPanelCollapses(t *testing.T) {
+	// The full view's height, given room to spare. Leaving out the five lines
+	// of details adds one pointing it out.
+	height := len(strings.Split(debugSnapshot(120, 200).View(), "\n"))
+
+	tests := []struct {
+		height  int
+		details bool // Whether the lines under the level line show
+		level   bool // Whether the level line shows
+	}{
+		{height, true, true},
+		{height - 1, false, true},
+		{height - 4, false, true},
+		{height - 5, false, false},
+	}
+	for _, test := range tests {
+		view := ansi.Strip(debugSnapshot(120, test.height).View())
+		if details := strings.Contains(view, "Gate: hold"); details != test.details {
+			t.Errorf("%d high: details shown %v, want %v:\n%s", test.height, details, test.details, view)
+		}
+		if level := strings.Contains(view, "Audio Level: RMS=0.012346"); level != test.level {
+			t.Errorf("%d high: level line shown %v, want %v:\n%s", test.height, level, test.level, view)
+		}
+		for _, line := range []string{"Detection: 1.8 ms", "Thresholds:", "SNR: 24 dB", "Harmonics:"} {
+			if strings.Contains(view, line) != test.details {
+				t.Errorf("%d high: %q shown %v", test.height, line, !test.details)
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TimelineExportedMsg reports the outcome of writing the timeline to files
+type TimelineExportedMsg struct {
+	Paths []string // Files written
+	Err   error    // Why writing failed, nil on success
+}
+
+// exportRecord is a timeline entry as written to a file
+type exportRecord struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Rest        bool      `json:"rest,omitempty"`
+	*exportNote           // Nil for a rest
+	Duration    float64   `json:"duration,omitempty"` // Seconds, left out while the note still sounds
+}
+
+// exportNote is the note of a timeline entry as written to a file
+type exportNote struct {
+	Note      string  `json:"note"`
+	Octave    int     `json:"octave"`
+	Frequency float64 `json:"frequency"`
+	Cents     float64 `json:"cents"`
+	Interval  *int    `json:"interval,omitempty"` // Semitones from the previous note, negative downward; nil for the first
+}
+
+// exportRecords converts timeline entries for writing. A glissando is
+// written as the note it landed on, and a chord as its strongest note.
+func exportRecords(entries []TimelineEntry) []exportRecord {
+	records := make([]exportRecord, len(entries))
+	for i, entry := range entries {
+		records[i] = exportRecord{
+			Timestamp: entry.Timestamp,
+			Rest:      entry.Note == nil,
+			Duration:  entry.Duration.Seconds(),
+		}
+		if entry.Note != nil {
+			records[i].exportNote = &exportNote{
+				Note:      entry.Note.Name,
+				Octave:    entry.Note.Octave,
+				Frequency: entry.Note.Frequency,
+				Cents:     entry.Note.Cents,
+			}
+			if entry.Interval != nil {
+				semitones := entry.Interval.Semitones
+				records[i].Interval = &semitones
+			}
+		}
+	}
+	return records
+}
+
+// writeTimelineCSV writes timeline entries as CSV with a header row. Rests
+// have "rest" for a note and no octave, frequency, cents or interval.
+func writeTimelineCSV(w io.Writer, entries []TimelineEntry) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"timestamp", "note", "octave", "frequency", "cents", "duration", "interval"})
+	for _, record := range exportRecords(entries) {
+		row := []string{record.Timestamp.Format(time.RFC3339Nano), "rest", "", "", "", "", ""}
+		if record.exportNote != nil {
+			row[1] = record.Note
+			row[2] = strconv.Itoa(record.Octave)
+			row[3] = strconv.FormatFloat(record.Frequency, 'f', 2, 64)
+			row[4] = strconv.FormatFloat(record.Cents, 'f', 1, 64)
+			if record.Interval != nil {
+				row[6] = strconv.Itoa(*record.Interval)
+			}
+		}
+		if record.Duration > 0 {
+			row[5] = strconv.FormatFloat(record.Duration, 'f', 3, 64)
+		}
+		out.Write(row)
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// writeTimelineJSON writes timeline entries as a JSON array
+func writeTimelineJSON(w io.Writer, entries []TimelineEntry) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(exportRecords(entries))
+}
+
+// exportPaths returns the files an export writes: the given file, as CSV
+// unless it ends in ".json", or both a CSV and a JSON file named after the
+// time in the given directory, or the current one when path is empty
+func exportPaths(path string, at time.Time) []string {
+	if info, err := os.Stat(path); path == "" || err == nil && info.IsDir() {
+		name := filepath.Join(path, "tunenote-"+at.Format("20060102-150405"))
+		return []string{name + ".csv", name + ".json"}
+	}
+	return []string{path}
+}
+
+// exportTimeline returns a tea.Cmd writing timeline entries to the files
+// given by exportPaths, so the UI doesn't wait on the disk
+func exportTimeline(path string, entries []TimelineEntry, at time.Time) tea.Cmd {
+	// The model keeps updating its timeline while the files are written
+	entries = append([]TimelineEntry{}, entries...)
+	return func() tea.Msg {
+		paths := exportPaths(path, at)
+		for _, path := range paths {
+			if err := writeTimelineFile(path, entries); err != nil {
+				return TimelineExportedMsg{Err: err}
+			}
+		}
+		return TimelineExportedMsg{Paths: paths}
+	}
+}
+
+// writeTimelineFile writes timeline entries to a file in the format its
+// extension names
+func writeTimelineFile(path string, entries []TimelineEntry) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	write := writeTimelineCSV
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		write = writeTimelineJSON
+	}
+	if err := write(file, entries); err != nil {
+		file.Close()
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return file.Close()
+}
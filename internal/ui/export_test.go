@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// intervalTimeline is a leap up a fifth, a rest and a step down a tone
+func intervalTimeline() []TimelineEntry {
+	converter := pitch.NewNoteConverter()
+	c4, g4, f4 := converter.FromFrequency(261.63), converter.FromFrequency(392), converter.FromFrequency(349.23)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	up, down := pitch.IntervalBetween(*c4, *g4), pitch.IntervalBetween(*g4, *f4)
+	return []TimelineEntry{
+		{Note: c4, Timestamp: start, Duration: time.Second},
+		{Note: g4, Timestamp: start.Add(time.Second), Duration: time.Second, Interval: &up},
+		{Timestamp: start.Add(2 * time.Second), Duration: time.Second},
+		{Note: f4, Timestamp: start.Add(3 * time.Second), Interval: &down},
+	}
+}
+
+func TestExportJSONIntervals(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeTimelineJSON(&out, intervalTimeline()); err != nil {
+		t.Fatal(err)
+	}
+
+	var records []map[string]any
+	if err := json.Unmarshal(out.Bytes(), &records); err != nil {
+		t.Fatal(err)
+	}
+	want := []any{nil, 7.0, nil, -2.0}
+	for i, record := range records {
+		if got := record["interval"]; got != want[i] {
+			t.Errorf("record %d has interval %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestExportCSVIntervals(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeTimelineCSV(&out, intervalTimeline()); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{"interval", "", "7", "", "-2"}
+	if len(lines) != len(want) {
+		t.Fatalf("%d lines, want %d:\n%s", len(lines), len(want), out.String())
+	}
+	for i, line := range lines {
+		fields := strings.Split(line, ",")
+		if got := fields[len(fields)-1]; got != want[i] {
+			t.Errorf("line %d ends with %q, want %q", i+1, got, want[i])
+		}
+	}
+}
+
+func TestExportKey(t *testing.T) {
+	press := func(m Model) (Model, tea.Cmd) {
+		next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+		return next.(Model), cmd
+	}
+
+	// Nothing is written from an empty timeline
+	dir := t.TempDir()
+	empty, cmd := press(NewModel(nil).WithExportPath(dir))
+	if cmd != nil || empty.status != "Nothing to export yet" || !empty.statusError {
+		t.Errorf("empty timeline: status %q, error %v, command %v", empty.status, empty.statusError, cmd != nil)
+	}
+
+	// A directory gets a CSV and a JSON file named after the time, written
+	// in the background
+	m := sizedModel(100, 30).WithExportPath(dir)
+	m, cmd = press(m)
+	if cmd == nil || m.status != "" {
+		t.Fatalf("export started with status %q, command %v", m.status, cmd != nil)
+	}
+	msg, ok := cmd().(TimelineExportedMsg)
+	if !ok || msg.Err != nil || len(msg.Paths) != 2 {
+		t.Fatalf("export finished with %+v", msg)
+	}
+
+	csvData, err := os.ReadFile(msg.Paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(csvData)), "\n")
+	if len(lines) != len(m.timeline)+1 || !strings.HasPrefix(lines[0], "timestamp,note,") {
+		t.Errorf("%s has %d lines for %d entries:\n%s", msg.Paths[0], len(lines), len(m.timeline), csvData)
+	}
+	for i, name := range []string{"G", "A", "B", "C", "A"} {
+		if fields := strings.Split(lines[i+1], ","); fields[1] != name {
+			t.Errorf("%s row %d is note %q, want %q", msg.Paths[0], i+1, fields[1], name)
+		}
+	}
+
+	jsonData, err := os.ReadFile(msg.Paths[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []map[string]any
+	if err := json.Unmarshal(jsonData, &records); err != nil || len(records) != len(m.timeline) || records[3]["note"] != "C" {
+		t.Errorf("%s holds %d records (%v), want %d", msg.Paths[1], len(records), err, len(m.timeline))
+	}
+	for _, path := range msg.Paths {
+		if filepath.Dir(path) != dir || !strings.HasPrefix(filepath.Base(path), "tunenote-") {
+			t.Errorf("wrote %s, want a tunenote- file in %s", path, dir)
+		}
+	}
+
+	// The outcome shows in the status line until it expires
+	m = updateModel(m, msg)
+	if m.statusError || !strings.Contains(m.View(), "Exported the timeline to "+msg.Paths[0]) {
+		t.Errorf("after export: status %q, error %v", m.status, m.statusError)
+	}
+	m = updateModel(m, TickMsg(m.statusUntil.Add(-time.Millisecond)))
+	if m.status == "" {
+		t.Error("status went before it expired")
+	}
+	m = updateModel(m, TickMsg(m.statusUntil))
+	if m.status != "" || strings.Contains(m.View(), "Exported") {
+		t.Errorf("status %q still shown once expired", m.status)
+	}
+
+	// A file that can't be created fails with the reason
+	m, cmd = press(m.WithExportPath(filepath.Join(dir, "missing", "timeline.csv")))
+	m = updateModel(m, cmd())
+	if !m.statusError || !strings.HasPrefix(m.status, "Export failed: ") {
+		t.Errorf("failed export: status %q, error %v", m.status, m.statusError)
+	}
+}
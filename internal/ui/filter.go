@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// noteFilter picks the timeline entries of one note, in any octave or in one
+type noteFilter struct {
+	label      string // The note as shown in the header, e.g. "Eb" or "Eb4"
+	pitchClass int    // Pitch class to match (0 = C)
+	octave     int    // Octave to match, unless anyOctave
+	anyOctave  bool   // Whether the note matches in every octave
+}
+
+// parseNoteFilter reads a filter from a note name, with an octave to match
+// only that octave ("Eb4") or without one to match all of them ("Eb")
+func parseNoteFilter(text string) (*noteFilter, error) {
+	text = strings.TrimSpace(text)
+	anyOctave := !strings.ContainsAny(text, "-0123456789")
+	parsed := text
+	if anyOctave {
+		// Any octave will do to learn the pitch class
+		parsed += "4"
+	}
+
+	note, err := pitch.ParseNote(parsed)
+	if err != nil {
+		return nil, err
+	}
+	filter := &noteFilter{label: note.Name, pitchClass: note.PitchClass, octave: note.Octave, anyOctave: anyOctave}
+	if !anyOctave {
+		filter.label += strconv.Itoa(note.Octave)
+	}
+	return filter, nil
+}
+
+// matches reports whether a note is the filter's note
+func (f *noteFilter) matches(note *pitch.Note) bool {
+	return note != nil && note.PitchClass == f.pitchClass && (f.anyOctave || note.Octave == f.octave)
+}
+
+// matchesEntry reports whether a timeline entry plays the filter's note: as
+// a note, a chord's strongest note, or either end of a glissando
+func (f *noteFilter) matchesEntry(entry TimelineEntry) bool {
+	return f.matches(entry.Note) || f.matches(entry.From)
+}
+
+// filterTimeline keeps the entries matching a filter, replacing each run of
+// the others with a gap marker. The entries given are left untouched.
+func filterTimeline(entries []TimelineEntry, filter *noteFilter) []TimelineEntry {
+	filtered := make([]TimelineEntry, 0, len(entries))
+	for _, entry := range entries {
+		if filter.matchesEntry(entry) {
+			filtered = append(filtered, entry)
+			continue
+		}
+		if last := len(filtered) - 1; last >= 0 && filtered[last].elided > 0 {
+			filtered[last].elided++
+			continue
+		}
+		filtered = append(filtered, TimelineEntry{Timestamp: entry.Timestamp, elided: 1})
+	}
+	return filtered
+}
+
+// filterMatches counts the entries matching a filter, each play of a
+// grouped repeat counting once
+func filterMatches(entries []TimelineEntry, filter *noteFilter) int {
+	count := 0
+	for _, entry := range entries {
+		if filter.matchesEntry(entry) {
+			count += max(entry.Repeats, 1)
+		}
+	}
+	return count
+}
+
+// confirmFilter applies the typed filter to the timeline, or clears it when
+// nothing was typed
+func (m Model) confirmFilter() (Model, tea.Cmd) {
+	text := strings.TrimSpace(m.inputText)
+	if text == "" {
+		m.typing = inputNone
+		return m.clearFilter(), nil
+	}
+
+	filter, err := parseNoteFilter(text)
+	if err != nil {
+		return m.withStatus(fmt.Sprintf("%q isn't a note, e.g. Eb or Eb4", text), true), nil
+	}
+
+	m.typing = inputNone
+	m.filter = filter
+	m.timelineScroll = 0
+	return m, nil
+}
+
+// clearFilter shows the whole timeline again
+func (m Model) clearFilter() Model {
+	if m.filter == nil {
+		return m
+	}
+	m.filter = nil
+	m.timelineScroll = 0
+	return m.withStatus("Timeline filter cleared", false)
+}
@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestParseNoteFilter(t *testing.T) {
+	tests := []struct {
+		text       string
+		label      string
+		pitchClass int
+		octave     int
+		anyOctave  bool
+	}{
+		{"Eb", "Eb", 3, 0, true},
+		{" Bb ", "Bb", 10, 0, true},
+		{"D#", "D#", 3, 0, true},
+		{"eb4", "Eb4", 3, 4, false},
+		{"E♭3", "Eb3", 3, 3, false},
+		{"C-1", "C-1", 0, -1, false},
+	}
+	for _, test := range tests {
+		filter, err := parseNoteFilter(test.text)
+		if err != nil {
+			t.Errorf("parseNoteFilter(%q): %v", test.text, err)
+			continue
+		}
+		if filter.label != test.label || filter.pitchClass != test.pitchClass || filter.anyOctave != test.anyOctave ||
+			(!test.anyOctave && filter.octave != test.octave) {
+			t.Errorf("parseNoteFilter(%q) = %+v, want %s", test.text, *filter, test.label)
+		}
+	}
+
+	for _, text := range []string{"", "H", "X4", "Eb44", "4"} {
+		if filter, err := parseNoteFilter(text); err == nil {
+			t.Errorf("parseNoteFilter(%q) = %+v, want an error", text, *filter)
+		}
+	}
+}
+
+func TestFilterTimeline(t *testing.T) {
+	converter := pitch.NewNoteConverter()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var entries []TimelineEntry
+	for i, frequency := range []float64{261.63, 311.13, 293.66, 0, 349.23, 155.56, 392, 0} {
+		entry := TimelineEntry{Timestamp: start.Add(time.Duration(i) * time.Second), Duration: 500 * time.Millisecond}
+		if frequency > 0 {
+			entry.Note = converter.FromFrequency(frequency)
+		}
+		entries = append(entries, entry)
+	}
+	// A glissando matches by the note it starts from too
+	entries = append(entries, TimelineEntry{Note: converter.FromFrequency(349.23), From: converter.FromFrequency(311.13),
+		Timestamp: start.Add(8 * time.Second), Duration: time.Second})
+	original := append([]TimelineEntry(nil), entries...)
+
+	tests := []struct {
+		filter  string
+		text    string
+		elided  []int
+		matches int
+	}{
+		{"Eb", "· D#4 · D#3 · D#4→F4", []int{1, 0, 3, 0, 2, 0}, 3},
+		{"Eb4", "· D#4 · D#4→F4", []int{1, 0, 6, 0}, 2},
+		{"C", "C4 ·", []int{0, 8}, 1},
+		{"B", "·", []int{9}, 0},
+	}
+	for _, test := range tests {
+		filter, _ := parseNoteFilter(test.filter)
+		filtered := filterTimeline(entries, filter)
+		if got := timelineText(filtered, pitch.NotationScientific); got != test.text {
+			t.Errorf("%s: timeline %q, want %q", test.filter, got, test.text)
+		}
+		elided := make([]int, len(filtered))
+		for i, entry := range filtered {
+			elided[i] = entry.elided
+		}
+		if !reflect.DeepEqual(elided, test.elided) {
+			t.Errorf("%s: gaps %v, want %v", test.filter, elided, test.elided)
+		}
+		if got := filterMatches(entries, filter); got != test.matches {
+			t.Errorf("%s: %d matches, want %d", test.filter, got, test.matches)
+		}
+	}
+	if !reflect.DeepEqual(entries, original) {
+		t.Error("filtering changed the timeline")
+	}
+}
+
+func TestTimelineFilterKeys(t *testing.T) {
+	var model tea.Model = NewModel(nil).WithPlain(true)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m := model.(Model)
+	start := time.Now().Add(-10 * time.Second)
+	m = playNotes(m, start, 300*time.Millisecond, 100*time.Millisecond, 261.63, 311.13, 311.13, 293.66, 155.56, 392)
+	timeline := append([]TimelineEntry(nil), m.timeline...)
+
+	// / switches to the timeline to type the note
+	m = typeText(m, "/", "Eb")
+	if m.tab != tabTimeline || m.typing != inputNone || m.filter == nil {
+		t.Fatalf("after typing a filter: tab %v, typing %v, filter %v", m.tab, m.typing, m.filter)
+	}
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "only Eb: 3 found, Esc to clear") {
+		t.Errorf("no match count in header:\n%s", view)
+	}
+	if got := timelineText(m.displayedTimeline(), m.notation); got != "· D#4 D#4 · D#3 ·" {
+		t.Errorf("filtered timeline %q", got)
+	}
+	if !reflect.DeepEqual(m.timeline, timeline) {
+		t.Error("filtering changed the timeline")
+	}
+
+	// Repeats group among the matches, and the count still counts each play
+	m = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	if got := timelineText(m.displayedTimeline(), m.notation); got != "· D#4×2 · D#3 ·" {
+		t.Errorf("filtered grouped timeline %q", got)
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "repeats grouped, only Eb: 3 found") {
+		t.Errorf("no match count with repeats grouped:\n%s", view)
+	}
+	m = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+
+	// A filter with an octave narrows the matches, and starts at the newest
+	m.timelineScroll = 2
+	m = typeText(m, "/", "Eb3")
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "only Eb3: 1 found") || m.timelineScroll != 0 {
+		t.Errorf("scrolled %d with header:\n%s", m.timelineScroll, view)
+	}
+
+	// A typo leaves the filter typed so far up to fix
+	m = typeText(m, "/", "H")
+	if m.typing != inputFilter || m.status != `"H" isn't a note, e.g. Eb or Eb4` || m.filter.label != "Eb3" {
+		t.Errorf("after a typo: typing %v, status %q, filter %q", m.typing, m.status, m.filter.label)
+	}
+	m = updateModel(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.typing != inputNone || m.filter == nil {
+		t.Errorf("Esc while typing: typing %v, filter %v", m.typing, m.filter)
+	}
+
+	// Esc clears the filter, as does confirming nothing
+	m.timelineScroll = 2
+	m = updateModel(m, tea.KeyMsg{Type: tea.KeyEsc})
+	view = ansi.Strip(m.View())
+	if m.filter != nil || m.status != "Timeline filter cleared" || m.timelineScroll != 0 || strings.Contains(view, "found") {
+		t.Errorf("after Esc: filter %v, status %q, scrolled %d:\n%s", m.filter, m.status, m.timelineScroll, view)
+	}
+	if got := timelineText(m.displayedTimeline(), m.notation); got != "C4 D#4 D#4 D4 D#3 G4" {
+		t.Errorf("unfiltered timeline %q", got)
+	}
+	m = typeText(m, "/", "Eb")
+	m = typeText(m, "/", "")
+	if m.filter != nil || m.typing != inputNone {
+		t.Errorf("confirming nothing left filter %v, typing %v", m.filter, m.typing)
+	}
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// fretMarks reads the markers off a rendered fretboard, one entry per string
+// from the top, each listing the frets marked, zero for the open string,
+// and whether brightly (●) or dimly (○), e.g. "0● 12○"
+func fretMarks(t *testing.T, fretboard string, labelWidth int) []string {
+	t.Helper()
+	lines := strings.Split(ansi.Strip(fretboard), "\n")
+	var marks []string
+	for _, line := range lines[:len(lines)-1] {
+		var frets []string
+		for column, r := range []rune(line) {
+			if r != '●' && r != '○' {
+				continue
+			}
+			// The open string sits before the nut, and each fret's marker
+			// in the second of its columns after it
+			fret := 0
+			if column > labelWidth+1 {
+				fret = (column-labelWidth-3)/fretWidth + 1
+				if (column-labelWidth-3)%fretWidth != (fretWidth-2)/2 {
+					t.Errorf("marker at column %d is off the middle of fret %d:\n%s", column, fret, line)
+				}
+			}
+			frets = append(frets, fmt.Sprintf("%d%c", fret, r))
+		}
+		marks = append(marks, strings.Join(frets, " "))
+	}
+	return marks
+}
+
+func TestFretboardMarkers(t *testing.T) {
+	st := newStyles(ThemeDefault, true)
+	tests := []struct {
+		note  string
+		set   pitch.StringSet
+		marks []string // High E string first
+	}{
+		{"A2", pitch.StringSetStandard, []string{"5○", "10○", "2○", "7○", "0● 12○", "5●"}},
+		{"A2", pitch.StringSetDropD, []string{"5○", "10○", "2○", "7○", "0● 12○", "7●"}},
+		{"C#4", pitch.StringSetStandard, []string{"9○", "2●", "6●", "11●", "4○", "9○"}},
+		{"C#4", pitch.StringSetDropD, []string{"9○", "2●", "6●", "11●", "4○", "11○"}},
+	}
+	for _, tt := range tests {
+		note, err := pitch.ParseNote(tt.note)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fretboard := renderFretboard(st, note, tt.set, fretboardFrets, false, 0, pitch.NotationScientific)
+		if got := fretMarks(t, fretboard, 2); !reflect.DeepEqual(got, tt.marks) {
+			t.Errorf("%s in %s:\n got %q\nwant %q", tt.note, tt.set.Name, got, tt.marks)
+		}
+
+		// Folding octaves marks every position brightly
+		folded := renderFretboard(st, note, tt.set, fretboardFrets, true, 0, pitch.NotationScientific)
+		want := strings.ReplaceAll(strings.Join(tt.marks, "|"), "○", "●")
+		if got := strings.Join(fretMarks(t, folded, 2), "|"); got != want {
+			t.Errorf("%s in %s folded:\n got %q\nwant %q", tt.note, tt.set.Name, got, want)
+		}
+	}
+}
+
+func TestFretboardFitsWidth(t *testing.T) {
+	// Frets beyond the width are dropped, along with their markers
+	st := newStyles(ThemeDefault, true)
+	note, _ := pitch.ParseNote("A2")
+	for _, width := range []int{20, 30, 40, 60} {
+		fretboard := renderFretboard(st, note, pitch.StringSetStandard, fretboardFrets, false, width, pitch.NotationScientific)
+		for _, line := range strings.Split(fretboard, "\n") {
+			if got := ansi.StringWidth(line); got > width {
+				t.Errorf("%d columns: line %d wide\n%s", width, got, ansi.Strip(line))
+			}
+		}
+		frets := (width - 2 - 4) / fretWidth
+		if got := fretMarks(t, fretboard, 2)[5]; (frets >= 5) != (got == "5●") {
+			t.Errorf("%d columns, %d frets: low E marks %q", width, frets, got)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderGauge(t *testing.T) {
+	st := newStyles(ThemeDefault, true)
+	tests := []struct {
+		name         string
+		cents        float64
+		width        int // Terminal width, zero for unknown
+		below, above string
+		want         string
+	}{
+		{"in tune", 0, 20, "C4", "D4", "C4 ──────●────── D4"},
+		{"flat edge", -50, 20, "C4", "D4", "C4 ●─────┼────── D4"},
+		{"sharp", 25, 20, "C4", "D4", "C4 ──────┼──●─── D4"},
+		{"beyond range", 80, 20, "C4", "D4", "C4 ──────┼─────● D4"},
+		{"narrowest", -15, 8, "C4", "D4", "C4 ────●┼───── D4"},
+		{"wider labels", -10, 40, "B3", "C#4", " B3 ────────────●──┼─────────────── C#4"},
+		{"unknown width", 12, 0, "A4", "B4", "A4 ────────────────────┼────●─────────────── B4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ansi.Strip(renderGauge(st, tt.cents, tt.width, tt.below, tt.above))
+			if got != tt.want {
+				t.Errorf("renderGauge(%v cents, width %d)\n got %q\nwant %q", tt.cents, tt.width, got, tt.want)
+			}
+		})
+	}
+}
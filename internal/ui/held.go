@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+)
+
+// heldLinger is how long a held note's summary stays up, dimmed, once the
+// note ends
+const heldLinger = 2 * time.Second
+
+// heldNote sums up the cents of a held note as its readings come in
+type heldNote struct {
+	start, last time.Time // First and latest reading
+	count       int
+	sum         float64
+	low, high   float64 // Flattest and sharpest reading
+}
+
+// add counts a cents reading
+func (h *heldNote) add(at time.Time, cents float64) {
+	if h.count == 0 {
+		h.start = at
+		h.low, h.high = cents, cents
+	}
+	h.last = at
+	h.count++
+	h.sum += cents
+	h.low = min(h.low, cents)
+	h.high = max(h.high, cents)
+}
+
+// average returns the mean of the readings
+func (h heldNote) average() float64 {
+	if h.count == 0 {
+		return 0
+	}
+	return h.sum / float64(h.count)
+}
+
+// String writes the summary, e.g. "avg -2.1¢ · range 7¢ · 3.2 s"
+func (h heldNote) String() string {
+	return fmt.Sprintf("avg %+.1f¢ · range %.0f¢ · %.1f s", h.average(), h.high-h.low, h.last.Sub(h.start).Seconds())
+}
+
+// endHeld ends the held note, keeping its summary up for a moment
+func (m Model) endHeld(at time.Time) Model {
+	if m.held.count > 0 {
+		m.heldEnded = m.held
+		m.heldEndedAt = at
+	}
+	m.held = heldNote{}
+	return m
+}
+
+// renderHeld renders the summary of the note being held, or dimmed that of
+// the last one for a moment after it ended, or "" for neither
+func (m Model) renderHeld(now time.Time) string {
+	switch {
+	case m.held.count > 0:
+		return m.styles.info.Render(m.held.String())
+	case !m.heldEndedAt.IsZero() && now.Sub(m.heldEndedAt) < heldLinger:
+		return m.styles.debug.Faint(true).Render(m.heldEnded.String())
+	default:
+		return ""
+	}
+}
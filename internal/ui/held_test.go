@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestHeldNoteAggregates(t *testing.T) {
+	var held heldNote
+	start := time.Unix(0, 0)
+	tests := []struct {
+		cents float64
+		want  string
+	}{
+		{-3, "avg -3.0¢ · range 0¢ · 0.0 s"},
+		{-1, "avg -2.0¢ · range 2¢ · 0.4 s"},
+		{2, "avg -0.7¢ · range 5¢ · 0.8 s"},
+		{-5, "avg -1.8¢ · range 7¢ · 1.2 s"},
+		{4, "avg -0.6¢ · range 9¢ · 1.6 s"},
+	}
+	for i, test := range tests {
+		held.add(start.Add(time.Duration(i)*400*time.Millisecond), test.cents)
+		if got := held.String(); got != test.want {
+			t.Errorf("after %v¢: %q, want %q", test.cents, got, test.want)
+		}
+	}
+}
+
+func TestHeldNoteSummary(t *testing.T) {
+	converter := pitch.NewNoteConverter()
+	play := func(m Model, frequency float64, cents ...float64) Model {
+		for _, c := range cents {
+			note := *converter.FromFrequency(frequency)
+			note.Cents = c
+			m = updateModel(m, UpdateNoteMsg(note))
+		}
+		return m
+	}
+	summary := func(m Model, now time.Time) string {
+		return ansi.Strip(m.renderHeld(now))
+	}
+
+	m := NewModel(nil).WithNoteHold(0)
+	if got := summary(m, time.Now()); got != "" {
+		t.Errorf("summary before any note: %q", got)
+	}
+
+	m = play(m, 440, -4, -2, 0, 2)
+	if m.held.count != 4 || m.held.average() != -1 || m.held.low != -4 || m.held.high != 2 {
+		t.Errorf("A4 summary %+v, want 4 readings averaging -1 from -4 to 2", m.held)
+	}
+
+	// A new note starts over, and the last one's summary lingers
+	m = play(m, 493.88, 7, 9)
+	if m.held.count != 2 || m.held.average() != 8 || m.held.high-m.held.low != 2 {
+		t.Errorf("B4 summary %+v, want 2 readings averaging 8 over 2", m.held)
+	}
+	if m.heldEnded.count != 4 {
+		t.Errorf("ended summary has %d readings, want A4's 4", m.heldEnded.count)
+	}
+
+	// Silence ends the note, and its summary stays up, dimmed, for a while
+	m = updateModel(m, ClearNoteMsg{})
+	ended := m.heldEndedAt
+	if m.held.count != 0 || m.heldEnded.count != 2 || ended.IsZero() {
+		t.Fatalf("after silence held %d, ended %d readings", m.held.count, m.heldEnded.count)
+	}
+	withTrueColor(t)
+	for _, test := range []struct {
+		after time.Duration
+		shown bool
+	}{
+		{0, true},
+		{heldLinger - time.Millisecond, true},
+		{heldLinger, false},
+		{5 * time.Second, false},
+	} {
+		got := m.renderHeld(ended.Add(test.after))
+		if shown := ansi.Strip(got) != ""; shown != test.shown {
+			t.Errorf("%v after the note ended: summary %q", test.after, ansi.Strip(got))
+		}
+		if test.shown && len(styledColumns(got, "2;")) == 0 {
+			t.Errorf("%v after the note ended: summary not dimmed: %q", test.after, got)
+		}
+	}
+
+	// A note played again hides the last one's summary behind its own
+	m = play(m, 440, 1)
+	if got := summary(m, ended); got != "avg +1.0¢ · range 0¢ · 0.0 s" {
+		t.Errorf("summary of a new note %q", got)
+	}
+}
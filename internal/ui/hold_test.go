@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Visual states of the note box
+const (
+	boxSounding  = "sounding"
+	boxReleasing = "releasing"
+	boxEmpty     = "placeholder"
+)
+
+// noteBoxState tells which state the note box of a view showing A4 is in:
+// the note on its own color, the note grayed out, or the placeholder
+func noteBoxState(t *testing.T, m Model) string {
+	t.Helper()
+	for _, line := range strings.Split(m.View(), "\n") {
+		text := ansi.Strip(line)
+		switch {
+		case strings.Contains(text, "│") && strings.Contains(text, "---"):
+			return boxEmpty
+		case !strings.Contains(text, "│") || !strings.Contains(text, "A4"):
+			continue
+		case strings.Contains(line, background(m.styles.theme.Notes["A"])):
+			return boxSounding
+		case strings.Contains(line, background(m.styles.theme.Rest)):
+			return boxReleasing
+		}
+		t.Fatalf("note box line in neither color: %q", line)
+	}
+	t.Fatal("no note box in view")
+	return ""
+}
+
+func TestNoteHoldStates(t *testing.T) {
+	withTrueColor(t)
+	const hold = 750 * time.Millisecond
+	note := pitch.NewNoteConverter().FromFrequency(440)
+	note.Confidence = 0.95
+
+	// The hold's deadline is set from the clock when the clear of a sounding
+	// note arrives, so ticks are timed from just before and just after that
+	var before, after time.Time
+	play := func() tea.Msg { return UpdateNoteMsg(*note) }
+	release := func() tea.Msg { return ClearNoteMsg{} }
+	tick := func(at func() time.Time) func() tea.Msg {
+		return func() tea.Msg { return TickMsg(at()) }
+	}
+	justBefore := tick(func() time.Time { return before.Add(hold - time.Millisecond) })
+	atDeadline := tick(func() time.Time { return after.Add(hold) })
+	soon := tick(func() time.Time { return after.Add(100 * time.Millisecond) })
+
+	type step struct {
+		msg  func() tea.Msg
+		want string
+	}
+	tests := []struct {
+		name  string
+		hold  time.Duration
+		steps []step
+	}{
+		{"hold expires", hold, []step{
+			{play, boxSounding},
+			{release, boxReleasing},
+			{soon, boxReleasing},
+			{justBefore, boxReleasing},
+			{atDeadline, boxEmpty},
+		}},
+		{"new note cancels hold", hold, []step{
+			{play, boxSounding},
+			{release, boxReleasing},
+			{play, boxSounding},
+			{atDeadline, boxSounding},
+		}},
+		{"repeated clear keeps deadline", hold, []step{
+			{play, boxSounding},
+			{release, boxReleasing},
+			{soon, boxReleasing},
+			{release, boxReleasing},
+			{atDeadline, boxEmpty},
+		}},
+		{"no hold", 0, []step{
+			{play, boxSounding},
+			{release, boxEmpty},
+		}},
+	}
+	for _, test := range tests {
+		m := sizedModel(100, 50).WithNoteHold(test.hold)
+		m.showDebug = false
+		for i, step := range test.steps {
+			msg := step.msg()
+			_, clearing := msg.(ClearNoteMsg)
+			clearing = clearing && m.currentNote != nil && m.releaseUntil.IsZero()
+			sent := time.Now()
+			m = updateModel(m, msg)
+			if clearing {
+				before, after = sent, time.Now()
+			}
+			if state := noteBoxState(t, m); state != step.want {
+				t.Errorf("%s, step %d: note box %s, want %s", test.name, i+1, state, step.want)
+			}
+		}
+	}
+}
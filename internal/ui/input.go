@@ -0,0 +1,63 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// textInput is what the keys are typing, if anything
+type textInput int
+
+const (
+	inputNone       textInput = iota
+	inputTargetNote           // A note to practice
+	inputScale                // A scale to practice
+	inputFilter               // A note to filter the timeline by
+)
+
+// inputPrompts are the prompts shown while typing, with how to finish
+var inputPrompts = map[textInput][2]string{
+	inputTargetNote: {"Target note: ", "e.g. C#3; Enter to confirm, empty to stop, Esc to cancel"},
+	inputScale:      {"Scale: ", "root and scale, e.g. G3 major or A2 harmonic minor; Enter to confirm, empty to stop, Esc to cancel"},
+	inputFilter:     {"Show only: ", "a note in any octave or one, e.g. Eb or Eb4; Enter to confirm, empty to show all, Esc to cancel"},
+}
+
+// startInput starts typing text for a mode
+func (m Model) startInput(input textInput) Model {
+	m.typing = input
+	m.inputText = ""
+	return m
+}
+
+// typeInput handles a key while text is being typed: Enter confirms it, Esc
+// cancels
+func (m Model) typeInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.typing = inputNone
+	case tea.KeyBackspace:
+		if runes := []rune(m.inputText); len(runes) > 0 {
+			m.inputText = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.inputText += string(msg.Runes)
+	case tea.KeyEnter:
+		switch m.typing {
+		case inputTargetNote:
+			return m.confirmTarget()
+		case inputScale:
+			return m.confirmScale()
+		case inputFilter:
+			return m.confirmFilter()
+		}
+	}
+	return m, nil
+}
+
+// renderInput renders the text being typed with its prompt
+func (m Model) renderInput() string {
+	prompt := inputPrompts[m.typing]
+	return m.styles.info.Bold(true).Render(prompt[0]) + m.styles.precise.Render(m.inputText+"█") +
+		m.styles.debug.Render("  ("+prompt[1]+")")
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+)
+
+func TestInTuneLockRingsBellOnce(t *testing.T) {
+	m := sizedModel(100, 30).WithBell(true)
+	m.inTune.Reset()
+	note := pitch.Note{Name: "A", Octave: 4, MIDINote: 69, Frequency: 440.5, Cents: 2}
+	m = updateModel(m, UpdateNoteMsg(note))
+	start := m.lastUpdate
+
+	// The ticker locks the note once the dwell is over, with no new reading
+	rings := 0
+	for at := 100 * time.Millisecond; at <= time.Second; at += 100 * time.Millisecond {
+		before := m.sequenceGeneration
+		m = updateModel(m, TickMsg(start.Add(at)))
+		if m.inTuneLocked != (at >= pitch.DefaultInTuneDwell) {
+			t.Errorf("locked %v at %v", m.inTuneLocked, at)
+		}
+		if m.sequenceGeneration != before {
+			rings++
+			if !strings.HasPrefix(m.View(), "\a") {
+				t.Error("View doesn't ring the bell")
+			}
+			m = updateModel(m, sequenceSentMsg{generation: m.sequenceGeneration})
+		}
+	}
+	if rings != 1 {
+		t.Errorf("bell rang %d times, want once", rings)
+	}
+	if strings.Contains(m.View(), "\a") {
+		t.Error("View still rings the bell once it's sent")
+	}
+
+	// A reading out of tolerance unlocks the note and a silence forgets it
+	note.Cents = pitch.DefaultInTuneTolerance + 5
+	if m = updateModel(m, UpdateNoteMsg(note)); m.inTuneLocked {
+		t.Error("still locked out of tolerance")
+	}
+	note.Cents = 0
+	m = updateModel(m, UpdateNoteMsg(note))
+	m = updateModel(m, ClearNoteMsg{})
+	m = updateModel(m, TickMsg(time.Now().Add(time.Second)))
+	if m.inTuneLocked {
+		t.Error("locked during a silence")
+	}
+}
+
+func TestInTuneLockSilentWithoutBell(t *testing.T) {
+	m := sizedModel(100, 30)
+	m.inTune.Reset()
+	m = updateModel(m, UpdateNoteMsg(pitch.Note{Name: "A", Octave: 4, MIDINote: 69, Frequency: 440}))
+	m = updateModel(m, TickMsg(m.lastUpdate.Add(pitch.DefaultInTuneDwell)))
+	if !m.inTuneLocked {
+		t.Fatal("not locked after the dwell")
+	}
+	if m.sequence != "" {
+		t.Errorf("sent %q with the bell off", m.sequence)
+	}
+}
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// withTrueColor makes styles render their colors for the rest of the test,
+// as they would in a color terminal
+func withTrueColor(t *testing.T) {
+	t.Helper()
+	previous := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	t.Cleanup(func() { lipgloss.SetColorProfile(previous) })
+}
+
+// background returns the SGR parameters a color terminal is sent for a
+// background color
+func background(color string) string {
+	return termenv.TrueColor.Color(color).Sequence(true)
+}
+
+// styledColumns returns the columns of a rendered line whose styling
+// includes the given SGR parameters
+func styledColumns(line, sgr string) []int {
+	var columns []int
+	current := ""
+	column := 0
+	for len(line) > 0 {
+		if strings.HasPrefix(line, "\x1b[") {
+			end := strings.IndexByte(line, 'm')
+			if params := line[2:end]; params == "0" || params == "" {
+				current = ""
+			} else {
+				current += ";" + params
+			}
+			line = line[end+1:]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(line)
+		width := ansi.StringWidth(string(r))
+		if strings.Contains(current, sgr) {
+			for i := 0; i < width; i++ {
+				columns = append(columns, column+i)
+			}
+		}
+		column += width
+		line = line[size:]
+	}
+	return columns
+}
+
+// span returns the columns from first to last inclusive
+func span(first, last int) []int {
+	var columns []int
+	for c := first; c <= last; c++ {
+		columns = append(columns, c)
+	}
+	return columns
+}
+
+func TestKeyboardHighlight(t *testing.T) {
+	withTrueColor(t)
+	st := newStyles(ThemeDefault, false)
+
+	// Keys are four columns wide. Three octaves fit in 100 columns, centered
+	// on the note's; two in 60, from the note's octave when it lies in the
+	// upper half of it. Black keys straddle the edge of the white keys.
+	tests := []struct {
+		note        string
+		width       int
+		first       string // Label of the leftmost C
+		top, bottom []int  // Highlighted columns of the black and white key rows
+	}{
+		{"C4", 100, "C3", span(28, 29), span(28, 31)},
+		{"C4", 60, "C3", span(28, 29), span(28, 31)},
+		{"F#3", 100, "C2", span(42, 44), nil},
+		{"F#3", 60, "C3", span(14, 16), nil},
+		{"B5", 100, "C4", span(53, 55), span(52, 55)},
+		{"B5", 60, "C5", span(25, 27), span(24, 27)},
+	}
+	for _, tt := range tests {
+		note, err := pitch.ParseNote(tt.note)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows := strings.Split(renderKeyboard(st, note, tt.width), "\n")
+		if len(rows) != 2 {
+			t.Fatalf("%s at %d columns: %d rows, want 2", tt.note, tt.width, len(rows))
+		}
+
+		octaves := 3
+		if tt.width < 84 {
+			octaves = 2
+		}
+		if got := ansi.StringWidth(rows[1]); got != octaves*7*keyWidth {
+			t.Errorf("%s at %d columns: %d columns wide, want %d octaves", tt.note, tt.width, got, octaves)
+		}
+		if !strings.HasPrefix(ansi.Strip(rows[1]), tt.first+" ") {
+			t.Errorf("%s at %d columns: starts at %q, want %s", tt.note, tt.width, ansi.Strip(rows[1])[:4], tt.first)
+		}
+
+		sgr := background(st.noteColor(note))
+		if got := styledColumns(rows[0], sgr); !reflect.DeepEqual(got, tt.top) {
+			t.Errorf("%s at %d columns: black key row highlighted at %v, want %v", tt.note, tt.width, got, tt.top)
+		}
+		if got := styledColumns(rows[1], sgr); !reflect.DeepEqual(got, tt.bottom) {
+			t.Errorf("%s at %d columns: white key row highlighted at %v, want %v", tt.note, tt.width, got, tt.bottom)
+		}
+	}
+
+	// Without a note the keyboard sits around middle C and no key is lit
+	view := renderKeyboard(st, nil, 100)
+	if !strings.HasPrefix(ansi.Strip(strings.Split(view, "\n")[1]), "C3 ") {
+		t.Errorf("keyboard without a note starts at %q, want C3", ansi.Strip(view)[:4])
+	}
+	for midiNote := 48; midiNote < 84; midiNote++ {
+		note := pitch.Note{MIDINote: midiNote, PitchClass: midiNote % 12, Octave: midiNote/12 - 1}
+		if got := styledColumns(view, background(st.noteColor(&note))); len(got) > 0 {
+			t.Errorf("keyboard without a note lights columns %v in the color of MIDI note %d", got, midiNote)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyAction is something a key does in Update
+type keyAction int
+
+const (
+	actionNone keyAction = iota // Nothing, e.g. no button under the pointer
+	actionQuit
+	actionHelp
+	actionDismiss
+	actionDebug
+	actionBigNote
+	actionStats
+	actionNextTab
+	actionPreviousTab
+	actionKeyboard
+	actionFretboard
+	actionSpectrogram
+	actionTheme
+	actionNotation
+	actionNaming
+	actionSpelling
+	actionNoteNames
+	actionPlain
+	actionKeySignature
+	actionFreeze
+	actionScrollBack
+	actionScrollForward
+	actionScrollOldest
+	actionScrollNewest
+	actionGroupRepeats
+	actionOctaveLegend
+	actionClear
+	actionUndo
+	actionExport
+	actionCopyNote
+	actionCopyTimeline
+	actionPause
+	actionLowerA4
+	actionRaiseA4
+	actionLowerA4Fine
+	actionRaiseA4Fine
+	actionTranspose
+	actionRaiseCapo
+	actionLowerCapo
+	actionTemperament
+	actionTonic
+	actionOctaveFold
+	actionSettings
+	actionSettingUp
+	actionSettingDown
+	actionIncrease
+	actionDecrease
+	actionRestartMelody
+	actionTargetNote
+	actionFilter
+	actionScale
+	actionMetronome
+	actionSlower
+	actionFaster
+	actionMeter
+	actionScaleStrict
+	actionStringSet
+	actionLockString
+)
+
+// keyBinding ties keys to an action, with how the help describes it
+type keyBinding struct {
+	keys     []string // As tea.KeyMsg.String() reports them
+	label    string   // Keys as the help shows them
+	help     string   // What the keys do
+	category string   // Heading the help lists the binding under
+	hint     bool     // Whether the hint line under the main view lists it
+	action   keyAction
+}
+
+// Categories of key bindings, in the order the help lists them
+var keyCategories = []string{"Display", "Timeline", "Audio", "Modes"}
+
+// keyBindings lists every key the UI handles. Update dispatches through it,
+// and the hint line and help overlay are generated from it, so they always
+// agree.
+var keyBindings = []keyBinding{
+	{keys: []string{"?"}, label: "?", help: "show or hide this help", category: "Display", hint: true, action: actionHelp},
+	{keys: []string{"esc"}, label: "Esc", help: "close this help, dismiss the error banner, or clear the timeline filter", category: "Display", action: actionDismiss},
+	{keys: []string{"tab"}, label: "Tab", help: "show the next tab", category: "Display", hint: true, action: actionNextTab},
+	{keys: []string{"shift+tab"}, label: "Shift+Tab", help: "show the previous tab", category: "Display", action: actionPreviousTab},
+	{keys: []string{"d"}, label: "d", help: "toggle debug info", category: "Display", action: actionDebug},
+	{keys: []string{"b"}, label: "b", help: "toggle the big note, readable from across a stage", category: "Display", action: actionBigNote},
+	{keys: []string{"s"}, label: "s", help: "show or leave the Stats tab", category: "Display", action: actionStats},
+	{keys: []string{"k"}, label: "k", help: "toggle the piano keyboard", category: "Display", action: actionKeyboard},
+	{keys: []string{"g"}, label: "g", help: "toggle the guitar fretboard", category: "Display", action: actionFretboard},
+	{keys: []string{"v"}, label: "v", help: "show or leave the Spectrum tab", category: "Display", action: actionSpectrogram},
+	{keys: []string{"T"}, label: "T", help: "cycle color themes", category: "Display", action: actionTheme},
+	{keys: []string{"H"}, label: "H", help: "cycle scientific/Helmholtz/German notation", category: "Display", action: actionNotation},
+	{keys: []string{"N"}, label: "N", help: "cycle letter/solfège note names", category: "Display", action: actionNaming},
+	{keys: []string{"a"}, label: "a", help: "cycle sharp/flat/automatic spelling", category: "Display", action: actionSpelling},
+	{keys: []string{"x"}, label: "x", help: "cycle sharps/flats/solfège/German note names", category: "Display", action: actionNoteNames},
+	{keys: []string{"A"}, label: "A", help: "toggle plain ASCII without colors", category: "Display", action: actionPlain},
+	{keys: []string{"K"}, label: "K", help: "cycle the key signature for automatic spelling", category: "Display", action: actionKeySignature},
+	{keys: []string{"q", "ctrl+c"}, label: "q", help: "quit", category: "Display", hint: true, action: actionQuit},
+
+	{keys: []string{"f", "space"}, label: "f/space", help: "freeze/resume the timeline", category: "Timeline", hint: true, action: actionFreeze},
+	{keys: []string{"left", "h"}, label: "←/h", help: "scroll the frozen timeline back", category: "Timeline", action: actionScrollBack},
+	{keys: []string{"right", "l"}, label: "→/l", help: "scroll the frozen timeline forward", category: "Timeline", action: actionScrollForward},
+	{keys: []string{"home"}, label: "Home", help: "jump to the oldest notes", category: "Timeline", action: actionScrollOldest},
+	{keys: []string{"end"}, label: "End", help: "jump to the newest notes", category: "Timeline", action: actionScrollNewest},
+	{keys: []string{"G"}, label: "G", help: "group repeated notes", category: "Timeline", action: actionGroupRepeats},
+	{keys: []string{"/"}, label: "/", help: "show only one note's plays (type e.g. Eb or Eb4, then Enter)", category: "Timeline", action: actionFilter},
+	{keys: []string{"L"}, label: "L", help: "toggle the octave shade legend", category: "Timeline", action: actionOctaveLegend},
+	{keys: []string{"c"}, label: "c", help: "clear the history and session stats", category: "Timeline", hint: true, action: actionClear},
+	{keys: []string{"u"}, label: "u", help: "undo clearing the history, within 30 seconds", category: "Timeline", action: actionUndo},
+	{keys: []string{"e"}, label: "e", help: "export the timeline", category: "Timeline", action: actionExport},
+	{keys: []string{"y"}, label: "y", help: "copy the current note, e.g. \"A3 −12¢, 218.9 Hz\"", category: "Timeline", action: actionCopyNote},
+	{keys: []string{"Y"}, label: "Y", help: "copy the notes the timeline shows", category: "Timeline", action: actionCopyTimeline},
+
+	{keys: []string{"p"}, label: "p", help: "pause or resume detection", category: "Audio", action: actionPause},
+	{keys: []string{"["}, label: "[", help: "lower A4 by 1 Hz", category: "Audio", action: actionLowerA4},
+	{keys: []string{"]"}, label: "]", help: "raise A4 by 1 Hz", category: "Audio", action: actionRaiseA4},
+	{keys: []string{"{"}, label: "{", help: "lower A4 by 0.1 Hz", category: "Audio", action: actionLowerA4Fine},
+	{keys: []string{"}"}, label: "}", help: "raise A4 by 0.1 Hz", category: "Audio", action: actionRaiseA4Fine},
+	{keys: []string{"t"}, label: "t", help: "cycle transposing instruments", category: "Audio", action: actionTranspose},
+	{keys: []string{"shift+up"}, label: "Shift+↑", help: "raise the capo", category: "Audio", action: actionRaiseCapo},
+	{keys: []string{"shift+down"}, label: "Shift+↓", help: "lower the capo", category: "Audio", action: actionLowerCapo},
+	{keys: []string{"m"}, label: "m", help: "cycle temperaments", category: "Audio", action: actionTemperament},
+	{keys: []string{"M"}, label: "M", help: "move the temperament's tonic up a semitone", category: "Audio", action: actionTonic},
+	{keys: []string{"o"}, label: "o", help: "toggle ignoring octaves", category: "Audio", action: actionOctaveFold},
+	{keys: []string{"S"}, label: "S", help: "show or hide the settings panel", category: "Audio", action: actionSettings},
+	{keys: []string{"up"}, label: "↑", help: "select the previous setting", category: "Audio", action: actionSettingUp},
+	{keys: []string{"down"}, label: "↓", help: "select the next setting", category: "Audio", action: actionSettingDown},
+	{keys: []string{"+", "="}, label: "+", help: "increase the selected setting", category: "Audio", action: actionIncrease},
+	{keys: []string{"-", "_"}, label: "-", help: "decrease the selected setting", category: "Audio", action: actionDecrease},
+
+	{keys: []string{"r"}, label: "r", help: "start the target melody over", category: "Modes", action: actionRestartMelody},
+	{keys: []string{"n"}, label: "n", help: "practice a target note (type e.g. C#3, then Enter)", category: "Modes", action: actionTargetNote},
+	{keys: []string{"B"}, label: "B", help: "start or stop the metronome", category: "Modes", action: actionMetronome},
+	{keys: []string{"<"}, label: "<", help: "slow the metronome down", category: "Modes", action: actionSlower},
+	{keys: []string{">"}, label: ">", help: "speed the metronome up", category: "Modes", action: actionFaster},
+	{keys: []string{"|"}, label: "|", help: "cycle the metronome's meter", category: "Modes", action: actionMeter},
+	{keys: []string{"w"}, label: "w", help: "practice a scale (type e.g. G3 major, then Enter)", category: "Modes", action: actionScale},
+	{keys: []string{"W"}, label: "W", help: "switch scale practice between lenient and strict about passing notes", category: "Modes", action: actionScaleStrict},
+	{keys: []string{"i"}, label: "i", help: "cycle string tuner tunings, then turn it off", category: "Modes", action: actionStringSet},
+	{keys: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, label: "0-9", help: "lock the string tuner to a string (1 = highest), again to release; with it off, 1-4 pick a tab", category: "Modes", action: actionLockString},
+}
+
+// findKeyBinding returns the binding of a key as tea.KeyMsg.String()
+// reports it
+func findKeyBinding(key string) (keyBinding, bool) {
+	for _, binding := range keyBindings {
+		if slices.Contains(binding.keys, key) {
+			return binding, true
+		}
+	}
+	return keyBinding{}, false
+}
+
+// renderHints renders the hint line under the main view, listing the keys
+// marked for it
+func renderHints(st styles) string {
+	var hints []string
+	for _, binding := range keyBindings {
+		if binding.hint {
+			hints = append(hints, "Press "+binding.label+" to "+binding.help)
+		}
+	}
+	return st.info.Render(strings.Join(hints, " | "))
+}
+
+// renderHelp renders every key binding grouped by category, wrapping the
+// descriptions to the given terminal width, zero for unknown
+func renderHelp(st styles, width int) string {
+	labelWidth := 0
+	for _, binding := range keyBindings {
+		labelWidth = max(labelWidth, lipgloss.Width(binding.label))
+	}
+	helpWidth := 0
+	if width > 0 {
+		helpWidth = max(10, width-labelWidth-4)
+	}
+
+	heading := st.info.Bold(true)
+	label := st.title.UnsetMarginBottom().PaddingLeft(1).PaddingRight(1).Width(labelWidth + 2)
+	help := st.info.PaddingLeft(1)
+	if helpWidth > 0 {
+		help = help.Width(helpWidth)
+	}
+
+	lines := []string{heading.Render("Keys"), ""}
+	for _, category := range keyCategories {
+		lines = append(lines, heading.Render(category))
+		for _, binding := range keyBindings {
+			if binding.category == category {
+				lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, label.Render(binding.label), help.Render(binding.help)))
+			}
+		}
+		lines = append(lines, "")
+	}
+	lines = append(lines, st.debug.Render("Press ? or Esc to close"))
+	return strings.Join(lines, "\n")
+}
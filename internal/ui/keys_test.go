@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// runActionCases returns the names of the actions runAction's switch has a
+// case for
+func runActionCases(t *testing.T) map[string]bool {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "model.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]bool{}
+	for _, decl := range file.Decls {
+		function, ok := decl.(*ast.FuncDecl)
+		if !ok || function.Name.Name != "runAction" {
+			continue
+		}
+		for _, statement := range function.Body.List {
+			if switchStatement, ok := statement.(*ast.SwitchStmt); ok {
+				for _, clause := range switchStatement.Body.List {
+					for _, expression := range clause.(*ast.CaseClause).List {
+						if ident, ok := expression.(*ast.Ident); ok {
+							cases[ident.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return cases
+}
+
+// actionNames returns the names of the keyActions in the order declared
+func actionNames(t *testing.T) []string {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "keys.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	ast.Inspect(file, func(node ast.Node) bool {
+		if spec, ok := node.(*ast.ValueSpec); ok && strings.HasPrefix(spec.Names[0].Name, "action") {
+			names = append(names, spec.Names[0].Name)
+		}
+		return true
+	})
+	return names
+}
+
+func TestEveryActionBoundAndHandled(t *testing.T) {
+	names := actionNames(t)
+	if len(names) != int(actionLockString)+1 {
+		t.Fatalf("found %d actions, want %d", len(names), actionLockString+1)
+	}
+
+	// Every action but actionNone has exactly one binding and a case in
+	// runAction, so no key is listed in the help without doing anything
+	cases := runActionCases(t)
+	bound := map[keyAction]int{}
+	for _, binding := range keyBindings {
+		bound[binding.action]++
+	}
+	for action := actionQuit; action <= actionLockString; action++ {
+		name := names[action]
+		if bound[action] != 1 {
+			t.Errorf("%s has %d bindings, want 1", name, bound[action])
+		}
+		if !cases[name] {
+			t.Errorf("%s has no case in runAction", name)
+		}
+	}
+	if bound[actionNone] > 0 || cases["actionNone"] {
+		t.Error("actionNone is bound or handled")
+	}
+
+	// Every key is bound once, and listed under a heading the help shows
+	keys := map[string]string{}
+	for _, binding := range keyBindings {
+		if !slices.Contains(keyCategories, binding.category) {
+			t.Errorf("%s is in unknown category %q", binding.label, binding.category)
+		}
+		if binding.label == "" || binding.help == "" || len(binding.keys) == 0 {
+			t.Errorf("incomplete binding %+v", binding)
+		}
+		for _, key := range binding.keys {
+			if other, ok := keys[key]; ok {
+				t.Errorf("%q is bound to both %s and %s", key, other, binding.label)
+			}
+			keys[key] = binding.label
+		}
+	}
+}
+
+func TestHelpFitsNarrowTerminals(t *testing.T) {
+	for _, width := range []int{40, 60, 80} {
+		m := updateModel(sizedModel(width, 200), tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+		view := m.View()
+		for i, line := range strings.Split(view, "\n") {
+			if got := ansi.StringWidth(line); got > width {
+				t.Errorf("%d columns: line %d is %d wide: %q", width, i+1, got, ansi.Strip(line))
+			}
+		}
+
+		// Wrapping keeps every binding, with the full text of its help,
+		// though a word too long for a line is split
+		text := strings.Join(strings.Fields(ansi.Strip(view)), "")
+		for _, binding := range keyBindings {
+			if !strings.Contains(text, strings.ReplaceAll(binding.label+binding.help, " ", "")) {
+				t.Errorf("%d columns: %s isn't listed in full", width, binding.label)
+			}
+		}
+
+		// Any other key leaves the help up, and Esc closes it
+		debug := m.showDebug
+		m = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+		if !m.showHelp || m.showDebug != debug {
+			t.Errorf("%d columns: d with the help up toggled debug %v, help %v", width, m.showDebug, m.showHelp)
+		}
+		if m = updateModel(m, tea.KeyMsg{Type: tea.KeyEsc}); m.showHelp {
+			t.Errorf("%d columns: Esc left the help up", width)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sizedModel returns a model on a terminal of the given size with a note
+// playing and a few in the timeline
+func sizedModel(width, height int) Model {
+	var m tea.Model = NewModel(nil)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+
+	converter := pitch.NewNoteConverter()
+	start := time.Now().Add(-5 * time.Second)
+	for i, frequency := range []float64{196, 220, 246.94, 261.63, 445} {
+		note := converter.FromFrequency(frequency)
+		at := start.Add(time.Duration(i) * time.Second)
+		m, _ = m.Update(NoteOnMsg{Note: *note, At: at})
+		m, _ = m.Update(UpdateNoteMsg(*note))
+		if i < 4 {
+			m, _ = m.Update(NoteOffMsg{Note: *note, At: at.Add(500 * time.Millisecond), Duration: 500 * time.Millisecond})
+		}
+	}
+	return m.(Model)
+}
+
+// checkFits fails the test if a view has a line wider than the terminal or
+// more lines than its height
+func checkFits(t *testing.T, view string, width, height int) {
+	t.Helper()
+	lines := strings.Split(view, "\n")
+	if len(lines) > height {
+		t.Errorf("%d lines on a terminal %d high", len(lines), height)
+	}
+	for i, line := range lines {
+		if lineWidth := lipgloss.Width(line); lineWidth > width {
+			t.Errorf("line %d is %d wide on a terminal %d wide: %q", i+1, lineWidth, width, line)
+		}
+	}
+}
+
+func TestViewFitsTerminal(t *testing.T) {
+	tests := []struct {
+		width, height int
+		debug, hints  bool // Whether the debug panel and key hints fit
+	}{
+		{60, 20, false, false},
+		{100, 30, true, true},
+		{200, 50, true, true},
+	}
+	for _, tt := range tests {
+		for tab := tabTuner; tab < tabCount; tab++ {
+			t.Run(fmt.Sprintf("%dx%d %s", tt.width, tt.height, tabNames[tab]), func(t *testing.T) {
+				m := sizedModel(tt.width, tt.height).switchTab(tab)
+				view := m.View()
+				checkFits(t, view, tt.width, tt.height)
+
+				if tab != tabTuner {
+					return
+				}
+				if got := strings.Contains(view, "Audio Level"); got != tt.debug {
+					t.Errorf("debug panel shown: %v, want %v", got, tt.debug)
+				}
+				if got := strings.Contains(view, "Press q to quit"); got != tt.hints {
+					t.Errorf("key hints shown: %v, want %v", got, tt.hints)
+				}
+			})
+		}
+	}
+}
+
+func TestViewTooSmall(t *testing.T) {
+	for _, size := range [][2]int{{minWidth - 1, 30}, {100, minHeight - 1}, {20, 5}} {
+		view := sizedModel(size[0], size[1]).View()
+		if !strings.Contains(view, "Terminal too small") {
+			t.Errorf("%dx%d: no \"too small\" message in %q", size[0], size[1], view)
+		}
+		checkFits(t, view, size[0], size[1])
+	}
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderLevelMeter(t *testing.T) {
+	// Thirty segments of 2 dB from -60 dB, lit up to the level, with the
+	// held peak ticked beyond it
+	st := newStyles(ThemeDefault, true)
+	tests := []struct {
+		name     string
+		db, peak float32
+		want     string
+	}{
+		{"silence", -100, -100, "Level ······························  -∞ dB CLIP"},
+		{"floor", -60, -60, "Level █·····························  -60 dB CLIP"},
+		{"quiet", -41, -41, "Level ██████████····················  -41 dB CLIP"},
+		{"peak above", -30, -12, "Level ████████████████········│·····  -30 dB CLIP"},
+		{"peak at level", -30, -30, "Level ████████████████··············  -30 dB CLIP"},
+		{"full scale", 0, 0, "Level ██████████████████████████████    0 dB CLIP"},
+	}
+	for _, tt := range tests {
+		got := ansi.Strip(renderLevelMeter(st, tt.db, tt.peak, false))
+		if got != tt.want {
+			t.Errorf("%s\n got %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLevelMeterZonesAndClip(t *testing.T) {
+	withTrueColor(t)
+	st := newStyles(ThemeDefault, false)
+
+	// A full-scale bar runs through the green, yellow and red zones, the
+	// yellow starting at -18 dB (segment 21) and the red at -6 dB (27)
+	bar := renderLevelMeter(st, 0, 0, false)
+	zones := []struct {
+		style    string
+		segments int
+	}{
+		{levelLowStyle.Render("█"), 21},
+		{levelMidStyle.Render("█"), 6},
+		{levelHighStyle.Render("█"), 3},
+	}
+	for _, zone := range zones {
+		if got := strings.Count(bar, zone.style); got != zone.segments {
+			t.Errorf("%d segments in %q, want %d", got, zone.style, zone.segments)
+		}
+	}
+
+	if got := renderLevelMeter(st, -30, -30, true); !strings.HasSuffix(got, clipStyle.Render("CLIP")) {
+		t.Errorf("clipped meter ends %q, want the lit indicator", got[max(0, len(got)-40):])
+	}
+	if got := renderLevelMeter(st, -30, -30, false); !strings.HasSuffix(got, levelOffStyle.Render("CLIP")) {
+		t.Errorf("unclipped meter ends %q, want the dim indicator", got[max(0, len(got)-40):])
+	}
+}
+
+func TestClipIndicatorLatches(t *testing.T) {
+	m := sizedModel(100, 30)
+	m = updateModel(m, UpdateAudioLevelMsg{DB: -1, PeakDB: 0, Clipped: true})
+	if !m.clipped {
+		t.Fatal("clip not shown")
+	}
+
+	// It stays lit through later clean frames for a moment, then goes out
+	m = updateModel(m, UpdateAudioLevelMsg{DB: -20, PeakDB: -18})
+	if !m.clipped {
+		t.Error("clip indicator went out straight away")
+	}
+	m.clippedAt = m.clippedAt.Add(-clipHold)
+	m = updateModel(m, UpdateAudioLevelMsg{DB: -20, PeakDB: -18})
+	if m.clipped {
+		t.Errorf("clip indicator still lit %v after clipping", clipHold)
+	}
+}
+
+func TestHeldPeakFalls(t *testing.T) {
+	tests := []struct {
+		since time.Duration
+		want  float32
+	}{
+		{0, -10},
+		{peakHold, -10},
+		{peakHold + 500*time.Millisecond, -20},
+		{peakHold + 2*time.Second, -50},
+	}
+	for _, tt := range tests {
+		if got := heldPeak(-10, tt.since); got != tt.want {
+			t.Errorf("heldPeak(-10, %v) = %v, want %v", tt.since, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Metronome settings
+const (
+	defaultMetronomeBPM = 100.0
+	metronomeStep       = 2.0                    // BPM per press of < or >
+	beatPulse           = 150 * time.Millisecond // How long a beat stays lit
+)
+
+// Beat indicator styles by accent, lit while the beat pulses
+var (
+	beatStyles = map[pitch.Accent]lipgloss.Style{
+		pitch.AccentStrong: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#D9534F")),
+		pitch.AccentMedium: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#E5C07B")),
+		pitch.AccentNone:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#43A047")),
+	}
+	beatOffStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#444444"))
+)
+
+// MetronomeBeatMsg is sent when a metronome beat falls
+type MetronomeBeatMsg struct {
+	Index      int       // Beats since the metronome started
+	At         time.Time // When the beat was scheduled to fall
+	generation int       // Schedule the beat belongs to; stale ones are dropped
+}
+
+// scheduleBeat returns a tea.Cmd waiting for the first metronome beat after
+// the given time. Waiting for each beat's own time, rather than a period
+// after the last, keeps late ticks from delaying the beats after them.
+func (m Model) scheduleBeat(after time.Time) tea.Cmd {
+	at, index := m.metronome.NextBeat(after)
+	generation := m.beatGeneration
+	return tea.Tick(time.Until(at), func(time.Time) tea.Msg {
+		return MetronomeBeatMsg{Index: index, At: at, generation: generation}
+	})
+}
+
+// toggleMetronome starts or stops the metronome, starting on a downbeat
+func (m Model) toggleMetronome() (Model, tea.Cmd) {
+	m.metronomeOn = !m.metronomeOn
+	m.beatGeneration++
+	m.beatAt = time.Time{}
+	m.beatTimed = false
+	if !m.metronomeOn {
+		return m, nil
+	}
+
+	now := time.Now()
+	m.metronome = pitch.NewMetronome(m.metronome.BPM(), m.metronome.Meter(), now)
+	m.beatIndex = 0
+	m.beatAt = now
+	return m, m.scheduleBeat(now)
+}
+
+// setMetronomeBPM changes the metronome's tempo from its next beat on
+func (m Model) setMetronomeBPM(bpm float64) (Model, tea.Cmd) {
+	if bpm < pitch.MinMetronomeBPM || bpm > pitch.MaxMetronomeBPM {
+		m = m.withStatus(fmt.Sprintf("The metronome runs at %.0f-%.0f BPM", pitch.MinMetronomeBPM, pitch.MaxMetronomeBPM), true)
+	}
+
+	now := time.Now()
+	m.metronome.SetBPM(bpm, now)
+	if !m.metronomeOn {
+		return m, nil
+	}
+
+	// The beat already scheduled may have moved
+	m.beatGeneration++
+	return m, m.scheduleBeat(now)
+}
+
+// cycleMeter moves the metronome to the next meter, starting its bar on the
+// next beat
+func (m Model) cycleMeter() (Model, tea.Cmd) {
+	current := m.metronome.Meter()
+	next := pitch.Meters[0]
+	for i, meter := range pitch.Meters {
+		if meter.Name == current.Name {
+			next = pitch.Meters[(i+1)%len(pitch.Meters)]
+		}
+	}
+
+	now := time.Now()
+	m.metronome.SetMeter(next, now)
+	m = m.withStatus("Meter: "+next.Name, false)
+	if !m.metronomeOn {
+		return m, nil
+	}
+	m.beatGeneration++
+	return m, m.scheduleBeat(now)
+}
+
+// timeOnset measures how far a note's onset lands from the nearest beat
+func (m Model) timeOnset(at time.Time) Model {
+	_, m.beatOffset = m.metronome.NearestBeat(at)
+	m.beatTimed = true
+	return m
+}
+
+// renderMetronome renders the metronome's tempo and meter, the beats of the
+// bar with the current one lit while it pulses, and the timing of the
+// latest onset against the beat
+func (m Model) renderMetronome() string {
+	meter := m.metronome.Meter()
+	beats := meter.Beats()
+	current := ((m.beatIndex % beats) + beats) % beats
+	pulsing := !m.beatAt.IsZero() && time.Since(m.beatAt) < beatPulse
+
+	cells := make([]string, beats)
+	for i := range cells {
+		accent := meter.Accent(i)
+		symbol := "○"
+		if accent != pitch.AccentNone {
+			symbol = "◯"
+		}
+		switch {
+		case i == current && pulsing:
+			cells[i] = beatStyles[accent].Render("●")
+		case i == current:
+			cells[i] = beatStyles[accent].Render(symbol)
+		default:
+			cells[i] = beatOffStyle.Render(symbol)
+		}
+	}
+
+	s := m.styles.info.Render(fmt.Sprintf("Metronome: %.0f BPM %s  ", m.metronome.BPM(), meter.Name)) + strings.Join(cells, " ")
+	if m.beatTimed {
+		s += m.styles.info.Render(fmt.Sprintf("  | Last onset: %+d ms", m.beatOffset.Milliseconds()))
+	}
+	return s
+}
@@ -2,7 +2,10 @@ package ui
 
 import (
 	"fmt"
+	"math"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/0xlemi/tunenote/internal/pitch"
@@ -13,366 +16,2839 @@ import (
 // Constants for UI behavior
 const (
 	// Timeline settings
-	maxTimelineEntries = 50 // Maximum entries in the timeline
-	timelineWidth      = 70 // Total width of the timeline
-	noteDisplayWidth   = 3  // Width of each note entry in timeline
+	maxTimelineEntries   = 1000 // Entries kept in the timeline unless set with WithTimelineLength
+	defaultTimelineWidth = 70   // Width of the timeline's notes until the terminal size is known
+	noteDisplayWidth     = 4    // Width of each note entry in timeline
+	timeMarkerEvery      = 10   // Entries between the timeline's time markers
+
+	// Layout settings: the main column takes the terminal's width up to
+	// maxColumnWidth, centered on wider terminals, and below minWidth by
+	// minHeight only a "too small" message is shown
+	maxColumnWidth = 120
+	minWidth       = 40
+	minHeight      = 16
+
+	// Spaces either side of a button's label inside its border
+	buttonPadding = 2
+
+	// How long a status message such as an export's outcome stays up
+	statusDuration = 4 * time.Second
+
+	// Longest silence between two plays of a note for them to be grouped
+	// as repeats
+	repeatGap = time.Second
+
+	// Rests take one timeline cell per this much silence, up to maxRestCells
+	restCellDuration = 500 * time.Millisecond
+	maxRestCells     = 4
+
+	// Ended notes and chords take one timeline cell per this much of their
+	// length, up to maxNoteCells, so a whole note stands out from a
+	// sixteenth at moderate tempos
+	noteCellDuration = 250 * time.Millisecond
+	maxNoteCells     = 8
+
+	// Confidence below which the note box is dimmed, and from which the
+	// confidence indicator shows a reading as fully reliable
+	marginalConfidence = 0.75
+	highConfidence     = 0.9
+
+	// Dots of the confidence indicator
+	confidenceDots = 5
+
+	// How long the last note stays up, dimmed, once the sound stops, unless
+	// set with WithNoteHold
+	defaultNoteHold = 750 * time.Millisecond
+
+	// Number of overtones listed in the debug panel
+	debugHarmonics = 4
+
+	// How often the estimated key is refreshed
+	keyUpdateInterval = 3 * time.Second
+
+	// Tempo estimates below this confidence aren't shown
+	minTempoConfidence = 0.5
+
+	// Number of notes listed in the intonation table
+	maxIntonationRows = 8
+
+	// Width of the longest bar of the pitch class histogram
+	histogramWidth = 30
+
+	// Cents from a string's target still shown as in tune
+	stringInTuneCents = 3.0
+
+	// Width of the steadiness bar in characters
+	steadinessBarWidth = 20
+
+	// Cents gauge settings: the gauge spans ±gaugeRange cents in gaugeWidth
+	// cells until the terminal size is known, then fills the main column
+	// within minGaugeWidth to maxGaugeWidth (a cent per cell)
+	gaugeRange    = 50.0
+	gaugeWidth    = 41
+	minGaugeWidth = 11
+	maxGaugeWidth = 101
+
+	// Piano keyboard settings: each white key takes keyWidth columns, and the
+	// keyboard spans up to maxKeyboardOctaves, fewer on narrow terminals
+	keyWidth           = 4
+	maxKeyboardOctaves = 3
+
+	// Guitar fretboard settings: frets shown beyond the open strings, each
+	// taking fretWidth columns, with fewer on narrow terminals
+	fretboardFrets = 12
+	fretWidth      = 4
+
+	// Spectrogram settings: frames kept, and the level below the loudest
+	// band drawn as background
+	maxSpectrogramColumns = maxColumnWidth
+	spectrogramFloor      = -60.0
+
+	// Level meter settings: segments spanning levelMeterFloor to 0 dB, the
+	// levels where the yellow and red zones start, how long the peak tick
+	// holds before falling at peakDecay dB per second, and how long the clip
+	// indicator stays lit
+	levelMeterWidth  = 30
+	levelMeterFloor  = -60.0
+	levelMeterYellow = -18.0
+	levelMeterRed    = -6.0
+	peakHold         = time.Second
+	peakDecay        = 20.0
+	clipHold         = time.Second
+
+	// Slack around a glissando's capture times when excluding the note
+	// updates received during it from the drift trend
+	glissandoMargin = 250 * time.Millisecond
 )
 
 var (
-	// Styles
-	titleStyle = lipgloss.NewStyle().
+	// String being tuned in the string tuner
+	activeStringStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("#1A1A1A")).
+				Background(lipgloss.Color("#CCCCCC"))
+
+	// Piano keys other than the one being played
+	whiteKeyStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#555555")).
+			Background(lipgloss.Color("#BBBBBB"))
+	blackKeyStyle = lipgloss.NewStyle().
+			Background(lipgloss.Color("#333333"))
+
+	// Spectrogram shades from background to loudest, and the color marking
+	// the band of the note shown
+	spectrogramShades = []string{"#000000", "#3A3A3A", "#6C6C6C", "#9E9E9E", "#D0D0D0", "#FFFFFF"}
+	spectrogramMarker = "#FFB000"
+
+	// Spectrogram cells by the shade of their upper and lower half, the
+	// marker counting as one more shade; built on first use
+	spectrogramCells     [][]string
+	spectrogramCellsOnce sync.Once
+
+	// Status messages reporting success and failure
+	statusStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("#43A047"))
+	statusErrorStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#D9534F"))
+
+	// Level meter zones, the unlit segments, and the clip indicator
+	levelLowStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#43A047"))
+	levelMidStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#E5C07B"))
+	levelHighStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#D9534F"))
+	levelOffStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#444444"))
+	clipStyle      = lipgloss.NewStyle().
 			Bold(true).
 			Foreground(lipgloss.Color("#FAFAFA")).
-			Background(lipgloss.Color("#7D56F4")).
-			PaddingLeft(2).
-			PaddingRight(2).
-			MarginBottom(1)
+			Background(lipgloss.Color("#D9534F"))
+
+	// Note box border and badge of a note locked in tune
+	inTuneColor      = "#43A047"
+	inTuneBadgeStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("#FAFAFA")).
+				Background(lipgloss.Color(inTuneColor)).
+				Padding(0, 1).
+				MarginBottom(1)
+
+	// Title row badges while recording, while detection is paused and while
+	// the timeline is frozen
+	pausedBadgeStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("#FAFAFA")).
+				Background(lipgloss.Color("#2A7BBA")).
+				Padding(0, 1)
+	recordingBadgeStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("#FAFAFA")).
+				Background(lipgloss.Color("#D9534F")).
+				Padding(0, 1)
+	frozenBadgeStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("#1A1A1A")).
+				Background(lipgloss.Color("#E5C07B")).
+				Padding(0, 1)
+
+	// Cents gauge marker colors by how far off the note is
+	gaugeInTuneStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#43A047")) // Within ±5 cents
+	gaugeCloseStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#E5C07B")) // Within ±15 cents
+	gaugeOffStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#D9534F")) // Further off
+
+	// Standard box size, fitting names of up to four characters (e.g. "Cis4")
+	// inside the padding
+	boxWidth = 12
+
+	// Letter names of the natural pitch classes (0 = C), empty for accidentals
+	naturalNoteLetters = [12]string{"C", "", "D", "", "E", "F", "", "G", "", "A", "", "B"}
+)
+
+// spectrogramColumn is one frame of the spectrogram
+type spectrogramColumn struct {
+	levels      []float64 // Level of each band in dB relative to the loudest, lowest first
+	fundamental int       // Band of the note shown at the time, -1 for none
+}
+
+// TimelineEntry represents a note or rest in the timeline with timestamp
+type TimelineEntry struct {
+	Note      *pitch.Note // Nil for a rest
+	Timestamp time.Time
+	Duration  time.Duration   // How long the note or rest lasted, zero while the note is still sounding
+	Interval  *pitch.Interval // Leap from the previous note, nil for the first one
+	From      *pitch.Note     // Note a glissando ending on Note started from, nil for attacked notes
+	Repeats   int             // Plays of the note in a row when repeats are grouped, zero for a single play
+	Chord     string          // Symbol of a chord played, with Note its strongest note; empty for single notes
+	Reattack  bool            // Whether the note was played again straight after itself, marked at its left edge
+
+	elided int // Entries a timeline filter left out here, shown as a gap marker; zero for a shown entry
+}
+
+// Model represents the UI state
+type Model struct {
+	currentNote       *pitch.Note
+	timeline          []TimelineEntry // Timeline of recent notes
+	lastUpdate        time.Time
+	width             int
+	height            int
+	isSilence         bool           // Whether we're currently detecting silence
+	silenceSince      time.Time      // When we first detected silence
+	noteHold          time.Duration  // How long the last note stays up, dimmed, once the sound stops
+	neighborThreshold float64        // Cents off from which the neighbor a note leans toward is pointed out
+	releaseUntil      time.Time      // When the released note gives way to the placeholder, zero while none is
+	audioRMS          float32        // Current RMS level
+	audioDB           float32        // Current dB level
+	noiseDB           float32        // Detector's noise estimate in dB
+	peakDB            float32        // Highest recent peak in dB, as held by the level meter
+	peakAt            time.Time      // When peakDB was reached
+	clippedAt         time.Time      // When clipping was last reported
+	clipped           bool           // Whether the clip indicator is lit
+	showDebug         bool           // Whether to show debug info
+	bigMode           bool           // Whether the note is drawn in big block letters
+	debugInfo         UpdateDebugMsg // Processing loop internals for the debug panel
+	banner            ErrorMsg       // Problem shown under the title, with a nil Err for none
+	bannerAt          time.Time      // When the banner went up
+	showHelp          bool           // Whether the key help replaces the main view
+	tab               tab            // Tab shown
+	timelineFrozen    bool           // Whether the timeline is frozen/paused
+	started           time.Time      // When the session started, for the session clock
+	paused            bool           // Whether detection is paused, leaving the last note up dimmed
+	recording         bool           // Whether the audio is being recorded to a WAV file
+	stats             UpdateStatsMsg // Input stream figures for the status bar
+	timelineLength    int            // Most entries kept in the timeline
+	timelineScroll    int            // Entries hidden right of the visible ones while frozen, zero for the newest
+	groupRepeats      bool           // Whether repeated notes share a timeline block
+	exportPath        string         // Where e writes the timeline; see exportPaths
+
+	status      string    // Transient message such as an export's outcome, "" for none
+	statusError bool      // Whether the status reports a failure
+	statusUntil time.Time // When the status goes away
+
+	sequence           string // Terminal sequence View sends ahead of the UI, such as a clipboard write or the bell
+	sequenceGeneration int    // Latest sequence; takedowns of earlier ones are dropped
+
+	cleared     *clearedState // What the latest clear threw away, nil once it can't be undone
+	hoverButton keyAction     // Action of the button under the mouse pointer, actionNone for none
+
+	settings     Settings // Processing loop settings as last sent
+	settingIndex int      // Row selected in the settings panel
+	showSettings bool     // Whether to show the settings panel
+
+	chordNotes  []pitch.Note     // Simultaneous notes in chord mode, strongest first
+	chordSymbol string           // Chord formed by chordNotes, e.g. "Cmaj7"; empty when they don't form one
+	harmonics   []pitch.Harmonic // Harmonic levels of the current note
+
+	vibrato *pitch.Vibrato // Vibrato of the held note, nil when there is none
+
+	steadiness *pitch.Steadiness // Steadiness of the held note, nil until it has been held a while
+
+	centsHistory centsHistory // Recent cents readings of the held note
+	held         heldNote     // Summary of the held note's cents
+	heldEnded    heldNote     // Summary of the last held note, shown dimmed for heldLinger after it ended
+	heldEndedAt  time.Time    // When the last held note ended
+
+	inTune       *pitch.InTuneDetector // Tells when the note has settled in tune
+	inTuneLocked bool                  // Whether the note is locked in tune
+	bell         bool                  // Whether to ring the terminal bell when a note locks in tune
+
+	hnr float64 // Harmonic-to-noise ratio of the current note in dB
+
+	interval     *pitch.Interval // Leap to the current note, nil when there was no previous note
+	intervalFrom *pitch.Note     // Note the leap started from
+
+	chordNamer *pitch.ChordIdentifier // Names the chords formed by chordNotes
+
+	keyEstimator *pitch.KeyEstimator // Estimates the key from the notes in the timeline
+	keyLabel     string              // Last key estimate shown, e.g. "G major (78%)"
+	keyUpdated   time.Time           // When keyLabel was last refreshed
+
+	tempoEstimator *pitch.TempoEstimator // Estimates the tempo from note onsets
+	onsetAt        time.Time             // When the latest onset was detected, zero before the first
+	tempoLabel     string                // Last tempo estimate shown, e.g. "≈ 96 BPM"
+
+	intonation *pitch.IntonationStats // Cents deviations per note over the session
+	session    *pitch.SessionStats    // Notes played over the session
+	drift      *pitch.DriftMonitor    // Trend of the cents deviations over the session
+
+	showKeyboard     bool // Whether to show the piano keyboard
+	showFretboard    bool // Whether to show the guitar fretboard
+	showOctaveLegend bool // Whether to show which timeline shade is which octave
+
+	spectrogram      []spectrogramColumn    // Recent frames, oldest first
+	spectrogramScale pitch.SpectrogramScale // Bands of the recent frames
+	waveform         []float32              // Latest captured block
+
+	melody      *pitch.MelodyScorer // Scores singing along to a target melody, nil without one
+	melodyCents float64             // Live deviation from the target note
+	melodyLive  bool                // Whether melodyCents is current
+	melodyLabel string              // Current target and running score
+
+	filter *noteFilter // Picks the timeline entries shown, nil to show all of them
+
+	typing    textInput // What keys type rather than acting on, inputNone when they act
+	inputText string    // Text typed so far
+
+	practice         *pitch.TargetPractice // Judges played notes against a target note, nil when target practice is off
+	judgment         pitch.Judgment        // Judgment of the latest note played
+	judgmentInterval pitch.Interval        // From the latest note played to the target
+	judgedNote       pitch.Note            // Latest note played
+	judgedAt         time.Time             // When the latest note was judged, zero before the first
+
+	scale        *pitch.ScaleSession // Scale being practiced, nil when scale practice is off
+	scaleStrict  bool                // Whether scale practice counts passing notes as mistakes
+	scaleMistake *pitch.Note         // Latest wrong note of the scale, nil since the last right one
+
+	metronome      *pitch.Metronome // Beat schedule, kept while off so its tempo and meter stay
+	metronomeOn    bool             // Whether the metronome is running
+	beatGeneration int              // Current beat schedule; beats of earlier ones are dropped
+	beatIndex      int              // Latest beat since the metronome started
+	beatAt         time.Time        // When the latest beat fell, zero while off
+	beatOffset     time.Duration    // Latest onset's distance from the nearest beat
+	beatTimed      bool             // Whether beatOffset has been measured
+
+	stringSelector *pitch.StringSelector // Picks the string being tuned, nil when the string tuner is off
+	stringMatch    *pitch.StringMatch    // Current note against the string being tuned, nil when none is near
+	stringSets     []pitch.StringSet     // String sets cycled through: the built-in ones and any given at startup
+
+	// Settings mirrored to the audio processing loop
+	referenceA4   float64             // A4 reference pitch in Hz
+	transposition pitch.Transposition // Active transposing-instrument setting
+	capoOffset    int                 // Semitones added to the displayed note, e.g. -3 for a capo
+	spelling      pitch.Spelling      // How accidentals are named
+	keySignature  int                 // Sharps (positive) or flats (negative) for automatic spelling
+	naming        pitch.NamingScheme  // Letters or solfège syllables
+	temperament   pitch.Temperament   // Tuning system cents are measured against
+	tonic         int                 // Pitch class (0 = C) the temperament is built on
+	octaveFold    bool                // Whether notes an octave apart count as the same note
+
+	notation pitch.Notation // How note names and octaves are written; display only
+
+	temperaments []pitch.Temperament // Temperaments cycled through: the built-in ones and any loaded
+
+	prefsPath       string      // File the preferences are written to, "" to keep them unsaved
+	scheduledPrefs  Preferences // Preferences last loaded or scheduled for writing
+	prefsGeneration int         // Latest scheduled write; earlier ones are dropped
+
+	styles      styles   // Styles drawn with the current theme
+	hiddenPanes []string // Panes View left out to fit the terminal's height
+
+	commands chan<- Command // Commands sent back to the audio processing loop
+}
+
+// NewModel creates a new UI model. Commands for the audio processing loop
+// (e.g., reference pitch changes) are delivered on the given channel.
+func NewModel(commands chan<- Command) Model {
+	return Model{
+		currentNote:       nil,
+		timeline:          make([]TimelineEntry, 0, maxTimelineEntries),
+		lastUpdate:        time.Now(),
+		isSilence:         true,
+		silenceSince:      time.Now(),
+		noteHold:          defaultNoteHold,
+		neighborThreshold: defaultNeighborThreshold,
+		audioDB:           -100, // Silent until the first level update
+		showDebug:         true, // Default to showing debug info
+		showOctaveLegend:  true,
+		timelineFrozen:    false,
+		started:           time.Now(),
+		timelineLength:    maxTimelineEntries,
+		referenceA4:       pitch.DefaultReferenceA4,
+		transposition:     pitch.TranspositionConcert,
+		spelling:          pitch.SpellingSharps,
+		naming:            pitch.NamingLetters,
+		temperament:       pitch.TemperamentEqual,
+		temperaments:      pitch.Temperaments,
+		stringSets:        pitch.StringSets,
+		keyEstimator:      pitch.NewKeyEstimator(),
+		tempoEstimator:    pitch.NewTempoEstimator(),
+		intonation:        pitch.NewIntonationStats(),
+		session:           pitch.NewSessionStats(),
+		styles:            newStyles(ThemeDefault, false),
+		scheduledPrefs:    DefaultPreferences(),
+		drift:             pitch.NewDriftMonitor(),
+		chordNamer:        pitch.NewChordIdentifier(),
+		inTune:            pitch.NewInTuneDetector(pitch.DefaultInTuneTolerance, pitch.DefaultInTuneDwell),
+		metronome:         pitch.NewMetronome(defaultMetronomeBPM, pitch.Meter4_4, time.Now()),
+		commands:          commands,
+	}
+}
+
+// WithTemperament returns the model with the given temperament selected and
+// added to those cycled through, e.g. for a scale loaded at startup. The
+// processing loop's converter must be set to the same temperament.
+func (m Model) WithTemperament(temperament pitch.Temperament) Model {
+	m.temperament = temperament
+	for _, known := range m.temperaments {
+		if known.Name == temperament.Name {
+			return m
+		}
+	}
+
+	m.temperaments = append(append([]pitch.Temperament{}, m.temperaments...), temperament)
+	return m
+}
+
+// WithMelody returns the model with a target melody to sing along to, started
+// with r
+func (m Model) WithMelody(melody *pitch.MelodyTarget) Model {
+	m.melody = pitch.NewMelodyScorer(melody, m.referenceA4)
+	m.melodyLabel = m.describeMelody(time.Now())
+	return m
+}
+
+// WithTimelineLength returns the model keeping up to the given number of
+// entries in the timeline history
+func (m Model) WithTimelineLength(length int) Model {
+	m.timelineLength = max(1, length)
+	m.timeline = appendTimeline(m.timeline, nil, m.timelineLength)
+	return m
+}
+
+// WithNoteHold returns the model keeping the last note up, dimmed, for the
+// given time once the sound stops; zero clears it at once
+func (m Model) WithNoteHold(hold time.Duration) Model {
+	m.noteHold = max(0, hold)
+	return m
+}
+
+// WithNeighborThreshold returns the model pointing out the neighboring note
+// a note leans toward once it's more than the given cents off
+func (m Model) WithNeighborThreshold(cents float64) Model {
+	m.neighborThreshold = math.Abs(cents)
+	return m
+}
+
+// WithInTuneLock returns the model showing a note as in tune once its cents
+// have stayed within the given tolerance for the given time
+func (m Model) WithInTuneLock(tolerance float64, dwell time.Duration) Model {
+	m.inTune = pitch.NewInTuneDetector(tolerance, dwell)
+	return m
+}
+
+// WithBell returns the model ringing the terminal bell each time a note locks
+// in tune
+func (m Model) WithBell(bell bool) Model {
+	m.bell = bell
+	return m
+}
+
+// WithExportPath returns the model writing the timeline to the given path
+// when e is pressed: a .csv or .json file, or a directory to write both to
+func (m Model) WithExportPath(path string) Model {
+	m.exportPath = path
+	return m
+}
+
+// WithTheme returns the model drawn in the given theme
+func (m Model) WithTheme(theme Theme) Model {
+	m.styles = newStyles(theme, m.styles.plain)
+	return m
+}
+
+// WithPlain returns the model drawn in plain ASCII without colors, e.g. for
+// monochrome terminals
+func (m Model) WithPlain(plain bool) Model {
+	m.styles = newStyles(m.styles.theme, plain)
+	return m
+}
+
+// WithStringSet returns the model with the string tuner on for the given
+// strings, which are added to those cycled through
+func (m Model) WithStringSet(set pitch.StringSet) Model {
+	m = m.WithStringSets([]pitch.StringSet{set})
+	m.stringSelector = pitch.NewStringSelector(set)
+	m.stringSelector.SetReferenceA4(m.referenceA4)
+	return m
+}
+
+// WithStringSets returns the model with more tunings to cycle the string
+// tuner through, e.g. custom ones loaded at startup. A tuning replaces a
+// built-in one of the same name.
+func (m Model) WithStringSets(sets []pitch.StringSet) Model {
+	m.stringSets = append([]pitch.StringSet{}, m.stringSets...)
+	for _, set := range sets {
+		replaced := false
+		for i, known := range m.stringSets {
+			if known.Name == set.Name {
+				m.stringSets[i] = set
+				replaced = true
+			}
+		}
+		if !replaced {
+			m.stringSets = append(m.stringSets, set)
+		}
+	}
+	return m
+}
+
+// Init initializes the UI model
+func (m Model) Init() tea.Cmd {
+	return tea.Tick(time.Millisecond*100, func(t time.Time) tea.Msg {
+		return TickMsg(t)
+	})
+}
+
+// TickMsg represents a timer tick
+type TickMsg time.Time
+
+// UpdateNoteMsg is a message to update the current note
+type UpdateNoteMsg pitch.Note
+
+// NoteOnMsg is a message that a new stable note has started
+type NoteOnMsg pitch.NoteOn
+
+// NoteOffMsg is a message that the stable note has ended
+type NoteOffMsg pitch.NoteOff
+
+// GlissandoMsg is a message that a slide from one note to another has ended
+type GlissandoMsg pitch.Glissando
+
+// RestMsg is a message that a rest has ended with the start of a new note
+type RestMsg pitch.Rest
+
+// OnsetMsg is a message that a note was attacked, whether or not it's a new
+// note. A note picked up again without one had only dropped out of detection.
+type OnsetMsg pitch.Onset
+
+// UpdateChordMsg is a message to update the simultaneously sounding notes,
+// strongest first. Up to four are shown.
+type UpdateChordMsg struct {
+	Notes  []pitch.Note
+	Symbol string // Chord the notes form, e.g. "Cmaj7"; named from the notes when empty
+}
+
+// UpdateHarmonicsMsg is a message to update the harmonic levels shown in the debug panel
+type UpdateHarmonicsMsg struct {
+	Harmonics []pitch.Harmonic
+	HNR       float64 // Harmonic-to-noise ratio of the detection in dB
+}
+
+// UpdateVibratoMsg is a message to update the vibrato of the held note
+type UpdateVibratoMsg struct {
+	Vibrato  pitch.Vibrato
+	Detected bool // False when the held note has no vibrato
+}
+
+// UpdateSteadinessMsg is a message to update the steadiness of the held note
+type UpdateSteadinessMsg struct {
+	Steadiness pitch.Steadiness
+	Measured   bool // False until the held note has lasted long enough to rate
+}
+
+// UpdateSpectrumMsg is a message to add a frame to the spectrogram
+type UpdateSpectrumMsg struct {
+	Scale  pitch.SpectrogramScale
+	Levels []float64 // Level of each of the scale's bands in dB relative to the loudest, lowest first
+}
+
+// UpdateAudioLevelMsg is a message to update the audio level display
+type UpdateAudioLevelMsg struct {
+	RMS     float32
+	DB      float32
+	NoiseDB float32 // Detector's adaptive noise estimate in dB (magnitude units)
+	PeakDB  float32 // Largest sample since the previous update in dB
+	Clipped bool    // Whether any sample since the previous update reached full scale
+}
+
+// ClearNoteMsg is sent when we should clear the note display (no sound detected)
+type ClearNoteMsg struct{}
+
+// RecordingStateMsg is sent when recording the audio to a WAV file starts or
+// stops
+type RecordingStateMsg struct {
+	Recording bool
+}
+
+// Command is a request sent from the UI back to the audio processing loop
+type Command interface{}
+
+// SetReferenceA4Command asks the processing loop to change the A4 reference pitch
+type SetReferenceA4Command struct {
+	Hz float64
+}
+
+// SetTranspositionCommand asks the processing loop to change the transposition
+type SetTranspositionCommand struct {
+	Transposition pitch.Transposition
+}
+
+// SetCapoOffsetCommand asks the processing loop to change the capo offset
+type SetCapoOffsetCommand struct {
+	Semitones int
+}
+
+// SetSpellingCommand asks the processing loop to change how accidentals are named
+type SetSpellingCommand struct {
+	Spelling     pitch.Spelling
+	KeySignature int
+}
+
+// SetNamingCommand asks the processing loop to change the note naming scheme
+type SetNamingCommand struct {
+	Naming pitch.NamingScheme
+}
+
+// SetTemperamentCommand asks the processing loop to change the temperament
+type SetTemperamentCommand struct {
+	Temperament pitch.Temperament
+	Tonic       int
+}
+
+// SetPausedCommand asks the processing loop to stop or resume detection.
+// While paused it leaves the audio alone and the microphone idle.
+type SetPausedCommand struct {
+	Paused bool
+}
+
+// SetOctaveFoldCommand asks the processing loop to compare notes by pitch
+// class alone, so octave flips don't count as note changes
+type SetOctaveFoldCommand struct {
+	Fold bool
+}
+
+// withStatus returns the model showing a status message for a few seconds
+func (m Model) withStatus(status string, failed bool) Model {
+	m.status = status
+	m.statusError = failed
+	m.statusUntil = time.Now().Add(statusDuration)
+	return m
+}
+
+// sendCommand returns a tea.Cmd that delivers a command to the processing loop
+// without blocking the UI
+func (m Model) sendCommand(command Command) tea.Cmd {
+	if m.commands == nil {
+		return nil
+	}
+
+	commands := m.commands
+	return func() tea.Msg {
+		commands <- command
+		return nil
+	}
+}
+
+// sequenceSentAfter is how long View keeps sending a terminal sequence,
+// long enough for the renderer to draw a frame with it at its frame rate
+const sequenceSentAfter = 100 * time.Millisecond
+
+// sequenceSentMsg takes a terminal sequence out of View once the renderer has
+// sent it
+type sequenceSentMsg struct {
+	generation int
+}
+
+// sendSequence has the renderer send a terminal sequence with the next
+// frame. Writing it to the terminal from a tea.Cmd instead would race the
+// renderer's own writes and could land in the middle of an escape sequence.
+func (m Model) sendSequence(sequence string) (Model, tea.Cmd) {
+	m.sequence += sequence
+	m.sequenceGeneration++
+	generation := m.sequenceGeneration
+	return m, tea.Tick(sequenceSentAfter, func(time.Time) tea.Msg {
+		return sequenceSentMsg{generation: generation}
+	})
+}
+
+// ringBell has the renderer ring the terminal bell when a note has just
+// locked in tune and the bell is on
+func (m Model) ringBell(justLocked bool) (Model, tea.Cmd) {
+	if !justLocked || !m.bell {
+		return m, nil
+	}
+	return m.sendSequence("\a")
+}
+
+// setReferenceA4 updates the reference pitch and notifies the processing loop
+func (m Model) setReferenceA4(hz float64) (Model, tea.Cmd) {
+	// Keep the reference to the tenth of a hertz shown, so fine steps don't
+	// drift, and within the range the converter accepts
+	hz = math.Round(hz*10) / 10
+	if hz < pitch.MinReferenceA4 {
+		hz = pitch.MinReferenceA4
+	}
+	if hz > pitch.MaxReferenceA4 {
+		hz = pitch.MaxReferenceA4
+	}
+
+	m.referenceA4 = hz
+	if m.stringSelector != nil {
+		m.stringSelector.SetReferenceA4(hz)
+	}
+	return m, m.sendCommand(SetReferenceA4Command{Hz: hz})
+}
+
+// cycleTransposition switches to the next transposition preset
+func (m Model) cycleTransposition() (Model, tea.Cmd) {
+	next := pitch.Transpositions[0]
+	for i, transposition := range pitch.Transpositions {
+		if transposition == m.transposition {
+			next = pitch.Transpositions[(i+1)%len(pitch.Transpositions)]
+			break
+		}
+	}
+
+	m.transposition = next
+	return m, m.sendCommand(SetTranspositionCommand{Transposition: next})
+}
+
+// setCapoOffset updates the capo offset and notifies the processing loop
+func (m Model) setCapoOffset(semitones int) (Model, tea.Cmd) {
+	m.capoOffset = max(pitch.MinCapoOffset, min(semitones, pitch.MaxCapoOffset))
+	return m, m.sendCommand(SetCapoOffsetCommand{Semitones: m.capoOffset})
+}
+
+// cycleSpelling switches to the next accidental spelling mode
+func (m Model) cycleSpelling() (Model, tea.Cmd) {
+	next := pitch.Spellings[0]
+	for i, spelling := range pitch.Spellings {
+		if spelling == m.spelling {
+			next = pitch.Spellings[(i+1)%len(pitch.Spellings)]
+			break
+		}
+	}
+
+	m.spelling = next
+	return m, m.sendCommand(SetSpellingCommand{Spelling: m.spelling, KeySignature: m.keySignature})
+}
+
+// cycleKeySignature moves the key signature one step around the circle of
+// fifths, wrapping from seven sharps back to seven flats
+func (m Model) cycleKeySignature() (Model, tea.Cmd) {
+	m.keySignature++
+	if m.keySignature > pitch.MaxKeySignature {
+		m.keySignature = pitch.MinKeySignature
+	}
+
+	return m, m.sendCommand(SetSpellingCommand{Spelling: m.spelling, KeySignature: m.keySignature})
+}
+
+// cycleNaming switches to the next note naming scheme
+func (m Model) cycleNaming() (Model, tea.Cmd) {
+	next := pitch.NamingSchemes[0]
+	for i, naming := range pitch.NamingSchemes {
+		if naming == m.naming {
+			next = pitch.NamingSchemes[(i+1)%len(pitch.NamingSchemes)]
+			break
+		}
+	}
+
+	m.naming = next
+	return m, m.sendCommand(SetNamingCommand{Naming: next})
+}
+
+// noteNames is a preset of the spelling, naming scheme and notation notes
+// are shown in
+type noteNames struct {
+	name     string
+	spelling pitch.Spelling
+	naming   pitch.NamingScheme
+	notation pitch.Notation
+}
+
+// noteNamePresets lists the presets in the order x cycles through them
+var noteNamePresets = []noteNames{
+	{name: "Sharps", spelling: pitch.SpellingSharps, naming: pitch.NamingLetters, notation: pitch.NotationScientific},
+	{name: "Flats", spelling: pitch.SpellingFlats, naming: pitch.NamingLetters, notation: pitch.NotationScientific},
+	{name: "Solfège", spelling: pitch.SpellingSharps, naming: pitch.NamingFixedDo, notation: pitch.NotationScientific},
+	{name: "German", spelling: pitch.SpellingSharps, naming: pitch.NamingLetters, notation: pitch.NotationGerman},
+}
+
+// cycleNoteNames switches to the note name preset after the one in use, or
+// to the first when the settings don't match one
+func (m Model) cycleNoteNames() (Model, tea.Cmd) {
+	next := noteNamePresets[0]
+	for i, preset := range noteNamePresets {
+		if preset.spelling == m.spelling && preset.naming == m.naming && preset.notation == m.notation {
+			next = noteNamePresets[(i+1)%len(noteNamePresets)]
+			break
+		}
+	}
+
+	m.spelling = next.spelling
+	m.naming = next.naming
+	m.notation = next.notation
+	m = m.withStatus("Note names: "+next.name, false)
+	return m, tea.Batch(
+		m.sendCommand(SetSpellingCommand{Spelling: m.spelling, KeySignature: m.keySignature}),
+		m.sendCommand(SetNamingCommand{Naming: m.naming}))
+}
+
+// named returns a copy of a note named in the spelling and naming scheme in
+// use. Notes keep the names they were converted with, so shown notes are
+// named again from their pitch classes to follow later changes.
+func (m Model) named(note *pitch.Note) *pitch.Note {
+	if note == nil {
+		return nil
+	}
+	converter := pitch.NoteConverter{Spelling: m.spelling, KeySignature: m.keySignature, Naming: m.naming}
+	renamed := converter.Rename(*note)
+	return &renamed
+}
+
+// nextNotation returns the notation after the given one. Notation only
+// affects display, so nothing is sent to the processing loop.
+func nextNotation(notation pitch.Notation) pitch.Notation {
+	for i, candidate := range pitch.Notations {
+		if candidate == notation {
+			return pitch.Notations[(i+1)%len(pitch.Notations)]
+		}
+	}
+	return pitch.Notations[0]
+}
+
+// cycleTemperament switches to the next temperament
+func (m Model) cycleTemperament() (Model, tea.Cmd) {
+	next := m.temperaments[0]
+	for i, temperament := range m.temperaments {
+		if temperament.Name == m.temperament.Name {
+			next = m.temperaments[(i+1)%len(m.temperaments)]
+			break
+		}
+	}
+
+	m.temperament = next
+	return m, m.sendCommand(SetTemperamentCommand{Temperament: m.temperament, Tonic: m.tonic})
+}
+
+// cycleTonic moves the temperament's tonic up a semitone
+func (m Model) cycleTonic() (Model, tea.Cmd) {
+	m.tonic = (m.tonic + 1) % 12
+	return m, m.sendCommand(SetTemperamentCommand{Temperament: m.temperament, Tonic: m.tonic})
+}
+
+// toggleOctaveFold switches between telling notes apart by octave and
+// ignoring it
+func (m Model) toggleOctaveFold() (Model, tea.Cmd) {
+	m.octaveFold = !m.octaveFold
+	if m.scale != nil {
+		m.scale.SetOctaveFold(m.octaveFold)
+	}
+	return m, m.sendCommand(SetOctaveFoldCommand{Fold: m.octaveFold})
+}
+
+// togglePause pauses detection, keeping the last note up dimmed, or resumes
+// it with a blank note box so nothing from before the pause lingers
+func (m Model) togglePause() (Model, tea.Cmd) {
+	m.paused = !m.paused
+	m.inTuneLocked = false
+	if m.paused {
+		m = m.withStatus("Detection paused; press p to resume", false)
+	} else {
+		m.currentNote = nil
+		m.chordNotes = nil
+		m.releaseUntil = time.Time{}
+		m.centsHistory.reset()
+		m.held = heldNote{}
+		m = m.withStatus("Detection resumed", false)
+	}
+	return m, m.sendCommand(SetPausedCommand{Paused: m.paused})
+}
+
+// isDetection reports whether a message carries what the processing loop
+// detected, which is dropped while paused
+func isDetection(msg tea.Msg) bool {
+	switch msg.(type) {
+	case UpdateNoteMsg, NoteOnMsg, GlissandoMsg, RestMsg, OnsetMsg, UpdateChordMsg, UpdateHarmonicsMsg,
+		UpdateVibratoMsg, UpdateSteadinessMsg, UpdateSpectrumMsg, UpdateWaveformMsg, UpdateAudioLevelMsg, ClearNoteMsg:
+		return true
+	}
+	return false
+}
+
+// cycleStringSet switches the string tuner to the next string set, turning it
+// off after the last one
+func (m Model) cycleStringSet() Model {
+	m.stringMatch = nil
+	if m.stringSelector == nil {
+		return m.WithStringSet(m.stringSets[0])
+	}
+
+	current := m.stringSelector.StringSet().Name
+	for i, set := range m.stringSets {
+		if set.Name == current && i+1 < len(m.stringSets) {
+			m.stringSelector.SetStringSet(m.stringSets[i+1])
+			return m
+		}
+	}
+	m.stringSelector = nil
+	return m
+}
+
+// lockString locks the string tuner to a string by number, or releases the
+// lock when that string is already locked or the number is zero
+func (m Model) lockString(number int) Model {
+	if m.stringSelector == nil {
+		return m
+	}
+	if m.stringSelector.Locked() == number {
+		number = 0
+	}
+	m.stringSelector.Lock(number)
+	m.stringMatch = nil
+	if m.currentNote != nil {
+		m.stringMatch = m.matchString(m.currentNote.Frequency)
+	}
+	return m
+}
+
+// matchString measures a frequency against the string being tuned, returning
+// nil when no string is near
+func (m Model) matchString(frequency float64) *pitch.StringMatch {
+	match, ok := m.stringSelector.Select(frequency)
+	if !ok {
+		return nil
+	}
+	return &match
+}
+
+// runAction does what a key or button does. The key is as
+// tea.KeyMsg.String() reports it, for actions that depend on it.
+func (m Model) runAction(action keyAction, key string) (Model, tea.Cmd) {
+	// The help only closes, or quits
+	if m.showHelp && action != actionHelp && action != actionDismiss && action != actionQuit {
+		return m, nil
+	}
+
+	switch action {
+	case actionQuit:
+		return m, tea.Quit
+	case actionHelp:
+		// Toggle the help overlay
+		m.showHelp = !m.showHelp
+	case actionDismiss:
+		// Close the help, or take down the error banner when it's closed,
+		// or else clear the timeline filter
+		switch {
+		case m.showHelp:
+			m.showHelp = false
+		case m.banner.Err != nil:
+			m.banner = ErrorMsg{}
+		default:
+			m = m.clearFilter()
+		}
+	case actionDebug:
+		// Toggle debug display
+		m.showDebug = !m.showDebug
+	case actionBigNote:
+		// Toggle the big note, shown on the Tuner tab
+		m.bigMode = !m.bigMode
+		if m.bigMode {
+			m = m.switchTab(tabTuner)
+		}
+	case actionFreeze:
+		// Toggle timeline freeze, showing the newest notes again on resume
+		m.timelineFrozen = !m.timelineFrozen
+		m.timelineScroll = 0
+	case actionScrollBack:
+		// Scroll the frozen timeline back through older notes
+		if m.timelineFrozen {
+			m.timelineScroll = min(m.timelineScroll+1, m.maxTimelineScroll())
+		}
+	case actionScrollForward:
+		// Scroll the frozen timeline towards the newest notes
+		if m.timelineFrozen {
+			m.timelineScroll = max(m.timelineScroll-1, 0)
+		}
+	case actionScrollOldest:
+		// Jump to the oldest notes of the frozen timeline
+		if m.timelineFrozen {
+			m.timelineScroll = m.maxTimelineScroll()
+		}
+	case actionScrollNewest:
+		// Jump back to the newest notes
+		m.timelineScroll = 0
+	case actionTheme:
+		// Cycle through the themes
+		next := Themes[0]
+		for i, theme := range Themes {
+			if theme.Name == m.styles.theme.Name {
+				next = Themes[(i+1)%len(Themes)]
+			}
+		}
+		m.styles = newStyles(next, m.styles.plain)
+		return m.withStatus("Theme: "+next.Name, false), nil
+	case actionPlain:
+		// Toggle plain ASCII without colors
+		m.styles = newStyles(m.styles.theme, !m.styles.plain)
+		if m.styles.plain {
+			return m.withStatus("Plain ASCII: on", false), nil
+		}
+		return m.withStatus("Plain ASCII: off", false), nil
+	case actionGroupRepeats:
+		// Toggle grouping repeated notes in the timeline
+		m.groupRepeats = !m.groupRepeats
+		m.timelineScroll = 0
+	case actionOctaveLegend:
+		// Toggle the octave shade legend under the timeline
+		m.showOctaveLegend = !m.showOctaveLegend
+	case actionExport:
+		// Write the timeline to files in the background
+		if len(m.timeline) == 0 {
+			return m.withStatus("Nothing to export yet", true), nil
+		}
+		return m, exportTimeline(m.exportPath, m.timeline, time.Now())
+	case actionCopyNote:
+		return m.copyNote()
+	case actionCopyTimeline:
+		return m.copyTimeline()
+	case actionClear:
+		// Clear the history and session statistics, which u brings back
+		m = m.clearHistory(time.Now())
+	case actionUndo:
+		m = m.undoClear(time.Now())
+	case actionStats:
+		// Show the Stats tab, or go back to the Tuner
+		m = m.toggleTab(tabStats)
+	case actionKeyboard:
+		// Toggle the piano keyboard
+		m.showKeyboard = !m.showKeyboard
+	case actionFretboard:
+		// Toggle the guitar fretboard
+		m.showFretboard = !m.showFretboard
+	case actionSpectrogram:
+		// Show the Spectrum tab, or go back to the Tuner
+		m = m.toggleTab(tabSpectrum)
+	case actionNextTab:
+		m = m.switchTab(m.tab + 1)
+	case actionPreviousTab:
+		m = m.switchTab(m.tab - 1)
+	case actionRestartMelody:
+		// Start the target melody over
+		if m.melody != nil {
+			m.melody = pitch.NewMelodyScorer(m.melody.Target(), m.referenceA4)
+			m.melody.Start(time.Now())
+			m.melodyLive = false
+			m.melodyLabel = m.describeMelody(time.Now())
+		}
+	case actionLowerA4:
+		// Lower the A4 reference pitch
+		return m.setReferenceA4(m.referenceA4 - 1)
+	case actionRaiseA4:
+		// Raise the A4 reference pitch
+		return m.setReferenceA4(m.referenceA4 + 1)
+	case actionLowerA4Fine:
+		// Lower the A4 reference pitch in fine steps
+		return m.setReferenceA4(m.referenceA4 - 0.1)
+	case actionRaiseA4Fine:
+		// Raise the A4 reference pitch in fine steps
+		return m.setReferenceA4(m.referenceA4 + 0.1)
+	case actionTranspose:
+		// Cycle transposing-instrument presets
+		return m.cycleTransposition()
+	case actionRaiseCapo:
+		// Raise the capo offset
+		return m.setCapoOffset(m.capoOffset + 1)
+	case actionLowerCapo:
+		// Lower the capo offset
+		return m.setCapoOffset(m.capoOffset - 1)
+	case actionSpelling:
+		// Cycle sharps/flats/automatic spelling
+		return m.cycleSpelling()
+	case actionKeySignature:
+		// Cycle the key signature used for automatic spelling
+		return m.cycleKeySignature()
+	case actionNaming:
+		// Cycle letter/solfège note names
+		return m.cycleNaming()
+	case actionNoteNames:
+		// Cycle the sharps/flats/solfège/German presets
+		return m.cycleNoteNames()
+	case actionTemperament:
+		// Cycle temperaments
+		return m.cycleTemperament()
+	case actionTonic:
+		// Move the temperament's tonic up a semitone
+		return m.cycleTonic()
+	case actionNotation:
+		// Cycle scientific/Helmholtz/German notation
+		m.notation = nextNotation(m.notation)
+	case actionOctaveFold:
+		// Toggle ignoring octaves
+		return m.toggleOctaveFold()
+	case actionPause:
+		// Pause or resume detection
+		return m.togglePause()
+	case actionSettings:
+		// Toggle the settings panel
+		m.showSettings = !m.showSettings
+	case actionSettingUp, actionSettingDown:
+		// Select a setting in the open panel
+		if m.showSettings {
+			if action == actionSettingUp {
+				m = m.selectSetting(-1)
+			} else {
+				m = m.selectSetting(1)
+			}
+		}
+	case actionIncrease, actionDecrease:
+		// Adjust the selected setting in the open panel
+		if m.showSettings {
+			if action == actionIncrease {
+				return m.adjustSetting(1)
+			}
+			return m.adjustSetting(-1)
+		}
+	case actionTargetNote:
+		// Type a target note to practice
+		m = m.startInput(inputTargetNote)
+	case actionFilter:
+		// Type a note to show only its plays in the timeline
+		m = m.switchTab(tabTimeline).startInput(inputFilter)
+	case actionMetronome:
+		// Start or stop the metronome
+		return m.toggleMetronome()
+	case actionSlower:
+		// Slow the metronome down
+		return m.setMetronomeBPM(m.metronome.BPM() - metronomeStep)
+	case actionFaster:
+		// Speed the metronome up
+		return m.setMetronomeBPM(m.metronome.BPM() + metronomeStep)
+	case actionMeter:
+		// Cycle the metronome's meter
+		return m.cycleMeter()
+	case actionScale:
+		// Type a scale to practice
+		m = m.startInput(inputScale)
+	case actionScaleStrict:
+		// Switch between strict and lenient scale practice
+		m = m.toggleScaleStrict()
+	case actionStringSet:
+		// Cycle the string tuner's string sets, then turn it off
+		m = m.cycleStringSet()
+	case actionLockString:
+		// Lock the string tuner to a string (1 = highest), or release it,
+		// and pick a tab while it's off
+		digit := int(key[0] - '0')
+		if m.stringSelector == nil {
+			if digit >= 1 && digit <= int(tabCount) {
+				m = m.switchTab(tab(digit - 1))
+			}
+			break
+		}
+		m = m.lockString(digit)
+	}
+
+	return m, nil
+}
+
+// Update handles the model update based on a message
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Drop detections still in flight when detection was paused
+	if m.paused && isDetection(msg) {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// Keys type text until it's confirmed or cancelled
+		if m.typing != inputNone {
+			return m.typeInput(msg)
+		}
+
+		binding, ok := findKeyBinding(msg.String())
+		if !ok {
+			break
+		}
+
+		next, cmd := m.runAction(binding.action, msg.String())
+		return next.savePreferencesLater(cmd)
+
+	case tea.MouseMsg:
+		next, cmd := m.handleMouse(msg)
+		return next.savePreferencesLater(cmd)
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case TimelineExportedMsg:
+		if msg.Err != nil {
+			m = m.withStatus("Export failed: "+msg.Err.Error(), true)
+		} else {
+			m = m.withStatus("Exported the timeline to "+strings.Join(msg.Paths, " and "), false)
+		}
+
+	case savePrefsMsg:
+		// Write the preferences once no change has followed for a while
+		if msg.generation == m.prefsGeneration {
+			return m, savePreferences(m.prefsPath, m.preferences())
+		}
+
+	case PreferencesSavedMsg:
+		if msg.Err != nil {
+			m = m.withStatus("Saving preferences failed: "+msg.Err.Error(), true)
+		}
+
+	case sequenceSentMsg:
+		// The renderer has sent the sequence, so don't send it again
+		if msg.generation == m.sequenceGeneration {
+			m.sequence = ""
+		}
+
+	case CopiedMsg:
+		if msg.Err != nil {
+			m = m.withStatus("Copy failed: "+msg.Err.Error(), true)
+		} else {
+			m = m.withStatus("The terminal can't set the clipboard, so "+msg.What+" went to "+msg.Path, false)
+		}
+
+	case TickMsg:
+		// Take down an expired status message and informational banner
+		if m.status != "" && !time.Time(msg).Before(m.statusUntil) {
+			m.status = ""
+		}
+		m = m.expireBanner(time.Time(msg))
+		m = m.expireClear(time.Time(msg))
+
+		// Clear a released note once its hold is over
+		if !m.releaseUntil.IsZero() && !time.Time(msg).Before(m.releaseUntil) {
+			m.currentNote = nil
+			m.chordNotes = nil
+			m.releaseUntil = time.Time{}
+		}
+
+		// Refresh the key estimate every few seconds
+		if time.Since(m.keyUpdated) >= keyUpdateInterval {
+			m.keyLabel = m.estimateKey()
+			m.keyUpdated = time.Now()
+		}
+		m.tempoLabel = m.estimateTempo()
+		if m.melody != nil {
+			m.melodyLabel = m.describeMelody(time.Time(msg))
+		}
+
+		// Lock a note in tune once its dwell is over, even between readings
+		var bell tea.Cmd
+		if m.currentNote != nil && m.releaseUntil.IsZero() {
+			locked, justLocked := m.inTune.Check(time.Time(msg))
+			m.inTuneLocked = locked
+			m, bell = m.ringBell(justLocked)
+		}
+
+		// Keep the ticker running
+		return m, tea.Batch(bell, tea.Tick(time.Millisecond*100, func(t time.Time) tea.Msg {
+			return TickMsg(t)
+		}))
+
+	case MetronomeBeatMsg:
+		// Light the beat and wait for the next, unless the schedule changed
+		if !m.metronomeOn || msg.generation != m.beatGeneration {
+			break
+		}
+		m.beatIndex = msg.Index
+		m.beatAt = msg.At
+		return m, m.scheduleBeat(msg.At)
+
+	case UpdateNoteMsg:
+		// We have a note, so we're not in silence mode
+		m.isSilence = false
+		note := pitch.Note(msg)
+
+		// Start the cents history and summary over for a new note
+		if m.currentNote == nil || !m.releaseUntil.IsZero() || m.currentNote.MIDINote != note.MIDINote {
+			m.centsHistory.reset()
+			m = m.endHeld(time.Now())
+		}
+
+		// Update current note, cancelling the hold of a released one
+		m.currentNote = &note
+		m.releaseUntil = time.Time{}
+		m.lastUpdate = time.Now()
+		m.centsHistory.add(m.lastUpdate, note.Cents)
+		m.held.add(m.lastUpdate, note.Cents)
+
+		// Record how far off the note was played
+		m.intonation.Add(note)
+		m.drift.Add(note, m.lastUpdate)
+
+		// Compare the pitch with the target melody
+		if m.melody != nil {
+			m.melodyCents, m.melodyLive = m.melody.Add(note.Frequency, m.lastUpdate)
+		}
+
+		// Find the string being tuned
+		if m.stringSelector != nil {
+			m.stringMatch = m.matchString(note.Frequency)
+		}
+
+		// Tell when the note settles in tune
+		locked, justLocked := m.inTune.Add(note, m.lastUpdate)
+		m.inTuneLocked = locked
+		return m.ringBell(justLocked)
+
+	case NoteOnMsg:
+		// Show the leap from the previous note
+		m.interval = nil
+		m.intervalFrom = msg.Previous
+		if msg.Previous != nil {
+			interval := msg.Interval
+			m.interval = &interval
+		}
+
+		// Count every note, even while the timeline is frozen
+		m.session.Add(msg.Note, msg.At)
+
+		// Judge the note against the practice target
+		if m.practice != nil {
+			m = m.judgeTarget(msg.Note, msg.At)
+		}
+
+		// Time the onset against the metronome
+		if m.metronomeOn {
+			m = m.timeOnset(msg.At)
+		}
+
+		// Move the practiced scale on
+		if m.scale != nil {
+			m = m.addScaleNote(msg.Note, msg.At)
+		}
+
+		// Add every new note to the timeline unless it is frozen. A note
+		// reached by a glissando already has its entry.
+		if !m.timelineFrozen && !msg.Glide {
+			// The same note again was played again only after an onset.
+			// Without one it dropped out of detection and still sounds, so
+			// it keeps its entry.
+			var previous *TimelineEntry
+			if last := len(m.timeline) - 1; last >= 0 {
+				previous = &m.timeline[last]
+			}
+			repeat := previous != nil && previous.Note != nil && previous.Chord == "" && m.sameNote(previous.Note, &msg.Note)
+			if repeat && previous.From == nil && !m.onsetAt.After(previous.Timestamp) {
+				previous.Duration = 0
+			} else {
+				m.tempoEstimator.AddOnset(msg.At)
+
+				// Create a copy to store in timeline
+				noteCopy := msg.Note
+
+				// Add to the end of the timeline, marking a note played again
+				entry := TimelineEntry{
+					Note:      &noteCopy,
+					Timestamp: msg.At,
+					Interval:  m.interval,
+					Reattack:  repeat,
+				}
+				m.timeline = appendTimeline(m.timeline, []TimelineEntry{entry}, m.timelineLength)
+			}
+		}
+
+	case OnsetMsg:
+		// Tell a note played again from one that dropped out of detection
+		m.onsetAt = msg.Time
+
+	case NoteOffMsg:
+		// Record how long the most recent note lasted, counting from its
+		// entry's start for a note that dropped out of detection on the way
+		if last := len(m.timeline) - 1; last >= 0 {
+			entry := &m.timeline[last]
+			if entry.Note != nil && entry.Duration == 0 && m.sameNote(entry.Note, &msg.Note) {
+				entry.Duration = msg.Duration
+				if entry.From == nil {
+					entry.Duration = msg.At.Sub(entry.Timestamp)
+				}
+			}
+		}
+
+		// Weigh the finished note into the key estimate
+		if !m.timelineFrozen {
+			m.keyEstimator.AddNote(msg.Note, msg.Duration)
+		}
+
+	case GlissandoMsg:
+		// Leave the slide out of the drift trend, as it was intentional
+		m.drift.Exclude(msg.At.Add(-glissandoMargin), msg.At.Add(msg.Duration+glissandoMargin))
+
+		// Show the slide as a single entry for the note it landed on
+		if !m.timelineFrozen {
+			from, to := msg.From, msg.To
+			m.timeline = appendTimeline(m.timeline, []TimelineEntry{{Note: &to, From: &from, Timestamp: msg.At}}, m.timelineLength)
+		}
+
+	case RestMsg:
+		// Record rests between notes already in the timeline
+		if !m.timelineFrozen && len(m.timeline) > 0 {
+			m.timeline = appendTimeline(m.timeline, []TimelineEntry{{Timestamp: msg.At, Duration: msg.Duration}}, m.timelineLength)
+		}
+
+	case UpdateChordMsg:
+		if len(msg.Notes) == 0 {
+			break
+		}
+
+		// Show the notes side by side, and the strongest one on the gauge
+		m.isSilence = false
+		m = m.setChord(msg.Notes, msg.Symbol, time.Now())
+		strongest := msg.Notes[0]
+		m.currentNote = &strongest
+		m.releaseUntil = time.Time{}
+		m.lastUpdate = time.Now()
+
+	case UpdateHarmonicsMsg:
+		m.harmonics = msg.Harmonics
+		m.hnr = msg.HNR
+
+	case UpdateVibratoMsg:
+		m.vibrato = nil
+		if msg.Detected {
+			vibrato := msg.Vibrato
+			m.vibrato = &vibrato
+		}
+
+	case UpdateSteadinessMsg:
+		m.steadiness = nil
+		if msg.Measured {
+			steadiness := msg.Steadiness
+			m.steadiness = &steadiness
+		}
+
+	case UpdateSpectrumMsg:
+		// Start over when the bands change
+		if msg.Scale != m.spectrogramScale {
+			m.spectrogram = nil
+			m.spectrogramScale = msg.Scale
+		}
+
+		// Mark the band of the note shown at the time
+		column := spectrogramColumn{levels: msg.Levels, fundamental: -1}
+		if m.currentNote != nil {
+			column.fundamental = msg.Scale.Band(m.currentNote.Frequency)
+		}
+		m.spectrogram = append(m.spectrogram, column)
+		if len(m.spectrogram) > maxSpectrogramColumns {
+			m.spectrogram = m.spectrogram[len(m.spectrogram)-maxSpectrogramColumns:]
+		}
+
+	case UpdateWaveformMsg:
+		m.waveform = msg.Samples
+
+	case UpdateAudioLevelMsg:
+		// Update audio levels for display
+		m.audioRMS = msg.RMS
+		m.audioDB = msg.DB
+		m.noiseDB = msg.NoiseDB
+
+		// Hold a new peak, letting the previous one fall once it has held
+		now := time.Now()
+		if m.peakAt.IsZero() || msg.PeakDB >= heldPeak(m.peakDB, now.Sub(m.peakAt)) {
+			m.peakDB = msg.PeakDB
+			m.peakAt = now
+		}
+
+		// Latch the clip indicator for a moment
+		if msg.Clipped {
+			m.clippedAt = now
+		}
+		m.clipped = !m.clippedAt.IsZero() && now.Sub(m.clippedAt) < clipHold
+
+	case RecordingStateMsg:
+		m.recording = msg.Recording
+
+	case UpdateStatsMsg:
+		m.stats = msg
+
+	case UpdateDebugMsg:
+		m.debugInfo = msg
+
+	case ErrorMsg:
+		m = m.showBanner(msg, time.Now())
+
+	case ClearNoteMsg:
+		// Keep the note up, dimmed, for a moment so short notes don't just
+		// flash, and clear everything else at once
+		if m.currentNote != nil && m.noteHold > 0 {
+			if m.releaseUntil.IsZero() {
+				m.releaseUntil = time.Now().Add(m.noteHold)
+			}
+		} else {
+			m.currentNote = nil
+			m.chordNotes = nil
+		}
+		m = m.endChord(time.Now())
+		m = m.endHeld(time.Now())
+		m.harmonics = nil
+		m.hnr = 0
+		m.vibrato = nil
+		m.steadiness = nil
+		m.melodyLive = false
+		m.stringMatch = nil
+		m.inTune.Reset()
+		m.inTuneLocked = false
+		m.isSilence = true
+		m.silenceSince = time.Now()
+	}
+
+	return m, nil
+}
+
+// isAccidental reports whether a pitch class (0 = C) falls between natural notes
+func isAccidental(pitchClass int) bool {
+	return naturalNoteLetters[pitchClass] == ""
+}
+
+// splitAccidental splits a trailing sharp or flat off a note name
+// ("Do#" -> "Do", "#"; German "Fis" -> "F", "is"), leaving names without
+// one whole
+func splitAccidental(name string) (stem, accidental string) {
+	if len(name) > 1 && (strings.HasSuffix(name, "#") || strings.HasSuffix(name, "b")) {
+		return name[:len(name)-1], name[len(name)-1:]
+	}
+
+	// German names: Cis, Des, and the contracted Es and As
+	for _, suffix := range []string{"is", "es", "s"} {
+		if len(name) > len(suffix) && strings.HasSuffix(name, suffix) {
+			return name[:len(name)-len(suffix)], suffix
+		}
+	}
+	return name, ""
+}
+
+// timelineCellWidth returns the width of a timeline entry, widened beyond
+// noteDisplayWidth when long names (e.g., solfège "Sol#4") are on display
+func timelineCellWidth(st styles, entries []TimelineEntry, notation pitch.Notation) int {
+	width := noteDisplayWidth
+	for _, entry := range entries {
+		if entry.Note == nil {
+			continue
+		}
+		if entry.Repeats > 1 {
+			continue // Repeats take several cells
+		}
+		if nameWidth := lipgloss.Width(st.bracket(notation.Format(entry.Note))) + 1; nameWidth > width {
+			width = nameWidth
+		}
+	}
+	return width
+}
+
+// appendTimeline adds entries to the end of a timeline, dropping the oldest
+// ones beyond the given length
+func appendTimeline(timeline, entries []TimelineEntry, length int) []TimelineEntry {
+	timeline = append(timeline, entries...)
+	if len(timeline) > length {
+		timeline = timeline[len(timeline)-length:]
+	}
+	return timeline
+}
+
+// timelineStart returns the index of the oldest entry that fits in a
+// timeline of the given width when the entries before end are shown, newest
+// at the right, each taking as many cells as its length or text needs
+func timelineStart(st styles, entries []TimelineEntry, end, cellWidth, width int, notation pitch.Notation) int {
+	start := end
+	for usedCells := 0; start > 0; start-- {
+		cells := entryCells(st, entries[start-1], cellWidth, notation)
+		if (usedCells+cells)*cellWidth > width {
+			break
+		}
+		usedCells += cells
+	}
+	return start
+}
+
+// maxTimelineScroll returns how far the timeline scrolls back before its
+// oldest entry reaches the left edge
+func (m Model) maxTimelineScroll() int {
+	entries := m.displayedTimeline()
+	cellWidth := timelineCellWidth(m.styles, entries, m.notation)
+	width := m.timelineWidth()
+	end := 0
+	for usedCells := 0; end < len(entries); end++ {
+		cells := entryCells(m.styles, entries[end], cellWidth, m.notation)
+		if (usedCells+cells)*cellWidth > width {
+			break
+		}
+		usedCells += cells
+	}
+	return len(entries) - end
+}
+
+// timelineWindow returns the timeline's entries as shown, the range of them
+// that fits its box, ending with the newest unless scrolled back, and the
+// width of a cell
+func (m Model) timelineWindow() (entries []TimelineEntry, start, end, cellWidth int) {
+	entries = m.displayedTimeline()
+	cellWidth = timelineCellWidth(m.styles, entries, m.notation)
+	end = len(entries) - min(m.timelineScroll, m.maxTimelineScroll())
+	start = timelineStart(m.styles, entries, end, cellWidth, m.timelineWidth(), m.notation)
+	return entries, start, end, cellWidth
+}
+
+// displayedTimeline returns the timeline's entries as shown: all of them, or
+// with repeated notes grouped when that is on, then only those matching the
+// filter when there is one, named in the names in use
+func (m Model) displayedTimeline() []TimelineEntry {
+	entries := m.timeline
+	if m.groupRepeats {
+		entries = groupRepeats(entries)
+	}
+	if m.filter != nil {
+		entries = filterTimeline(entries, m.filter)
+	}
+
+	named := make([]TimelineEntry, len(entries))
+	for i, entry := range entries {
+		entry.Note = m.named(entry.Note)
+		entry.From = m.named(entry.From)
+		named[i] = entry
+	}
+	return named
+}
+
+// groupRepeats merges runs of the same note, in the same octave and each
+// started within repeatGap of the previous one ending, into one entry
+// counting the plays. Rests, glissandi and chords break a run.
+func groupRepeats(entries []TimelineEntry) []TimelineEntry {
+	grouped := make([]TimelineEntry, 0, len(entries))
+	var end time.Time // When the last grouped play ended
+	for _, entry := range entries {
+		if last := len(grouped) - 1; last >= 0 && entry.Note != nil && entry.From == nil && entry.Chord == "" {
+			run := &grouped[last]
+			if run.Note != nil && run.From == nil && run.Chord == "" &&
+				run.Note.PitchClass == entry.Note.PitchClass && run.Note.Octave == entry.Note.Octave &&
+				entry.Timestamp.Sub(end) <= repeatGap {
+				run.Repeats = max(run.Repeats, 1) + 1
+				end = entry.Timestamp.Add(entry.Duration)
+				continue
+			}
+		}
+		grouped = append(grouped, entry)
+		end = entry.Timestamp.Add(entry.Duration)
+	}
+	return grouped
+}
+
+// renderTimelineNote renders a compact note representation for the timeline,
+// with the number of plays for grouped repeats
+func renderTimelineNote(st styles, note *pitch.Note, repeats int, width int, notation pitch.Notation) string {
+	if note == nil {
+		return strings.Repeat(" ", width)
+	}
+
+	// Create a compact representation of the note (e.g., "C4", "D#4", "Fis4",
+	// "C4×5")
+	noteText := notation.Format(note)
+	if repeats > 1 {
+		noteText += fmt.Sprintf("×%d", repeats)
+	}
+	noteText = st.bracket(noteText)
+
+	// Color the note by its pitch class, shaded for its octave
+	noteColor := st.octaveColor(note)
+	timelineNoteStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(noteColor)).
+		Foreground(lipgloss.Color(st.textOn(note))).
+		Width(width).
+		Align(lipgloss.Center)
+
+	return timelineNoteStyle.Render(noteText)
+}
+
+// restCells returns how many timeline cells a rest takes, roughly in
+// proportion to its length
+func restCells(duration time.Duration) int {
+	cells := int((duration + restCellDuration/2) / restCellDuration)
+	return max(1, min(cells, maxRestCells))
+}
+
+// noteCells returns how many timeline cells an ended note or chord takes, in
+// proportion to its length, or one while it's still sounding
+func noteCells(duration time.Duration) int {
+	cells := int((duration + noteCellDuration/2) / noteCellDuration)
+	return max(1, min(cells, maxNoteCells))
+}
+
+// entryCells returns how many timeline cells of the given width an entry
+// takes: for notes and chords as many as their length, and for rests,
+// glissandi and grouped repeats as many as they need. A filter's gap marker
+// takes one.
+func entryCells(st styles, entry TimelineEntry, width int, notation pitch.Notation) int {
+	switch {
+	case entry.elided > 0:
+		return 1
+	case entry.Note == nil:
+		return restCells(entry.Duration)
+	case entry.Chord != "":
+		textWidth := lipgloss.Width(st.bracket(entry.Chord)) + 2
+		return max((textWidth+width-1)/width, noteCells(entry.Duration))
+	case entry.From != nil:
+		textWidth := lipgloss.Width(st.bracket(glissandoText(entry, notation))) + 2
+		return (textWidth + width - 1) / width
+	case entry.Repeats > 1:
+		textWidth := lipgloss.Width(st.bracket(fmt.Sprintf("%s×%d", notation.Format(entry.Note), entry.Repeats))) + 1
+		return (textWidth + width - 1) / width
+	default:
+		return noteCells(entry.Duration)
+	}
+}
+
+// glissandoText names a glissando entry's notes, e.g. "G3→B3"
+func glissandoText(entry TimelineEntry, notation pitch.Notation) string {
+	return notation.Format(entry.From) + "→" + notation.Format(entry.Note)
+}
+
+// renderTimelineEntry renders a note, a dim gray block for a rest, a
+// connector block from the start to the end note for a glissando, the
+// symbol of a chord, or a dot where a filter left entries out. A note played
+// again straight after itself is marked at its left edge.
+func renderTimelineEntry(st styles, entry TimelineEntry, width int, notation pitch.Notation) string {
+	switch {
+	case entry.elided > 0:
+		return st.debug.Width(width).Align(lipgloss.Center).Render("·")
+	case entry.Note == nil && st.plain:
+		// Without its color a rest would be blank
+		return st.rest.Render(strings.Repeat(".", restCells(entry.Duration)*width))
+	case entry.Note == nil:
+		return st.rest.Render(strings.Repeat(" ", restCells(entry.Duration)*width))
+	case entry.Chord != "":
+		return st.chord.Width(entryCells(st, entry, width, notation) * width).Align(lipgloss.Center).Render(st.bracket(entry.Chord))
+	case entry.From != nil && st.plain:
+		return lipgloss.NewStyle().Width(entryCells(st, entry, width, notation) * width).Render(" " + st.bracket(glissandoText(entry, notation)))
+	case entry.From != nil:
+		// Start in the color of the first note and end in that of the last
+		total := entryCells(st, entry, width, notation) * width
+		from := lipgloss.NewStyle().
+			Background(lipgloss.Color(st.octaveColor(entry.From))).
+			Foreground(lipgloss.Color(st.textOn(entry.From))).
+			Render(" " + notation.Format(entry.From) + "→")
+		to := lipgloss.NewStyle().
+			Background(lipgloss.Color(st.octaveColor(entry.Note))).
+			Foreground(lipgloss.Color(st.textOn(entry.Note))).
+			Width(total - lipgloss.Width(from)).
+			Render(notation.Format(entry.Note))
+		return from + to
+	case entry.Reattack:
+		// Mark the attack with a thin bar at the left edge
+		marker := lipgloss.NewStyle().
+			Background(lipgloss.Color(st.octaveColor(entry.Note))).
+			Foreground(lipgloss.Color(st.textOn(entry.Note))).
+			Render("▏")
+		return marker + renderTimelineNote(st, entry.Note, entry.Repeats, entryCells(st, entry, width, notation)*width-1, notation)
+	default:
+		return renderTimelineNote(st, entry.Note, entry.Repeats, entryCells(st, entry, width, notation)*width, notation)
+	}
+}
+
+// renderTimelineTimes renders the row under the timeline's blocks, marking
+// how long ago every tenth entry started (e.g. "-12s") at the entry's first
+// column. Markers that would overlap the previous one or run past the given
+// width are left out.
+func renderTimelineTimes(st styles, entries []TimelineEntry, cellWidth int, notation pitch.Notation, width int, now time.Time) string {
+	row := []byte(strings.Repeat(" ", width))
+	column, free := 0, 0
+	for i, entry := range entries {
+		if (len(entries)-1-i)%timeMarkerEvery == 0 {
+			marker := formatAgo(now.Sub(entry.Timestamp))
+			if column >= free && column+len(marker) <= width {
+				copy(row[column:], marker)
+				free = column + len(marker) + 1
+			}
+		}
+		column += entryCells(st, entry, cellWidth, notation) * cellWidth
+	}
+	return strings.TrimRight(string(row), " ")
+}
+
+// formatAgo writes a time in the past compactly, e.g. "-5s" or "-3m"
+func formatAgo(ago time.Duration) string {
+	if ago < time.Minute {
+		return fmt.Sprintf("-%ds", int(max(0, ago.Seconds())))
+	}
+	return fmt.Sprintf("-%dm", int(ago.Minutes()))
+}
+
+// gaugeCells returns the cells the cents gauge spans at a terminal width,
+// zero for unknown, leaving room for labels of the given width at either end
+// and keeping a center cell
+func gaugeCells(terminalWidth, labelWidth int) int {
+	if terminalWidth <= 0 {
+		return gaugeWidth
+	}
+	width := max(minGaugeWidth, min(terminalWidth-2*(labelWidth+1), maxGaugeWidth))
+	if width%2 == 0 {
+		width--
+	}
+	return width
+}
+
+// renderGauge renders a tuner needle for a cents offset: a bar spanning
+// ±50 cents with a center tick and a marker colored by how far off the note
+// is, between the names of the notes a semitone below and above, e.g.
+// "C4 ──────────────────┼──●────────────────── D4". It fits the given
+// terminal width, zero for unknown.
+func renderGauge(st styles, cents float64, terminalWidth int, below, above string) string {
+	labelWidth := gaugeLabelWidth(below, above)
+	width := gaugeCells(terminalWidth, labelWidth)
+
+	// Offsets beyond the range pin the marker to the end
+	clamped := math.Max(-gaugeRange, math.Min(cents, gaugeRange))
+	marker := int(math.Round((clamped + gaugeRange) / (2 * gaugeRange) * float64(width-1)))
+	center := width / 2
+
+	markerStyle := gaugeOffStyle
+	switch {
+	case math.Abs(cents) <= 5:
+		markerStyle = gaugeInTuneStyle
+	case math.Abs(cents) <= 15:
+		markerStyle = gaugeCloseStyle
+	}
+
+	var bar strings.Builder
+	for i := 0; i < width; i++ {
+		switch {
+		case i == marker:
+			bar.WriteString(markerStyle.Render("●"))
+		case i == center:
+			bar.WriteString(st.info.Render("┼"))
+		default:
+			bar.WriteString(st.debug.Render("─"))
+		}
+	}
+	return st.info.Width(labelWidth).Align(lipgloss.Right).Render(below) + " " + bar.String() + " " + st.info.Render(above)
+}
+
+// gaugeLabelWidth returns the width of the labels at the ends of the cents
+// gauge, the longer of the two
+func gaugeLabelWidth(below, above string) int {
+	return max(lipgloss.Width(below), lipgloss.Width(above))
+}
+
+// renderKeyboard renders a piano keyboard of up to three octaves around the
+// note's octave, with the note's key in its color: white keys along the
+// bottom row, labeled at each C, and black keys between them in the top
+// row. Fewer octaves are shown when the given terminal width (zero for
+// unknown) can't fit them. A nil note shows the keyboard around middle C
+// with nothing highlighted.
+func renderKeyboard(st styles, note *pitch.Note, width int) string {
+	octaves := maxKeyboardOctaves
+	for octaves > 1 && width > 0 && octaves*7*keyWidth > width {
+		octaves--
+	}
+
+	// Center the octaves on the note, leaning towards the half of its
+	// octave it lies in
+	octave := 4
+	if note != nil {
+		octave = note.Octave
+	}
+	lowest := octave - octaves/2
+	if octaves == 2 && note != nil && note.PitchClass >= 6 {
+		lowest = octave
+	}
+
+	highlight := lipgloss.NewStyle()
+	if note != nil {
+		highlight = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(st.theme.NoteText)).
+			Background(lipgloss.Color(st.noteColor(note)))
+	}
+	active := func(pitchClass, keyOctave int) bool {
+		return note != nil && note.PitchClass == pitchClass && note.Octave == keyOctave
+	}
+
+	// Pitch classes of the white keys, left to right
+	whites := []int{0, 2, 4, 5, 7, 9, 11}
+	var top, bottom strings.Builder
+	for o := lowest; o < lowest+octaves; o++ {
+		for _, pitchClass := range whites {
+			white := whiteKeyStyle
+			if active(pitchClass, o) {
+				white = highlight
+			}
+			label := ""
+			if pitchClass == 0 {
+				label = fmt.Sprintf("C%d", o)
+			}
+			bottom.WriteString(white.Render(fmt.Sprintf("%-*s", keyWidth-1, label) + "│"))
+
+			// The top row shows the black keys straddling the white keys'
+			// edges: the one below this key takes its first column and the
+			// one above its last two. C and F have none below, E and B none
+			// above.
+			first := white.Render(" ")
+			if below := pitchClass - 1; below >= 0 && isAccidental(below) {
+				first = blackKeyStyle.Render(" ")
+				if active(below, o) {
+					first = highlight.Render(" ")
+				}
+			}
+			middle := white.Render(strings.Repeat(" ", keyWidth-3))
+			last := white.Render(" │")
+			if above := pitchClass + 1; isAccidental(above % 12) {
+				last = blackKeyStyle.Render("  ")
+				if active(above, o) {
+					last = highlight.Render("  ")
+				}
+			}
+			top.WriteString(first + middle + last)
+		}
+	}
+	return top.String() + "\n" + bottom.String()
+}
+
+// renderFretboard renders the neck of a stringed instrument in the given
+// tuning, highest string on top, marking every position up to the given fret
+// that plays the note: brightly where it sounds in the same octave and dimly
+// in other octaves, or brightly everywhere when octaves are folded. Fewer
+// frets are shown when the given terminal width (zero for unknown) can't fit
+// them. A nil note marks nothing.
+func renderFretboard(st styles, note *pitch.Note, set pitch.StringSet, frets int, fold bool, width int, notation pitch.Notation) string {
+	// Each string starts with its open note, then the open position and nut
+	labelWidth := 0
+	for i := range set.Strings {
+		labelWidth = max(labelWidth, lipgloss.Width(notation.Format(&set.Strings[i])))
+	}
+	if width > 0 {
+		frets = max(1, min(frets, (width-labelWidth-4)/fretWidth))
+	}
+
+	bright := lipgloss.NewStyle().Bold(true)
+	if note != nil {
+		bright = bright.Foreground(lipgloss.Color(st.noteColor(note)))
+	}
+	marker := func(midiNote int) string {
+		switch {
+		case note == nil || (midiNote-note.MIDINote)%12 != 0:
+			return ""
+		case fold || midiNote == note.MIDINote:
+			return bright.Render("●")
+		default:
+			return st.debug.Render("○")
+		}
+	}
+
+	var lines []string
+	for i := len(set.Strings) - 1; i >= 0; i-- {
+		open := set.Strings[i]
+		line := st.info.Render(fmt.Sprintf("%-*s ", labelWidth, notation.Format(&open)))
+
+		// Open string, then the nut
+		if mark := marker(open.MIDINote); mark != "" {
+			line += mark
+		} else {
+			line += st.debug.Render("─")
+		}
+		line += st.info.Render("║")
+
+		for fret := 1; fret <= frets; fret++ {
+			cell := st.debug.Render(strings.Repeat("─", fretWidth-1))
+			if mark := marker(open.MIDINote + fret); mark != "" {
+				side := st.debug.Render(strings.Repeat("─", (fretWidth-2)/2))
+				cell = side + mark + st.debug.Render(strings.Repeat("─", fretWidth-2-(fretWidth-2)/2))
+			}
+			line += cell + st.debug.Render("┼")
+		}
+		lines = append(lines, line)
+	}
+
+	// Number the frets with inlays
+	numbers := strings.Repeat(" ", labelWidth+3)
+	for fret := 1; fret <= frets; fret++ {
+		label := ""
+		switch fret % 12 {
+		case 0, 3, 5, 7, 9:
+			label = fmt.Sprint(fret)
+		}
+		numbers += fmt.Sprintf("%*s ", (fretWidth+len(label))/2, label) + strings.Repeat(" ", fretWidth-1-(fretWidth+len(label))/2)
+	}
+	lines = append(lines, st.debug.Render(numbers))
+	return strings.Join(lines, "\n")
+}
+
+// heldPeak returns the level the peak tick shows some time after reaching a
+// peak: the peak itself for a moment, then falling steadily
+func heldPeak(peak float32, since time.Duration) float32 {
+	if since <= peakHold {
+		return peak
+	}
+	return peak - float32((since-peakHold).Seconds()*peakDecay)
+}
+
+// renderLevelMeter renders the input level as a bar from -60 to 0 dB in
+// green, yellow and red zones, with a tick at the held peak and a clip
+// indicator
+func renderLevelMeter(st styles, db, peak float32, clipped bool) string {
+	// Segment holding a level, -1 below the floor
+	segment := func(level float32) int {
+		if level < levelMeterFloor {
+			return -1
+		}
+		return min(levelMeterWidth-1, int((float64(level)-levelMeterFloor)/-levelMeterFloor*levelMeterWidth))
+	}
+	lit, tick := segment(db), segment(peak)
+
+	var bar strings.Builder
+	for i := 0; i < levelMeterWidth; i++ {
+		// Level at the segment's lower edge picks its zone
+		level := levelMeterFloor - levelMeterFloor*float64(i)/levelMeterWidth
+		style := levelLowStyle
+		switch {
+		case level >= levelMeterRed:
+			style = levelHighStyle
+		case level >= levelMeterYellow:
+			style = levelMidStyle
+		}
+
+		switch {
+		case i <= lit:
+			bar.WriteString(style.Render("█"))
+		case i == tick:
+			bar.WriteString(style.Render("│"))
+		default:
+			bar.WriteString(levelOffStyle.Render("·"))
+		}
+	}
+
+	label := fmt.Sprintf(" %4.0f dB ", math.Max(float64(db), levelMeterFloor))
+	if db < levelMeterFloor {
+		label = "  -∞ dB "
+	}
+	indicator := levelOffStyle.Render("CLIP")
+	if clipped {
+		indicator = clipStyle.Render("CLIP")
+	}
+	return st.info.Render("Level ") + bar.String() + st.info.Render(label) + indicator
+}
 
-	infoStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#CCCCCC"))
+// renderSpectrogram renders recent spectra as a scrolling spectrogram,
+// newest on the right and high frequencies on top, two bands per line drawn
+// with half blocks. Louder bands are brighter, and each frame marks the band
+// of the note shown at the time. It fits the given terminal width, zero for
+// unknown.
+func renderSpectrogram(st styles, columns []spectrogramColumn, scale pitch.SpectrogramScale, width int) string {
+	spectrogramCellsOnce.Do(buildSpectrogramCells)
+
+	const labelWidth = 6
+	if width > 0 && width-labelWidth < len(columns) {
+		columns = columns[len(columns)-max(0, width-labelWidth):]
+	}
 
-	debugStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888"))
+	// Shade of a band in a frame, the marker being the last
+	shade := func(column spectrogramColumn, band int) int {
+		if band < 0 || band >= len(column.levels) {
+			return 0
+		}
+		if band == column.fundamental {
+			return len(spectrogramShades)
+		}
+		level := (column.levels[band] - spectrogramFloor) / -spectrogramFloor
+		return max(0, min(int(level*float64(len(spectrogramShades))), len(spectrogramShades)-1))
+	}
 
-	noSoundStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FAFAFA")).
-			Background(lipgloss.Color("#888888")).
-			Bold(true).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#333333")).
-			Padding(2, 4).
-			MarginBottom(1)
-
-	timelineStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#666666")).
-			Padding(0, 1).
-			MarginTop(1).
-			Width(timelineWidth + 4) // Add padding for borders
-
-	timelineLabelStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#CCCCCC"))
-
-	buttonStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(lipgloss.Color("#555555")).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#999999")).
-			Padding(0, 2).
-			MarginLeft(2).
-			Bold(true)
-
-	clearButtonStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#FFFFFF")).
-				Background(lipgloss.Color("#AA3333")). // Red background for clear button
-				BorderStyle(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("#662222")).
-				Padding(0, 2).
-				MarginLeft(2).
-				Bold(true)
-
-	// Standard box size
-	boxWidth = 8
-
-	// Note colors (moderate, not too bright, not too pastel)
-	noteColors = map[string]string{
-		"C": "#e5cf9e", // Moderate Beige
-		"D": "#663e7d", // Medium Purple
-		"E": "#e3a53e", // Moderate Yellow
-		"F": "#c4563f", // Moderate Orange-Red
-		"G": "#43873c", // Moderate Green
-		"A": "#b64040", // Moderate Red
-		"B": "#2a7bba", // Moderate Blue
+	var lines []string
+	for upper := scale.Bands - 1; upper >= 0; upper -= 2 {
+		lower := upper - 1
+
+		// Label every fourth line with its lowest frequency
+		label := strings.Repeat(" ", labelWidth)
+		if (scale.Bands-1-upper)%8 == 6 || lower <= 0 {
+			label = fmt.Sprintf("%*s ", labelWidth-1, formatHz(scale.Frequency(max(lower, 0))))
+		}
+
+		var line strings.Builder
+		line.WriteString(st.debug.Render(label))
+		for _, column := range columns {
+			line.WriteString(spectrogramCells[shade(column, upper)][shade(column, lower)])
+		}
+		lines = append(lines, line.String())
 	}
-)
+	return strings.Join(lines, "\n")
+}
 
-// TimelineEntry represents a note in the timeline with timestamp
-type TimelineEntry struct {
-	Note      *pitch.Note
-	Timestamp time.Time
+// buildSpectrogramCells renders every combination of upper and lower shade
+// once, as styling each cell on every frame is too slow
+func buildSpectrogramCells() {
+	colors := append(append([]string{}, spectrogramShades...), spectrogramMarker)
+	spectrogramCells = make([][]string, len(colors))
+	for upper, upperColor := range colors {
+		spectrogramCells[upper] = make([]string, len(colors))
+		for lower, lowerColor := range colors {
+			spectrogramCells[upper][lower] = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(upperColor)).
+				Background(lipgloss.Color(lowerColor)).
+				Render("▀")
+		}
+	}
 }
 
-// Returns a style for a note
-func getNoteStyle(noteName string) lipgloss.Style {
-	if strings.HasSuffix(noteName, "#") {
-		// For sharp notes, we handle the rendering separately in View()
-		// Just return a basic style
-		return lipgloss.NewStyle().Bold(true).MarginBottom(1)
-	} else {
-		// For natural notes, use a single color
-		return lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FAFAFA")).
-			Background(lipgloss.Color(noteColors[noteName])).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#333333")).
-			Padding(2, 4).
-			MarginBottom(1)
+// formatHz writes a frequency compactly, e.g. "440" or "1.2k"
+func formatHz(hz float64) string {
+	if hz >= 1000 {
+		return fmt.Sprintf("%.1fk", hz/1000)
 	}
+	return fmt.Sprintf("%.0f", hz)
 }
 
-// Model represents the UI state
-type Model struct {
-	currentNote    *pitch.Note
-	timeline       []TimelineEntry // Timeline of recent notes
-	lastUpdate     time.Time
-	width          int
-	height         int
-	isSilence      bool      // Whether we're currently detecting silence
-	silenceSince   time.Time // When we first detected silence
-	audioRMS       float32   // Current RMS level
-	audioDB        float32   // Current dB level
-	showDebug      bool      // Whether to show debug info
-	timelineFrozen bool      // Whether the timeline is frozen/paused
-}
-
-// NewModel creates a new UI model
-func NewModel() Model {
-	return Model{
-		currentNote:    nil,
-		timeline:       make([]TimelineEntry, 0, maxTimelineEntries),
-		lastUpdate:     time.Now(),
-		isSilence:      true,
-		silenceSince:   time.Now(),
-		showDebug:      true, // Default to showing debug info
-		timelineFrozen: false,
+// renderConfidence renders how trustworthy a detection is as dots and a
+// percentage, e.g. "Confidence ●●●●○ 82%", colored green when reliable,
+// yellow when marginal and red when the note box is dimmed
+func renderConfidence(st styles, confidence float64) string {
+	filled := max(0, min(int(math.Round(confidence*confidenceDots)), confidenceDots))
+	style := gaugeOffStyle
+	switch {
+	case confidence >= highConfidence:
+		style = gaugeInTuneStyle
+	case confidence >= marginalConfidence:
+		style = gaugeCloseStyle
 	}
+	dots := style.Render(strings.Repeat("●", filled)) + st.debug.Render(strings.Repeat("○", confidenceDots-filled))
+	return st.info.Render("Confidence ") + dots + st.info.Render(fmt.Sprintf(" %.0f%%", confidence*100))
 }
 
-// Init initializes the UI model
-func (m Model) Init() tea.Cmd {
-	return tea.Tick(time.Millisecond*100, func(t time.Time) tea.Msg {
-		return TickMsg(t)
-	})
+// renderSteadiness renders a steadiness score as a bar with the score and
+// drift, e.g. "Steadiness: ███████████████░░░░░ 76 | Drift: +1.3¢/s"
+func renderSteadiness(st styles, steadiness *pitch.Steadiness) string {
+	filled := int(math.Round(steadiness.Score / 100 * steadinessBarWidth))
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", steadinessBarWidth-filled)
+	return st.info.Render(fmt.Sprintf("Steadiness: %s %.0f | Drift: %+.1f¢/s", bar, steadiness.Score, steadiness.Drift))
 }
 
-// TickMsg represents a timer tick
-type TickMsg time.Time
+// capoLabel describes a nonzero capo offset, e.g. " | Capo: -3"
+func (m Model) capoLabel() string {
+	if m.capoOffset == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" | Capo: %+d", m.capoOffset)
+}
 
-// UpdateNoteMsg is a message to update the current note
-type UpdateNoteMsg pitch.Note
+// spellingLabel describes the spelling mode, including the key for automatic spelling
+func (m Model) spellingLabel() string {
+	if m.spelling == pitch.SpellingAuto {
+		return fmt.Sprintf("%s (key of %s)", m.spelling, pitch.KeySignatureName(m.keySignature))
+	}
+	return m.spelling.String()
+}
 
-// UpdateAudioLevelMsg is a message to update the audio level display
-type UpdateAudioLevelMsg struct {
-	RMS float32
-	DB  float32
+// estimateKey describes the key estimated from the notes played so far, e.g.
+// "G major (78%)", or returns "" when there is no estimate
+func (m Model) estimateKey() string {
+	key, mode, confidence := m.keyEstimator.Estimate()
+	if key == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s %s (%.0f%%)", key, mode, confidence*100)
 }
 
-// ClearNoteMsg is sent when we should clear the note display (no sound detected)
-type ClearNoteMsg struct{}
+// sameNote reports whether two notes are the same, ignoring the octave when
+// octaves are folded
+func (m Model) sameNote(a, b *pitch.Note) bool {
+	if m.octaveFold {
+		return a.ConcertPitchClass == b.ConcertPitchClass
+	}
+	return a.MIDINote == b.MIDINote
+}
 
-// Update handles the model update based on a message
-func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c":
-			return m, tea.Quit
-		case "d":
-			// Toggle debug display
-			m.showDebug = !m.showDebug
-		case "f", "space":
-			// Toggle timeline freeze
-			m.timelineFrozen = !m.timelineFrozen
-		case "c":
-			// Clear timeline history
-			m.timeline = make([]TimelineEntry, 0, maxTimelineEntries)
-		}
+// noteLabel writes a note in the current names and notation, leaving out
+// the octave when octaves are folded
+func (m Model) noteLabel(note *pitch.Note) string {
+	note = m.named(note)
+	if m.octaveFold {
+		return m.notation.Name(note)
+	}
+	return m.notation.Format(note)
+}
 
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
+// estimateTempo describes the tempo estimated from the note onsets so far,
+// e.g. "≈ 96 BPM", or returns "" when there is no trustworthy estimate
+func (m Model) estimateTempo() string {
+	bpm, confidence := m.tempoEstimator.Estimate()
+	if confidence < minTempoConfidence {
+		return ""
+	}
+	return fmt.Sprintf("≈ %.0f BPM", bpm)
+}
 
-	case TickMsg:
-		// Just keep the ticker running
-		return m, tea.Tick(time.Millisecond*100, func(t time.Time) tea.Msg {
-			return TickMsg(t)
-		})
+// renderSession summarizes the notes played over the session, with a
+// histogram of their pitch classes scaled to the most played one
+func (m Model) renderSession() string {
+	summary := m.session.Summary()
+	if summary.Notes == 0 {
+		return m.styles.info.Render("Session: no notes yet")
+	}
 
-	case UpdateNoteMsg:
-		// We have a note, so we're not in silence mode
-		m.isSilence = false
-		note := pitch.Note(msg)
+	lines := []string{fmt.Sprintf("Session: %d notes, %d distinct pitches, most played %s (%d×), %s",
+		summary.Notes, summary.Distinct, m.notation.Format(&summary.MostPlayed), summary.MostPlayedPlays,
+		summary.Duration.Round(time.Second))}
 
-		// Check if the current note is different from the last note
-		addToTimeline := true
-		if m.currentNote != nil && note.Name == m.currentNote.Name && note.Octave == m.currentNote.Octave {
-			// Same note as current, don't add to timeline
-			addToTimeline = false
+	widest := 0
+	for _, plays := range summary.PitchClasses {
+		widest = max(widest, plays)
+	}
+	for pitchClass, plays := range summary.PitchClasses {
+		bar := strings.Repeat("█", (plays*histogramWidth+widest-1)/widest)
+		lines = append(lines, fmt.Sprintf("  %-2s %s %d", pitch.PitchClassName(pitchClass), bar, plays))
+	}
+	return m.styles.info.Render(strings.Join(lines, "\n"))
+}
+
+// renderIntonation renders a table of the notes played furthest off on
+// average, e.g. "E4: 132 samples, -8.3¢ avg"
+func (m Model) renderIntonation() string {
+	report := m.intonation.Report()
+	if len(report) == 0 {
+		return m.styles.info.Render("Intonation: no notes yet")
+	}
+
+	lines := []string{"Intonation (furthest off first):"}
+	for i, summary := range report {
+		if i == maxIntonationRows {
+			break
 		}
+		lines = append(lines, fmt.Sprintf("  %s: %d samples, %+.1f¢ avg, ±%.1f¢ spread, worst %+.0f¢",
+			m.notation.Format(&summary.Note), summary.Count, summary.Mean, summary.StdDev, summary.Worst))
+	}
+	return m.styles.info.Render(strings.Join(lines, "\n"))
+}
 
-		// Update current note
-		m.currentNote = &note
+// renderDrift describes the trend of the cents deviations over the recent
+// minutes with a sparkline of the minute means, e.g.
+// "Drift: +6¢ over last 10 min ▁▂▂▃▄▄▅▆▇█"
+func (m Model) renderDrift() string {
+	trend, ok := m.drift.Trend()
+	if !ok {
+		return m.styles.info.Render("Drift: needs two minutes of playing")
+	}
 
-		// Add to timeline if it's a new note and timeline is not frozen
-		if addToTimeline && !m.timelineFrozen {
-			// Create a copy to store in timeline
-			noteCopy := note
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, mean := range trend.Means {
+		if !math.IsNaN(mean) {
+			low, high = math.Min(low, mean), math.Max(high, mean)
+		}
+	}
+	levels := []rune("▁▂▃▄▅▆▇█")
+	var spark strings.Builder
+	for _, mean := range trend.Means {
+		switch {
+		case math.IsNaN(mean):
+			spark.WriteRune(' ')
+		case high == low:
+			spark.WriteRune(levels[len(levels)/2])
+		default:
+			spark.WriteRune(levels[int((mean-low)/(high-low)*float64(len(levels)-1)+0.5)])
+		}
+	}
+	return m.styles.info.Render(fmt.Sprintf("Drift: %+.0f¢ over last %.0f min %s", trend.Cents, trend.Span.Minutes(), spark.String()))
+}
 
-			// Add to the end of the timeline
-			entry := TimelineEntry{
-				Note:      &noteCopy,
-				Timestamp: time.Now(),
-			}
-			m.timeline = append(m.timeline, entry)
+// scaleBaseLabel describes the 1/1 of a loaded scale: its frequency, or the
+// tonic it is tuned to
+func (m Model) scaleBaseLabel() string {
+	if m.temperament.Reference > 0 {
+		return fmt.Sprintf("1/1 = %.2f Hz", m.temperament.Reference)
+	}
+	return pitch.PitchClassName(m.tonic)
+}
 
-			// Trim timeline if it gets too long
-			if len(m.timeline) > maxTimelineEntries {
-				m.timeline = m.timeline[len(m.timeline)-maxTimelineEntries:]
-			}
+// describeMelody describes the target melody's progress at the given time:
+// the target note with the live deviation from it and the running score
+func (m Model) describeMelody(at time.Time) string {
+	target := m.melody.Target()
+	index, ok := m.melody.Current(at)
+	switch {
+	case m.melody.Done(at):
+		return fmt.Sprintf("Melody %s finished: %.0f%% in tune (press r to sing it again)",
+			target.Name, m.melody.Score(at)*100)
+	case !ok:
+		return fmt.Sprintf("Melody %s: %d notes (press r to start)", target.Name, len(target.Notes))
+	}
+
+	note := target.Notes[index].Note
+	label := fmt.Sprintf("Melody %s: rest", target.Name)
+	if note != nil {
+		label = fmt.Sprintf("Melody %s: sing %s", target.Name, m.notation.Format(note))
+		if m.melodyLive {
+			label += fmt.Sprintf(" | You: %+.0f¢", m.melodyCents)
 		}
+	}
+	return label + fmt.Sprintf(" | Note %d of %d | Score: %.0f%%", index+1, len(target.Notes), m.melody.Score(at)*100)
+}
 
-		m.lastUpdate = time.Now()
+// renderStrings renders the string tuner: the strings of the tuning with the
+// one being tuned highlighted, and how far the note is from it, e.g.
+// "Strings: E2 A2 D3 [G3] B3 E4 | String 3 (G3): -12.4¢ flat, tune up"
+func (m Model) renderStrings() string {
+	set := m.stringSelector.StringSet()
+	names := make([]string, len(set.Strings))
+	for i := range set.Strings {
+		name := m.notation.Format(&set.Strings[i])
+		if m.stringMatch != nil && m.stringMatch.Number == len(set.Strings)-i {
+			names[i] = activeStringStyle.Render(" " + name + " ")
+			continue
+		}
+		names[i] = m.styles.info.Render(" " + name + " ")
+	}
+	line := m.styles.info.Render("Strings:") + strings.Join(names, "")
+
+	match := m.stringMatch
+	switch {
+	case match == nil && m.currentNote != nil:
+		return line + m.styles.info.Render(" | No string nearby (press 1-9 to pick one)")
+	case match == nil:
+		return line
+	}
 
-	case UpdateAudioLevelMsg:
-		// Update audio levels for display
-		m.audioRMS = msg.RMS
-		m.audioDB = msg.DB
+	label := fmt.Sprintf("String %d (%s)", match.Number, m.notation.Format(&match.String))
+	if match.Locked {
+		label = fmt.Sprintf("String %d (%s, locked)", match.Number, m.notation.Format(&match.String))
+	}
+	direction := "in tune"
+	switch {
+	case match.Cents < -stringInTuneCents:
+		direction = "flat, tune up"
+	case match.Cents > stringInTuneCents:
+		direction = "sharp, tune down"
+	}
+	return line + m.styles.info.Render(fmt.Sprintf(" | %s: %+.1f¢ %s", label, match.Cents, direction))
+}
 
-	case ClearNoteMsg:
-		// Immediately clear the note display - no delay
-		m.currentNote = nil
-		m.isSilence = true
-		m.silenceSince = time.Now()
+// namingLabel describes the naming scheme, including the tonic for movable do
+func (m Model) namingLabel() string {
+	if m.naming == pitch.NamingMovableDo {
+		return fmt.Sprintf("%s (Do = %s)", m.naming, pitch.KeySignatureName(m.keySignature))
 	}
+	return m.naming.String()
+}
 
-	return m, nil
+// View renders the UI, led by any terminal sequence waiting to be sent
+func (m Model) View() string {
+	if m.styles.plain {
+		return m.sequence + plainText(m.view())
+	}
+	return m.sequence + m.view()
 }
 
-// getNextNote returns the next note in the scale (C -> D, D -> E, etc.)
-func getNextNote(note string) string {
-	noteOrder := []string{"C", "D", "E", "F", "G", "A", "B"}
-	for i, n := range noteOrder {
-		if n == note {
-			if i < len(noteOrder)-1 {
-				return noteOrder[i+1]
-			}
-			return noteOrder[0] // Wrap around to C if B
+// view renders the UI in color
+func (m Model) view() string {
+	if m.width > 0 && (m.width < minWidth || m.height < minHeight) {
+		return lipgloss.NewStyle().Width(m.width).Render(
+			fmt.Sprintf("Terminal too small (%d×%d): TuneNote needs at least %d×%d", m.width, m.height, minWidth, minHeight))
+	}
+
+	// Leave out optional panes until everything fits the terminal's height
+	s := m.layout()
+	for _, pane := range optionalPanes {
+		if m.height == 0 || lipgloss.Height(s) <= m.height {
+			break
+		}
+		if pane.hide(&m) {
+			m.hiddenPanes = append(m.hiddenPanes, pane.name)
+			s = m.layout()
 		}
 	}
-	return note // Fallback
+
+	// Cut off what still doesn't fit, such as a long Stats tab
+	if lines := strings.Split(s, "\n"); m.height > 0 && len(lines) > m.height {
+		s = strings.Join(lines[:m.height], "\n")
+	}
+
+	// Center the main column on wide terminals
+	if m.width > maxColumnWidth {
+		s = lipgloss.PlaceHorizontal(m.width, lipgloss.Center, s)
+	}
+	return s
 }
 
-// getNoteColor returns the color for a note
-func getNoteColor(noteName string) string {
-	if strings.HasSuffix(noteName, "#") {
-		// For sharp notes, use the base note color
-		baseNote := string(noteName[0])
-		return noteColors[baseNote]
+// layout renders the view wrapped to the main column
+func (m Model) layout() string {
+	s := m.render()
+	if width := m.columnWidth(); width > 0 {
+		s = lipgloss.NewStyle().Width(width).Render(s)
 	}
-	return noteColors[noteName]
+	return s
 }
 
-// renderTimelineNote renders a compact note representation for the timeline
-func renderTimelineNote(note *pitch.Note) string {
-	if note == nil {
-		return strings.Repeat(" ", noteDisplayWidth)
+// columnWidth returns the width of the main column, the terminal's full
+// width on the Timeline tab, zero while the terminal size is unknown
+func (m Model) columnWidth() int {
+	if m.width <= 0 {
+		return 0
+	}
+	if m.tab == tabTimeline {
+		return m.width
 	}
+	return min(m.width, maxColumnWidth)
+}
 
-	// Create a compact representation of the note (e.g., "C4", "D#5")
-	noteText := note.Name
-	if len(noteText) == 1 {
-		noteText += " " // Add space for single-char notes to align with sharps
+// timelineWidth returns the width the timeline's notes take inside its box
+func (m Model) timelineWidth() int {
+	if m.width <= 0 {
+		return defaultTimelineWidth
 	}
+	return m.columnWidth() - 6 // Leave room for the box's borders and padding
+}
 
-	// Create style with appropriate color
-	noteColor := getNoteColor(note.Name)
-	timelineNoteStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color(noteColor)).
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Width(noteDisplayWidth).
-		Align(lipgloss.Center)
+// optionalPanes are the panes View leaves out, in this order, when the
+// terminal is too short to show everything. Each hides its pane in the
+// model and reports whether it was shown; those without a toggle are
+// always shown and are left out through hiddenPanes.
+var optionalPanes = []struct {
+	name string
+	hide func(m *Model) bool
+}{
+	{paneDebugDetails, func(m *Model) bool { return m.showDebug && !m.bigMode }},
+	{"debug", func(m *Model) bool { shown := m.showDebug && !m.bigMode; m.showDebug = false; return shown }},
+	{"octave legend", func(m *Model) bool { shown := m.showOctaveLegend; m.showOctaveLegend = false; return shown }},
+	{"fretboard", func(m *Model) bool { shown := m.showFretboard; m.showFretboard = false; return shown }},
+	{"keyboard", func(m *Model) bool { shown := m.showKeyboard; m.showKeyboard = false; return shown }},
+	{"settings", func(m *Model) bool { shown := m.showSettings; m.showSettings = false; return shown }},
+	{paneCentsSpark, func(*Model) bool { return true }},
+	{paneHeldNote, func(*Model) bool { return true }},
+	{paneStatusBar, func(*Model) bool { return true }},
+	{paneHints, func(*Model) bool { return true }},
+	{paneNotePadding, func(*Model) bool { return true }},
+	{paneLevelMeter, func(*Model) bool { return true }},
+	{paneHeader, func(*Model) bool { return true }},
+	{paneBigNote, func(m *Model) bool { return m.bigMode }},
+}
 
-	return timelineNoteStyle.Render(noteText)
+// Panes without a toggle that View may leave out
+const (
+	paneDebugDetails = "debug details"
+	paneCentsSpark   = "cents history"
+	paneHeldNote     = "held note summary"
+	paneStatusBar    = "status bar"
+	paneHints        = "key hints"
+	paneNotePadding  = "note box padding"
+	paneLevelMeter   = "level meter"
+	paneHeader       = "settings summary"
+	paneBigNote      = "big note"
+)
+
+// paneHidden reports whether View left a pane out to fit the terminal
+func (m Model) paneHidden(name string) bool {
+	return slices.Contains(m.hiddenPanes, name)
 }
 
-// View renders the UI
-func (m Model) View() string {
-	s := titleStyle.Render("TuneNote - Musical Note Detector")
+// noteBoxPadding returns the blank lines above and below the note in the
+// note box
+func (m Model) noteBoxPadding() int {
+	if m.paneHidden(paneNotePadding) {
+		return 0
+	}
+	return 2
+}
+
+// render renders the view at its natural width
+func (m Model) render() string {
+	// The help replaces everything else while it's open
+	if m.showHelp {
+		return m.renderTitle(time.Now()) + "\n" + renderHelp(m.styles, m.columnWidth())
+	}
+
+	s := m.renderTitle(time.Now())
+	s += "\n"
+	if banner := m.renderBanner(); banner != "" {
+		s += banner
+		s += "\n"
+	}
+	s += m.renderTabBar()
+	s += "\n\n"
+	if !m.paneHidden(paneHeader) {
+		s += m.renderHeader()
+	}
+	if !m.paneHidden(paneLevelMeter) {
+		s += renderLevelMeter(m.styles, m.audioDB, heldPeak(m.peakDB, time.Since(m.peakAt)), m.clipped)
+		s += "\n"
+	}
+	s += m.renderMain()
+	return s
+}
+
+// renderTitle renders the title with the session clock beside it, and
+// badges while recording, while paused and while the timeline is frozen. On a narrow
+// terminal the title shortens, then the row is cut at the edge.
+func (m Model) renderTitle(now time.Time) string {
+	status := []string{m.styles.info.Render(formatClock(now.Sub(m.started)))}
+	if m.recording {
+		status = append(status, recordingBadgeStyle.Render("● REC"))
+	}
+	if m.paused {
+		status = append(status, pausedBadgeStyle.Render("PAUSED"))
+	}
+	if m.timelineFrozen {
+		status = append(status, frozenBadgeStyle.Render("FROZEN"))
+	}
+
+	row := lipgloss.JoinHorizontal(lipgloss.Top, m.styles.title.Render("TuneNote - Musical Note Detector"), " ", strings.Join(status, " "))
+	width := m.columnWidth()
+	if width > 0 && lipgloss.Width(row) > width {
+		row = lipgloss.JoinHorizontal(lipgloss.Top, m.styles.title.Render("TuneNote"), " ", strings.Join(status, " "))
+		row = lipgloss.NewStyle().MaxWidth(width).Render(row)
+	}
+	return row
+}
+
+// formatClock writes an elapsed time as hours, minutes and seconds, e.g.
+// "01:02:03"
+func formatClock(elapsed time.Duration) string {
+	seconds := int(max(0, elapsed.Seconds()))
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+// renderHeader renders the lines summing up the settings
+func (m Model) renderHeader() string {
+	s := m.styles.info.Render(fmt.Sprintf("Reference: A4 = %.1f Hz | Transposition: %s%s | Spelling: %s | Names: %s | Notation: %s",
+		m.referenceA4, m.transposition.Name, m.capoLabel(), m.spellingLabel(), m.namingLabel(), m.notation))
 	s += "\n"
+	status := fmt.Sprintf("Temperament: %s on %s", m.temperament.Name, pitch.PitchClassName(m.tonic))
+	if degrees := len(m.temperament.Degrees); degrees > 0 {
+		status = fmt.Sprintf("Scale: %s (%d degrees) on %s", m.temperament.Name, degrees, m.scaleBaseLabel())
+	}
+	if m.octaveFold {
+		status += " | Octave: ignored"
+	}
+	if m.stringSelector != nil {
+		status += " | Tuning: " + m.stringSelector.StringSet().Name
+	}
+	s += m.styles.info.Render(status)
+	s += "\n"
+	return s
+}
+
+// renderMain renders everything under the header: the open tab, then the
+// lines every tab shares
+func (m Model) renderMain() string {
+	var s string
+	switch m.tab {
+	case tabTimeline:
+		s = m.renderTimelineTab()
+	case tabStats:
+		s = m.renderStatsTab()
+	case tabSpectrum:
+		s = m.renderSpectrumTab()
+	default:
+		s = m.renderTunerTab()
+	}
+
+	// Show debug info if enabled, making room for the big note instead
+	if m.showDebug && !m.bigMode {
+		s += m.renderDebug()
+	}
+
+	// Show the outcome of the last action, such as an export
+	if m.status != "" {
+		style := statusStyle
+		if m.statusError {
+			style = statusErrorStyle
+		}
+		s += "\n"
+		s += style.Render(m.status)
+		s += "\n"
+	}
+
+	if !m.paneHidden(paneHints) {
+		s += "\n"
+		s += renderHints(m.styles)
+	}
+
+	// Point out the panes left out to fit the terminal
+	if len(m.hiddenPanes) > 0 {
+		s += "\n"
+		s += m.styles.debug.Render("Hidden to fit the terminal: " + strings.Join(m.hiddenPanes, ", "))
+	}
+
+	// Sum up the input stream on the bottom line
+	if bar := m.renderStatusBar(); bar != "" && !m.paneHidden(paneStatusBar) {
+		s += "\n"
+		s += bar
+	}
+
+	return s
+}
+
+// renderTunerTab renders the Tuner tab: the note, its gauge and the modes
+// following it
+func (m Model) renderTunerTab() string {
+	s := ""
+
+	// Show the text being typed, then the target note being practiced
+	if m.typing != inputNone {
+		s += m.renderInput()
+		s += "\n\n"
+	}
+	if m.practice != nil {
+		s += m.renderPractice()
+		s += "\n\n"
+	}
 
 	if m.currentNote != nil {
 		// Get note style based on the note name
-		noteStyle := getNoteStyle(m.currentNote.Name)
+		noteStyle := m.styles.noteStyle(m.currentNote)
 
 		// Generate note text
-		noteText := fmt.Sprintf("%s%d", m.currentNote.Name, m.currentNote.Octave)
-
-		// For sharps, we need to render the note with split colors
-		if strings.HasSuffix(m.currentNote.Name, "#") {
-			baseNote := string(m.currentNote.Name[0])
-			nextNote := getNextNote(baseNote)
-
-			baseColor := noteColors[baseNote]
-			nextColor := noteColors[nextNote]
+		noteText := m.noteLabel(m.currentNote)
+
+		// Dim the note box when the detection is only marginally trustworthy
+		// or paused, and more so once the note has stopped sounding
+		dimmed := m.currentNote.Confidence < marginalConfidence || m.paused
+		releasing := !m.releaseUntil.IsZero()
+
+		// For sharps and flats, we need to render the note with split colors:
+		// the accidental takes the color of the neighbor it leans toward
+		var box string
+		big, showBig := "", false
+		if m.bigMode && !m.paneHidden(paneBigNote) {
+			big, showBig = m.renderBigNote()
+		}
+		if len(m.chordNotes) > 1 {
+			box = m.renderChord()
+		} else if showBig {
+			box = big
+		} else if isAccidental(m.currentNote.PitchClass) && !m.styles.plain {
+			baseColor, nextColor := m.styles.noteBoxColors(m.currentNote)
 
 			// Create joined style with rounded border
 			joinedStyle := lipgloss.NewStyle().
 				Bold(true).
-				Foreground(lipgloss.Color("#FAFAFA")).
+				Foreground(lipgloss.Color(m.styles.theme.NoteText)).
 				BorderStyle(lipgloss.RoundedBorder()).
 				BorderForeground(lipgloss.Color("#333333")).
-				Padding(2, 4).
+				Padding(m.noteBoxPadding(), 4).
 				Width(boxWidth / 2). // Half width
 				Align(lipgloss.Center).
 				MarginBottom(1)
 
-			// Split rendering approach for sharp notes
+			if dimmed {
+				joinedStyle = m.styles.dimNote(joinedStyle)
+			}
+
+			// Split rendering approach for sharp and flat notes
 			baseStyle := joinedStyle.Copy().Background(lipgloss.Color(baseColor))
 			sharpStyle := joinedStyle.Copy().Background(lipgloss.Color(nextColor))
+			if releasing {
+				baseStyle = m.styles.releaseNote(baseStyle)
+				sharpStyle = m.styles.releaseNote(sharpStyle)
+			}
+			if m.inTuneLocked {
+				baseStyle = baseStyle.BorderForeground(lipgloss.Color(inTuneColor))
+				sharpStyle = sharpStyle.BorderForeground(lipgloss.Color(inTuneColor))
+			}
 
-			// Render each part separately
-			baseChar := string(noteText[0])
-			sharpChar := "#"
-			octave := noteText[2:]
+			// Render each part separately. Names without an accidental sign
+			// (movable-do syllables like "Di") keep only the octave on the right.
+			name := m.notation.Name(m.named(m.currentNote))
+			baseChar, sharpChar := splitAccidental(name)
+			octave := strings.TrimPrefix(noteText, name)
 
 			// Combine the parts
-			s += lipgloss.JoinHorizontal(lipgloss.Top,
+			box = lipgloss.JoinHorizontal(lipgloss.Top,
 				baseStyle.Render(baseChar),
 				sharpStyle.Render(sharpChar+octave))
 
 		} else {
 			// For natural notes, use a single color with fixed width
-			noteStyle = noteStyle.Width(boxWidth).Align(lipgloss.Center)
-			s += noteStyle.Render(noteText)
+			noteStyle = noteStyle.Width(boxWidth).Align(lipgloss.Center).Padding(m.noteBoxPadding(), 4)
+			if dimmed {
+				noteStyle = m.styles.dimNote(noteStyle)
+			}
+			if releasing {
+				noteStyle = m.styles.releaseNote(noteStyle)
+			}
+			if m.inTuneLocked {
+				noteStyle = noteStyle.BorderForeground(lipgloss.Color(inTuneColor))
+			}
+			box = noteStyle.Render(noteText)
+		}
+
+		// Badge a note that has settled in tune
+		if m.inTuneLocked {
+			box = lipgloss.JoinHorizontal(lipgloss.Center, box, "  ", inTuneBadgeStyle.Render("IN TUNE ✓"))
 		}
+		s += box
 
 		s += "\n"
 
-		info := fmt.Sprintf("Frequency: %.2f Hz | Cents: %+.1f",
-			m.currentNote.Frequency,
-			m.currentNote.Cents)
-		s += infoStyle.Render(info)
+		// Show the cents on a tuner needle, smoothed by the tracker, their
+		// last few seconds under it, and how the note has been held
+		below, above := m.neighborLabels()
+		s += renderGauge(m.styles, m.currentNote.Cents, m.columnWidth(), below, above)
+		s += "\n"
+		if !m.paneHidden(paneCentsSpark) {
+			labelWidth := gaugeLabelWidth(below, above)
+			s += renderCentsSpark(m.styles, m.centsHistory.since(time.Now().Add(-sparkWindow)), time.Now(),
+				gaugeCells(m.columnWidth(), labelWidth), labelWidth)
+			s += "\n"
+		}
+		if held := m.renderHeld(time.Now()); held != "" && !m.paneHidden(paneHeldNote) {
+			s += held
+			s += "\n"
+		}
+
+		// Refined readings are steady enough for a second decimal
+		cents := m.styles.info.Render(fmt.Sprintf("Cents: %+.1f", m.currentNote.Cents))
+		if m.currentNote.Precise {
+			cents = m.styles.precise.Render(fmt.Sprintf("Cents: %+.2f", m.currentNote.Cents))
+		}
+		cents += m.renderNeighborHint()
+		if m.capoOffset != 0 {
+			sounding := m.currentNote.Sounding()
+			s += m.styles.info.Render(fmt.Sprintf("Sounding %s | ", m.noteLabel(&sounding)))
+		}
+		s += m.styles.info.Render(fmt.Sprintf("Frequency: %.2f Hz | ", m.currentNote.Frequency)) + cents
+		s += m.styles.info.Render(" | ") + renderConfidence(m.styles, m.currentNote.Confidence)
+		if m.harmonics != nil {
+			s += m.styles.info.Render(fmt.Sprintf(" | SNR %.0f dB", m.hnr))
+		}
+		if len(m.temperament.Degrees) > 0 {
+			s += m.styles.info.Render(fmt.Sprintf(" | Degree %d of %s", m.currentNote.Degree, m.temperament.Name))
+		}
+		if m.interval != nil {
+			s += m.styles.info.Render(fmt.Sprintf(" | %s from %s", m.interval, m.noteLabel(m.intervalFrom)))
+		}
+
+		// Show how steadily the note is held
+		if m.steadiness != nil {
+			s += "\n"
+			s += renderSteadiness(m.styles, m.steadiness)
+		}
+
+		// Show the vibrato of a held note
+		if m.vibrato != nil {
+			s += "\n"
+			s += m.styles.info.Render(fmt.Sprintf("Vibrato: %.1f Hz ± %.0f¢", m.vibrato.Rate, m.vibrato.Depth))
+		}
 	} else {
 		// No note being detected - show gray placeholder box
-		placeholder := noSoundStyle.Width(boxWidth).Align(lipgloss.Center).Render("---")
+		placeholder := m.styles.noSound.Width(boxWidth).Align(lipgloss.Center).Padding(m.noteBoxPadding(), 4).Render("---")
 		s += placeholder
 		s += "\n"
-		s += infoStyle.Render("Make a sound to see the note...")
+		if held := m.renderHeld(time.Now()); held != "" && !m.paneHidden(paneHeldNote) {
+			s += held
+			s += "\n"
+		}
+		if m.paused {
+			s += m.styles.info.Render("Detection paused; press p to resume")
+		} else {
+			s += m.styles.info.Render("Make a sound to see the note...")
+		}
 	}
 
 	s += "\n"
 
+	// Show where the note lies on a piano keyboard
+	if m.showKeyboard {
+		s += renderKeyboard(m.styles, m.currentNote, m.columnWidth())
+		s += "\n"
+	}
+
+	// Show where the note lies on the neck, in the string tuner's tuning
+	if m.showFretboard {
+		set := pitch.StringSetStandard
+		if m.stringSelector != nil {
+			set = m.stringSelector.StringSet()
+		}
+		s += renderFretboard(m.styles, m.currentNote, set, fretboardFrets, m.octaveFold, m.columnWidth(), m.notation)
+		s += "\n"
+	}
+
+	// Show the string being tuned
+	if m.stringSelector != nil {
+		s += m.renderStrings()
+		s += "\n"
+	}
+
+	// Show the metronome's beat
+	if m.metronomeOn {
+		s += m.renderMetronome()
+		s += "\n"
+	}
+
+	// Show the scale being practiced
+	if m.scale != nil {
+		s += m.renderScale()
+		s += "\n"
+	}
+
+	// Show the target melody's progress
+	if m.melody != nil {
+		s += m.styles.info.Render(m.melodyLabel)
+		s += "\n"
+	}
+
+	// Show the settings panel if enabled
+	if m.showSettings {
+		s += "\n"
+		s += m.renderSettings()
+		s += "\n"
+	}
+
+	return s
+}
+
+// renderTimelineTab renders the Timeline tab: the history across the
+// terminal's full width, with the key and tempo it suggests
+func (m Model) renderTimelineTab() string {
+	s := ""
+
 	// Render timeline
 	if len(m.timeline) > 0 {
-		// Create timeline header with freeze button
+		entries, startIndex, endIndex, cellWidth := m.timelineWindow()
+
+		// Create timeline header with freeze button and the entries' position
+		// in the history
 		var timelineHeader string
 		freezeButtonText := "Freeze"
+		position := fmt.Sprintf("%d–%d of %d", startIndex+1, endIndex, len(entries))
+		if m.groupRepeats {
+			position += ", repeats grouped"
+		}
+		if m.filter != nil {
+			position += fmt.Sprintf(", only %s: %d found, Esc to clear", m.filter.label, filterMatches(m.timeline, m.filter))
+		}
 		if m.timelineFrozen {
 			freezeButtonText = "Resume"
-			timelineHeader = timelineLabelStyle.Render("Timeline: FROZEN")
+			timelineHeader = m.styles.timelineLabel.Render("Timeline: FROZEN, ←/→ to scroll (" + position + ")")
 		} else {
-			timelineHeader = timelineLabelStyle.Render("Timeline: (newest notes on the right)")
+			timelineHeader = m.styles.timelineLabel.Render("Timeline: (newest notes on the right, " + position + ")")
 		}
 
 		// Add the freeze/resume button
-		freezeButton := buttonStyle.Render(freezeButtonText)
+		freezeButton := m.renderButton(m.styles.button, freezeButtonText, actionFreeze)
 
 		// Add clear button
-		clearButton := clearButtonStyle.Render("Clear")
+		clearButton := m.renderButton(m.styles.clearButton, "Clear", actionClear)
 
-		// Join all header elements
-		timelineHeader = lipgloss.JoinHorizontal(lipgloss.Top, timelineHeader, freezeButton, clearButton)
+		// Join all header elements, leaving the buttons out when they don't fit
+		withButtons := lipgloss.JoinHorizontal(lipgloss.Top, timelineHeader, freezeButton, clearButton)
+		if width := m.columnWidth(); width == 0 || lipgloss.Width(withButtons) <= width {
+			timelineHeader = withButtons
+		}
 
 		s += timelineHeader
 		s += "\n"
@@ -380,38 +2856,41 @@ func (m Model) View() string {
 		// Create timeline display
 		timelineContent := ""
 
-		// Calculate how many entries we can show in the timeline
-		entriesToShow := len(m.timeline)
-		startIndex := 0
-
-		if entriesToShow > timelineWidth/noteDisplayWidth {
-			entriesToShow = timelineWidth / noteDisplayWidth
-			startIndex = len(m.timeline) - entriesToShow
-		}
-
 		// Create the timeline as a series of colored blocks
-		for i := startIndex; i < len(m.timeline); i++ {
-			timelineContent += renderTimelineNote(m.timeline[i].Note)
+		for i := startIndex; i < endIndex; i++ {
+			timelineContent += renderTimelineEntry(m.styles, entries[i], cellWidth, m.notation)
 		}
 
-		// Wrap it in the timeline box
-		s += timelineStyle.Render(timelineContent)
+		// Mark how long ago the entries started, counting back from the newest
+		timelineContent += "\n" + m.styles.debug.Render(renderTimelineTimes(m.styles, entries[startIndex:endIndex], cellWidth, m.notation, m.timelineWidth(), time.Now()))
+
+		// Wrap it in the timeline box, with which shade is which octave under
+		// it while colors are shown
+		s += m.styles.timeline.Width(m.timelineWidth() + 4).Render(timelineContent)
 		s += "\n"
+		if m.showOctaveLegend && !m.styles.plain {
+			s += renderOctaveLegend(m.styles)
+			s += "\n"
+		}
+
+		// Show the key and tempo the timeline's notes suggest
+		var stats []string
+		if m.keyLabel != "" {
+			stats = append(stats, "Key: "+m.keyLabel)
+		}
+		if m.tempoLabel != "" {
+			stats = append(stats, m.tempoLabel)
+		}
+		if len(stats) > 0 {
+			s += m.styles.info.Render(strings.Join(stats, " | "))
+			s += "\n"
+		}
 	} else {
 		// Show empty timeline box
 		emptyMessage := "No notes recorded yet"
-		s += timelineStyle.Render(emptyMessage)
-	}
-
-	// Show debug info if enabled
-	if m.showDebug {
-		dbInfo := fmt.Sprintf("Audio Level: RMS=%.6f, dB=%.1f", m.audioRMS, m.audioDB)
-		s += debugStyle.Render(dbInfo)
+		s += m.styles.timeline.Width(m.timelineWidth() + 4).Render(emptyMessage)
 		s += "\n"
 	}
 
-	s += "\n"
-	s += infoStyle.Render("Press f or space to freeze/resume | Press c to clear history | Press d to toggle debug | Press q to quit")
-
 	return s
 }
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// button is a clickable widget of the timeline header
+type button struct {
+	labels []string  // Texts the button shows
+	action keyAction // What a click does, as its key does
+}
+
+// timelineButtons lists the timeline header's buttons
+var timelineButtons = []button{
+	{labels: []string{"Freeze", "Resume"}, action: actionFreeze},
+	{labels: []string{"Clear"}, action: actionClear},
+}
+
+// buttonAt returns the button under a cell of the view. It finds the
+// buttons in the rendered view, so wherever the layout puts them, clicks
+// land where they're drawn.
+func (m Model) buttonAt(x, y int) (button, bool) {
+	if m.showHelp || m.typing != inputNone {
+		return button{}, false
+	}
+
+	// A button's label sits between the top and bottom of its border
+	corner := "╭"
+	if m.styles.plain {
+		corner = "+"
+	}
+	lines := strings.Split(m.View(), "\n")
+	for row := 1; row < len(lines)-1; row++ {
+		if y < row-1 || y > row+1 || !strings.Contains(lines[row-1], corner) {
+			continue
+		}
+		for _, b := range timelineButtons {
+			for _, label := range b.labels {
+				index := strings.Index(lines[row], label)
+				if index < 0 {
+					continue
+				}
+
+				// The box takes the padding and a border cell either side
+				column := lipgloss.Width(lines[row][:index])
+				left := column - buttonPadding - 1
+				right := column + len(label) + buttonPadding + 1
+				if x >= left && x < right {
+					return b, true
+				}
+			}
+		}
+	}
+	return button{}, false
+}
+
+// handleMouse clicks the button under a press of the left button, as its
+// key would, and highlights the button under the pointer
+func (m Model) handleMouse(msg tea.MouseMsg) (Model, tea.Cmd) {
+	b, over := m.buttonAt(msg.X, msg.Y)
+	m.hoverButton = actionNone
+	if over {
+		m.hoverButton = b.action
+	}
+
+	if over && msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+		return m.runAction(b.action, "")
+	}
+	return m, nil
+}
+
+// renderButton renders a timeline header button, highlighted while the
+// pointer is over it
+func (m Model) renderButton(style lipgloss.Style, label string, action keyAction) string {
+	if m.hoverButton == action {
+		style = style.Underline(true).BorderForeground(lipgloss.Color(m.styles.theme.Emphasis))
+	}
+	return style.Render(label)
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// timelineNames returns the note names on the first line of the timeline
+// tab's box
+func timelineNames(t *testing.T, m Model) []string {
+	t.Helper()
+	for _, line := range strings.Split(ansi.Strip(m.switchTab(tabTimeline).View()), "\n") {
+		if strings.HasPrefix(line, "│") {
+			return strings.Fields(strings.Trim(line, "│"))
+		}
+	}
+	t.Fatal("no timeline in view")
+	return nil
+}
+
+func TestNoteNameKeyRenamesTimeline(t *testing.T) {
+	commands := make(chan Command, 4)
+	m := updateModel(NewModel(commands), tea.WindowSizeMsg{Width: 100, Height: 50})
+
+	// C4, C#4, E4, A#4, B4, C#3, with A#4 still sounding
+	m = noteOns(m, time.Now().Add(-10*time.Second), 261.63, 277.18, 329.63, 466.16, 493.88, 138.59)
+	m = updateModel(m, UpdateNoteMsg(*pitch.NewNoteConverter().FromFrequency(466.16)))
+
+	tests := []struct {
+		status   string
+		timeline []string
+		current  string
+	}{
+		{"Note names: Flats", []string{"C4", "Db4", "E4", "Bb4", "B4", "Db3"}, "Bb4"},
+		{"Note names: Solfège", []string{"Do4", "Do#4", "Mi4", "La#4", "Si4", "Do#3"}, "La#4"},
+		{"Note names: German", []string{"C4", "Cis4", "E4", "Ais4", "H4", "Cis3"}, "Ais4"},
+		{"Note names: Sharps", []string{"C4", "C#4", "E4", "A#4", "B4", "C#3"}, "A#4"},
+		{"Note names: Flats", []string{"C4", "Db4", "E4", "Bb4", "B4", "Db3"}, "Bb4"},
+	}
+	if got := timelineNames(t, m); !slices.Equal(got, []string{"C4", "C#4", "E4", "A#4", "B4", "C#3"}) {
+		t.Fatalf("timeline before any x: %q", got)
+	}
+	for i, test := range tests {
+		next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+		m = next.(Model)
+		if cmd != nil {
+			for _, msg := range cmd().(tea.BatchMsg) {
+				msg()
+			}
+		}
+
+		// The processing loop hears of the spelling and naming
+		if len(commands) != 2 {
+			t.Errorf("press %d: %d commands sent, want 2", i+1, len(commands))
+		}
+		for len(commands) > 0 {
+			<-commands
+		}
+
+		if m.status != test.status {
+			t.Errorf("press %d: status %q, want %q", i+1, m.status, test.status)
+		}
+		if got := timelineNames(t, m); !slices.Equal(got, test.timeline) {
+			t.Errorf("press %d: timeline %q, want %q", i+1, got, test.timeline)
+		}
+		if got := m.noteLabel(m.currentNote); got != test.current {
+			t.Errorf("press %d: current note %q, want %q", i+1, got, test.current)
+		}
+	}
+
+	// Entries keep the names they were converted with
+	for _, entry := range m.timeline {
+		if entry.Note != nil && strings.Contains(entry.Note.Name, "b") {
+			t.Errorf("timeline entry renamed to %s in place", entry.Note.Name)
+		}
+	}
+}
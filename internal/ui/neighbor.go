@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+)
+
+// defaultNeighborThreshold is how many cents off a note has to be for the
+// neighbor it leans toward to be pointed out, unless set with
+// WithNeighborThreshold
+const defaultNeighborThreshold = 35
+
+// neighborLabels returns the names of the notes a semitone below and above
+// the current one, for the ends of the cents gauge
+func (m Model) neighborLabels() (below, above string) {
+	lower := m.currentNote.Neighbor(-1)
+	upper := m.currentNote.Neighbor(1)
+	return m.noteLabel(&lower), m.noteLabel(&upper)
+}
+
+// renderNeighborHint renders the note the current one leans toward and how
+// far off it is, e.g. "(↗ C#4, 55¢ away)", once the cents pass the neighbor
+// threshold, or "" while the note is closer to in tune
+func (m Model) renderNeighborHint() string {
+	cents := m.currentNote.Cents
+	if math.Abs(cents) <= m.neighborThreshold {
+		return ""
+	}
+
+	step, arrow := 1, "↗"
+	if cents < 0 {
+		step, arrow = -1, "↘"
+	}
+	neighbor := m.currentNote.Neighbor(step)
+	return m.styles.info.Render(fmt.Sprintf(" (%s %s, %.0f¢ away)", arrow, m.noteLabel(&neighbor), math.Abs(neighbor.Cents)))
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// offBy returns the note a number of cents from a frequency
+func offBy(frequency, cents float64) pitch.Note {
+	return *pitch.NewNoteConverter().FromFrequency(frequency * math.Pow(2, cents/1200))
+}
+
+func TestNeighborHint(t *testing.T) {
+	tests := []struct {
+		name      string
+		note      pitch.Note
+		threshold float64
+		hint      string
+		gauge     [2]string
+	}{
+		{"sharp B3", offBy(246.9417, 45), defaultNeighborThreshold, "Cents: +45.0 (↗ C4, 55¢ away)", [2]string{"A#3", "C4"}},
+		{"flat C4", offBy(261.6256, -45), defaultNeighborThreshold, "Cents: -45.0 (↘ B3, 55¢ away)", [2]string{"B3", "C#4"}},
+		{"flat B3", offBy(246.9417, -40), defaultNeighborThreshold, "Cents: -40.0 (↘ A#3, 60¢ away)", [2]string{"A#3", "C4"}},
+		{"just under the threshold", offBy(246.9417, 34), defaultNeighborThreshold, "Cents: +34.0 |", [2]string{"A#3", "C4"}},
+		{"in tune", offBy(246.9417, 0), defaultNeighborThreshold, "Cents: +0.0 |", [2]string{"A#3", "C4"}},
+		{"lower threshold", offBy(246.9417, 25), 20, "Cents: +25.0 (↗ C4, 75¢ away)", [2]string{"A#3", "C4"}},
+		{"higher threshold", offBy(246.9417, 45), 48, "Cents: +45.0 |", [2]string{"A#3", "C4"}},
+	}
+	for _, test := range tests {
+		m := sizedModel(100, 60).WithNeighborThreshold(test.threshold)
+		m = updateModel(m, UpdateNoteMsg(test.note))
+		view := ansi.Strip(m.View())
+		if !strings.Contains(view, test.hint) {
+			t.Errorf("%s: no %q in view:\n%s", test.name, test.hint, view)
+		}
+		if strings.Contains(test.hint, "|") && strings.Contains(view, "¢ away") {
+			t.Errorf("%s: hint shown under the threshold:\n%s", test.name, view)
+		}
+
+		// The gauge's ends name the neighbors, however far off the note is
+		gauge := ""
+		for _, line := range strings.Split(view, "\n") {
+			if strings.Contains(line, "●") && strings.Contains(line, "─") {
+				gauge = strings.TrimSpace(line)
+			}
+		}
+		if !strings.HasPrefix(gauge, test.gauge[0]+" ") || !strings.HasSuffix(gauge, " "+test.gauge[1]) {
+			t.Errorf("%s: gauge %q, want it between %s and %s", test.name, gauge, test.gauge[0], test.gauge[1])
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestPauseRoundTrip(t *testing.T) {
+	withTrueColor(t)
+	converter := pitch.NewNoteConverter()
+	a4 := *converter.FromFrequency(440)
+	a4.Confidence = 0.95
+	c5 := *converter.FromFrequency(523.25)
+	c5.Confidence = 0.95
+
+	commands := make(chan Command, 4)
+	m := sizedModel(100, 50)
+	m.commands = commands
+	m = updateModel(m, UpdateNoteMsg(a4))
+	entries := len(m.timeline)
+
+	// Pausing tells the processing loop once
+	m, sent := pressCommands(m, commands, "p")
+	if len(sent) != 1 || sent[0] != (SetPausedCommand{Paused: true}) {
+		t.Fatalf("pausing sent %#v, want one SetPausedCommand{Paused: true}", sent)
+	}
+	if !m.paused || m.status != "Detection paused; press p to resume" {
+		t.Errorf("paused %v with status %q", m.paused, m.status)
+	}
+
+	// The last note stays up, dimmed, and detections still in flight are
+	// dropped
+	dim := foreground(lipgloss.Color("#AAAAAA"))
+	if lines := noteBoxLines(m.View(), "A4"); len(lines) != 1 || len(styledColumns(lines[0], dim)) == 0 {
+		t.Errorf("paused note box not dimmed: %q", lines)
+	}
+	for _, msg := range []any{
+		UpdateNoteMsg(c5),
+		NoteOnMsg{Note: c5, At: time.Now()},
+		UpdateChordMsg{Notes: []pitch.Note{c5, a4}},
+		ClearNoteMsg{},
+	} {
+		m = updateModel(m, msg)
+	}
+	if m.currentNote == nil || m.currentNote.Name != "A" || len(m.timeline) != entries {
+		t.Errorf("in-flight detections got through the pause: note %v, %d timeline entries", m.currentNote, len(m.timeline))
+	}
+
+	// Resuming tells the processing loop once, and starts with a blank note
+	// box rather than the note from before the pause
+	m, sent = pressCommands(m, commands, "p")
+	if len(sent) != 1 || sent[0] != (SetPausedCommand{Paused: false}) {
+		t.Fatalf("resuming sent %#v, want one SetPausedCommand{Paused: false}", sent)
+	}
+	if m.paused || m.currentNote != nil || m.status != "Detection resumed" {
+		t.Errorf("after resuming: paused %v, note %v, status %q", m.paused, m.currentNote, m.status)
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "Make a sound to see the note...") || strings.Contains(view, "PAUSED") {
+		t.Errorf("resumed view:\n%s", view)
+	}
+
+	// Detections come through again
+	m = updateModel(m, UpdateNoteMsg(c5))
+	if lines := noteBoxLines(m.View(), "C5"); len(lines) != 1 || len(styledColumns(lines[0], dim)) != 0 {
+		t.Errorf("resumed note box: %q", lines)
+	}
+}
+
+func TestPausedWithoutNote(t *testing.T) {
+	commands := make(chan Command, 1)
+	m := sizedModel(100, 50).WithNoteHold(0)
+	m.commands = commands
+	m = updateModel(m, ClearNoteMsg{})
+	m, _ = pressCommands(m, commands, "p")
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "Detection paused; press p to resume") || !strings.Contains(view, "PAUSED") {
+		t.Errorf("paused view:\n%s", view)
+	}
+}
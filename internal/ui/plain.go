@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// plainRunes swaps the symbols the UI draws with for ASCII characters of the
+// same width, so boxes and columns stay lined up
+var plainRunes = strings.NewReplacer(
+	"╭", "+", "╮", "+", "╰", "+", "╯", "+", "┼", "+",
+	"─", "-", "│", "|", "║", "|",
+	"●", "*", "○", "o", "◯", "O", "▸", ">",
+	"♯", "#", "♭", "b", "✓", "v", "✗", "x", "×", "x",
+	"←", "<", "→", ">", "↑", "^", "↓", "v", "↗", "/", "↘", "\\",
+	"·", ".", "…", ".", "–", "-", "−", "-", "≈", "~", "±", "~", "∞", "~", "¢", "c", "µ", "u", "²", "2",
+	"′", "'", "″", "\"", "‴", "\"", "⁗", "\"", "͵", ",",
+	"è", "e", "é", "e",
+	"░", ".", "▁", "_", "▂", "_", "▃", "-", "▄", "=", "▅", "=", "▆", "#", "▇", "#", "█", "#", "▀", "\"", "▏", "|",
+)
+
+// plainText strips the colors and styling from a rendered view and draws it
+// in plain ASCII, for terminals that show neither. Braille plot cells become
+// dots, and whatever else isn't ASCII a question mark.
+func plainText(s string) string {
+	s = plainRunes.Replace(ansi.Strip(s))
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80:
+			b.WriteRune(r)
+		case r == 0x2800: // Empty braille cell
+			b.WriteByte(' ')
+		case r > 0x2800 && r <= 0x28FF:
+			b.WriteByte('.')
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
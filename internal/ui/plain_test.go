@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestPlainViewIsASCII(t *testing.T) {
+	for _, size := range [][2]int{{60, 20}, {100, 30}, {200, 50}} {
+		for tab := tabTuner; tab < tabCount; tab++ {
+			t.Run(fmt.Sprintf("%dx%d %s", size[0], size[1], tabNames[tab]), func(t *testing.T) {
+				m := sizedModel(size[0], size[1]).WithPlain(true).switchTab(tab)
+
+				view := m.View()
+				for i, r := range view {
+					if r == '\x1b' {
+						t.Fatalf("escape sequence at byte %d: %q", i, view[i:min(len(view), i+12)])
+					}
+					if r != '\n' && (r < ' ' || r > '~') {
+						t.Fatalf("non-ASCII %q at byte %d", r, i)
+					}
+				}
+
+				// Notes in the timeline are told apart by name alone
+				if tab == tabTimeline && !strings.Contains(view, "[C4]") {
+					t.Error("timeline shows no bracketed note names")
+				}
+
+				// Every symbol drawn has an ASCII stand-in rather than
+				// falling back to a question mark
+				for _, r := range ansi.Strip(m.view()) {
+					if r >= 0x80 && (r < 0x2800 || r > 0x28FF) && plainRunes.Replace(string(r)) == string(r) {
+						t.Errorf("no ASCII stand-in for %q", r)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestPlainTextStripsStyling(t *testing.T) {
+	styled := "\x1b[1;38;2;255;0;0m●\x1b[0m A4 \x1b[2m+3¢\x1b[0m ╭─╮ ⣿⠀"
+	if got, want := plainText(styled), "* A4 +3c +-+ . "; got != want {
+		t.Errorf("plainText = %q, want %q", got, want)
+	}
+	if strings.ContainsRune(plainText("\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\"), '\x1b') {
+		t.Error("plainText kept a hyperlink sequence")
+	}
+}
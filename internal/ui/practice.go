@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// How long the target flashes the color of the latest judgment
+const judgmentFlash = 800 * time.Millisecond
+
+// Colors of the judgments: hits, notes in the wrong octave, and misses
+var judgmentColors = map[pitch.Judgment]string{
+	pitch.JudgmentHit:         "#43A047",
+	pitch.JudgmentWrongOctave: "#E5C07B",
+	pitch.JudgmentMiss:        "#D9534F",
+}
+
+// confirmTarget starts practicing the typed target note, or reports why it
+// isn't one and lets it be corrected. An empty one turns target practice off.
+func (m Model) confirmTarget() (Model, tea.Cmd) {
+	text := strings.TrimSpace(m.inputText)
+	if text == "" {
+		m.typing = inputNone
+		if m.practice == nil {
+			return m, nil
+		}
+		m.practice = nil
+		return m.withStatus("Target practice off", false), nil
+	}
+
+	note, err := pitch.ParseNote(text)
+	if err != nil {
+		return m.withStatus(fmt.Sprintf("%q isn't a note with an octave, e.g. C#3 or Bb4", text), true), nil
+	}
+
+	m.typing = inputNone
+	m.practice = pitch.NewTargetPractice(*note)
+	m.judgedAt = time.Time{}
+	return m.withStatus("Practicing "+m.notation.Format(note), false), nil
+}
+
+// judgeTarget judges a newly played note against the practice target
+func (m Model) judgeTarget(note pitch.Note, at time.Time) Model {
+	m.judgment, m.judgmentInterval = m.practice.Judge(note)
+	m.judgedNote = note
+	m.judgedAt = at
+	return m
+}
+
+// renderPractice renders the target being practiced with the latest
+// judgment and the running score
+func (m Model) renderPractice() string {
+	target := m.practice.Target()
+	box := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color(m.styles.theme.NoteText)).
+		Background(lipgloss.Color(m.styles.noteColor(&target))).
+		Padding(0, 2)
+
+	// Flash the judgment's color over the target just after a note
+	flashing := !m.judgedAt.IsZero() && time.Since(m.judgedAt) < judgmentFlash
+	if flashing {
+		box = box.Background(lipgloss.Color(judgmentColors[m.judgment]))
+	}
+	s := m.styles.info.Bold(true).Render("Target ") + box.Render(m.notation.Format(&target))
+
+	if !m.judgedAt.IsZero() {
+		verdict := m.notation.Format(&m.judgedNote) + ": " + m.judgment.String()
+		if m.judgment == pitch.JudgmentMiss {
+			verdict += fmt.Sprintf(", target is %s", m.judgmentInterval)
+		}
+		s += "  " + lipgloss.NewStyle().Bold(flashing).Foreground(lipgloss.Color(judgmentColors[m.judgment])).Render(verdict)
+	}
+
+	hits, misses := m.practice.Score()
+	score := fmt.Sprintf("  Hits %d | Misses %d", hits, misses)
+	if total := hits + misses; total > 0 {
+		score += fmt.Sprintf(" (%.0f%%)", float64(hits)/float64(total)*100)
+	}
+	return s + m.styles.info.Render(score)
+}
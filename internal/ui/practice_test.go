@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// typeText presses a mode's key, then types the text and presses Enter
+func typeText(m Model, key, text string) Model {
+	m = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	for _, r := range text {
+		if r == ' ' {
+			m = updateModel(m, tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}})
+		} else {
+			m = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	return updateModel(m, tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func TestTargetPracticeInput(t *testing.T) {
+	m := NewModel(nil).WithPlain(true)
+
+	// Text that isn't a note with an octave is kept for correcting
+	for _, text := range []string{"C#", "H4", "C#4x", "4C"} {
+		got := typeText(m, "n", text)
+		if got.typing != inputTargetNote || got.practice != nil || !got.statusError || !strings.Contains(got.status, "isn't a note with an octave") {
+			t.Errorf("%q: typing %v, practice %v, status %q", text, got.typing, got.practice != nil, got.status)
+		}
+	}
+
+	m = typeText(m, "n", "C#4")
+	if m.typing != inputNone || m.practice == nil || m.status != "Practicing C#4" {
+		t.Fatalf("C#4: typing %v, practice %v, status %q", m.typing, m.practice != nil, m.status)
+	}
+	if got := ansi.Strip(m.renderPractice()); got != "Target   C#4    Hits 0 | Misses 0" {
+		t.Errorf("before playing: %q", got)
+	}
+
+	// Each note played is judged, naming the way to the target on a miss
+	converter := pitch.NewNoteConverter()
+	tests := []struct {
+		frequency float64
+		verdict   string
+		score     string
+	}{
+		{277.18, "C#4: hit", "Hits 1 | Misses 0 (100%)"},
+		{554.37, "C#5: wrong octave", "Hits 1 | Misses 1 (50%)"},
+		{293.66, "D4: miss, target is ↓ m2", "Hits 1 | Misses 2 (33%)"},
+		{220, "A3: miss, target is ↑ M3", "Hits 1 | Misses 3 (25%)"},
+	}
+	for i, tt := range tests {
+		m = updateModel(m, NoteOnMsg{Note: *converter.FromFrequency(tt.frequency), At: time.Now().Add(time.Duration(i) * time.Second)})
+		got := ansi.Strip(m.renderPractice())
+		if !strings.Contains(got, "  "+tt.verdict+"  ") || !strings.HasSuffix(got, tt.score) {
+			t.Errorf("note %d: %q, want %q and %q", i, got, tt.verdict, tt.score)
+		}
+	}
+
+	// Confirming nothing turns practice off
+	m = typeText(m, "n", "")
+	if m.practice != nil || m.status != "Target practice off" {
+		t.Errorf("empty target: practice %v, status %q", m.practice != nil, m.status)
+	}
+}
+
+func TestScalePracticeInput(t *testing.T) {
+	m := NewModel(nil).WithPlain(true)
+	tests := []struct {
+		text   string
+		status string // Start of the status
+	}{
+		{"G", `"G" isn't a root note with an octave`},
+		{"H3 major", `"H3" isn't a root note with an octave`},
+		{"G3 dorian", `Unknown scale "dorian"; try major, natural minor,`},
+	}
+	for _, tt := range tests {
+		got := typeText(m, "w", tt.text)
+		if got.typing != inputScale || got.scale != nil || !got.statusError || !strings.HasPrefix(got.status, tt.status) {
+			t.Errorf("%q: typing %v, scale %v, status %q", tt.text, got.typing, got.scale != nil, got.status)
+		}
+	}
+
+	// The scale defaults to major, and takes any built-in name
+	if got := typeText(m, "w", "A2 harmonic-minor"); got.scale == nil || got.status != "Practicing A Harmonic Minor" {
+		t.Errorf("A2 harmonic-minor: status %q", got.status)
+	}
+	m = typeText(m, "w", "G3")
+	if m.scale == nil || m.status != "Practicing G Major" {
+		t.Fatalf("G3: status %q", m.status)
+	}
+
+	// The expected note moves on with each right one; a wrong one is
+	// named until the next right one
+	converter := pitch.NewNoteConverter()
+	play := func(frequency float64) {
+		m = updateModel(m, NoteOnMsg{Note: *converter.FromFrequency(frequency), At: time.Now()})
+	}
+	play(196)
+	play(220)
+	play(261.63)
+	lines := strings.Split(ansi.Strip(m.renderScale()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "Scale: G Major (lenient) | Mistakes: 1 |") ||
+		!strings.HasPrefix(lines[1], "G3 A3  B3  C4 D4") || lines[2] != "✗ C4, expected B3" {
+		t.Errorf("after a wrong note:\n%s", strings.Join(lines, "\n"))
+	}
+
+	for _, frequency := range []float64{246.94, 261.63, 293.66, 329.63, 369.99, 392, 369.99, 329.63, 293.66, 261.63, 246.94, 220, 196} {
+		play(frequency)
+	}
+	lines = strings.Split(ansi.Strip(m.renderScale()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[2], "Finished G Major in ") || !strings.HasSuffix(lines[2], " with 1 mistakes (press w for another)") {
+		t.Errorf("after the last note:\n%s", strings.Join(lines, "\n"))
+	}
+
+	if m = typeText(m, "w", ""); m.scale != nil || m.status != "Scale practice off" {
+		t.Errorf("empty scale: status %q", m.status)
+	}
+}
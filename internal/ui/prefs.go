@@ -0,0 +1,249 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Preferences file settings
+const (
+	prefsVersion  = 1           // Version written to the file, raised when a field changes meaning
+	prefsDebounce = time.Second // Quiet time after the last change before the file is written
+)
+
+// Preferences are the display choices kept from one run to the next
+type Preferences struct {
+	Theme       string             // Name of the color theme
+	Plain       bool               // Whether to draw in plain ASCII without colors
+	Debug       bool               // Whether to show the debug panel
+	Spelling    pitch.Spelling     // How accidentals are named
+	Naming      pitch.NamingScheme // Letters or solfège syllables
+	Notation    pitch.Notation     // How note names and octaves are written
+	OctaveFold  bool               // Whether notes an octave apart count as the same note
+	ReferenceA4 float64            // A4 reference pitch in Hz
+	CapoOffset  int                // Semitones added to the displayed note, e.g. -3 for a capo
+}
+
+// DefaultPreferences returns the preferences of a first run
+func DefaultPreferences() Preferences {
+	return Preferences{
+		Theme:       ThemeDefault.Name,
+		Debug:       true,
+		Spelling:    pitch.SpellingSharps,
+		Naming:      pitch.NamingLetters,
+		Notation:    pitch.NotationScientific,
+		ReferenceA4: pitch.DefaultReferenceA4,
+	}
+}
+
+// prefsFile is the preferences as written to the file, with choices stored by
+// their names so the file reads well and survives reordered lists
+type prefsFile struct {
+	Version     int     `json:"version"`
+	Theme       string  `json:"theme"`
+	Plain       bool    `json:"plain"`
+	Debug       bool    `json:"debug"`
+	Spelling    string  `json:"spelling"`
+	Naming      string  `json:"naming"`
+	Notation    string  `json:"notation"`
+	OctaveFold  bool    `json:"octave_fold"`
+	ReferenceA4 float64 `json:"reference_a4"`
+	CapoOffset  int     `json:"capo_offset"`
+}
+
+// PreferencesPath returns where the preferences are kept:
+// tunenote/prefs.json in the user's configuration directory
+func PreferencesPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tunenote", "prefs.json"), nil
+}
+
+// LoadPreferences reads the preferences at path. A missing file gives the
+// defaults. So does a file that can't be parsed or whose version this build
+// doesn't know, along with the reason; unknown fields are ignored and unknown
+// or out-of-range values keep their defaults.
+func LoadPreferences(path string) (Preferences, error) {
+	prefs := DefaultPreferences()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return prefs, nil
+	}
+	if err != nil {
+		return prefs, fmt.Errorf("reading preferences: %w", err)
+	}
+
+	file := toPrefsFile(prefs)
+	file.Version = 0
+	if err := json.Unmarshal(data, &file); err != nil {
+		return prefs, fmt.Errorf("reading preferences from %s: %w", path, err)
+	}
+	if file.Version != prefsVersion {
+		return prefs, fmt.Errorf("reading preferences from %s: unknown version %d", path, file.Version)
+	}
+	return fromPrefsFile(file, prefs), nil
+}
+
+// toPrefsFile converts preferences for writing
+func toPrefsFile(prefs Preferences) prefsFile {
+	return prefsFile{
+		Version:     prefsVersion,
+		Theme:       prefs.Theme,
+		Plain:       prefs.Plain,
+		Debug:       prefs.Debug,
+		Spelling:    prefs.Spelling.String(),
+		Naming:      prefs.Naming.String(),
+		Notation:    prefs.Notation.String(),
+		OctaveFold:  prefs.OctaveFold,
+		ReferenceA4: prefs.ReferenceA4,
+		CapoOffset:  prefs.CapoOffset,
+	}
+}
+
+// fromPrefsFile converts preferences as read, taking values it doesn't know
+// from fallback
+func fromPrefsFile(file prefsFile, fallback Preferences) Preferences {
+	prefs := fallback
+	prefs.Plain = file.Plain
+	prefs.Debug = file.Debug
+	prefs.OctaveFold = file.OctaveFold
+	if theme, ok := ThemeByName(file.Theme); ok {
+		prefs.Theme = theme.Name
+	}
+	if spelling, ok := findByName(pitch.Spellings, file.Spelling); ok {
+		prefs.Spelling = spelling
+	}
+	if naming, ok := findByName(pitch.NamingSchemes, file.Naming); ok {
+		prefs.Naming = naming
+	}
+	if notation, ok := findByName(pitch.Notations, file.Notation); ok {
+		prefs.Notation = notation
+	}
+	if file.ReferenceA4 >= pitch.MinReferenceA4 && file.ReferenceA4 <= pitch.MaxReferenceA4 {
+		prefs.ReferenceA4 = file.ReferenceA4
+	}
+	if file.CapoOffset >= pitch.MinCapoOffset && file.CapoOffset <= pitch.MaxCapoOffset {
+		prefs.CapoOffset = file.CapoOffset
+	}
+	return prefs
+}
+
+// findByName returns the choice displayed with the given name, ignoring case
+func findByName[T fmt.Stringer](choices []T, name string) (T, bool) {
+	for _, choice := range choices {
+		if strings.EqualFold(choice.String(), name) {
+			return choice, true
+		}
+	}
+	var none T
+	return none, false
+}
+
+// PreferencesSavedMsg reports the outcome of writing the preferences
+type PreferencesSavedMsg struct {
+	Err error // Why writing failed, nil on success
+}
+
+// savePrefsMsg asks for the preferences to be written unless they changed
+// again since it was scheduled
+type savePrefsMsg struct {
+	generation int
+}
+
+// savePreferences writes the preferences to path in the background. The file
+// is replaced whole, so a crash mid-write leaves the old one.
+func savePreferences(path string, prefs Preferences) tea.Cmd {
+	return func() tea.Msg {
+		data, err := json.MarshalIndent(toPrefsFile(prefs), "", "  ")
+		if err != nil {
+			return PreferencesSavedMsg{Err: err}
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return PreferencesSavedMsg{Err: err}
+		}
+
+		temp, err := os.CreateTemp(filepath.Dir(path), "prefs-*.json")
+		if err != nil {
+			return PreferencesSavedMsg{Err: err}
+		}
+		defer os.Remove(temp.Name())
+		if _, err := temp.Write(append(data, '\n')); err != nil {
+			temp.Close()
+			return PreferencesSavedMsg{Err: err}
+		}
+		if err := temp.Close(); err != nil {
+			return PreferencesSavedMsg{Err: err}
+		}
+		return PreferencesSavedMsg{Err: os.Rename(temp.Name(), path)}
+	}
+}
+
+// WithPreferences returns the model showing the given preferences. The
+// processing loop's converter and tracker must be set to the same reference
+// pitch, spelling, naming scheme, capo offset and octave folding.
+func (m Model) WithPreferences(prefs Preferences) Model {
+	theme, ok := ThemeByName(prefs.Theme)
+	if !ok {
+		theme = ThemeDefault
+	}
+	m.styles = newStyles(theme, prefs.Plain)
+	m.showDebug = prefs.Debug
+	m.spelling = prefs.Spelling
+	m.naming = prefs.Naming
+	m.notation = prefs.Notation
+	m.octaveFold = prefs.OctaveFold
+	m.referenceA4 = prefs.ReferenceA4
+	m.capoOffset = prefs.CapoOffset
+	m.scheduledPrefs = m.preferences()
+	return m
+}
+
+// WithPreferencesPath returns the model writing its preferences to path
+// shortly after they change; "" keeps them unsaved
+func (m Model) WithPreferencesPath(path string) Model {
+	m.prefsPath = path
+	return m
+}
+
+// preferences returns the model's current preferences
+func (m Model) preferences() Preferences {
+	return Preferences{
+		Theme:       m.styles.theme.Name,
+		Plain:       m.styles.plain,
+		Debug:       m.showDebug,
+		Spelling:    m.spelling,
+		Naming:      m.naming,
+		Notation:    m.notation,
+		OctaveFold:  m.octaveFold,
+		ReferenceA4: m.referenceA4,
+		CapoOffset:  m.capoOffset,
+	}
+}
+
+// savePreferencesLater schedules writing the preferences once they've gone
+// unchanged for prefsDebounce, so a run of changes is written once. It's
+// called after each key or click, alongside the command that handled it.
+func (m Model) savePreferencesLater(cmd tea.Cmd) (Model, tea.Cmd) {
+	prefs := m.preferences()
+	if m.prefsPath == "" || prefs == m.scheduledPrefs {
+		return m, cmd
+	}
+
+	m.scheduledPrefs = prefs
+	m.prefsGeneration++
+	generation := m.prefsGeneration
+	return m, tea.Batch(cmd, tea.Tick(prefsDebounce, func(time.Time) tea.Msg {
+		return savePrefsMsg{generation: generation}
+	}))
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// changedPreferences differs from the defaults in every field
+var changedPreferences = Preferences{
+	Theme:       ThemeHighContrast.Name,
+	Plain:       true,
+	Debug:       false,
+	Spelling:    pitch.SpellingFlats,
+	Naming:      pitch.NamingMovableDo,
+	Notation:    pitch.NotationGerman,
+	OctaveFold:  true,
+	ReferenceA4: 432.5,
+	CapoOffset:  -3,
+}
+
+// savePrefs writes preferences to path, failing the test on an error
+func savePrefs(t *testing.T, path string, prefs Preferences) {
+	t.Helper()
+	msg := savePreferences(path, prefs)()
+	if err := msg.(PreferencesSavedMsg).Err; err != nil {
+		t.Fatalf("saving preferences: %v", err)
+	}
+}
+
+func TestPreferencesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tunenote", "prefs.json")
+	savePrefs(t, path, changedPreferences)
+
+	got, err := LoadPreferences(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != changedPreferences {
+		t.Errorf("loaded %+v, want %+v", got, changedPreferences)
+	}
+
+	// The model shows every field and reports them back unchanged
+	if shown := NewModel(nil).WithPreferences(got).preferences(); shown != changedPreferences {
+		t.Errorf("model shows %+v, want %+v", shown, changedPreferences)
+	}
+}
+
+func TestLoadPreferencesFallsBack(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string // "" for no file
+		want    Preferences
+		wantErr bool
+	}{
+		{name: "missing file", want: DefaultPreferences()},
+		{name: "corrupt file", content: `{"version": 1, "theme": `, want: DefaultPreferences(), wantErr: true},
+		{name: "not an object", content: `[1, 2]`, want: DefaultPreferences(), wantErr: true},
+		{name: "newer version", content: `{"version": 2, "debug": false}`, want: DefaultPreferences(), wantErr: true},
+		{name: "no version", content: `{"debug": false}`, want: DefaultPreferences(), wantErr: true},
+		{
+			name:    "unknown fields and values",
+			content: `{"version": 1, "debug": false, "keymap": "vim", "theme": "Neon", "naming": "Numbers", "reference_a4": 9000, "capo_offset": 40}`,
+			want: func() Preferences {
+				prefs := DefaultPreferences()
+				prefs.Debug = false
+				return prefs
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".json")
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := LoadPreferences(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error %v, want one: %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("loaded %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreferencesDebounce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prefs.json")
+	m := NewModel(nil).WithPreferencesPath(path)
+
+	// Debug off, two themes on and flat note names in quick succession
+	for _, key := range []string{"d", "T", "T", "x"} {
+		next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = next.(Model)
+		if cmd == nil {
+			t.Fatalf("no save scheduled after %s", key)
+		}
+	}
+
+	// A key changing no preference schedules nothing
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	m = next.(Model)
+	if cmd != nil {
+		t.Error("save scheduled without a change")
+	}
+
+	// Of the scheduled saves only the last one writes
+	writes := 0
+	for generation := 1; generation <= m.prefsGeneration; generation++ {
+		_, cmd := m.Update(savePrefsMsg{generation: generation})
+		if cmd == nil {
+			continue
+		}
+		writes++
+		if err := cmd().(PreferencesSavedMsg).Err; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if writes != 1 {
+		t.Errorf("%d writes for a run of changes, want 1", writes)
+	}
+
+	got, err := LoadPreferences(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != m.preferences() {
+		t.Errorf("saved %+v, want %+v", got, m.preferences())
+	}
+}
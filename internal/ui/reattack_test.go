@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// sustainNotes sends the note events of notes dropping in and out of
+// detection without being attacked again, one a second from start, each
+// sounding for 900ms
+func sustainNotes(m Model, start time.Time, frequencies ...float64) Model {
+	converter := pitch.NewNoteConverter()
+	for i, frequency := range frequencies {
+		note := *converter.FromFrequency(frequency)
+		at := start.Add(time.Duration(i) * time.Second)
+		m = updateModel(m, NoteOnMsg{Note: note, At: at})
+		m = updateModel(m, NoteOffMsg{Note: note, At: at.Add(900 * time.Millisecond), Duration: 900 * time.Millisecond})
+	}
+	return m
+}
+
+func TestReattackedNotes(t *testing.T) {
+	start := time.Now().Add(-10 * time.Second)
+	tests := []struct {
+		name      string
+		play      func(m Model) Model
+		timeline  string
+		reattacks []bool
+		duration  time.Duration // Of the first entry
+	}{
+		{"sustained", func(m Model) Model {
+			return sustainNotes(m, start, 261.63, 261.63, 261.63, 261.63)
+		}, "C4", []bool{false}, 3900 * time.Millisecond},
+		{"re-attacked", func(m Model) Model {
+			return playNotes(m, start, 900*time.Millisecond, 100*time.Millisecond, 261.63, 261.63, 261.63, 261.63)
+		}, "C4 C4 C4 C4", []bool{false, true, true, true}, 900 * time.Millisecond},
+		{"attacked then sustained", func(m Model) Model {
+			m = playNotes(m, start, 900*time.Millisecond, 100*time.Millisecond, 261.63, 261.63)
+			return sustainNotes(m, start.Add(2*time.Second), 261.63, 261.63)
+		}, "C4 C4", []bool{false, true}, 900 * time.Millisecond},
+		{"another note between", func(m Model) Model {
+			return playNotes(m, start, 900*time.Millisecond, 100*time.Millisecond, 261.63, 293.66, 261.63)
+		}, "C4 D4 C4", []bool{false, false, false}, 900 * time.Millisecond},
+	}
+	for _, test := range tests {
+		m := test.play(sizedModel(100, 40).switchTab(tabTimeline))
+		m.timeline = m.timeline[5:] // Leave out sizedModel's notes
+		if got := timelineText(m.timeline, m.notation); got != test.timeline {
+			t.Errorf("%s: timeline %q, want %q", test.name, got, test.timeline)
+			continue
+		}
+		markers := 0
+		for i, entry := range m.timeline {
+			if entry.Reattack != test.reattacks[i] {
+				t.Errorf("%s: entry %d re-attacked %v, want %v", test.name, i, entry.Reattack, test.reattacks[i])
+			}
+			if test.reattacks[i] {
+				markers++
+			}
+		}
+		if m.timeline[0].Duration != test.duration {
+			t.Errorf("%s: first entry lasts %v, want %v", test.name, m.timeline[0].Duration, test.duration)
+		}
+
+		// Each re-attack is marked once at its left edge
+		view := ansi.Strip(m.View())
+		if got := strings.Count(view, "▏"); got != markers {
+			t.Errorf("%s: %d attack markers, want %d:\n%s", test.name, got, markers, view)
+		}
+	}
+
+	// The marker takes the entry's first column and keeps its width
+	st := newStyles(ThemeDefault, false)
+	entry := TimelineEntry{Note: pitch.NewNoteConverter().FromFrequency(261.63), Duration: time.Second}
+	plain := ansi.Strip(renderTimelineEntry(st, entry, 3, pitch.NotationScientific))
+	entry.Reattack = true
+	marked := ansi.Strip(renderTimelineEntry(st, entry, 3, pitch.NotationScientific))
+	if !strings.HasPrefix(marked, "▏") || len([]rune(marked)) != len([]rune(plain)) || !strings.Contains(marked, "C4") {
+		t.Errorf("marked entry %q, unmarked %q", marked, plain)
+	}
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// pressCommands presses a key and returns the model and every command the
+// key sent to the processing loop
+func pressCommands(m Model, commands chan Command, key string) (Model, []Command) {
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	if cmd != nil {
+		cmd()
+	}
+	var sent []Command
+	for len(commands) > 0 {
+		sent = append(sent, <-commands)
+	}
+	return next.(Model), sent
+}
+
+func TestReferenceKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		presses int
+		want    float64
+	}{
+		{"raise", "]", 3, 443},
+		{"lower", "[", 2, 438},
+		{"raise finely", "}", 10, 441},
+		{"lower finely", "{", 7, 439.3},
+		{"clamp high", "]", 50, 480},
+		{"clamp low", "[", 50, 400},
+		{"clamp high finely", "}", 500, 480},
+	}
+	for _, test := range tests {
+		commands := make(chan Command, 4)
+		m := sizedModel(100, 50)
+		m.commands = commands
+		for i := range test.presses {
+			var sent []Command
+			m, sent = pressCommands(m, commands, test.key)
+			if len(sent) != 1 {
+				t.Fatalf("%s, press %d: %d commands sent, want 1", test.name, i+1, len(sent))
+			}
+			if command, ok := sent[0].(SetReferenceA4Command); !ok || command.Hz != m.referenceA4 {
+				t.Fatalf("%s, press %d: sent %#v with the reference at %v", test.name, i+1, sent[0], m.referenceA4)
+			}
+		}
+
+		if m.referenceA4 != test.want {
+			t.Errorf("%s: reference %v Hz, want %v", test.name, m.referenceA4, test.want)
+		}
+		if want := fmt.Sprintf("Reference: A4 = %.1f Hz", test.want); !strings.Contains(ansi.Strip(m.View()), want) {
+			t.Errorf("%s: view lacks %q", test.name, want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// confirmScale starts practicing the typed scale, e.g. "G3 major", or
+// reports why it isn't one and lets it be corrected. The scale defaults to
+// major, and an empty one turns scale practice off.
+func (m Model) confirmScale() (Model, tea.Cmd) {
+	fields := strings.Fields(m.inputText)
+	if len(fields) == 0 {
+		m.typing = inputNone
+		if m.scale == nil {
+			return m, nil
+		}
+		m.scale = nil
+		return m.withStatus("Scale practice off", false), nil
+	}
+
+	root, err := pitch.ParseNote(fields[0])
+	if err != nil {
+		return m.withStatus(fmt.Sprintf("%q isn't a root note with an octave, e.g. G3 or Bb2", fields[0]), true), nil
+	}
+	scale := pitch.ScaleMajor
+	if len(fields) > 1 {
+		name := strings.Join(fields[1:], " ")
+		var ok bool
+		if scale, ok = pitch.ScaleByName(name); !ok {
+			names := make([]string, len(pitch.Scales))
+			for i, scale := range pitch.Scales {
+				names[i] = strings.ToLower(scale.Name)
+			}
+			return m.withStatus(fmt.Sprintf("Unknown scale %q; try %s", name, strings.Join(names, ", ")), true), nil
+		}
+	}
+
+	m.typing = inputNone
+	m.scale = pitch.NewScaleSession(scale, *root, m.scaleStrict)
+	m.scale.SetOctaveFold(m.octaveFold)
+	m.scaleMistake = nil
+	return m.withStatus(fmt.Sprintf("Practicing %s %s", m.notation.Name(root), scale.Name), false), nil
+}
+
+// toggleScaleStrict switches scale practice between counting every wrong
+// note as a mistake and forgiving passing notes
+func (m Model) toggleScaleStrict() Model {
+	m.scaleStrict = !m.scaleStrict
+	if m.scale != nil {
+		m.scale.SetStrict(m.scaleStrict)
+	}
+	return m.withStatus("Scale practice: "+strictnessLabel(m.scaleStrict), false)
+}
+
+// addScaleNote moves the practiced scale on with a newly played note
+func (m Model) addScaleNote(note pitch.Note, at time.Time) Model {
+	switch m.scale.Add(note, at) {
+	case pitch.ScaleMistake:
+		m.scaleMistake = &note
+	case pitch.ScaleAdvanced, pitch.ScaleCompleted:
+		m.scaleMistake = nil
+	}
+	return m
+}
+
+// strictnessLabel describes how scale practice treats passing notes
+func strictnessLabel(strict bool) string {
+	if strict {
+		return "strict"
+	}
+	return "lenient"
+}
+
+// renderScale renders the scale being practiced: its notes with those played
+// dimmed and the expected one highlighted, the time and mistakes so far, and
+// the latest wrong note or a summary once it's done
+func (m Model) renderScale() string {
+	sequence := m.scale.Sequence()
+	position := m.scale.Position()
+	root := sequence[0]
+	name := fmt.Sprintf("%s %s", m.notation.Name(&root), m.scale.Scale().Name)
+
+	header := fmt.Sprintf("Scale: %s (%s) | Mistakes: %d | %s",
+		name, strictnessLabel(m.scale.Strict()), m.scale.Mistakes(), m.scale.Elapsed(time.Now()).Round(time.Second))
+	lines := []string{m.styles.info.Bold(true).Render(header)}
+
+	notes := make([]string, len(sequence))
+	for i, note := range sequence {
+		label := m.notation.Format(&note)
+		switch {
+		case i < position:
+			notes[i] = m.styles.debug.Render(label)
+		case i == position:
+			notes[i] = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color(m.styles.theme.NoteText)).
+				Background(lipgloss.Color(m.styles.noteColor(&note))).
+				Render(" " + label + " ")
+		default:
+			notes[i] = m.styles.info.Render(label)
+		}
+	}
+	lines = append(lines, strings.Join(notes, " "))
+
+	switch {
+	case m.scale.Done():
+		lines = append(lines, statusStyle.Render(fmt.Sprintf("Finished %s in %s with %d mistakes (press w for another)",
+			name, m.scale.Elapsed(time.Now()).Round(time.Second), m.scale.Mistakes())))
+	case m.scaleMistake != nil:
+		expected := sequence[position]
+		lines = append(lines, gaugeOffStyle.Render(fmt.Sprintf("✗ %s, expected %s",
+			m.notation.Format(m.scaleMistake), m.notation.Format(&expected))))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// noteOns sends a NoteOn for each frequency, a second apart
+func noteOns(m Model, start time.Time, frequencies ...float64) Model {
+	converter := pitch.NewNoteConverter()
+	for i, frequency := range frequencies {
+		m = updateModel(m, NoteOnMsg{Note: *converter.FromFrequency(frequency), At: start.Add(time.Duration(i) * time.Second)})
+	}
+	return m
+}
+
+func TestRenderSession(t *testing.T) {
+	m := NewModel(nil).WithPlain(true)
+	if got := ansi.Strip(m.renderSession()); got != "Session: no notes yet" {
+		t.Errorf("empty session: %q", got)
+	}
+
+	// C4, E4, C4, G4, E4, C4, C5
+	m = noteOns(m, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), 261.63, 329.63, 261.63, 392, 329.63, 261.63, 523.25)
+	want := strings.Join([]string{
+		"Session: 7 notes, 4 distinct pitches, most played C4 (3×), 6s",
+		"  C  ██████████████████████████████ 4",
+		"  C#  0",
+		"  D   0",
+		"  D#  0",
+		"  E  ███████████████ 2",
+		"  F   0",
+		"  F#  0",
+		"  G  ████████ 1",
+		"  G#  0",
+		"  A   0",
+		"  A#  0",
+		"  B   0",
+	}, "\n")
+	lines := strings.Split(ansi.Strip(m.renderSession()), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ") // Padded to the widest line
+	}
+	if got := strings.Join(lines, "\n"); got != want {
+		t.Errorf("session summary\n got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSessionAccumulatesAndResets(t *testing.T) {
+	m := sizedModel(100, 30)
+	before := m.session.Summary().Notes
+
+	// Notes count while the timeline is frozen too
+	start := time.Now()
+	m = noteOns(m, start, 440, 493.88)
+	m = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	m = noteOns(m, start.Add(2*time.Second), 440)
+	if got := m.session.Summary(); got.Notes != before+3 || got.PitchClasses[9] < 2 {
+		t.Errorf("after three more notes: %d notes, %d As", got.Notes, got.PitchClasses[9])
+	}
+
+	// Clearing the history starts the session over
+	m = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if got := ansi.Strip(m.renderSession()); got != "Session: no notes yet" {
+		t.Errorf("after clearing: %q", got)
+	}
+	m = noteOns(m, start.Add(5*time.Second), 329.63)
+	if got := m.session.Summary(); got.Notes != 1 || got.Duration != 0 || got.MostPlayed.Name != "E" {
+		t.Errorf("after clearing and one note: %+v", got)
+	}
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Settings are the processing loop's settings adjustable in the settings
+// panel. The model must start with the values the loop starts with.
+type Settings struct {
+	Gain            float64 // Input amplification factor
+	SilenceDB       float64 // Level below which the input counts as silence (dB)
+	ConfidenceFloor float64 // Detections less confident than this are dropped (0.0-1.0)
+	AttackSettle    float64 // Relative level change per frame below which a note's attack has passed
+}
+
+// SetGainCommand asks the processing loop to change the input amplification
+type SetGainCommand struct {
+	Factor float64
+}
+
+// SetSilenceThresholdCommand asks the processing loop to change the level
+// below which the input counts as silence
+type SetSilenceThresholdCommand struct {
+	DB float64
+}
+
+// SetConfidenceFloorCommand asks the processing loop to change the
+// confidence below which detections are dropped
+type SetConfidenceFloorCommand struct {
+	Confidence float64
+}
+
+// SetAttackSettleCommand asks the processing loop to change the level change
+// below which a note's attack counts as passed
+type SetAttackSettleCommand struct {
+	Change float64
+}
+
+// setting is one row of the settings panel
+type setting struct {
+	name      string
+	format    string  // Format of the value, e.g. "%.1f×"
+	low, high float64 // Range the value is clamped to
+	step      float64 // Change per press of +/-
+
+	value   func(s *Settings) *float64  // Field of Settings the row adjusts
+	command func(value float64) Command // Command applying a new value
+}
+
+// settingRows lists the settings panel's rows, top to bottom
+var settingRows = []setting{
+	{
+		name: "Input gain", format: "%.1f×", low: 1, high: 20, step: 0.5,
+		value:   func(s *Settings) *float64 { return &s.Gain },
+		command: func(value float64) Command { return SetGainCommand{Factor: value} },
+	},
+	{
+		name: "Silence threshold", format: "%.0f dB", low: -70, high: -10, step: 2,
+		value:   func(s *Settings) *float64 { return &s.SilenceDB },
+		command: func(value float64) Command { return SetSilenceThresholdCommand{DB: value} },
+	},
+	{
+		name: "Confidence floor", format: "%.2f", low: 0.1, high: 0.95, step: 0.05,
+		value:   func(s *Settings) *float64 { return &s.ConfidenceFloor },
+		command: func(value float64) Command { return SetConfidenceFloorCommand{Confidence: value} },
+	},
+	{
+		name: "Attack settle", format: "%.2f", low: 0.05, high: 1, step: 0.05,
+		value:   func(s *Settings) *float64 { return &s.AttackSettle },
+		command: func(value float64) Command { return SetAttackSettleCommand{Change: value} },
+	},
+}
+
+// WithSettings returns the model showing the processing loop's initial
+// settings in the settings panel
+func (m Model) WithSettings(settings Settings) Model {
+	m.settings = settings
+	return m
+}
+
+// selectSetting moves the settings panel's selection by the given number of
+// rows, stopping at the ends
+func (m Model) selectSetting(delta int) Model {
+	m.settingIndex = max(0, min(m.settingIndex+delta, len(settingRows)-1))
+	return m
+}
+
+// adjustSetting changes the selected setting by the given number of steps and
+// sends the new value to the processing loop. Values stop at the ends of the
+// setting's range, which the status line points out.
+func (m Model) adjustSetting(steps int) (Model, tea.Cmd) {
+	row := settingRows[m.settingIndex]
+	value := row.value(&m.settings)
+
+	// Round to the step so repeated adjustments don't accumulate float error
+	wanted := math.Round((*value+float64(steps)*row.step)/row.step) * row.step
+	clamped := math.Max(row.low, math.Min(wanted, row.high))
+	if clamped != wanted {
+		limit := "minimum"
+		if wanted > row.high {
+			limit = "maximum"
+		}
+		m = m.withStatus(fmt.Sprintf("%s is at its %s of "+row.format, row.name, limit, clamped), true)
+	}
+	if clamped == *value {
+		return m, nil
+	}
+
+	*value = clamped
+	return m, m.sendCommand(row.command(clamped))
+}
+
+// renderSettings renders the settings panel, marking the selected row and
+// values at the ends of their range
+func (m Model) renderSettings() string {
+	nameWidth := 0
+	for _, row := range settingRows {
+		nameWidth = max(nameWidth, len(row.name))
+	}
+
+	settings := m.settings
+	lines := []string{m.styles.info.Bold(true).Render("Settings (↑/↓ to select, +/- to adjust)")}
+	for i, row := range settingRows {
+		value := *row.value(&settings)
+		text := fmt.Sprintf(row.format, value)
+		if value <= row.low || value >= row.high {
+			text = gaugeOffStyle.Render(text)
+		}
+
+		marker := "  "
+		name := m.styles.info.Render(fmt.Sprintf("%-*s", nameWidth, row.name))
+		if i == m.settingIndex {
+			marker = "▸ "
+			name = activeStringStyle.Render(fmt.Sprintf("%-*s", nameWidth, row.name))
+		}
+		limits := m.styles.debug.Render(fmt.Sprintf("  ["+row.format+" – "+row.format+"]", row.low, row.high))
+		lines = append(lines, m.styles.info.Render(marker)+name+"  "+text+limits)
+	}
+	return strings.Join(lines, "\n")
+}
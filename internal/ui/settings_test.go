@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// settingsKeys are the keys the settings panel answers to
+var settingsKeys = map[string]tea.KeyMsg{
+	"S":    {Type: tea.KeyRunes, Runes: []rune("S")},
+	"up":   {Type: tea.KeyUp},
+	"down": {Type: tea.KeyDown},
+	"+":    {Type: tea.KeyRunes, Runes: []rune("+")},
+	"-":    {Type: tea.KeyRunes, Runes: []rune("-")},
+}
+
+// pressSending presses a key and returns the command it sent to the
+// processing loop, nil for none
+func pressSending(t *testing.T, m Model, commands chan Command, key string) (Model, Command) {
+	t.Helper()
+	next, cmd := m.Update(settingsKeys[key])
+	if cmd != nil {
+		cmd()
+	}
+	select {
+	case command := <-commands:
+		return next.(Model), command
+	default:
+		return next.(Model), nil
+	}
+}
+
+func TestSettingsPanel(t *testing.T) {
+	commands := make(chan Command, 1)
+	m := NewModel(commands).WithSettings(Settings{Gain: 1.5, SilenceDB: -40, ConfidenceFloor: 0.5, AttackSettle: 0.15})
+
+	tests := []struct {
+		key     string
+		index   int     // Selected row after the key
+		command Command // Sent by the key, nil for none
+		status  string  // Status shown after the key, empty for none
+	}{
+		{"+", 0, nil, ""}, // Panel closed
+		{"down", 0, nil, ""},
+		{"S", 0, nil, ""},
+		{"-", 0, SetGainCommand{Factor: 1}, ""},
+		{"-", 0, nil, "Input gain is at its minimum of 1.0×"},
+		{"+", 0, SetGainCommand{Factor: 1.5}, ""},
+		{"up", 0, nil, ""}, // Already at the top
+		{"down", 1, nil, ""},
+		{"-", 1, SetSilenceThresholdCommand{DB: -42}, ""},
+		{"down", 2, nil, ""},
+		{"+", 2, SetConfidenceFloorCommand{Confidence: 0.55}, ""},
+		{"down", 3, nil, ""},
+		{"down", 3, nil, ""}, // Already at the bottom
+		{"+", 3, SetAttackSettleCommand{Change: 0.2}, ""},
+		{"up", 2, nil, ""},
+	}
+	for i, tt := range tests {
+		var command Command
+		m.status = ""
+		m, command = pressSending(t, m, commands, tt.key)
+		if m.settingIndex != tt.index || command != tt.command || m.status != tt.status {
+			t.Errorf("key %d (%s): row %d, sent %#v, status %q; want row %d, %#v, %q", i, tt.key, m.settingIndex, command, m.status, tt.index, tt.command, tt.status)
+		}
+	}
+
+	// The panel shows the values, marking the selected row
+	panel := ansi.Strip(m.renderSettings())
+	for _, want := range []string{"  Input gain         1.5×  [1.0× – 20.0×]", "  Silence threshold  -42 dB", "▸ Confidence floor   0.55", "  Attack settle      0.20"} {
+		if !strings.Contains(panel, want) {
+			t.Errorf("panel lacks %q:\n%s", want, panel)
+		}
+	}
+}
+
+func TestSettingsStepWithoutDrift(t *testing.T) {
+	// Stepping the confidence floor from its top down to its bottom lands
+	// on each step exactly, and stops at both ends
+	commands := make(chan Command, 1)
+	m := NewModel(commands).WithSettings(Settings{Gain: 20, SilenceDB: -10, ConfidenceFloor: 0.95, AttackSettle: 0.05})
+	m, _ = pressSending(t, m, commands, "S")
+	m, command := pressSending(t, m, commands, "+")
+	if command != nil || m.status != "Input gain is at its maximum of 20.0×" {
+		t.Errorf("gain at the top: sent %#v, status %q", command, m.status)
+	}
+
+	m, _ = pressSending(t, m, commands, "down")
+	m, _ = pressSending(t, m, commands, "down")
+	var sent []float64
+	for i := 0; i < 20; i++ {
+		m, command = pressSending(t, m, commands, "-")
+		if command != nil {
+			sent = append(sent, command.(SetConfidenceFloorCommand).Confidence)
+		}
+	}
+	if len(sent) != 17 || m.settings.ConfidenceFloor != sent[len(sent)-1] {
+		t.Fatalf("stepped down through %v to %v", sent, m.settings.ConfidenceFloor)
+	}
+	for i, value := range sent {
+		if want := float64(18-i) * 0.05; value != want {
+			t.Errorf("step %d sent %v, want %v", i, value, want)
+		}
+	}
+}
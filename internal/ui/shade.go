@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Octave shading of the timeline's colors
+const (
+	shadedOctave = 4    // Octave drawn in the note colors as they are
+	octaveShade  = 0.22 // Share of the way to black or white each octave further away moves a color
+	maxShade     = 0.88 // Furthest a color is moved, so far octaves keep a trace of their hue
+	textContrast = 0.3  // Lightness between names and a shaded color that keeps them readable
+	legendOctave = 1    // Lowest octave the legend shows
+	legendSpan   = 7    // Octaves the legend shows
+)
+
+// octaveColor returns a note's color shaded for its octave: darker below
+// octave 4 and lighter above it, keeping the hue of its pitch class
+func (st styles) octaveColor(note *pitch.Note) string {
+	amount := float64(note.Octave-shadedOctave) * octaveShade
+	return shadeColor(st.noteColor(note), math.Max(-maxShade, math.Min(amount, maxShade)))
+}
+
+// textOn returns the color of a note's name on its octave color: the
+// theme's, unless shading has brought the background closer to it than the
+// note color itself is, and too close to read
+func (st styles) textOn(note *pitch.Note) string {
+	_, _, text := hexToHSL(st.theme.NoteText)
+	_, _, base := hexToHSL(st.noteColor(note))
+	_, _, lightness := hexToHSL(st.octaveColor(note))
+	switch {
+	case math.Abs(lightness-text) >= math.Min(math.Abs(base-text), textContrast):
+		return st.theme.NoteText
+	case lightness > 0.5:
+		return "#1A1A1A"
+	default:
+		return "#FAFAFA"
+	}
+}
+
+// renderOctaveLegend renders the shade of each octave, in the color of C
+func renderOctaveLegend(st styles) string {
+	cells := make([]string, legendSpan)
+	for i := range cells {
+		note := pitch.Note{Name: "C", Octave: legendOctave + i}
+		cells[i] = lipgloss.NewStyle().
+			Background(lipgloss.Color(st.octaveColor(&note))).
+			Foreground(lipgloss.Color(st.textOn(&note))).
+			Render(fmt.Sprintf(" %d ", note.Octave))
+	}
+	return st.info.Render("Octaves: ") + strings.Join(cells, "")
+}
+
+// shadeColor moves a "#RRGGBB" color's lightness part of the way to white,
+// for a positive amount up to 1, or to black, for a negative one
+func shadeColor(hex string, amount float64) string {
+	hue, saturation, lightness := hexToHSL(hex)
+	if amount >= 0 {
+		lightness += (1 - lightness) * amount
+	} else {
+		lightness += lightness * amount
+	}
+	return hslToHex(hue, saturation, lightness)
+}
+
+// hexToHSL converts a "#RRGGBB" color to hue (0-360), saturation and
+// lightness (0-1). Colors that don't parse come back black.
+func hexToHSL(hex string) (hue, saturation, lightness float64) {
+	rgb, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil {
+		return 0, 0, 0
+	}
+	r := float64(rgb>>16&0xFF) / 255
+	g := float64(rgb>>8&0xFF) / 255
+	b := float64(rgb&0xFF) / 255
+
+	high := math.Max(r, math.Max(g, b))
+	low := math.Min(r, math.Min(g, b))
+	lightness = (high + low) / 2
+	if high == low {
+		return 0, 0, lightness // Gray
+	}
+
+	chroma := high - low
+	saturation = chroma / (1 - math.Abs(2*lightness-1))
+	switch high {
+	case r:
+		hue = math.Mod((g-b)/chroma, 6)
+	case g:
+		hue = (b-r)/chroma + 2
+	default:
+		hue = (r-g)/chroma + 4
+	}
+	hue *= 60
+	if hue < 0 {
+		hue += 360
+	}
+	return hue, saturation, lightness
+}
+
+// hslToHex converts hue (0-360), saturation and lightness (0-1) to a
+// "#RRGGBB" color
+func hslToHex(hue, saturation, lightness float64) string {
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case hue < 60:
+		r, g = chroma, x
+	case hue < 120:
+		r, g = x, chroma
+	case hue < 180:
+		g, b = chroma, x
+	case hue < 240:
+		g, b = x, chroma
+	case hue < 300:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+
+	m := lightness - chroma/2
+	channel := func(v float64) int {
+		return int(math.Round(math.Max(0, math.Min(v+m, 1)) * 255))
+	}
+	return fmt.Sprintf("#%02X%02X%02X", channel(r), channel(g), channel(b))
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOctaveShadesLighten(t *testing.T) {
+	converter := pitch.NewNoteConverter()
+	for _, theme := range Themes {
+		st := newStyles(theme, false)
+		for _, frequency := range []float64{261.63, 277.18, 440, 493.88} {
+			previous, lightness := "", -1.0
+			for octave := -2; octave <= 3; octave++ {
+				note := converter.FromFrequency(frequency * math.Pow(2, float64(octave)))
+				color := st.octaveColor(note)
+				_, _, shade := hexToHSL(color)
+				// Only a color already lightened to white can't get lighter
+				if shade < lightness || (shade == lightness && !strings.EqualFold(color, "#FFFFFF")) {
+					t.Errorf("%s: %s is %s, lightness %.3f after %s at %.3f", theme.Name, note.Name+string(rune('0'+note.Octave)), color, shade, previous, lightness)
+				}
+				previous, lightness = color, shade
+
+				// Octave 4 keeps the note color, and every octave its hue
+				base := st.noteColor(note)
+				if note.Octave == shadedOctave && !strings.EqualFold(color, base) {
+					t.Errorf("%s: %s%d shaded to %s from %s", theme.Name, note.Name, note.Octave, color, base)
+				}
+				baseHue, saturation, _ := hexToHSL(base)
+				if hue, _, _ := hexToHSL(color); saturation > 0.1 && math.Abs(math.Remainder(hue-baseHue, 360)) > 3 {
+					t.Errorf("%s: %s%d hue %.0f°, note color's %.0f°", theme.Name, note.Name, note.Octave, hue, baseHue)
+				}
+			}
+		}
+	}
+}
+
+func TestHSLRoundTrip(t *testing.T) {
+	for _, hex := range []string{"#000000", "#FFFFFF", "#808080", "#FF0000", "#00FF00", "#0000FF", "#7D56F4", "#B64040", "#43A047", "#E5C07B"} {
+		if got := hslToHex(hexToHSL(hex)); got != hex {
+			t.Errorf("%s came back as %s", hex, got)
+		}
+	}
+	if got := shadeColor("#B64040", 0); got != "#B64040" {
+		t.Errorf("unshaded color %s", got)
+	}
+	if got := shadeColor("#B64040", 1); got != "#FFFFFF" {
+		t.Errorf("color shaded all the way to white: %s", got)
+	}
+	if got := shadeColor("#B64040", -1); got != "#000000" {
+		t.Errorf("color shaded all the way to black: %s", got)
+	}
+}
+
+func TestTimelineShadesByOctave(t *testing.T) {
+	withTrueColor(t)
+	m := updateModel(NewModel(nil), tea.WindowSizeMsg{Width: 100, Height: 50})
+	m = noteOns(m, time.Now().Add(-5*time.Second), 130.81, 261.63, 523.25) // C3, C4, C5
+	m = m.switchTab(tabTimeline)
+
+	var shades []string
+	for _, entry := range m.timeline {
+		shades = append(shades, m.styles.octaveColor(entry.Note))
+	}
+	if len(slices.Compact(slices.Clone(shades))) != 3 {
+		t.Fatalf("C3, C4 and C5 shaded %q", shades)
+	}
+	for _, line := range strings.Split(m.View(), "\n") {
+		if !strings.Contains(line, "C3") {
+			continue
+		}
+		for i, shade := range shades {
+			if len(styledColumns(line, background(shade))) == 0 {
+				t.Errorf("%s block not drawn in %s: %q", m.timeline[i].Note.Name, shade, line)
+			}
+		}
+		return
+	}
+	t.Errorf("no timeline line with C3")
+}
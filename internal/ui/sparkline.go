@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"math"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Cents sparkline settings
+const (
+	centsHistorySize = 256             // Readings kept, several seconds' worth at the update rate
+	sparkWindow      = 4 * time.Second // Time the sparkline spans
+	sparkLevels      = 7               // Braille dot rows used, +50 cents at the top and -50 at the bottom
+)
+
+// Braille dots by dot row (top first) for the left and right column of a cell
+var brailleDots = [2][4]rune{{0x01, 0x02, 0x04, 0x40}, {0x08, 0x10, 0x20, 0x80}}
+
+// centsPoint is a cents reading of the held note
+type centsPoint struct {
+	at    time.Time
+	cents float64
+}
+
+// centsHistory is a ring buffer of the held note's recent cents readings
+type centsHistory struct {
+	points [centsHistorySize]centsPoint
+	next   int // Where the next reading goes
+	count  int // Readings held, up to centsHistorySize
+}
+
+// add records a reading, overwriting the oldest once the buffer is full
+func (h *centsHistory) add(at time.Time, cents float64) {
+	h.points[h.next] = centsPoint{at: at, cents: cents}
+	h.next = (h.next + 1) % centsHistorySize
+	h.count = min(h.count+1, centsHistorySize)
+}
+
+// reset forgets every reading, e.g. when the note changes
+func (h *centsHistory) reset() {
+	h.next, h.count = 0, 0
+}
+
+// since returns the readings taken at or after the given time, oldest first
+func (h *centsHistory) since(from time.Time) []centsPoint {
+	var points []centsPoint
+	for i := h.count; i > 0; i-- {
+		point := h.points[(h.next-i+centsHistorySize)%centsHistorySize]
+		if !point.at.Before(from) {
+			points = append(points, point)
+		}
+	}
+	return points
+}
+
+// renderCentsSpark renders the readings of the last sparkWindow before now as
+// two rows of braille, each cell holding two time slots, spanning ±50 cents
+// over cells columns with a dotted zero line. Slots without a reading stay
+// blank; those with several show their mean. The ♯/♭ row labels are
+// right-aligned to the given width, to line up with the gauge.
+func renderCentsSpark(st styles, points []centsPoint, now time.Time, cells, labelWidth int) string {
+	slots := cells * 2
+	sums := make([]float64, slots)
+	counts := make([]int, slots)
+	start := now.Add(-sparkWindow)
+	for _, point := range points {
+		slot := int(math.Floor(float64(point.at.Sub(start)) / float64(sparkWindow) * float64(slots)))
+		if slot < 0 || slot >= slots {
+			continue
+		}
+		sums[slot] += point.cents
+		counts[slot]++
+	}
+
+	// Dot rows 0-6 from +50 cents down to -50, zero on row 3; each text row
+	// holds four
+	const zeroRow = sparkLevels / 2
+	rows := [2][]rune{make([]rune, cells), make([]rune, cells)}
+	for cell := range cells {
+		dots := [2]rune{}
+		dots[zeroRow/4] |= brailleDots[0][zeroRow%4] // The zero line, dotted
+
+		for side := range 2 {
+			slot := cell*2 + side
+			if counts[slot] == 0 {
+				continue
+			}
+			cents := math.Max(-gaugeRange, math.Min(sums[slot]/float64(counts[slot]), gaugeRange))
+			row := int(math.Round((gaugeRange - cents) / (2 * gaugeRange) * (sparkLevels - 1)))
+			dots[row/4] |= brailleDots[side][row%4]
+		}
+		rows[0][cell] = 0x2800 + dots[0]
+		rows[1][cell] = 0x2800 + dots[1]
+	}
+
+	label := st.info.Width(labelWidth).Align(lipgloss.Right)
+	return label.Render("♯") + " " + st.debug.Render(string(rows[0])) + "\n" +
+		label.Render("♭") + " " + st.debug.Render(string(rows[1]))
+}
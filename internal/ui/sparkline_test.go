@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderCentsSpark(t *testing.T) {
+	st := newStyles(ThemeDefault, true)
+	now := time.Unix(100, 0)
+	start := now.Add(-sparkWindow)
+
+	// Four cells hold eight half-second slots; each reading falls mid-slot
+	slotted := func(cents ...float64) []centsPoint {
+		var points []centsPoint
+		for slot, c := range cents {
+			if c == 999 {
+				continue // No reading
+			}
+			points = append(points, centsPoint{at: start.Add(time.Duration(slot)*500*time.Millisecond + 250*time.Millisecond), cents: c})
+		}
+		return points
+	}
+	tests := []struct {
+		name   string
+		points []centsPoint
+		want   string
+	}{
+		{"no readings", nil, "  ♯ ⡀⡀⡀⡀\n  ♭ ⠀⠀⠀⠀"},
+		{"in tune", slotted(0, 0, 0, 0, 0, 0, 0, 0), "  ♯ ⣀⣀⣀⣀\n  ♭ ⠀⠀⠀⠀"},
+		{"every dot row", slotted(50, 35, 0, -17, -50, 999, -60, 20), "  ♯ ⡑⡀⡀⡠\n  ♭ ⠀⠈⠄⠄"},
+		{"falling", slotted(45, 30, 15, 0, -15, -30, -45, 999), "  ♯ ⡑⣄⡀⡀\n  ♭ ⠀⠀⠑⠄"},
+		{"slot mean", []centsPoint{
+			{at: start.Add(100 * time.Millisecond), cents: 50},
+			{at: start.Add(400 * time.Millisecond), cents: 0},
+		}, "  ♯ ⡄⡀⡀⡀\n  ♭ ⠀⠀⠀⠀"},
+		{"outside window", []centsPoint{
+			{at: start.Add(-time.Millisecond), cents: 50},
+			{at: now, cents: 50},
+		}, "  ♯ ⡀⡀⡀⡀\n  ♭ ⠀⠀⠀⠀"},
+	}
+	for _, test := range tests {
+		if got := ansi.Strip(renderCentsSpark(st, test.points, now, 4, 3)); got != test.want {
+			t.Errorf("%s:\n%s\nwant\n%s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCentsHistoryRing(t *testing.T) {
+	var history centsHistory
+	start := time.Unix(0, 0)
+	for i := range centsHistorySize + 44 {
+		history.add(start.Add(time.Duration(i)*time.Millisecond), float64(i))
+	}
+
+	// The oldest readings are overwritten, and the rest come back in order
+	points := history.since(start)
+	if len(points) != centsHistorySize || points[0].cents != 44 || points[len(points)-1].cents != centsHistorySize+43 {
+		t.Fatalf("%d readings from %v to %v", len(points), points[0].cents, points[len(points)-1].cents)
+	}
+	if points := history.since(start.Add(290 * time.Millisecond)); len(points) != 10 || points[0].cents != 290 {
+		t.Errorf("readings since 290ms: %d from %v", len(points), points[0].cents)
+	}
+
+	history.reset()
+	if points := history.since(start); len(points) != 0 {
+		t.Errorf("%d readings after reset", len(points))
+	}
+}
+
+func TestCentsHistoryResetsOnNoteChange(t *testing.T) {
+	converter := pitch.NewNoteConverter()
+	play := func(m Model, frequency, cents float64) Model {
+		note := *converter.FromFrequency(frequency)
+		note.Cents = cents
+		return updateModel(m, UpdateNoteMsg(note))
+	}
+	readings := func(m Model) []float64 {
+		var cents []float64
+		for _, point := range m.centsHistory.since(time.Time{}) {
+			cents = append(cents, point.cents)
+		}
+		return cents
+	}
+
+	m := NewModel(nil)
+	for _, cents := range []float64{-10, -5, 0, 5} {
+		m = play(m, 440, cents)
+	}
+	if got := readings(m); len(got) != 4 || got[0] != -10 || got[3] != 5 {
+		t.Fatalf("A4 readings %v, want [-10 -5 0 5]", got)
+	}
+
+	// A new note starts over
+	m = play(m, 466.16, 12)
+	if got := readings(m); len(got) != 1 || got[0] != 12 {
+		t.Errorf("readings after moving to A#4: %v, want [12]", got)
+	}
+
+	// So does the same note played again after it was released
+	m = play(m, 466.16, 8)
+	m = updateModel(m, ClearNoteMsg{})
+	m = play(m, 466.16, -3)
+	if got := readings(m); len(got) != 1 || got[0] != -3 {
+		t.Errorf("readings after replaying A#4: %v, want [-3]", got)
+	}
+}
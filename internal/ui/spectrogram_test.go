@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/audio"
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+func TestSpectrogramLightsNoteRow(t *testing.T) {
+	// The cells are styled once, so style them afresh in color and again
+	// for whichever test comes next
+	withTrueColor(t)
+	spectrogramCellsOnce = sync.Once{}
+	t.Cleanup(func() { spectrogramCellsOnce = sync.Once{} })
+
+	detector, err := pitch.NewFFTDetector(4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := audio.SynthesizeTone(440, []float64{1}, 4096, 44100)
+	spectrum, err := detector.Spectrum(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scale := pitch.DefaultSpectrogramScale
+	spectra := UpdateSpectrumMsg{Scale: scale, Levels: scale.Levels(spectrum, buffer.SampleRate)}
+
+	// 440 Hz falls in band 21 of 40, the upper half of the tenth line from
+	// the top, which alone is drawn in the brightest shade
+	const row = 9
+	if band := scale.Band(440); band != 39-2*row {
+		t.Fatalf("440 Hz in band %d", band)
+	}
+	brightest := termenv.TrueColor.Color(spectrogramShades[len(spectrogramShades)-1]).Sequence(false)
+	marker := termenv.TrueColor.Color(spectrogramMarker).Sequence(false)
+
+	for _, playing := range []bool{false, true} {
+		var m tea.Model = NewModel(nil)
+		m, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+		if playing {
+			m, _ = m.Update(UpdateNoteMsg(*pitch.NewNoteConverter().FromFrequency(440)))
+		}
+		for i := 0; i < 10; i++ {
+			m, _ = m.Update(spectra)
+		}
+		model := m.(Model)
+
+		lines := strings.Split(renderSpectrogram(model.styles, model.spectrogram, model.spectrogramScale, 100), "\n")
+		if len(lines) != scale.Bands/2 {
+			t.Fatalf("playing %v: %d lines, want %d", playing, len(lines), scale.Bands/2)
+		}
+
+		// Frames taken while the note shows mark its band instead
+		lit := brightest
+		if playing {
+			lit = marker
+		}
+		for i, line := range lines {
+			want := 0
+			if i == row {
+				want = 10 // Every frame
+			}
+			if got := len(styledColumns(line, lit)); got != want {
+				t.Errorf("playing %v: line %d lights %d columns, want %d", playing, i, got, want)
+			}
+		}
+	}
+}
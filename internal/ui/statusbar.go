@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// minDeviceWidth is the narrowest the device name is cut to before the
+// status bar leaves it out
+const minDeviceWidth = 6
+
+// UpdateStatsMsg is a message to update the status bar with the input
+// stream and how the processing loop keeps up with it
+type UpdateStatsMsg struct {
+	Device           string        // Name of the input device
+	SampleRate       int           // Sample rate in Hz
+	Latency          time.Duration // Estimated time from sound to detection
+	DetectionsPerSec float64       // Detections made per second
+	DroppedPercent   float64       // Share of captured blocks never analyzed
+}
+
+// renderStatusBar renders the input stream's figures on one line, "" until
+// they arrive. On a narrow terminal the device name is cut short first, then
+// left out.
+func (m Model) renderStatusBar() string {
+	if m.stats.SampleRate == 0 {
+		return ""
+	}
+
+	figures := strings.Join([]string{
+		fmt.Sprintf("%.1f kHz", float64(m.stats.SampleRate)/1000),
+		fmt.Sprintf("latency %d ms", m.stats.Latency.Milliseconds()),
+		fmt.Sprintf("%.1f det/s", m.stats.DetectionsPerSec),
+		fmt.Sprintf("%.1f%% dropped", m.stats.DroppedPercent),
+	}, " · ")
+
+	device := m.stats.Device
+	if device == "" {
+		device = "unknown input"
+	}
+	if width := m.columnWidth(); width > 0 {
+		room := width - lipgloss.Width(figures) - lipgloss.Width(" · ")
+		switch {
+		case room < minDeviceWidth:
+			device = ""
+		case lipgloss.Width(device) > room:
+			device = ansi.Truncate(device, room, "…")
+		}
+	}
+
+	line := figures
+	if device != "" {
+		line = device + " · " + figures
+	}
+	if width := m.columnWidth(); width > 0 {
+		line = lipgloss.NewStyle().MaxWidth(width).Render(line)
+	}
+	return m.styles.debug.Render(line)
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestStatusBarFormatting(t *testing.T) {
+	tests := []struct {
+		stats UpdateStatsMsg
+		want  string
+	}{
+		{UpdateStatsMsg{}, ""},
+		{UpdateStatsMsg{Device: "Built-in Microphone", SampleRate: 44100, Latency: 23700 * time.Microsecond, DetectionsPerSec: 21.53, DroppedPercent: 0.25},
+			"Built-in Microphone · 44.1 kHz · latency 23 ms · 21.5 det/s · 0.2% dropped"},
+		{UpdateStatsMsg{Device: "USB Audio", SampleRate: 48000, Latency: 120 * time.Millisecond, DetectionsPerSec: 9, DroppedPercent: 12.5},
+			"USB Audio · 48.0 kHz · latency 120 ms · 9.0 det/s · 12.5% dropped"},
+		{UpdateStatsMsg{SampleRate: 96000}, "unknown input · 96.0 kHz · latency 0 ms · 0.0 det/s · 0.0% dropped"},
+	}
+	for _, test := range tests {
+		m := updateModel(NewModel(nil), test.stats)
+		if got := ansi.Strip(m.renderStatusBar()); got != test.want {
+			t.Errorf("%+v:\n%q\nwant\n%q", test.stats, got, test.want)
+		}
+	}
+}
+
+func TestStatusBarTruncation(t *testing.T) {
+	// The figures take 52 columns, and a separator 3 more
+	stats := UpdateStatsMsg{
+		Device:           "Scarlett 2i2 USB Audio Interface Analog Stereo Input",
+		SampleRate:       44100,
+		Latency:          23 * time.Millisecond,
+		DetectionsPerSec: 21.5,
+		DroppedPercent:   0.3,
+	}
+	const figures = "44.1 kHz · latency 23 ms · 21.5 det/s · 0.3% dropped"
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{120, stats.Device + " · " + figures},
+		{107, stats.Device + " · " + figures},
+		{106, "Scarlett 2i2 USB Audio Interface Analog Stereo Inp… · " + figures},
+		{80, "Scarlett 2i2 USB Audio I… · " + figures},
+		{61, "Scarl… · " + figures},
+		{60, figures}, // Too little room left to name the device
+		{52, figures},
+		{45, "44.1 kHz · latency 23 ms · 21.5 det/s · 0.3% "},
+	}
+	for _, test := range tests {
+		m := updateModel(NewModel(nil), stats)
+		m.width = test.width
+		bar := m.renderStatusBar()
+		if got := ansi.Strip(bar); got != test.want {
+			t.Errorf("%d wide:\n%q\nwant\n%q", test.width, got, test.want)
+		}
+		if width := lipgloss.Width(bar); width > test.width {
+			t.Errorf("%d wide: bar is %d wide", test.width, width)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tab is one of the views the UI switches between
+type tab int
+
+// Tabs in the order the tab bar shows them
+const (
+	tabTuner    tab = iota // The note, its gauge and the keyboard
+	tabTimeline            // The history across the full width
+	tabStats               // Session stats and the intonation report
+	tabSpectrum            // The spectrogram and waveform
+	tabCount
+)
+
+// tabNames are the tab bar's labels of the tabs
+var tabNames = [tabCount]string{"Tuner", "Timeline", "Stats", "Spectrum"}
+
+// switchTab shows a tab, counting on past the last one back to the first
+// and back before the first to the last
+func (m Model) switchTab(to tab) Model {
+	m.tab = (to%tabCount + tabCount) % tabCount
+	return m
+}
+
+// toggleTab shows a tab, or the Tuner tab if it's already shown
+func (m Model) toggleTab(to tab) Model {
+	if m.tab == to {
+		return m.switchTab(tabTuner)
+	}
+	return m.switchTab(to)
+}
+
+// renderTabBar renders the tabs with their numbers, the one shown
+// highlighted, bracketed when drawn in plain ASCII
+func (m Model) renderTabBar() string {
+	labels := make([]string, tabCount)
+	for i, name := range tabNames {
+		label := fmt.Sprintf("%d %s", i+1, name)
+		switch {
+		case tab(i) != m.tab:
+			labels[i] = m.styles.info.Padding(0, 1).Render(label)
+		case m.styles.plain:
+			labels[i] = "[" + label + "]"
+		default:
+			labels[i] = m.styles.title.UnsetMarginBottom().Padding(0, 1).Render(label)
+		}
+	}
+	return strings.Join(labels, m.styles.debug.Render("│"))
+}
+
+// renderStatsTab renders the Stats tab: the notes played over the session,
+// how in tune each was and how the tuning drifted
+func (m Model) renderStatsTab() string {
+	s := m.renderSession()
+	s += "\n"
+	s += m.renderIntonation()
+	s += "\n"
+	s += m.renderDrift()
+	s += "\n"
+	return s
+}
+
+// renderSpectrumTab renders the Spectrum tab: the recent spectra over the
+// waveform of the last captured block
+func (m Model) renderSpectrumTab() string {
+	if len(m.spectrogram) == 0 && len(m.waveform) == 0 {
+		return m.styles.info.Render("Make a sound to see its spectrum...") + "\n"
+	}
+
+	s := ""
+	if len(m.spectrogram) > 0 {
+		s += renderSpectrogram(m.styles, m.spectrogram, m.spectrogramScale, m.columnWidth())
+		s += "\n"
+	}
+	if len(m.waveform) > 0 {
+		s += "\n"
+		s += m.styles.info.Render("Waveform:")
+		s += "\n"
+		s += renderWaveform(m.styles, m.waveform, m.columnWidth())
+		s += "\n"
+	}
+	return s
+}
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// tabKeys are the keys that switch tabs
+var tabKeys = map[string]tea.KeyMsg{
+	"tab":       {Type: tea.KeyTab},
+	"shift+tab": {Type: tea.KeyShiftTab},
+	"1":         {Type: tea.KeyRunes, Runes: []rune("1")},
+	"2":         {Type: tea.KeyRunes, Runes: []rune("2")},
+	"3":         {Type: tea.KeyRunes, Runes: []rune("3")},
+	"4":         {Type: tea.KeyRunes, Runes: []rune("4")},
+	"5":         {Type: tea.KeyRunes, Runes: []rune("5")},
+}
+
+func TestTabSwitching(t *testing.T) {
+	m := sizedModel(100, 50).WithPlain(true)
+	tests := []struct {
+		key  string
+		want tab
+		bar  string // Label of the highlighted tab
+	}{
+		{"tab", tabTimeline, "2 Timeline"},
+		{"tab", tabStats, "3 Stats"},
+		{"tab", tabSpectrum, "4 Spectrum"},
+		{"tab", tabTuner, "1 Tuner"},
+		{"shift+tab", tabSpectrum, "4 Spectrum"},
+		{"shift+tab", tabStats, "3 Stats"},
+		{"2", tabTimeline, "2 Timeline"},
+		{"4", tabSpectrum, "4 Spectrum"},
+		{"5", tabSpectrum, "4 Spectrum"}, // No fifth tab
+		{"1", tabTuner, "1 Tuner"},
+	}
+	for i, test := range tests {
+		m = updateModel(m, tabKeys[test.key])
+		if m.tab != test.want {
+			t.Errorf("key %d (%s): tab %s, want %s", i+1, test.key, tabNames[m.tab], tabNames[test.want])
+		}
+		if bar := ansi.Strip(m.renderTabBar()); strings.Count(bar, "[") != 1 || !strings.Contains(bar, "["+test.bar+"]") {
+			t.Errorf("key %d (%s): tab bar %q, want %q highlighted", i+1, test.key, bar, test.bar)
+		}
+	}
+
+	// With the string tuner on, digits lock strings rather than pick tabs
+	m = m.WithStringSet(pitch.StringSetStandard)
+	m = updateModel(m, tabKeys["3"])
+	if m.tab != tabTuner {
+		t.Errorf("3 with the string tuner on showed the %s tab", tabNames[m.tab])
+	}
+}
+
+func TestTabsUpdateInBackground(t *testing.T) {
+	m := sizedModel(100, 50)
+	entries := len(m.timeline)
+
+	// Notes played while the Spectrum tab is up still reach the timeline and
+	// the session, on top of the five already played
+	m = updateModel(m, tabKeys["4"])
+	m = noteOns(m, time.Now(), 261.63, 329.63, 392)
+	if got := len(m.timeline); got != entries+3 {
+		t.Errorf("%d timeline entries after three notes on the Spectrum tab, want %d", got, entries+3)
+	}
+	if view := ansi.Strip(m.View()); strings.Contains(view, "G4") {
+		t.Errorf("Spectrum tab shows the timeline:\n%s", view)
+	}
+	m = updateModel(m, tabKeys["2"])
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "C4") || !strings.Contains(view, "G4") {
+		t.Errorf("Timeline tab lacks the notes played on the Spectrum tab:\n%s", view)
+	}
+	m = updateModel(m, tabKeys["3"])
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "Session: 8 notes") {
+		t.Errorf("Stats tab lacks the notes played on the Spectrum tab:\n%s", view)
+	}
+
+	// Spectra arriving while the Timeline tab is up still build up
+	m = updateModel(m, tabKeys["2"])
+	levels := make([]float64, pitch.DefaultSpectrogramScale.Bands)
+	for range 5 {
+		m = updateModel(m, UpdateSpectrumMsg{Scale: pitch.DefaultSpectrogramScale, Levels: levels})
+	}
+	m = updateModel(m, UpdateWaveformMsg{Samples: []float32{0, 0.5, 0, -0.5}})
+	if len(m.spectrogram) != 5 || len(m.waveform) != 4 {
+		t.Errorf("%d spectra and %d samples kept on the Timeline tab", len(m.spectrogram), len(m.waveform))
+	}
+}
+
+func TestTabRendering(t *testing.T) {
+	m := sizedModel(100, 50)
+	m = updateModel(m, UpdateWaveformMsg{Samples: []float32{0, 0.5, 0, -0.5}})
+
+	// What each tab shows, and what only another tab does
+	tests := []struct {
+		tab   tab
+		shows []string
+		hides []string
+	}{
+		{tabTuner, []string{"Frequency: 445.00 Hz", "╭"}, []string{"Timeline:", "Session:", "Waveform:"}},
+		{tabTimeline, []string{"Timeline:", "G3", "B3", "Freeze", "Octaves:"}, []string{"Frequency:", "Session:", "Waveform:"}},
+		{tabStats, []string{"Session: 5 notes", "Intonation"}, []string{"Timeline:", "Frequency:", "Waveform:"}},
+		{tabSpectrum, []string{"Waveform:"}, []string{"Timeline:", "Session:", "Frequency:"}},
+	}
+	for _, test := range tests {
+		view := m.switchTab(test.tab).View()
+		checkFits(t, view, 100, 50)
+		text := ansi.Strip(view)
+		for _, want := range test.shows {
+			if !strings.Contains(text, want) {
+				t.Errorf("%s tab lacks %q:\n%s", tabNames[test.tab], want, text)
+			}
+		}
+		for _, unwanted := range test.hides {
+			if strings.Contains(text, unwanted) {
+				t.Errorf("%s tab shows %q:\n%s", tabNames[test.tab], unwanted, text)
+			}
+		}
+	}
+}
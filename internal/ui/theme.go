@@ -0,0 +1,272 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the set of colors the UI is drawn in
+type Theme struct {
+	Name            string
+	Title           string            // Title text
+	TitleBackground string            // Title bar
+	Info            string            // Regular text
+	Debug           string            // Secondary text such as debug info and axis labels
+	Emphasis        string            // Text standing out from the regular text
+	Border          string            // Borders of the note box and timeline
+	NoteText        string            // Note names on their colors
+	Notes           map[string]string // Color of each natural note by letter; accidentals take their neighbors'
+	Rest            string            // Rests in the timeline
+}
+
+// Built-in themes
+var (
+	ThemeDefault = Theme{
+		Name:            "Default",
+		Title:           "#FAFAFA",
+		TitleBackground: "#7D56F4",
+		Info:            "#CCCCCC",
+		Debug:           "#888888",
+		Emphasis:        "#FFFFFF",
+		Border:          "#666666",
+		NoteText:        "#FAFAFA",
+		Notes: map[string]string{ // Moderate, not too bright, not too pastel
+			"C": "#e5cf9e", // Moderate Beige
+			"D": "#663e7d", // Medium Purple
+			"E": "#e3a53e", // Moderate Yellow
+			"F": "#c4563f", // Moderate Orange-Red
+			"G": "#43873c", // Moderate Green
+			"A": "#b64040", // Moderate Red
+			"B": "#2a7bba", // Moderate Blue
+		},
+		Rest: "#3A3A3A",
+	}
+
+	// ThemeLight keeps text readable on a light terminal background
+	ThemeLight = Theme{
+		Name:            "Light",
+		Title:           "#FFFFFF",
+		TitleBackground: "#5A3FC0",
+		Info:            "#333333",
+		Debug:           "#6E6E6E",
+		Emphasis:        "#000000",
+		Border:          "#999999",
+		NoteText:        "#FFFFFF",
+		Notes: map[string]string{ // Deeper than the default, to stand out from white
+			"C": "#9c8449",
+			"D": "#5b2f75",
+			"E": "#b7791f",
+			"F": "#a63e2a",
+			"G": "#2f6b29",
+			"A": "#9b2c2c",
+			"B": "#1f5f96",
+		},
+		Rest: "#D8D8D8",
+	}
+
+	// ThemeHighContrast colors the notes from the Okabe-Ito palette, which
+	// stays distinguishable with the common forms of color blindness
+	ThemeHighContrast = Theme{
+		Name:            "High Contrast",
+		Title:           "#000000",
+		TitleBackground: "#F0E442",
+		Info:            "#FFFFFF",
+		Debug:           "#BBBBBB",
+		Emphasis:        "#F0E442",
+		Border:          "#FFFFFF",
+		NoteText:        "#000000",
+		Notes: map[string]string{
+			"C": "#F0E442", // Yellow
+			"D": "#CC79A7", // Reddish purple
+			"E": "#E69F00", // Orange
+			"F": "#D55E00", // Vermillion
+			"G": "#009E73", // Bluish green
+			"A": "#FFFFFF", // White
+			"B": "#56B4E9", // Sky blue
+		},
+		Rest: "#444444",
+	}
+)
+
+// Themes lists the built-in themes in the order the UI cycles through them
+var Themes = []Theme{
+	ThemeDefault,
+	ThemeLight,
+	ThemeHighContrast,
+}
+
+// ThemeByName returns the built-in theme with the given name, ignoring case,
+// spaces and hyphens ("high-contrast" finds "High Contrast")
+func ThemeByName(name string) (Theme, bool) {
+	compact := strings.NewReplacer(" ", "", "-", "")
+	for _, theme := range Themes {
+		if strings.EqualFold(compact.Replace(theme.Name), compact.Replace(name)) {
+			return theme, true
+		}
+	}
+	return Theme{}, false
+}
+
+// styles are the styles drawn with a theme
+type styles struct {
+	theme Theme
+	plain bool // Drawn in plain ASCII without colors, notes told apart by their names alone
+
+	title         lipgloss.Style
+	info          lipgloss.Style
+	debug         lipgloss.Style
+	precise       lipgloss.Style // Cents measured over a long window by precision mode
+	noSound       lipgloss.Style
+	timeline      lipgloss.Style
+	timelineLabel lipgloss.Style
+	rest          lipgloss.Style // Rests in the timeline
+	chord         lipgloss.Style // Chord symbols, above the chord's notes and in the timeline
+	button        lipgloss.Style
+	clearButton   lipgloss.Style
+}
+
+// newStyles builds the styles of a theme, or of plain mode
+func newStyles(theme Theme, plain bool) styles {
+	return styles{
+		theme: theme,
+		plain: plain,
+
+		title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color(theme.Title)).
+			Background(lipgloss.Color(theme.TitleBackground)).
+			PaddingLeft(2).
+			PaddingRight(2).
+			MarginBottom(1),
+
+		info: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Info)),
+
+		debug: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Debug)),
+
+		precise: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color(theme.Emphasis)),
+
+		noSound: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.NoteText)).
+			Background(lipgloss.Color(theme.Debug)).
+			Bold(true).
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#333333")).
+			Padding(2, 4).
+			MarginBottom(1),
+
+		timeline: lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color(theme.Border)).
+			Padding(0, 1).
+			MarginTop(1).
+			Width(defaultTimelineWidth + 4), // Add padding for borders
+
+		timelineLabel: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Info)),
+
+		rest: lipgloss.NewStyle().
+			Faint(true).
+			Background(lipgloss.Color(theme.Rest)).
+			Foreground(lipgloss.Color(theme.Debug)),
+
+		chord: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color(theme.Title)).
+			Background(lipgloss.Color(theme.TitleBackground)),
+
+		button: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#555555")).
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#999999")).
+			Padding(0, buttonPadding).
+			MarginLeft(2).
+			Bold(true),
+
+		clearButton: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#AA3333")). // Red background for clear button
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#662222")).
+			Padding(0, buttonPadding).
+			MarginLeft(2).
+			Bold(true),
+	}
+}
+
+// noteStyle returns the note box style for a note
+func (st styles) noteStyle(note *pitch.Note) lipgloss.Style {
+	if isAccidental(note.PitchClass) && !st.plain {
+		// For sharp and flat notes, we handle the rendering separately in View()
+		// Just return a basic style
+		return lipgloss.NewStyle().Bold(true).MarginBottom(1)
+	}
+
+	// For natural notes, use a single color
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color(st.theme.NoteText)).
+		Background(lipgloss.Color(st.noteColor(note))).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#333333")).
+		Padding(2, 4).
+		MarginBottom(1)
+}
+
+// bracket wraps a timeline entry's text in brackets in plain mode, which
+// keeps entries apart without their colors
+func (st styles) bracket(text string) string {
+	if st.plain {
+		return "[" + text + "]"
+	}
+	return text
+}
+
+// dimNote returns a faded variant of a note box style
+func (st styles) dimNote(style lipgloss.Style) lipgloss.Style {
+	return style.
+		Faint(true).
+		Foreground(lipgloss.Color("#AAAAAA")).
+		BorderForeground(lipgloss.Color("#222222"))
+}
+
+// releaseNote returns a grayed-out variant of a note box style, for a note
+// that has stopped sounding and is about to be cleared
+func (st styles) releaseNote(style lipgloss.Style) lipgloss.Style {
+	return style.
+		Faint(true).
+		Foreground(lipgloss.Color(st.theme.Debug)).
+		Background(lipgloss.Color(st.theme.Rest)).
+		BorderForeground(lipgloss.Color(st.theme.Border))
+}
+
+// noteBoxColors returns the colors for a note. Natural notes use their own
+// color twice. Accidentals use the color of the natural note they're spelled
+// from, then that of the neighbor they lean toward (C# -> C, D; Db -> D, C),
+// so the colors follow the pitch class whatever the naming scheme.
+func (st styles) noteBoxColors(note *pitch.Note) (base, neighbor string) {
+	pitchClass := note.PitchClass
+	if !isAccidental(pitchClass) {
+		color := st.theme.Notes[naturalNoteLetters[pitchClass]]
+		return color, color
+	}
+
+	lower := st.theme.Notes[naturalNoteLetters[(pitchClass+11)%12]]
+	upper := st.theme.Notes[naturalNoteLetters[(pitchClass+1)%12]]
+	if _, accidental := splitAccidental(note.Name); accidental == "b" {
+		return upper, lower
+	}
+	return lower, upper
+}
+
+// noteColor returns the color for a note
+func (st styles) noteColor(note *pitch.Note) string {
+	base, _ := st.noteBoxColors(note)
+	return base
+}
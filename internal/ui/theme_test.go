@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"math"
+	"regexp"
+	"testing"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var hexColor = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+func TestThemesColorEveryPitchClass(t *testing.T) {
+	// Every pitch class, spelled with sharps and with flats, takes its own
+	// natural's color, or an accidental its neighbors' two
+	for _, theme := range Themes {
+		st := newStyles(theme, false)
+		for _, spelling := range []pitch.Spelling{pitch.SpellingSharps, pitch.SpellingFlats} {
+			converter := pitch.NewNoteConverter()
+			converter.SetSpelling(spelling)
+			for pitchClass := 0; pitchClass < 12; pitchClass++ {
+				note := converter.FromFrequency(261.63 * math.Pow(2, float64(pitchClass)/12))
+				base, neighbor := st.noteBoxColors(note)
+				if !hexColor.MatchString(base) || !hexColor.MatchString(neighbor) {
+					t.Errorf("%s: %s has colors %q and %q", theme.Name, note.Name, base, neighbor)
+				}
+				if isAccidental(pitchClass) == (base == neighbor) {
+					t.Errorf("%s: %s has colors %q and %q", theme.Name, note.Name, base, neighbor)
+				}
+			}
+		}
+
+		for _, color := range []string{theme.Title, theme.TitleBackground, theme.Info, theme.Debug, theme.Emphasis, theme.Border, theme.NoteText, theme.Rest} {
+			if !hexColor.MatchString(color) {
+				t.Errorf("%s: color %q", theme.Name, color)
+			}
+		}
+		if len(theme.Notes) != 7 {
+			t.Errorf("%s: colors for %d natural notes", theme.Name, len(theme.Notes))
+		}
+	}
+}
+
+func TestThemeKeyRecolors(t *testing.T) {
+	withTrueColor(t)
+
+	// Each press of T moves to the next theme, changing the colors drawn
+	// but not the text, and the last wraps round to the first
+	m := sizedModel(100, 30)
+	seen := map[string]string{}
+	for i := 0; i <= len(Themes); i++ {
+		theme := Themes[i%len(Themes)]
+		if m.styles.theme.Name != theme.Name {
+			t.Fatalf("press %d: theme %q, want %q", i, m.styles.theme.Name, theme.Name)
+		}
+		view := m.renderTunerTab()
+		if i < len(Themes) {
+			for name, other := range seen {
+				if other == view {
+					t.Errorf("%s draws the same as %s", theme.Name, name)
+				}
+			}
+			seen[theme.Name] = view
+		} else if view != seen[theme.Name] {
+			t.Errorf("%s drawn differently the second time round", theme.Name)
+		}
+		if ansi.Strip(view) != ansi.Strip(seen[Themes[0].Name]) {
+			t.Errorf("%s changes the text:\n%s", theme.Name, ansi.Strip(view))
+		}
+		m = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	}
+}
@@ -0,0 +1,258 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// mixedTimeline returns count entries a second apart, cycling through short
+// and long notes, a rest and a chord, the newest ending at now
+func mixedTimeline(count int, now time.Time) []TimelineEntry {
+	converter := pitch.NewNoteConverter()
+	start := now.Add(-time.Duration(count) * time.Second)
+	entries := make([]TimelineEntry, count)
+	for i := range entries {
+		at := start.Add(time.Duration(i) * time.Second)
+		note := converter.FromFrequency(220 * float64(1+i%3))
+		switch i % 4 {
+		case 0:
+			entries[i] = TimelineEntry{Note: note, Timestamp: at, Duration: 250 * time.Millisecond}
+		case 1:
+			entries[i] = TimelineEntry{Note: note, Timestamp: at, Duration: 900 * time.Millisecond}
+		case 2:
+			entries[i] = TimelineEntry{Timestamp: at, Duration: time.Second}
+		case 3:
+			entries[i] = TimelineEntry{Note: note, Timestamp: at, Duration: 600 * time.Millisecond, Chord: "Am"}
+		}
+	}
+	return entries
+}
+
+func TestTimelineTimesAlignWithEntries(t *testing.T) {
+	// Every tenth entry back from the newest is marked at its first column,
+	// which is where its block starts in the row above
+	st := newStyles(ThemeDefault, true)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	entries := mixedTimeline(21, now)
+	cellWidth := timelineCellWidth(st, entries, pitch.NotationScientific)
+
+	var blocks strings.Builder
+	starts := make([]int, len(entries))
+	for i, entry := range entries {
+		starts[i] = ansi.StringWidth(blocks.String())
+		blocks.WriteString(ansi.Strip(renderTimelineEntry(st, entry, cellWidth, pitch.NotationScientific)))
+	}
+	width := ansi.StringWidth(blocks.String())
+
+	times := renderTimelineTimes(st, entries, cellWidth, pitch.NotationScientific, width, now)
+	for _, i := range []int{0, 10, 20} {
+		marker := formatAgo(now.Sub(entries[i].Timestamp))
+		if got := strings.Index(times, marker); got != starts[i] {
+			t.Errorf("marker %q at column %d, want %d where entry %d starts\n%s\n%s", marker, got, starts[i], i, blocks.String(), times)
+		}
+	}
+	if got := strings.Count(times, "-"); got != 3 {
+		t.Errorf("%d markers, want 3:\n%s", got, times)
+	}
+
+	// A marker that would run past the right edge is left out
+	last := starts[20]
+	if clipped := renderTimelineTimes(st, entries, cellWidth, pitch.NotationScientific, last+2, now); strings.Contains(clipped, "-1s") || !strings.Contains(clipped, "-11s") {
+		t.Errorf("times cut at column %d: %q", last+2, clipped)
+	}
+}
+
+func TestTimelineFitsRightEdge(t *testing.T) {
+	// However long the history, the blocks and their times fill the box up
+	// to its right edge and no further, ending with the newest entry
+	for _, width := range []int{60, 80, 100, 140} {
+		m := sizedModel(width, 40)
+		now := time.Now()
+		m.timeline = mixedTimeline(200, now)
+
+		entries, start, end, cellWidth := m.timelineWindow()
+		if end != len(entries) {
+			t.Errorf("%d columns: shows up to entry %d of %d", width, end, len(entries))
+		}
+		used := 0
+		for _, entry := range entries[start:end] {
+			used += ansi.StringWidth(renderTimelineEntry(m.styles, entry, cellWidth, m.notation))
+		}
+		next := entryCells(m.styles, entries[start-1], cellWidth, m.notation) * cellWidth
+		if used > m.timelineWidth() || used+next <= m.timelineWidth() {
+			t.Errorf("%d columns: blocks take %d of %d columns, and the next older needs %d", width, used, m.timelineWidth(), next)
+		}
+
+		view := m.switchTab(tabTimeline).renderTimelineTab()
+		for _, line := range strings.Split(view, "\n") {
+			if got := ansi.StringWidth(line); got > width {
+				t.Errorf("%d columns: line %d wide\n%s", width, got, ansi.Strip(line))
+			}
+		}
+	}
+}
+
+func TestTimelineBlockWidths(t *testing.T) {
+	// Notes and chords take a cell per quarter second, rounded, from one
+	// while still sounding up to eight; rests a cell per half second up to
+	// four; chords at least enough for their symbol
+	st := newStyles(ThemeDefault, true)
+	a4 := pitch.NewNoteConverter().FromFrequency(440)
+	tests := []struct {
+		name      string
+		entry     TimelineEntry
+		cells     int // At four-column cells
+		wideCells int // At six-column cells, zero for the same
+	}{
+		{"sounding note", TimelineEntry{Note: a4}, 1, 0},
+		{"short note", TimelineEntry{Note: a4, Duration: 100 * time.Millisecond}, 1, 0},
+		{"quarter second", TimelineEntry{Note: a4, Duration: 250 * time.Millisecond}, 1, 0},
+		{"rounded up", TimelineEntry{Note: a4, Duration: 400 * time.Millisecond}, 2, 0},
+		{"second", TimelineEntry{Note: a4, Duration: time.Second}, 4, 0},
+		{"longest", TimelineEntry{Note: a4, Duration: 2 * time.Second}, 8, 0},
+		{"beyond longest", TimelineEntry{Note: a4, Duration: 10 * time.Second}, 8, 0},
+		{"short rest", TimelineEntry{Duration: 100 * time.Millisecond}, 1, 0},
+		{"rest", TimelineEntry{Duration: 1500 * time.Millisecond}, 3, 0},
+		{"long rest", TimelineEntry{Duration: time.Minute}, 4, 0},
+		{"sounding chord", TimelineEntry{Note: a4, Chord: "Am"}, 2, 1},       // "[Am]" and a space either side
+		{"wide chord symbol", TimelineEntry{Note: a4, Chord: "Cmaj7"}, 3, 2}, // "[Cmaj7]" likewise
+		{"long chord", TimelineEntry{Note: a4, Chord: "Cmaj7", Duration: 1500 * time.Millisecond}, 6, 6},
+	}
+	for _, tt := range tests {
+		for _, cellWidth := range []int{noteDisplayWidth, 6} {
+			cells := tt.cells
+			if cellWidth == 6 && tt.wideCells > 0 {
+				cells = tt.wideCells
+			}
+			if got := entryCells(st, tt.entry, cellWidth, pitch.NotationScientific); got != cells {
+				t.Errorf("%s at %d-column cells: %d cells, want %d", tt.name, cellWidth, got, cells)
+			}
+			if got := ansi.StringWidth(renderTimelineEntry(st, tt.entry, cellWidth, pitch.NotationScientific)); got != cells*cellWidth {
+				t.Errorf("%s at %d-column cells: %d columns wide, want %d", tt.name, cellWidth, got, cells*cellWidth)
+			}
+		}
+	}
+}
+
+func TestTimelineScrollClamps(t *testing.T) {
+	keys := map[string]tea.KeyMsg{
+		"f":     {Type: tea.KeyRunes, Runes: []rune("f")},
+		"left":  {Type: tea.KeyLeft},
+		"right": {Type: tea.KeyRight},
+		"home":  {Type: tea.KeyHome},
+		"end":   {Type: tea.KeyEnd},
+	}
+	press := func(m Model, names ...string) Model {
+		for _, name := range names {
+			m = updateModel(m, keys[name])
+		}
+		return m
+	}
+
+	// A hundred one-second notes, five of which fit the box at a time
+	m := sizedModel(100, 30).switchTab(tabTimeline)
+	converter := pitch.NewNoteConverter()
+	start := time.Now().Add(-100 * time.Second)
+	m.timeline = nil
+	for i := 0; i < 100; i++ {
+		m.timeline = append(m.timeline, TimelineEntry{Note: converter.FromFrequency(220 + float64(i)), Timestamp: start.Add(time.Duration(i) * time.Second), Duration: time.Second})
+	}
+	window := func(m Model) (int, int) {
+		_, start, end, _ := m.timelineWindow()
+		return start, end
+	}
+	if start, end := window(m); start != 95 || end != 100 {
+		t.Fatalf("shows entries %d-%d, want the newest five", start, end)
+	}
+
+	tests := []struct {
+		keys       []string
+		start, end int
+	}{
+		{[]string{"left"}, 95, 100}, // Not frozen, so no scrolling
+		{[]string{"f", "left", "left", "left"}, 92, 97},
+		{[]string{"right"}, 93, 98},
+		{[]string{"right", "right", "right"}, 95, 100}, // Stops at the newest
+		{[]string{"home"}, 0, 5},
+		{[]string{"left", "left"}, 0, 5}, // Stops at the oldest
+		{[]string{"end"}, 95, 100},
+		{[]string{"home", "f"}, 95, 100}, // Resuming shows the newest again
+	}
+	for _, tt := range tests {
+		m = press(m, tt.keys...)
+		if start, end := window(m); start != tt.start || end != tt.end {
+			t.Errorf("after %v: shows entries %d-%d, want %d-%d", tt.keys, start, end, tt.start, tt.end)
+		}
+	}
+	if m.timelineScroll != 0 || m.maxTimelineScroll() != 95 {
+		t.Errorf("scrolled %d of at most %d, want 0 of 95", m.timelineScroll, m.maxTimelineScroll())
+	}
+
+	// Unscrolled, the box stays on the newest entry as notes arrive
+	for i, frequency := range []float64{330, 440, 550} {
+		at := time.Now()
+		m = updateModel(m, NoteOnMsg{Note: *converter.FromFrequency(frequency), At: at})
+		entries, start, end, _ := m.timelineWindow()
+		if end != len(entries) || !entries[end-1].Timestamp.Equal(at) {
+			t.Errorf("note %d: shows entries %d-%d of %d", i, start, end, len(entries))
+		}
+	}
+}
+
+// playNotes sends each note as an onset, a NoteOn and, after length, a
+// NoteOff, starting gap after the previous one ended
+func playNotes(m Model, start time.Time, length, gap time.Duration, frequencies ...float64) Model {
+	converter := pitch.NewNoteConverter()
+	at := start
+	for _, frequency := range frequencies {
+		note := *converter.FromFrequency(frequency)
+		m = updateModel(m, OnsetMsg{Time: at})
+		m = updateModel(m, NoteOnMsg{Note: note, At: at})
+		m = updateModel(m, NoteOffMsg{Note: note, At: at.Add(length), Duration: length})
+		at = at.Add(length + gap)
+	}
+	return m
+}
+
+func TestGroupRepeatsKeepsEntries(t *testing.T) {
+	var model tea.Model = NewModel(nil).WithPlain(true)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m := model.(Model).switchTab(tabTimeline)
+
+	// Five C4s in quick succession, a D4, then C4 again after a pause too
+	// long to join the run
+	start := time.Now().Add(-10 * time.Second)
+	m = playNotes(m, start, 300*time.Millisecond, 100*time.Millisecond, 261.63, 261.63, 261.63, 261.63, 261.63, 293.66)
+	m = playNotes(m, start.Add(5*time.Second), 300*time.Millisecond, 0, 261.63)
+	if len(m.timeline) != 7 {
+		t.Fatalf("%d timeline entries, want 7", len(m.timeline))
+	}
+
+	grouped := updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	view := ansi.Strip(grouped.renderTimelineTab())
+	if !strings.Contains(view, "[C4×5]") || strings.Count(view, "[C4") != 2 || !strings.Contains(view, "[D4]") {
+		t.Errorf("grouped timeline:\n%s", view)
+	}
+	if !strings.Contains(view, "1–3 of 3, repeats grouped") {
+		t.Errorf("grouped timeline counts the wrong entries:\n%s", view)
+	}
+
+	// The plays themselves are all kept, to show again when ungrouped
+	if len(grouped.timeline) != 7 {
+		t.Errorf("grouping left %d timeline entries, want 7", len(grouped.timeline))
+	}
+	for i, entry := range grouped.timeline[:5] {
+		if entry.Repeats != 0 || entry.Note.Name != "C" || entry.Duration != 300*time.Millisecond {
+			t.Errorf("entry %d became %+v", i, entry)
+		}
+	}
+	ungrouped := ansi.Strip(updateModel(grouped, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")}).renderTimelineTab())
+	if strings.Contains(ungrouped, "×") || strings.Count(ungrouped, "[C4]") != 6 {
+		t.Errorf("ungrouped timeline:\n%s", ungrouped)
+	}
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// titleText returns the words of a rendered title row
+func titleText(title string) string {
+	return strings.Join(strings.Fields(ansi.Strip(title)), " ")
+}
+
+func TestFormatClock(t *testing.T) {
+	tests := []struct {
+		elapsed time.Duration
+		want    string
+	}{
+		{0, "00:00:00"},
+		{-time.Second, "00:00:00"},
+		{999 * time.Millisecond, "00:00:00"},
+		{59 * time.Second, "00:00:59"},
+		{time.Minute, "00:01:00"},
+		{59*time.Minute + 59*time.Second + 999*time.Millisecond, "00:59:59"},
+		{time.Hour, "01:00:00"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "01:02:03"},
+		{23*time.Hour + 59*time.Minute + 59*time.Second, "23:59:59"},
+		{24 * time.Hour, "24:00:00"},
+		{100*time.Hour + 30*time.Second, "100:00:30"},
+	}
+	for _, test := range tests {
+		if got := formatClock(test.elapsed); got != test.want {
+			t.Errorf("formatClock(%v) = %q, want %q", test.elapsed, got, test.want)
+		}
+	}
+}
+
+func TestTitleBadges(t *testing.T) {
+	now := time.Now()
+	for state := range 8 {
+		recording, paused, frozen := state&1 != 0, state&2 != 0, state&4 != 0
+
+		// Recording arrives from the processing loop, and the others are keys
+		m := NewModel(make(chan Command, 4))
+		m.started = now.Add(-(time.Hour + 5*time.Second))
+		m = updateModel(m, RecordingStateMsg{Recording: recording})
+		if paused {
+			m = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+		}
+		if frozen {
+			m = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+		}
+
+		want := "TuneNote - Musical Note Detector 01:00:05"
+		if recording {
+			want += " ● REC"
+		}
+		if paused {
+			want += " PAUSED"
+		}
+		if frozen {
+			want += " FROZEN"
+		}
+		if got := titleText(m.renderTitle(now)); got != want {
+			t.Errorf("recording %v, paused %v, frozen %v: %q, want %q", recording, paused, frozen, got, want)
+		}
+
+		// Recording stopping takes its badge down
+		if recording {
+			m = updateModel(m, RecordingStateMsg{Recording: false})
+			if strings.Contains(ansi.Strip(m.renderTitle(now)), "REC") {
+				t.Errorf("recording badge left up once recording stopped")
+			}
+		}
+	}
+}
+
+func TestTitleTruncates(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{100, "TuneNote - Musical Note Detector 01:00:05 ● REC PAUSED FROZEN"},
+		{60, "TuneNote 01:00:05 ● REC PAUSED FROZEN"},
+		{40, "TuneNote 01:00:05 ● REC PAUSED"},
+		{30, "TuneNote 01:00:05 ● REC"},
+	}
+	for _, test := range tests {
+		m := updateModel(NewModel(make(chan Command, 4)), RecordingStateMsg{Recording: true})
+		m = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+		m = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+		m.started = now.Add(-(time.Hour + 5*time.Second))
+		m.width = test.width
+
+		title := m.renderTitle(now)
+		if width := lipgloss.Width(title); width > test.width {
+			t.Errorf("%d wide: title %d wide", test.width, width)
+		}
+		if got := titleText(title); got != test.want {
+			t.Errorf("%d wide: %q, want %q", test.width, got, test.want)
+		}
+	}
+}
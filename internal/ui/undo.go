@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/0xlemi/tunenote/internal/pitch"
+)
+
+// undoWindow is how long clearing the history can be undone
+const undoWindow = 30 * time.Second
+
+// clearedState is what clearing threw away, kept for a while to undo it
+type clearedState struct {
+	at time.Time // When the history was cleared
+
+	timeline       []TimelineEntry
+	timelineScroll int
+	keyEstimator   *pitch.KeyEstimator
+	keyLabel       string
+	tempoEstimator *pitch.TempoEstimator
+	tempoLabel     string
+	intonation     *pitch.IntonationStats
+	drift          *pitch.DriftMonitor
+	session        *pitch.SessionStats
+	practice       *pitch.TargetPractice
+	judgedAt       time.Time
+}
+
+// clearHistory clears the timeline and the session's stats, keeping them in
+// place of anything cleared before so u can bring them back for a while
+func (m Model) clearHistory(at time.Time) Model {
+	m.cleared = &clearedState{
+		at:             at,
+		timeline:       m.timeline,
+		timelineScroll: m.timelineScroll,
+		keyEstimator:   m.keyEstimator,
+		keyLabel:       m.keyLabel,
+		tempoEstimator: m.tempoEstimator,
+		tempoLabel:     m.tempoLabel,
+		intonation:     m.intonation,
+		drift:          m.drift,
+		session:        m.session,
+		practice:       m.practice,
+		judgedAt:       m.judgedAt,
+	}
+
+	m.timeline = make([]TimelineEntry, 0, min(m.timelineLength, maxTimelineEntries))
+	m.timelineScroll = 0
+	m.keyEstimator = pitch.NewKeyEstimator()
+	m.keyLabel = ""
+	m.tempoEstimator = pitch.NewTempoEstimator()
+	m.tempoLabel = ""
+	m.intonation = pitch.NewIntonationStats()
+	m.drift = pitch.NewDriftMonitor()
+	m.session = pitch.NewSessionStats()
+	if m.practice != nil {
+		m.practice = pitch.NewTargetPractice(m.practice.Target())
+		m.judgedAt = time.Time{}
+	}
+	return m.withStatus("Cleared — press u to undo", false)
+}
+
+// undoClear brings back what the latest clear threw away, unless it was
+// longer than undoWindow ago. What was played since is dropped.
+func (m Model) undoClear(now time.Time) Model {
+	m = m.expireClear(now)
+	if m.cleared == nil {
+		return m.withStatus("Nothing to undo", true)
+	}
+
+	c := m.cleared
+	m.cleared = nil
+	m.timeline = c.timeline
+	m.timelineScroll = c.timelineScroll
+	m.keyEstimator = c.keyEstimator
+	m.keyLabel = c.keyLabel
+	m.tempoEstimator = c.tempoEstimator
+	m.tempoLabel = c.tempoLabel
+	m.intonation = c.intonation
+	m.drift = c.drift
+	m.session = c.session
+	if m.practice != nil && c.practice != nil {
+		m.practice = c.practice
+		m.judgedAt = c.judgedAt
+	}
+	return m.withStatus("Restored the cleared history", false)
+}
+
+// expireClear drops what was cleared once it can no longer be undone
+func (m Model) expireClear(now time.Time) Model {
+	if m.cleared != nil && now.Sub(m.cleared.at) > undoWindow {
+		m.cleared = nil
+	}
+	return m
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// historyKeys are the keys that clear and restore the history
+var historyKeys = map[string]tea.KeyMsg{
+	"c": {Type: tea.KeyRunes, Runes: []rune("c")},
+	"u": {Type: tea.KeyRunes, Runes: []rune("u")},
+}
+
+func TestClearUndo(t *testing.T) {
+	m := noteOns(NewModel(nil), time.Now().Add(-time.Minute), 261.63, 329.63, 392)
+	timeline := append([]TimelineEntry(nil), m.timeline...)
+	session := ansi.Strip(m.renderSession())
+
+	m = updateModel(m, historyKeys["c"])
+	if len(m.timeline) != 0 || m.status != "Cleared — press u to undo" {
+		t.Fatalf("after clearing: %d entries, status %q", len(m.timeline), m.status)
+	}
+	if got := ansi.Strip(m.renderSession()); got != "Session: no notes yet" {
+		t.Errorf("session after clearing: %q", got)
+	}
+
+	m = updateModel(m, historyKeys["u"])
+	if !reflect.DeepEqual(m.timeline, timeline) {
+		t.Errorf("undo restored %d entries unlike the %d cleared", len(m.timeline), len(timeline))
+	}
+	if got := ansi.Strip(m.renderSession()); got != session {
+		t.Errorf("undo restored session\n%s\nwant\n%s", got, session)
+	}
+	if m.status != "Restored the cleared history" || m.cleared != nil {
+		t.Errorf("after undoing: status %q, stash kept %v", m.status, m.cleared != nil)
+	}
+
+	// Only one undo per clear
+	if m = updateModel(m, historyKeys["u"]); m.status != "Nothing to undo" || !reflect.DeepEqual(m.timeline, timeline) {
+		t.Errorf("second undo: status %q, %d entries", m.status, len(m.timeline))
+	}
+}
+
+func TestUndoWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		expire   func(m Model) Model
+		restored bool
+	}{
+		{"tick inside window", func(m Model) Model {
+			return updateModel(m, TickMsg(time.Now().Add(undoWindow-time.Second)))
+		}, true},
+		{"tick after window", func(m Model) Model {
+			return updateModel(m, TickMsg(time.Now().Add(undoWindow+time.Second)))
+		}, false},
+		{"undo after window", func(m Model) Model {
+			m.cleared.at = m.cleared.at.Add(-undoWindow - time.Second)
+			return m
+		}, false},
+	}
+	for _, test := range tests {
+		m := noteOns(NewModel(nil), time.Now().Add(-time.Minute), 261.63, 329.63)
+		m = updateModel(m, historyKeys["c"])
+		m = test.expire(m)
+		m = updateModel(m, historyKeys["u"])
+		if restored := len(m.timeline) == 2; restored != test.restored {
+			t.Errorf("%s: restored %v, want %v", test.name, restored, test.restored)
+		}
+		if want := map[bool]string{true: "Restored the cleared history", false: "Nothing to undo"}[test.restored]; m.status != want {
+			t.Errorf("%s: status %q, want %q", test.name, m.status, want)
+		}
+	}
+}
+
+func TestClearClearUndo(t *testing.T) {
+	start := time.Now().Add(-time.Minute)
+	m := noteOns(NewModel(nil), start, 261.63, 329.63, 392)
+	m = updateModel(m, historyKeys["c"])
+
+	// What's played after the first clear is what the second throws away,
+	// and all undo brings back
+	m = noteOns(m, start.Add(10*time.Second), 440, 493.88)
+	timeline := append([]TimelineEntry(nil), m.timeline...)
+	session := ansi.Strip(m.renderSession())
+	m = updateModel(m, historyKeys["c"])
+	m = updateModel(m, historyKeys["u"])
+	if !reflect.DeepEqual(m.timeline, timeline) {
+		t.Errorf("undo restored %d entries, want the 2 played between the clears", len(m.timeline))
+	}
+	if got := ansi.Strip(m.renderSession()); got != session {
+		t.Errorf("undo restored session\n%s\nwant\n%s", got, session)
+	}
+	if m = updateModel(m, historyKeys["u"]); len(m.timeline) != 2 || m.status != "Nothing to undo" {
+		t.Errorf("second undo: %d entries, status %q", len(m.timeline), m.status)
+	}
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"math"
+	"strings"
+)
+
+// Waveform view settings
+const (
+	waveformRows  = 7  // Lines the waveform takes, full scale at the top and bottom
+	waveformWidth = 80 // Columns drawn while the terminal's width is unknown
+)
+
+// UpdateWaveformMsg is a message to update the waveform with the latest
+// captured block
+type UpdateWaveformMsg struct {
+	Samples []float32
+}
+
+// renderWaveform renders the samples as an envelope: each column spans the
+// lowest to the highest sample of its share of the block, between -1 and 1
+// over waveformRows lines with the zero line in the middle
+func renderWaveform(st styles, samples []float32, width int) string {
+	if width <= 0 {
+		width = waveformWidth
+	}
+	columns := min(width, len(samples))
+
+	// Row of a sample, 0 at the top
+	row := func(sample float32) int {
+		level := (1 - math.Max(-1, math.Min(float64(sample), 1))) / 2
+		return min(int(level*waveformRows), waveformRows-1)
+	}
+
+	grid := make([][]rune, waveformRows)
+	for r := range grid {
+		grid[r] = []rune(strings.Repeat(" ", columns))
+		if r == waveformRows/2 {
+			grid[r] = []rune(strings.Repeat("·", columns))
+		}
+	}
+	for c := 0; c < columns; c++ {
+		chunk := samples[c*len(samples)/columns : (c+1)*len(samples)/columns]
+		low, high := chunk[0], chunk[0]
+		for _, sample := range chunk {
+			low, high = min(low, sample), max(high, sample)
+		}
+		for r := row(high); r <= row(low); r++ {
+			grid[r][c] = '│'
+		}
+	}
+
+	lines := make([]string, waveformRows)
+	for r, line := range grid {
+		lines[r] = st.info.Render(string(line))
+	}
+	return strings.Join(lines, "\n")
+}